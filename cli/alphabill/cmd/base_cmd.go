@@ -28,7 +28,12 @@ func New(opts ...interface{}) *WalletApp {
 
 // Execute runs the application
 func (a *WalletApp) Execute(ctx context.Context) (err error) {
-	return a.baseCmd.ExecuteContext(ctx)
+	defer a.baseConf.ReleaseTimeout()
+	err = a.baseCmd.ExecuteContext(ctx)
+	if err != nil && a.baseConf.TimedOut() {
+		return fmt.Errorf("%w after %s: %w", types.ErrCommandTimeout, a.baseConf.Timeout, err)
+	}
+	return err
 }
 
 func (a *WalletApp) AddSubcommands(opts []interface{}) {
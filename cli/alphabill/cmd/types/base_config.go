@@ -1,12 +1,14 @@
 package types
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -23,9 +25,18 @@ type (
 		ConsoleWriter ConsoleWrapper
 
 		Logger *slog.Logger
+
+		// Timeout bounds the runtime of the command, zero means no timeout.
+		Timeout time.Duration
+
+		timeoutCtx    context.Context
+		cancelTimeout context.CancelFunc
 	}
 )
 
+// ErrCommandTimeout is the cause of the command context cancellation when the --timeout is reached.
+var ErrCommandTimeout = errors.New("operation timed out")
+
 const (
 	// The prefix for configuration keys inside environment.
 	envPrefix = "AB"
@@ -41,6 +52,7 @@ const (
 	flagNameLogOutputFile = "log-file"
 	flagNameLogLevel      = "log-level"
 	flagNameLogFormat     = "log-format"
+	flagNameTimeout       = "timeout"
 )
 
 func (c *BaseConfiguration) AddConfigurationFlags(cmd *cobra.Command) {
@@ -49,6 +61,33 @@ func (c *BaseConfiguration) AddConfigurationFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().String(flagNameLogOutputFile, "", "log file path or one of the special values: stdout, stderr, discard")
 	cmd.PersistentFlags().String(flagNameLogLevel, "", "logging level, one of: DEBUG, INFO, WARN, ERROR")
 	cmd.PersistentFlags().String(flagNameLogFormat, "", "log format, one of: text, json, console")
+	cmd.PersistentFlags().DurationVar(&c.Timeout, flagNameTimeout, 0, "abort the command when it has not completed in the given time, ie 30s or 5m (default no timeout)")
+}
+
+// initTimeout replaces the context of the command with one which is cancelled with ErrCommandTimeout
+// when the configured timeout is reached.
+func (c *BaseConfiguration) initTimeout(cmd *cobra.Command) {
+	if c.Timeout <= 0 || c.timeoutCtx != nil {
+		return
+	}
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	c.timeoutCtx, c.cancelTimeout = context.WithTimeoutCause(ctx, c.Timeout, ErrCommandTimeout)
+	cmd.SetContext(c.timeoutCtx)
+}
+
+// TimedOut returns true when the command was aborted because the --timeout was reached.
+func (c *BaseConfiguration) TimedOut() bool {
+	return c.timeoutCtx != nil && errors.Is(context.Cause(c.timeoutCtx), ErrCommandTimeout)
+}
+
+// ReleaseTimeout releases the resources of the command timeout, it must be called once the command has completed.
+func (c *BaseConfiguration) ReleaseTimeout() {
+	if c.cancelTimeout != nil {
+		c.cancelTimeout()
+	}
 }
 
 func (c *BaseConfiguration) InitConfigFileLocation() {
@@ -209,6 +248,7 @@ func InitializeConfig(cmd *cobra.Command, config *BaseConfiguration) error {
 	}
 
 	config.Logger = log
+	config.initTimeout(cmd)
 
 	if config.ConsoleWriter == nil {
 		config.ConsoleWriter = NewStdoutWriter()
@@ -0,0 +1,57 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseConfiguration_Timeout(t *testing.T) {
+	newCmd := func(conf *BaseConfiguration, run func(ctx context.Context) error) *cobra.Command {
+		cmd := &cobra.Command{
+			Use: "test",
+			PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+				return InitializeConfig(cmd, conf)
+			},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return run(cmd.Context())
+			},
+		}
+		conf.AddConfigurationFlags(cmd)
+		return cmd
+	}
+
+	t.Run("command is aborted when timeout is reached", func(t *testing.T) {
+		conf := &BaseConfiguration{ConsoleWriter: NewStdoutWriter()}
+		cmd := newCmd(conf, func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		cmd.SetArgs([]string{"--home", t.TempDir(), "--timeout", "10ms"})
+		defer conf.ReleaseTimeout()
+
+		err := cmd.ExecuteContext(context.Background())
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.True(t, conf.TimedOut())
+		require.Equal(t, 10*time.Millisecond, conf.Timeout)
+	})
+
+	t.Run("no timeout by default", func(t *testing.T) {
+		conf := &BaseConfiguration{ConsoleWriter: NewStdoutWriter()}
+		cmd := newCmd(conf, func(ctx context.Context) error {
+			if _, ok := ctx.Deadline(); ok {
+				return errors.New("unexpected deadline")
+			}
+			return nil
+		})
+		cmd.SetArgs([]string{"--home", t.TempDir()})
+		defer conf.ReleaseTimeout()
+
+		require.NoError(t, cmd.ExecuteContext(context.Background()))
+		require.False(t, conf.TimedOut())
+	})
+}
@@ -1,8 +1,16 @@
 package types
 
+import "github.com/alphabill-org/alphabill-wallet/util"
+
 type WalletConfig struct {
 	Base            *BaseConfiguration
 	WalletHomeDir   string
 	PasswordFromArg string
 	PromptPassword  bool
+	AmountFormat    util.AmountFormat
+}
+
+// FormatAmount converts amount to string using the amount format of the wallet configuration.
+func (c *WalletConfig) FormatAmount(amount uint64, decimals uint32) string {
+	return util.FormatAmount(amount, decimals, c.AmountFormat)
 }
@@ -1,7 +1,9 @@
 package account
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"syscall"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -74,3 +76,18 @@ func PubKeyHexToBytes(s string) ([]byte, bool) {
 	}
 	return pubKeyBytes, true
 }
+
+// AccountNumberByPubKeyHash returns the (1-based) number of the account whose
+// public key SHA256 hash is pubKeyHash.
+func AccountNumberByPubKeyHash(am account.Manager, pubKeyHash []byte) (uint64, error) {
+	keys, err := am.GetAccountKeys()
+	if err != nil {
+		return 0, fmt.Errorf("reading account keys: %w", err)
+	}
+	for i, key := range keys {
+		if bytes.Equal(key.PubKeyHash.Sha256, pubKeyHash) {
+			return uint64(i) + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("no account with public key hash %X in the wallet", pubKeyHash)
+}
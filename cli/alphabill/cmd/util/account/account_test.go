@@ -0,0 +1,28 @@
+package account
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/alphabill-org/alphabill-wallet/wallet/account"
+)
+
+func TestAccountNumberByPubKeyHash(t *testing.T) {
+	am, err := account.NewManager(t.TempDir(), "", true)
+	require.NoError(t, err)
+	require.NoError(t, am.CreateKeys(""))
+	_, _, err = am.AddAccount()
+	require.NoError(t, err)
+
+	for _, accountNumber := range []uint64{1, 2} {
+		ak, err := am.GetAccountKey(accountNumber - 1)
+		require.NoError(t, err)
+		n, err := AccountNumberByPubKeyHash(am, ak.PubKeyHash.Sha256)
+		require.NoError(t, err)
+		require.Equal(t, accountNumber, n)
+	}
+
+	_, err = AccountNumberByPubKeyHash(am, []byte{1, 2, 3})
+	require.EqualError(t, err, "no account with public key hash 010203 in the wallet")
+}
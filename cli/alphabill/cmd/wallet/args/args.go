@@ -40,6 +40,13 @@ const (
 	proofOutputFlagName        = "proof-output"
 	MaxFeeFlagName             = "max-fee"
 	TargetPubkeyFlagName       = "target-pubkey"
+	LatestAdditionTimeCmdName  = "latest-addition-time"
+	VerifyCmdName              = "verify"
+	FromRoundCmdName           = "from"
+	ToRoundCmdName             = "to"
+	PreviewCmdName             = "preview"
+	PlanCmdName                = "plan"
+	AmountFormatCmdName        = "amount-format"
 )
 
 func BuildRpcUrl(url string) string {
@@ -11,7 +11,6 @@ import (
 	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/wallet/args"
 	"github.com/alphabill-org/alphabill-wallet/client"
 	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
-	"github.com/alphabill-org/alphabill-wallet/util"
 	"github.com/alphabill-org/alphabill-wallet/wallet"
 	"github.com/spf13/cobra"
 )
@@ -99,7 +98,7 @@ func execListCmd(cmd *cobra.Command, config *clitypes.BillsConfig) error {
 			config.WalletConfig.Base.ConsoleWriter.Println(fmt.Sprintf("Account #%d", group.accountIndex+1))
 		}
 		for j, bill := range group.bills {
-			billValueStr := util.AmountToString(bill.Value, 8)
+			billValueStr := config.WalletConfig.FormatAmount(bill.Value, 8)
 			config.WalletConfig.Base.ConsoleWriter.Println(fmt.Sprintf("#%d 0x%s %s%s", j+1, bill.ID.String(), billValueStr, getLockedReasonString(bill)))
 		}
 	}
@@ -13,7 +13,6 @@ import (
 	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/types"
 	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/util/account"
 	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/wallet/args"
-	"github.com/alphabill-org/alphabill-wallet/util"
 	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
 	evmclient "github.com/alphabill-org/alphabill-wallet/wallet/evm/client"
 )
@@ -207,7 +206,7 @@ func execEvmCmdDeploy(cmd *cobra.Command, config *types.EvmConfig) error {
 		}
 		return fmt.Errorf("deploy failed, %w", err)
 	}
-	printResult(config.WalletConfig.Base.ConsoleWriter, result)
+	printResult(config.WalletConfig, result)
 	return nil
 }
 
@@ -250,7 +249,7 @@ func execEvmCmdExecute(cmd *cobra.Command, config *types.EvmConfig) error {
 		}
 		return fmt.Errorf("excution failed, %w", err)
 	}
-	printResult(config.WalletConfig.Base.ConsoleWriter, result)
+	printResult(config.WalletConfig, result)
 	return nil
 }
 
@@ -298,7 +297,7 @@ func execEvmCmdCall(cmd *cobra.Command, config *types.EvmConfig) error {
 	if err != nil {
 		return fmt.Errorf("call failed, %w", err)
 	}
-	printResult(config.WalletConfig.Base.ConsoleWriter, result)
+	printResult(config.WalletConfig, result)
 	return nil
 }
 
@@ -317,20 +316,21 @@ func execEvmCmdBalance(cmd *cobra.Command, config *types.EvmConfig) error {
 		return fmt.Errorf("get balance failed, %w", err)
 	}
 	inAlpha := evmwallet.ConvertBalanceToAlpha(balance)
-	balanceStr := util.AmountToString(inAlpha, 8)
-	balanceEthStr := util.AmountToString(balance.Uint64(), 18)
+	balanceStr := config.WalletConfig.FormatAmount(inAlpha, 8)
+	balanceEthStr := config.WalletConfig.FormatAmount(balance.Uint64(), 18)
 	config.WalletConfig.Base.ConsoleWriter.Println(fmt.Sprintf("#%d %s (eth: %s)", accountNumber, balanceStr, balanceEthStr))
 	return nil
 }
 
-func printResult(consoleWriter types.ConsoleWrapper, result *evmclient.Result) {
+func printResult(config *types.WalletConfig, result *evmclient.Result) {
+	consoleWriter := config.Base.ConsoleWriter
 	if !result.Success {
 		consoleWriter.Println(fmt.Sprintf("Evm transaction failed: %s", result.Details.ErrorDetails))
-		consoleWriter.Println(fmt.Sprintf("Evm transaction processing fee: %v", util.AmountToString(result.ActualFee, 8)))
+		consoleWriter.Println(fmt.Sprintf("Evm transaction processing fee: %v", config.FormatAmount(result.ActualFee, 8)))
 		return
 	}
 	consoleWriter.Println("Evm transaction succeeded")
-	consoleWriter.Println(fmt.Sprintf("Evm transaction processing fee: %v", util.AmountToString(result.ActualFee, 8)))
+	consoleWriter.Println(fmt.Sprintf("Evm transaction processing fee: %v", config.FormatAmount(result.ActualFee, 8)))
 	noContract := common.Address{} // content if no contract is deployed
 	if result.Details.ContractAddr != noContract {
 		consoleWriter.Println(fmt.Sprintf("Deployed smart contract address: %x", result.Details.ContractAddr))
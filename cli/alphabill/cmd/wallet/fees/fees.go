@@ -1,6 +1,7 @@
 package fees
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -40,6 +41,10 @@ func NewFeesCmd(walletConfig *clitypes.WalletConfig) *cobra.Command {
 	cmd.AddCommand(reclaimFeeCreditCmd(config))
 	cmd.AddCommand(lockFeeCreditCmd(config))
 	cmd.AddCommand(unlockFeeCreditCmd(config))
+	cmd.AddCommand(abortPendingCmd(config))
+	cmd.AddCommand(pendingStatusCmd(config))
+	cmd.AddCommand(feeCreditRecordIDCmd(config))
+	cmd.AddCommand(feeSpendingCmd(config))
 
 	cmd.PersistentFlags().StringVarP(&config.moneyPartitionNodeUrl, args.RpcUrl, "r", args.DefaultMoneyRpcUrl, "money rpc node url")
 	cmd.PersistentFlags().VarP(&config.targetPartitionType, args.PartitionCmdName, "n", "partition name for which to manage fees [money|tokens|enterprise-tokens|evm]")
@@ -58,6 +63,7 @@ func addFeeCreditCmd(config *feesConfig) *cobra.Command {
 	}
 	cmd.Flags().Uint64P(args.KeyCmdName, "k", 1, "specifies to which account to add the fee credit")
 	cmd.Flags().StringP(args.AmountCmdName, "v", "1", "specifies how much fee credit to create in ALPHA")
+	cmd.Flags().Bool(args.PlanCmdName, false, "show the bills that would be used to add the fee credit without sending any transactions")
 	args.AddMaxFeeFlag(cmd, cmd.Flags())
 	return cmd
 }
@@ -79,6 +85,10 @@ func addFeeCreditCmdExec(cmd *cobra.Command, config *feesConfig) error {
 	if err != nil {
 		return err
 	}
+	plan, err := cmd.Flags().GetBool(args.PlanCmdName)
+	if err != nil {
+		return err
+	}
 
 	walletConfig := config.walletConfig
 	am, err := cliaccount.LoadExistingAccountManager(walletConfig)
@@ -98,6 +108,9 @@ func addFeeCreditCmdExec(cmd *cobra.Command, config *feesConfig) error {
 	}
 	defer fm.Close()
 
+	if plan {
+		return planAddFees(cmd.Context(), accountNumber, amountString, config, fm, walletConfig.Base.ConsoleWriter)
+	}
 	return addFees(cmd.Context(), accountNumber, amountString, config, fm, walletConfig.Base.ConsoleWriter)
 }
 
@@ -154,6 +167,7 @@ func reclaimFeeCreditCmd(config *feesConfig) *cobra.Command {
 		},
 	}
 	cmd.Flags().Uint64P(args.KeyCmdName, "k", 1, "specifies to which account to reclaim the fee credit")
+	cmd.Flags().Bool(args.PreviewCmdName, false, "show the target bill and the expected outcome of the reclaim without sending any transactions")
 	args.AddMaxFeeFlag(cmd, cmd.Flags())
 	return cmd
 }
@@ -170,6 +184,10 @@ func reclaimFeeCreditCmdExec(cmd *cobra.Command, config *feesConfig) error {
 	if err != nil {
 		return err
 	}
+	preview, err := cmd.Flags().GetBool(args.PreviewCmdName)
+	if err != nil {
+		return err
+	}
 
 	walletConfig := config.walletConfig
 	am, err := cliaccount.LoadExistingAccountManager(walletConfig)
@@ -190,6 +208,9 @@ func reclaimFeeCreditCmdExec(cmd *cobra.Command, config *feesConfig) error {
 	}
 	defer fm.Close()
 
+	if preview {
+		return previewReclaim(cmd.Context(), accountNumber, config, fm, walletConfig.Base.ConsoleWriter)
+	}
 	return reclaimFees(cmd.Context(), accountNumber, config, fm, walletConfig.Base.ConsoleWriter)
 }
 
@@ -308,12 +329,226 @@ func unlockFeeCreditCmdExec(cmd *cobra.Command, config *feesConfig) error {
 	return nil
 }
 
+func abortPendingCmd(config *feesConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "abort",
+		Short: "aborts pending add and reclaim fee credit processes of the account if it can be done without losing funds",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return abortPendingCmdExec(cmd, config)
+		},
+	}
+	cmd.Flags().Uint64P(args.KeyCmdName, "k", 0, "specifies which account pending fee credit processes to abort")
+	args.AddMaxFeeFlag(cmd, cmd.Flags())
+	_ = cmd.MarkFlagRequired(args.KeyCmdName)
+	return cmd
+}
+
+func abortPendingCmdExec(cmd *cobra.Command, config *feesConfig) error {
+	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
+	if err != nil {
+		return err
+	}
+	if accountNumber == 0 {
+		return errors.New("account number must be greater than zero")
+	}
+	maxFee, err := args.ParseMaxFeeFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	walletConfig := config.walletConfig
+	am, err := cliaccount.LoadExistingAccountManager(walletConfig)
+	if err != nil {
+		return fmt.Errorf("failed to load account manager: %w", err)
+	}
+	defer am.Close()
+
+	feeManagerDB, err := fees.NewFeeManagerDB(walletConfig.WalletHomeDir)
+	if err != nil {
+		return fmt.Errorf("failed to create fee manager db: %w", err)
+	}
+	defer feeManagerDB.Close()
+
+	fm, err := getFeeCreditManager(cmd.Context(), config, am, feeManagerDB, maxFee, walletConfig.Base.Logger)
+	if err != nil {
+		return err
+	}
+	defer fm.Close()
+
+	if err := fm.AbortPending(cmd.Context(), accountNumber-1); err != nil {
+		return fmt.Errorf("failed to abort pending fee credit processes: %w", err)
+	}
+	walletConfig.Base.ConsoleWriter.Println("No pending fee credit processes left.")
+	return nil
+}
+
+func pendingStatusCmd(config *feesConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "reports unfinished add and reclaim fee credit processes of the account",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return pendingStatusCmdExec(cmd, config)
+		},
+	}
+	cmd.Flags().Uint64P(args.KeyCmdName, "k", 1, "specifies which account pending fee credit processes to report")
+	return cmd
+}
+
+func pendingStatusCmdExec(cmd *cobra.Command, config *feesConfig) error {
+	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
+	if err != nil {
+		return err
+	}
+	if accountNumber == 0 {
+		return errors.New("account number must be greater than zero")
+	}
+
+	walletConfig := config.walletConfig
+	am, err := cliaccount.LoadExistingAccountManager(walletConfig)
+	if err != nil {
+		return fmt.Errorf("failed to load account manager: %w", err)
+	}
+	defer am.Close()
+
+	feeManagerDB, err := fees.NewFeeManagerDB(walletConfig.WalletHomeDir)
+	if err != nil {
+		return fmt.Errorf("failed to create fee manager db: %w", err)
+	}
+	defer feeManagerDB.Close()
+
+	fm, err := getFeeCreditManager(cmd.Context(), config, am, feeManagerDB, 0, walletConfig.Base.Logger)
+	if err != nil {
+		return err
+	}
+	defer fm.Close()
+
+	return pendingStatus(accountNumber, config, fm, walletConfig.Base.ConsoleWriter)
+}
+
+func feeCreditRecordIDCmd(config *feesConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "record-id",
+		Short: "generates fee credit record ID of the account (for troubleshooting)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return feeCreditRecordIDCmdExec(cmd, config)
+		},
+	}
+	cmd.Flags().Uint64P(args.KeyCmdName, "k", 1, "specifies for which account to generate the fee credit record ID")
+	cmd.Flags().Uint64(args.LatestAdditionTimeCmdName, 0, "latest addition time used to generate the ID (default: pending add fee process ID or current round + transferFC timeout)")
+	cmd.Flags().Bool(args.VerifyCmdName, false, "fetch the fee credit record of the account and compare it to the generated ID")
+	return cmd
+}
+
+func feeCreditRecordIDCmdExec(cmd *cobra.Command, config *feesConfig) error {
+	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
+	if err != nil {
+		return err
+	}
+	if accountNumber == 0 {
+		return errors.New("account number must be greater than zero")
+	}
+	latestAdditionTime, err := cmd.Flags().GetUint64(args.LatestAdditionTimeCmdName)
+	if err != nil {
+		return err
+	}
+	verify, err := cmd.Flags().GetBool(args.VerifyCmdName)
+	if err != nil {
+		return err
+	}
+
+	walletConfig := config.walletConfig
+	am, err := cliaccount.LoadExistingAccountManager(walletConfig)
+	if err != nil {
+		return fmt.Errorf("failed to load account manager: %w", err)
+	}
+	defer am.Close()
+
+	feeManagerDB, err := fees.NewFeeManagerDB(walletConfig.WalletHomeDir)
+	if err != nil {
+		return fmt.Errorf("failed to create fee manager db: %w", err)
+	}
+	defer feeManagerDB.Close()
+
+	fm, err := getFeeCreditManager(cmd.Context(), config, am, feeManagerDB, 0, walletConfig.Base.Logger)
+	if err != nil {
+		return err
+	}
+	defer fm.Close()
+
+	return feeCreditRecordID(cmd.Context(), accountNumber, latestAdditionTime, verify, config, fm, walletConfig.Base.ConsoleWriter)
+}
+
+func feeSpendingCmd(config *feesConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "spending",
+		Short: "reports fee credit spent by the account in the given round range",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return feeSpendingCmdExec(cmd, config)
+		},
+	}
+	cmd.Flags().Uint64P(args.KeyCmdName, "k", 1, "specifies for which account to report the fee spending")
+	cmd.Flags().Uint64(args.FromRoundCmdName, 0, "first round of the range")
+	cmd.Flags().Uint64(args.ToRoundCmdName, 0, "last round of the range")
+	if err := cmd.MarkFlagRequired(args.FromRoundCmdName); err != nil {
+		panic(err)
+	}
+	if err := cmd.MarkFlagRequired(args.ToRoundCmdName); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func feeSpendingCmdExec(cmd *cobra.Command, config *feesConfig) error {
+	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
+	if err != nil {
+		return err
+	}
+	if accountNumber == 0 {
+		return errors.New("account number must be greater than zero")
+	}
+	fromRound, err := cmd.Flags().GetUint64(args.FromRoundCmdName)
+	if err != nil {
+		return err
+	}
+	toRound, err := cmd.Flags().GetUint64(args.ToRoundCmdName)
+	if err != nil {
+		return err
+	}
+
+	walletConfig := config.walletConfig
+	am, err := cliaccount.LoadExistingAccountManager(walletConfig)
+	if err != nil {
+		return fmt.Errorf("failed to load account manager: %w", err)
+	}
+	defer am.Close()
+
+	feeManagerDB, err := fees.NewFeeManagerDB(walletConfig.WalletHomeDir)
+	if err != nil {
+		return fmt.Errorf("failed to create fee manager db: %w", err)
+	}
+	defer feeManagerDB.Close()
+
+	fm, err := getFeeCreditManager(cmd.Context(), config, am, feeManagerDB, 0, walletConfig.Base.Logger)
+	if err != nil {
+		return err
+	}
+	defer fm.Close()
+
+	return feeSpending(cmd.Context(), accountNumber, fromRound, toRound, config, fm, walletConfig.Base.ConsoleWriter)
+}
+
 type FeeCreditManager interface {
 	GetFeeCredit(ctx context.Context, cmd fees.GetFeeCreditCmd) (*types.FeeCreditRecord, error)
+	GetFeeCreditRecordID(ctx context.Context, cmd fees.GetFeeCreditRecordIDCmd) (basetypes.UnitID, error)
+	GetFeeSpending(ctx context.Context, accountIndex, fromRound, toRound uint64) (uint64, error)
+	PreviewReclaim(ctx context.Context, accountIndex uint64) (*fees.ReclaimPreview, error)
+	PlanAddFeeCredit(ctx context.Context, cmd fees.AddFeeCmd) (*fees.AddFeePlan, error)
 	AddFeeCredit(ctx context.Context, cmd fees.AddFeeCmd) (*fees.AddFeeCmdResponse, error)
 	ReclaimFeeCredit(ctx context.Context, cmd fees.ReclaimFeeCmd) (*fees.ReclaimFeeCmdResponse, error)
 	LockFeeCredit(ctx context.Context, cmd fees.LockFeeCreditCmd) (*basetypes.TxRecordProof, error)
 	UnlockFeeCredit(ctx context.Context, cmd fees.UnlockFeeCreditCmd) (*basetypes.TxRecordProof, error)
+	AbortPending(ctx context.Context, accountIndex uint64) error
+	PendingProcesses(accountIndex uint64) (*fees.AddFeeCreditCtx, *fees.ReclaimFeeCreditCtx, error)
 	MinAddFeeAmount() uint64
 	MinReclaimFeeAmount() uint64
 	Close()
@@ -327,7 +562,7 @@ func listFees(ctx context.Context, accountNumber uint64, listFcrIds bool, am acc
 			return err
 		}
 		for accountIndex := range pubKeys {
-			accountInfo, err := getAccountInfo(uint64(accountIndex), listFcrIds, ctx, w)
+			accountInfo, err := getAccountInfo(uint64(accountIndex), listFcrIds, ctx, w, c.walletConfig.AmountFormat)
 			if err != nil {
 				return err
 			}
@@ -336,7 +571,7 @@ func listFees(ctx context.Context, accountNumber uint64, listFcrIds bool, am acc
 		return nil
 	}
 	accountIndex := accountNumber - 1
-	accountInfo, err := getAccountInfo(accountIndex, listFcrIds, ctx, w)
+	accountInfo, err := getAccountInfo(accountIndex, listFcrIds, ctx, w, c.walletConfig.AmountFormat)
 	if err != nil {
 		return err
 	}
@@ -356,32 +591,94 @@ func addFees(ctx context.Context, accountNumber uint64, amountString string, c *
 	})
 	if err != nil {
 		if errors.Is(err, fees.ErrMinimumFeeAmount) {
-			return fmt.Errorf("minimum fee credit amount to add is %s", util.AmountToString(w.MinAddFeeAmount(), 8))
+			return fmt.Errorf("minimum fee credit amount to add is %s", c.walletConfig.FormatAmount(w.MinAddFeeAmount(), 8))
 		}
 		if errors.Is(err, fees.ErrInsufficientBalance) {
-			return fmt.Errorf("insufficient balance for transaction. Bills smaller than the minimum amount (%s) are not counted", util.AmountToString(w.MinAddFeeAmount(), 8))
+			return fmt.Errorf("insufficient balance for transaction. Bills smaller than the minimum amount (%s) are not counted", c.walletConfig.FormatAmount(w.MinAddFeeAmount(), 8))
 		}
 		if errors.Is(err, fees.ErrInvalidPartition) {
 			return fmt.Errorf("pending fee process exists for another partition, run the command for the correct partition: %w", err)
 		}
 		return err
 	}
-	var feeSum uint64
-	for _, proof := range rsp.Proofs {
-		feeSum += proof.GetFees()
-	}
 	consoleWriter.Println("Successfully created", amountString, "fee credits on", c.targetPartitionType, "partition.")
-	consoleWriter.Println("Paid", util.AmountToString(feeSum, 8), "ALPHA fee for transactions.")
+	consoleWriter.Println("Paid", c.walletConfig.FormatAmount(rsp.TotalFees(), 8), "ALPHA fee for transactions.")
+	return nil
+}
+
+func planAddFees(ctx context.Context, accountNumber uint64, amountString string, c *feesConfig, w FeeCreditManager, consoleWriter clitypes.ConsoleWrapper) error {
+	amount, err := util.StringToAmount(amountString, 8)
+	if err != nil {
+		return err
+	}
+	plan, err := w.PlanAddFeeCredit(ctx, fees.AddFeeCmd{
+		Amount:         amount,
+		AccountIndex:   accountNumber - 1,
+		DisableLocking: c.targetPartitionType == clitypes.EvmType,
+	})
+	if err != nil {
+		if errors.Is(err, fees.ErrMinimumFeeAmount) {
+			return fmt.Errorf("minimum fee credit amount to add is %s", c.walletConfig.FormatAmount(w.MinAddFeeAmount(), 8))
+		}
+		if errors.Is(err, fees.ErrInsufficientBalance) {
+			return fmt.Errorf("insufficient balance for transaction. Bills smaller than the minimum amount (%s) are not counted", c.walletConfig.FormatAmount(w.MinAddFeeAmount(), 8))
+		}
+		if errors.Is(err, fees.ErrInvalidPartition) {
+			return fmt.Errorf("pending fee process exists for another partition, run the command for the correct partition: %w", err)
+		}
+		return err
+	}
+	consoleWriter.Println(fmt.Sprintf("Add fee credit plan for account #%d on %s partition:", accountNumber, c.targetPartitionType))
+	for i, b := range plan.Bills {
+		consoleWriter.Println(fmt.Sprintf("#%d bill: 0x%s value %s, amount %s", i+1, b.Bill.ID, c.walletConfig.FormatAmount(b.Bill.Value, 8), c.walletConfig.FormatAmount(b.Amount, 8)))
+	}
+	consoleWriter.Println(fmt.Sprintf("Fee credit to add: %s", c.walletConfig.FormatAmount(plan.Amount, 8)))
+	consoleWriter.Println(fmt.Sprintf("Max fees: %s", c.walletConfig.FormatAmount(plan.Fees, 8)))
 	return nil
 }
 
+func pendingStatus(accountNumber uint64, c *feesConfig, w FeeCreditManager, consoleWriter clitypes.ConsoleWrapper) error {
+	addFeeCtx, reclaimFeeCtx, err := w.PendingProcesses(accountNumber - 1)
+	if err != nil {
+		return fmt.Errorf("failed to load pending fee credit processes: %w", err)
+	}
+	if addFeeCtx == nil && reclaimFeeCtx == nil {
+		consoleWriter.Println(fmt.Sprintf("Account #%d has no pending fee credit processes.", accountNumber))
+		return nil
+	}
+	if addFeeCtx != nil {
+		consoleWriter.Println(formatPendingProcess(accountNumber, addFeeCtx.Summary(), c))
+	}
+	if reclaimFeeCtx != nil {
+		consoleWriter.Println(formatPendingProcess(accountNumber, reclaimFeeCtx.Summary(), c))
+	}
+	consoleWriter.Println("Run the add or reclaim command to complete the process or the abort command to abort it.")
+	return nil
+}
+
+func formatPendingProcess(accountNumber uint64, p *fees.PendingProcess, c *feesConfig) string {
+	msg := fmt.Sprintf("Account #%d has an unfinished %s", accountNumber, p.Kind)
+	if p.Amount > 0 {
+		msg += fmt.Sprintf(" of %s ALPHA", c.walletConfig.FormatAmount(p.Amount, 8))
+	}
+	if p.Kind == fees.PendingAdd {
+		msg += fmt.Sprintf(" to partition %s from bill 0x%s", p.TargetPartitionID, p.TargetBillID)
+	} else {
+		msg += fmt.Sprintf(" from partition %s to bill 0x%s", p.TargetPartitionID, p.TargetBillID)
+	}
+	if p.LastTx != "" {
+		msg += fmt.Sprintf(", last sent transaction: %s", p.LastTx)
+	}
+	return msg + "."
+}
+
 func reclaimFees(ctx context.Context, accountNumber uint64, c *feesConfig, w FeeCreditManager, consoleWriter clitypes.ConsoleWrapper) error {
 	rsp, err := w.ReclaimFeeCredit(ctx, fees.ReclaimFeeCmd{
 		AccountIndex: accountNumber - 1,
 	})
 	if err != nil {
 		if errors.Is(err, fees.ErrMinimumFeeAmount) {
-			return fmt.Errorf("insufficient fee credit balance. Minimum amount is %s", util.AmountToString(w.MinReclaimFeeAmount(), 8))
+			return fmt.Errorf("insufficient fee credit balance. Minimum amount is %s", c.walletConfig.FormatAmount(w.MinReclaimFeeAmount(), 8))
 		}
 		if errors.Is(err, fees.ErrInvalidPartition) {
 			return fmt.Errorf("wallet contains locked bill for different partition, run the command for the correct partition: %w", err)
@@ -389,7 +686,65 @@ func reclaimFees(ctx context.Context, accountNumber uint64, c *feesConfig, w Fee
 		return err
 	}
 	consoleWriter.Println("Successfully reclaimed fee credits on", c.targetPartitionType, "partition.")
-	consoleWriter.Println("Paid", util.AmountToString(rsp.Proofs.GetFees(), 8), "ALPHA fee for transactions.")
+	consoleWriter.Println("Paid", c.walletConfig.FormatAmount(rsp.Proofs.GetFees(), 8), "ALPHA fee for transactions.")
+	return nil
+}
+
+func previewReclaim(ctx context.Context, accountNumber uint64, c *feesConfig, w FeeCreditManager, consoleWriter clitypes.ConsoleWrapper) error {
+	preview, err := w.PreviewReclaim(ctx, accountNumber-1)
+	if err != nil {
+		if errors.Is(err, fees.ErrMinimumFeeAmount) {
+			return fmt.Errorf("insufficient fee credit balance. Minimum amount is %s", c.walletConfig.FormatAmount(w.MinReclaimFeeAmount(), 8))
+		}
+		if errors.Is(err, fees.ErrInvalidPartition) {
+			return fmt.Errorf("wallet contains locked bill for different partition, run the command for the correct partition: %w", err)
+		}
+		return err
+	}
+	consoleWriter.Println(fmt.Sprintf("Reclaim preview for account #%d on %s partition:", accountNumber, c.targetPartitionType))
+	consoleWriter.Println(fmt.Sprintf("Target bill: 0x%s value %s", preview.TargetBillID, c.walletConfig.FormatAmount(preview.TargetBillValue, 8)))
+	consoleWriter.Println(fmt.Sprintf("Fee credit to reclaim: %s", c.walletConfig.FormatAmount(preview.FeeCreditBalance, 8)))
+	consoleWriter.Println(fmt.Sprintf("Max fees: %s", c.walletConfig.FormatAmount(preview.ExpectedFees, 8)))
+	consoleWriter.Println(fmt.Sprintf("Target bill value after reclaim: at least %s", c.walletConfig.FormatAmount(preview.ProjectedBillValue, 8)))
+	return nil
+}
+
+func feeCreditRecordID(ctx context.Context, accountNumber, latestAdditionTime uint64, verify bool, c *feesConfig, w FeeCreditManager, consoleWriter clitypes.ConsoleWrapper) error {
+	accountIndex := accountNumber - 1
+	fcrID, err := w.GetFeeCreditRecordID(ctx, fees.GetFeeCreditRecordIDCmd{
+		AccountIndex:       accountIndex,
+		LatestAdditionTime: latestAdditionTime,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to generate fee credit record id: %w", err)
+	}
+	consoleWriter.Println(fmt.Sprintf("Account #%d fee credit record ID on %s partition: 0x%s", accountNumber, c.targetPartitionType, fcrID))
+	if !verify {
+		return nil
+	}
+
+	fcr, err := w.GetFeeCredit(ctx, fees.GetFeeCreditCmd{AccountIndex: accountIndex})
+	if err != nil {
+		return fmt.Errorf("failed to fetch fee credit record: %w", err)
+	}
+	if fcr == nil {
+		consoleWriter.Println("Fee credit record not found.")
+		return nil
+	}
+	if bytes.Equal(fcr.ID, fcrID) {
+		consoleWriter.Println(fmt.Sprintf("Fee credit record found: balance %s%s", c.walletConfig.FormatAmount(fcr.Balance, 8), getLockedReasonString(fcr)))
+	} else {
+		consoleWriter.Println(fmt.Sprintf("Fee credit record with different ID found: 0x%s balance %s%s", fcr.ID, c.walletConfig.FormatAmount(fcr.Balance, 8), getLockedReasonString(fcr)))
+	}
+	return nil
+}
+
+func feeSpending(ctx context.Context, accountNumber, fromRound, toRound uint64, c *feesConfig, w FeeCreditManager, consoleWriter clitypes.ConsoleWrapper) error {
+	spending, err := w.GetFeeSpending(ctx, accountNumber-1, fromRound, toRound)
+	if err != nil {
+		return fmt.Errorf("failed to calculate fee spending: %w", err)
+	}
+	consoleWriter.Println(fmt.Sprintf("Account #%d spent %s fee credit on %s partition in rounds %d-%d.", accountNumber, c.walletConfig.FormatAmount(spending, 8), c.targetPartitionType, fromRound, toRound))
 	return nil
 }
 
@@ -454,6 +809,7 @@ func getFeeCreditManager(ctx context.Context, c *feesConfig, am account.Manager,
 			fcrGenerator,
 			maxFee,
 			logger,
+			fees.WithTargetPartitionFcrUnitType(pdr, money.FeeCreditRecordUnitType),
 		), nil
 	case clitypes.TokensType:
 		tokensClient, err := client.NewTokensPartitionClient(ctx, c.getTargetPartitionRpcUrl())
@@ -491,6 +847,7 @@ func getFeeCreditManager(ctx context.Context, c *feesConfig, am account.Manager,
 			},
 			maxFee,
 			logger,
+			fees.WithTargetPartitionFcrUnitType(tokenPDR, tokens.FeeCreditRecordUnitType),
 		), nil
 	case clitypes.EnterpriseTokensType:
 		tokensRpcUrl := c.getTargetPartitionRpcUrl()
@@ -516,6 +873,7 @@ func getFeeCreditManager(ctx context.Context, c *feesConfig, am account.Manager,
 			},
 			maxFee,
 			logger,
+			fees.WithTargetPartitionFcrUnitType(pdr, tokens.FeeCreditRecordUnitType),
 		), nil
 	case clitypes.EvmType:
 		moneyClient, err := client.NewMoneyPartitionClient(ctx, c.getMoneyRpcUrl())
@@ -558,7 +916,7 @@ func getFeeCreditManager(ctx context.Context, c *feesConfig, am account.Manager,
 	}
 }
 
-func getAccountInfo(accountIndex uint64, showFcrId bool, ctx context.Context, w FeeCreditManager) (*AccountInfoWrapper, error) {
+func getAccountInfo(accountIndex uint64, showFcrId bool, ctx context.Context, w FeeCreditManager, amountFormat util.AmountFormat) (*AccountInfoWrapper, error) {
 	fcr, err := w.GetFeeCredit(ctx, fees.GetFeeCreditCmd{AccountIndex: accountIndex})
 	if err != nil {
 		return nil, err
@@ -576,6 +934,7 @@ func getAccountInfo(accountIndex uint64, showFcrId bool, ctx context.Context, w
 		FcrId:         fcrId,
 		Balance:       balance,
 		LockedReason:  getLockedReasonString(fcr),
+		AmountFormat:  amountFormat,
 	}, nil
 }
 
@@ -591,10 +950,11 @@ type AccountInfoWrapper struct {
 	FcrId         basetypes.UnitID
 	Balance       uint64
 	LockedReason  string
+	AmountFormat  util.AmountFormat
 }
 
 func (a AccountInfoWrapper) String() string {
-	accountAmount := util.AmountToString(a.Balance, 8)
+	accountAmount := util.FormatAmount(a.Balance, 8, a.AmountFormat)
 	if a.FcrId == nil {
 		return fmt.Sprintf("Account #%d %s%s", a.AccountNumber, accountAmount, a.LockedReason)
 	} else {
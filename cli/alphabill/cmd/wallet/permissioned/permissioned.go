@@ -2,6 +2,7 @@ package permissioned
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/alphabill-org/alphabill-go-base/hash"
@@ -272,6 +273,13 @@ func listFeeCreditCmdExec(cmd *cobra.Command, config *listCreditConfig) error {
 	}
 	defer tokensClient.Close()
 
+	caps, err := tokensClient.NodeCapabilities(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("failed to query node capabilities: %w", err)
+	}
+	if !caps.UnitListing {
+		return errors.New("node does not support listing units, state_getUnits must be enabled on the node")
+	}
 	unitIDs, err := tokensClient.GetUnits(cmd.Context(), &config.unitTypeID)
 	if err != nil {
 		return fmt.Errorf("failed to fetch units: %w", err)
@@ -1,13 +1,25 @@
 package tokens
 
 import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"mime"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
 	basetypes "github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
 	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/types"
 	cliaccount "github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/util/account"
 	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/wallet/args"
@@ -43,21 +55,55 @@ const (
 	cmdFlagTokenURI                          = "token-uri"
 	cmdFlagTokenData                         = "data"
 	cmdFlagTokenDataFile                     = "data-file"
+	cmdFlagChangeBearerClause                = "change-bearer-clause"
+	cmdFlagFailOnLocked                      = "fail-on-locked"
+	cmdFlagTransfersFile                     = "transfers-file"
+	cmdFlagAddressFile                       = "address-file"
+	cmdFlagOutput                            = "output"
+	cmdFlagDryRun                            = "dry-run"
+	cmdFlagTimeoutRounds                     = "timeout-rounds"
+	cmdFlagProofMetadata                     = "proof-metadata"
+	cmdFlagProofFormat                       = "proof-format"
+	cmdFlagMaxBatch                          = "max-batch"
+	cmdFlagConfirmTimeout                    = "confirm-timeout"
+	cmdFlagSince                             = "since"
+	cmdFlagInterval                          = "interval"
+	cmdFlagAddressHash                       = "address-hash"
+	cmdFlagFile                              = "file"
+	cmdFlagTrustBaseFile                     = "trust-base-file"
+	cmdFlagKeyHash                           = "key-hash"
+	cmdFlagExpectedCounter                   = "expected-counter"
+
+	proofFormatCBOR = "cbor"
+	proofFormatJSON = "json"
 
 	cmdFlagWithAll       = "with-all"
 	cmdFlagWithTypeName  = "with-type-name"
 	cmdFlagWithTokenURI  = "with-token-uri"
 	cmdFlagWithTokenData = "with-token-data"
 
+	cmdFlagValidateTypes = "validate-types"
+	cmdFlagNamePrefix    = "name-prefix"
+	cmdFlagAll           = "all"
+
+	// values of the output format flag
+	outputText = "text"
+	outputJSON = "json"
+
+	cmdFlagResolveMetadata = "resolve-metadata"
+	cmdFlagIPFSGateway     = "ipfs-gateway"
+
 	predicateTrue  = "true"
 	predicatePtpkh = "ptpkh"
 
 	iconFileExtSvgz     = ".svgz"
 	iconFileExtSvgzType = "image/svg+xml; encoding=gzip"
 
-	maxBinaryFile64KiB = 64 * 1024
-	maxDecimalPlaces   = 8
-	allAccounts        = 0
+	maxBinaryFile64KiB   = 64 * 1024
+	maxTransfersFile1MiB = 1024 * 1024
+	fileReadChunkSize    = 4 * 1024
+	maxDecimalPlaces     = 8
+	allAccounts          = 0
 
 	helpPredicateValues = `Valid values are either one of the predicate template name [ true | false | ptpkh | ptpkh:n | ptpkh:0x<hex-string> ] ` +
 		`or @<filename> to load predicate from given file.`
@@ -89,14 +135,28 @@ func NewTokenCmd(config *types.WalletConfig) *cobra.Command {
 	cmd.AddCommand(tokenCmdNewToken(config))
 	cmd.AddCommand(tokenCmdUpdateNFTData(config))
 	cmd.AddCommand(tokenCmdSend(config))
+	cmd.AddCommand(tokenCmdBurn(config))
 	cmd.AddCommand(tokenCmdDC(config, execTokenCmdDC))
 	cmd.AddCommand(tokenCmdList(config, execTokenCmdList))
+	cmd.AddCommand(tokenCmdCount(config))
 	cmd.AddCommand(tokenCmdListTypes(config, execTokenCmdListTypes))
+	cmd.AddCommand(tokenCmdShow(config))
+	cmd.AddCommand(tokenCmdDescribeType(config))
+	cmd.AddCommand(tokenCmdIcon(config))
 	cmd.AddCommand(tokenCmdLock(config))
 	cmd.AddCommand(tokenCmdUnlock(config))
+	cmd.AddCommand(tokenCmdResume(config))
+	cmd.AddCommand(tokenCmdExportProofs(config))
+	cmd.AddCommand(tokenCmdVerifyProof(config))
+	cmd.AddCommand(tokenCmdWatch(config))
 	cmd.PersistentFlags().StringP(args.RpcUrl, "r", args.DefaultTokensRpcUrl, "rpc node url")
 	args.AddWaitForProofFlags(cmd, cmd.PersistentFlags())
 	args.AddMaxFeeFlag(cmd, cmd.PersistentFlags())
+	cmd.PersistentFlags().Bool(cmdFlagDryRun, false, "build and sign the transaction(s) without sending them, prints the max fee of the transaction(s)")
+	cmd.PersistentFlags().Uint64(cmdFlagTimeoutRounds, 0, "number of rounds the transaction(s) are valid for (default 10)")
+	cmd.PersistentFlags().Duration(cmdFlagConfirmTimeout, 0, "maximum time to wait for the transaction(s) to be confirmed, ie 30s or 2m (default waits until the transaction(s) time out)")
+	cmd.PersistentFlags().Bool(cmdFlagProofMetadata, false, "save the transaction proof(s) in an envelope with the metadata of the operation (time, command, account, type and amount)")
+	cmd.PersistentFlags().String(cmdFlagProofFormat, proofFormatCBOR, "format of the saved transaction proof(s), one of: cbor, json")
 	return cmd
 }
 
@@ -112,9 +172,24 @@ func tokenCmdNewType(config *types.WalletConfig) *cobra.Command {
 
 func addCommonAccountFlags(cmd *cobra.Command) *cobra.Command {
 	cmd.Flags().Uint64P(args.KeyCmdName, "k", 1, "which key to use for sending the transaction")
+	setHexFlag(cmd, cmdFlagKeyHash, nil, "SHA256 hash of the public key of the account to use for sending the transaction in hexadecimal format, alternative to --key")
+	cmd.MarkFlagsMutuallyExclusive(args.KeyCmdName, cmdFlagKeyHash)
 	return cmd
 }
 
+// accountNumberArg returns the account number given with the "key" flag or, when the "key-hash"
+// flag is set, the number of the account whose public key hash is given.
+func accountNumberArg(cmd *cobra.Command, am account.Manager) (uint64, error) {
+	if !cmd.Flags().Changed(cmdFlagKeyHash) {
+		return cmd.Flags().GetUint64(args.KeyCmdName)
+	}
+	pubKeyHash, err := getHexFlag(cmd, cmdFlagKeyHash)
+	if err != nil {
+		return 0, err
+	}
+	return cliaccount.AccountNumberByPubKeyHash(am, pubKeyHash)
+}
+
 func addDataFlags(cmd *cobra.Command) {
 	altMsg := ". Alternatively flag %q can be used to add data."
 	setHexFlag(cmd, cmdFlagTokenData, nil, "custom data (hex)"+fmt.Sprintf(altMsg, cmdFlagTokenDataFile))
@@ -154,10 +229,6 @@ func tokenCmdNewTypeFungible(config *types.WalletConfig) *cobra.Command {
 }
 
 func execTokenCmdNewTypeFungible(cmd *cobra.Command, config *types.WalletConfig) error {
-	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
-	if err != nil {
-		return err
-	}
 	typeID, err := getHexFlag(cmd, cmdFlagType)
 	if err != nil {
 		return err
@@ -190,6 +261,10 @@ func execTokenCmdNewTypeFungible(cmd *cobra.Command, config *types.WalletConfig)
 		return err
 	}
 	defer tw.Close()
+	accountNumber, err := accountNumberArg(cmd, tw.GetAccountManager())
+	if err != nil {
+		return err
+	}
 	am := tw.GetAccountManager()
 	parentType, creationInputs, err := readParentTypeInfo(cmd, accountNumber, am)
 	if err != nil {
@@ -225,10 +300,12 @@ func execTokenCmdNewTypeFungible(cmd *cobra.Command, config *types.WalletConfig)
 		return err
 	}
 	config.Base.ConsoleWriter.Println(fmt.Sprintf("Sent request for new fungible token type with id=%s", result.GetUnit()))
+	printTxIDs(config, result)
 	if result.FeeSum > 0 {
-		config.Base.ConsoleWriter.Println(fmt.Sprintf("Paid %s fees for transaction(s).", util.AmountToString(result.FeeSum, 8)))
+		config.Base.ConsoleWriter.Println(fmt.Sprintf("Paid %s fees for transaction(s).", config.FormatAmount(result.FeeSum, 8)))
 	}
-	if err := saveTxProofs(cmd, result.GetProofs(), config.Base.ConsoleWriter); err != nil {
+	printMaxFeeWarning(config, tw)
+	if err := saveTxProofs(cmd, accountNumber, result.GetProofs(), config.Base.ConsoleWriter); err != nil {
 		return fmt.Errorf("saving transaction proof(s): %w", err)
 	}
 	return nil
@@ -249,10 +326,6 @@ func tokenCmdNewTypeNonFungible(config *types.WalletConfig) *cobra.Command {
 }
 
 func execTokenCmdNewTypeNonFungible(cmd *cobra.Command, config *types.WalletConfig) error {
-	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
-	if err != nil {
-		return err
-	}
 	typeID, err := getHexFlag(cmd, cmdFlagType)
 	if err != nil {
 		return err
@@ -278,6 +351,10 @@ func execTokenCmdNewTypeNonFungible(cmd *cobra.Command, config *types.WalletConf
 		return err
 	}
 	defer tw.Close()
+	accountNumber, err := accountNumberArg(cmd, tw.GetAccountManager())
+	if err != nil {
+		return err
+	}
 	am := tw.GetAccountManager()
 	parentType, creationInputs, err := readParentTypeInfo(cmd, accountNumber, am)
 	if err != nil {
@@ -317,10 +394,12 @@ func execTokenCmdNewTypeNonFungible(cmd *cobra.Command, config *types.WalletConf
 		return err
 	}
 	config.Base.ConsoleWriter.Println(fmt.Sprintf("Sent request for new NFT type with id=%s", result.GetUnit()))
+	printTxIDs(config, result)
 	if result.FeeSum > 0 {
-		config.Base.ConsoleWriter.Println(fmt.Sprintf("Paid %s fees for transaction(s).", util.AmountToString(result.FeeSum, 8)))
+		config.Base.ConsoleWriter.Println(fmt.Sprintf("Paid %s fees for transaction(s).", config.FormatAmount(result.FeeSum, 8)))
 	}
-	if err := saveTxProofs(cmd, result.GetProofs(), config.Base.ConsoleWriter); err != nil {
+	printMaxFeeWarning(config, tw)
+	if err := saveTxProofs(cmd, accountNumber, result.GetProofs(), config.Base.ConsoleWriter); err != nil {
 		return fmt.Errorf("saving transaction proof(s): %w", err)
 	}
 	return nil
@@ -360,16 +439,16 @@ func tokenCmdNewTokenFungible(config *types.WalletConfig) *cobra.Command {
 }
 
 func execTokenCmdNewTokenFungible(cmd *cobra.Command, config *types.WalletConfig) error {
-	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
-	if err != nil {
-		return err
-	}
 	tw, err := initTokensWallet(cmd, config)
 	if err != nil {
 		return err
 	}
 	am := tw.GetAccountManager()
 	defer tw.Close()
+	accountNumber, err := accountNumberArg(cmd, am)
+	if err != nil {
+		return err
+	}
 
 	amountStr, err := cmd.Flags().GetString(cmdFlagAmount)
 	if err != nil {
@@ -388,7 +467,7 @@ func execTokenCmdNewTokenFungible(cmd *cobra.Command, config *types.WalletConfig
 		return err
 	}
 	// convert amount from string to uint64
-	amount, err := util.StringToAmount(amountStr, tt.DecimalPlaces)
+	amount, err := parseTokenAmount(amountStr, tt.DecimalPlaces)
 	if err != nil {
 		return err
 	}
@@ -413,10 +492,12 @@ func execTokenCmdNewTokenFungible(cmd *cobra.Command, config *types.WalletConfig
 	}
 
 	config.Base.ConsoleWriter.Println(fmt.Sprintf("Sent request for new fungible token with id=%s", result.GetUnit()))
+	printTxIDs(config, result)
 	if result.FeeSum > 0 {
-		config.Base.ConsoleWriter.Println(fmt.Sprintf("Paid %s fees for transaction(s).", util.AmountToString(result.FeeSum, 8)))
+		config.Base.ConsoleWriter.Println(fmt.Sprintf("Paid %s fees for transaction(s).", config.FormatAmount(result.FeeSum, 8)))
 	}
-	if err := saveTxProofs(cmd, result.GetProofs(), config.Base.ConsoleWriter); err != nil {
+	printMaxFeeWarning(config, tw)
+	if err := saveTxProofs(cmd, accountNumber, result.GetProofs(), config.Base.ConsoleWriter); err != nil {
 		return fmt.Errorf("saving transaction proof(s): %w", err)
 	}
 	return nil
@@ -445,10 +526,6 @@ func tokenCmdNewTokenNonFungible(config *types.WalletConfig) *cobra.Command {
 }
 
 func execTokenCmdNewTokenNonFungible(cmd *cobra.Command, config *types.WalletConfig) error {
-	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
-	if err != nil {
-		return err
-	}
 	typeID, err := getHexFlag(cmd, cmdFlagType)
 	if err != nil {
 		return err
@@ -461,7 +538,7 @@ func execTokenCmdNewTokenNonFungible(cmd *cobra.Command, config *types.WalletCon
 	if err != nil {
 		return err
 	}
-	data, err := readNFTData(cmd, false)
+	data, err := readNFTData(cmd, false, config.Base.Logger)
 	if err != nil {
 		return err
 	}
@@ -470,6 +547,10 @@ func execTokenCmdNewTokenNonFungible(cmd *cobra.Command, config *types.WalletCon
 		return err
 	}
 	defer tw.Close()
+	accountNumber, err := accountNumberArg(cmd, tw.GetAccountManager())
+	if err != nil {
+		return err
+	}
 	am := tw.GetAccountManager()
 	mintPredicateInput, err := readSinglePredicateInput(cmd, cmdFlagMintClauseInput, accountNumber, am)
 	if err != nil {
@@ -507,10 +588,12 @@ func execTokenCmdNewTokenNonFungible(cmd *cobra.Command, config *types.WalletCon
 		return err
 	}
 	config.Base.ConsoleWriter.Println(fmt.Sprintf("Sent request for new non-fungible token with id=%s", result.GetUnit()))
+	printTxIDs(config, result)
 	if result.FeeSum > 0 {
-		config.Base.ConsoleWriter.Println(fmt.Sprintf("Paid %s fees for transaction(s).", util.AmountToString(result.FeeSum, 8)))
+		config.Base.ConsoleWriter.Println(fmt.Sprintf("Paid %s fees for transaction(s).", config.FormatAmount(result.FeeSum, 8)))
 	}
-	if err := saveTxProofs(cmd, result.GetProofs(), config.Base.ConsoleWriter); err != nil {
+	printMaxFeeWarning(config, tw)
+	if err := saveTxProofs(cmd, accountNumber, result.GetProofs(), config.Base.ConsoleWriter); err != nil {
 		return fmt.Errorf("saving transaction proof(s): %w", err)
 	}
 	return nil
@@ -526,6 +609,72 @@ func tokenCmdSend(config *types.WalletConfig) *cobra.Command {
 	return cmd
 }
 
+func tokenCmdBurn(config *types.WalletConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "burn",
+		Short: "burn a token",
+	}
+	cmd.AddCommand(tokenCmdBurnFungible(config))
+	return cmd
+}
+
+func tokenCmdBurnFungible(config *types.WalletConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fungible",
+		Short: "burns fungible token, the value of the token is permanently removed from circulation",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execTokenCmdBurnFungible(cmd, config)
+		},
+	}
+	setHexFlag(cmd, cmdFlagTokenID, nil, "token identifier")
+	if err := cmd.MarkFlagRequired(cmdFlagTokenID); err != nil {
+		panic(err)
+	}
+	cmd.Flags().StringSlice(cmdFlagInheritBearerClauseInput, []string{predicateTrue}, "input to satisfy the owner predicates inherited from types. "+helpPredicateArgument)
+	cmd.Flags().String(cmdFlagBearerClauseInput, predicatePtpkh, "input to satisfy the bearer clause. "+helpPredicateArgument)
+	return addCommonAccountFlags(cmd)
+}
+
+func execTokenCmdBurnFungible(cmd *cobra.Command, config *types.WalletConfig) error {
+	tokenID, err := getHexFlag(cmd, cmdFlagTokenID)
+	if err != nil {
+		return err
+	}
+
+	tw, err := initTokensWallet(cmd, config)
+	if err != nil {
+		return err
+	}
+	defer tw.Close()
+	accountNumber, err := accountNumberArg(cmd, tw.GetAccountManager())
+	if err != nil {
+		return err
+	}
+
+	ib, err := readPredicateInputs(cmd, cmdFlagInheritBearerClauseInput, accountNumber, tw.GetAccountManager())
+	if err != nil {
+		return err
+	}
+	ownerPredicateInput, err := readSinglePredicateInput(cmd, cmdFlagBearerClauseInput, accountNumber, tw.GetAccountManager())
+	if err != nil {
+		return err
+	}
+
+	result, err := tw.BurnFungibleToken(cmd.Context(), accountNumber, tokenID, ownerPredicateInput, ib)
+	if err != nil {
+		return ownerProofHint(err)
+	}
+	printTxIDs(config, result)
+	if result.FeeSum > 0 {
+		config.Base.ConsoleWriter.Println(fmt.Sprintf("Paid %s fees for transaction(s).", config.FormatAmount(result.FeeSum, 8)))
+	}
+	printMaxFeeWarning(config, tw)
+	if err := saveTxProofs(cmd, accountNumber, result.GetProofs(), config.Base.ConsoleWriter); err != nil {
+		return fmt.Errorf("saving transaction proof(s): %w", err)
+	}
+	return nil
+}
+
 func tokenCmdSendFungible(config *types.WalletConfig) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "fungible",
@@ -536,6 +685,8 @@ func tokenCmdSendFungible(config *types.WalletConfig) *cobra.Command {
 	}
 	cmd.Flags().StringSlice(cmdFlagInheritBearerClauseInput, []string{predicateTrue}, "input to satisfy the owner predicates inherited from types. "+helpPredicateArgument)
 	cmd.Flags().String(cmdFlagBearerClauseInput, predicatePtpkh, "input to satisfy the bearer clause. "+helpPredicateArgument)
+	cmd.Flags().String(cmdFlagChangeBearerClause, "", "predicate that defines the ownership of the change when a token has to be split, by default the change keeps the owner of the split token. "+helpPredicateValues)
+	cmd.Flags().Bool(cmdFlagFailOnLocked, false, "fail with the list of locked tokens when the send is possible only after unlocking them, by default locked tokens are skipped")
 	cmd.Flags().String(cmdFlagAmount, "", "amount, must be bigger than 0 and is interpreted according to token type precision (decimals)")
 	err := cmd.MarkFlagRequired(cmdFlagAmount)
 	if err != nil {
@@ -547,10 +698,11 @@ func tokenCmdSendFungible(config *types.WalletConfig) *cobra.Command {
 		return nil
 	}
 	cmd.Flags().StringP(args.AddressCmdName, "a", "", "compressed secp256k1 public key of the receiver in hexadecimal format, must start with 0x and be 68 characters in length")
-	err = cmd.MarkFlagRequired(args.AddressCmdName)
-	if err != nil {
-		return nil
-	}
+	cmd.Flags().String(cmdFlagAddressFile, "", "file with the public keys of the receivers, one per line, lines starting with # are ignored; every receiver is sent the given amount")
+	cmd.MarkFlagsOneRequired(args.AddressCmdName, cmdFlagAddressFile)
+	cmd.MarkFlagsMutuallyExclusive(args.AddressCmdName, cmdFlagAddressFile)
+	cmd.MarkFlagsMutuallyExclusive(cmdFlagAddressFile, cmdFlagChangeBearerClause)
+	cmd.MarkFlagsMutuallyExclusive(cmdFlagAddressFile, cmdFlagFailOnLocked)
 	return addCommonAccountFlags(cmd)
 }
 
@@ -574,27 +726,22 @@ func getPubKeyBytes(cmd *cobra.Command, flag string) ([]byte, error) {
 }
 
 func execTokenCmdSendFungible(cmd *cobra.Command, config *types.WalletConfig) error {
-	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
-	if err != nil {
-		return err
-	}
 	tw, err := initTokensWallet(cmd, config)
 	if err != nil {
 		return err
 	}
 	defer tw.Close()
-
-	typeId, err := getHexFlag(cmd, cmdFlagType)
+	accountNumber, err := accountNumberArg(cmd, tw.GetAccountManager())
 	if err != nil {
 		return err
 	}
 
-	amountStr, err := cmd.Flags().GetString(cmdFlagAmount)
+	typeId, err := getHexFlag(cmd, cmdFlagType)
 	if err != nil {
 		return err
 	}
 
-	pubKey, err := getPubKeyBytes(cmd, args.AddressCmdName)
+	amountStr, err := cmd.Flags().GetString(cmdFlagAmount)
 	if err != nil {
 		return err
 	}
@@ -615,24 +762,64 @@ func execTokenCmdSendFungible(cmd *cobra.Command, config *types.WalletConfig) er
 		return err
 	}
 	// convert amount from string to uint64
-	targetValue, err := util.StringToAmount(amountStr, tt.DecimalPlaces)
+	targetValue, err := parseTokenAmount(amountStr, tt.DecimalPlaces)
 	if err != nil {
 		return err
 	}
 	if targetValue == 0 {
 		return fmt.Errorf("invalid parameter \"%s\" for \"--amount\": 0 is not valid amount", amountStr)
 	}
-	result, err := tw.SendFungible(cmd.Context(), accountNumber, typeId, targetValue, pubKey, ownerProofInput, ib)
-	if err != nil {
+	var result *tokenswallet.SubmissionResult
+	if addressFile, err := cmd.Flags().GetString(cmdFlagAddressFile); err != nil {
+		return err
+	} else if addressFile != "" {
+		pubKeys, err := readAddressFile(addressFile)
+		if err != nil {
+			return err
+		}
+		targets := make([]tokenswallet.TransferTarget, len(pubKeys))
+		for i, pubKey := range pubKeys {
+			targets[i] = tokenswallet.TransferTarget{PubKey: pubKey, Amount: targetValue}
+		}
+		if result, err = tw.SendFungibleMulti(cmd.Context(), accountNumber, typeId, targets, ownerProofInput, ib); err != nil {
+			return err
+		}
+	} else if result, err = sendFungible(cmd, tw, accountNumber, typeId, targetValue, ownerProofInput, ib); err != nil {
 		return err
 	}
+	printTxIDs(config, result)
 	if result.FeeSum > 0 {
-		config.Base.ConsoleWriter.Println(fmt.Sprintf("Paid %s fees for transaction(s).", util.AmountToString(result.FeeSum, 8)))
+		config.Base.ConsoleWriter.Println(fmt.Sprintf("Paid %s fees for transaction(s).", config.FormatAmount(result.FeeSum, 8)))
 	}
-	if err := saveTxProofs(cmd, result.GetProofs(), config.Base.ConsoleWriter); err != nil {
+	printMaxFeeWarning(config, tw)
+	if err := saveTxProofs(cmd, accountNumber, result.GetProofs(), config.Base.ConsoleWriter); err != nil {
 		return fmt.Errorf("saving transaction proof(s): %w", err)
 	}
-	return err
+	return nil
+}
+
+// sendFungible sends the amount to the single receiver of the --address flag.
+func sendFungible(cmd *cobra.Command, tw *tokenswallet.Wallet, accountNumber uint64, typeId sdktypes.TokenTypeID, targetValue uint64, ownerProofInput *tokenswallet.PredicateInput, ib []*tokenswallet.PredicateInput) (*tokenswallet.SubmissionResult, error) {
+	pubKey, err := getPubKeyBytes(cmd, args.AddressCmdName)
+	if err != nil {
+		return nil, err
+	}
+	var opts []tokenswallet.SendFungibleOption
+	if cmd.Flags().Changed(cmdFlagChangeBearerClause) {
+		changeOwnerPredicate, err := parsePredicateClauseCmd(cmd, cmdFlagChangeBearerClause, accountNumber, tw.GetAccountManager())
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, tokenswallet.WithChangeOwnerPredicate(changeOwnerPredicate))
+	}
+	failOnLocked, err := cmd.Flags().GetBool(cmdFlagFailOnLocked)
+	if err != nil {
+		return nil, err
+	}
+	if failOnLocked {
+		opts = append(opts, tokenswallet.WithFailOnLockedTokens())
+	}
+	return tw.SendFungible(cmd.Context(), accountNumber, typeId, targetValue, pubKey, ownerProofInput, ib, opts...)
 }
 
 func tokenCmdSendNonFungible(config *types.WalletConfig) *cobra.Command {
@@ -646,62 +833,222 @@ func tokenCmdSendNonFungible(config *types.WalletConfig) *cobra.Command {
 	cmd.Flags().StringSlice(cmdFlagInheritBearerClauseInput, []string{predicateTrue}, "input to satisfy the owner predicates inherited from types. "+helpPredicateArgument)
 	cmd.Flags().String(cmdFlagBearerClauseInput, predicatePtpkh, "input to satisfy the bearer clause. "+helpPredicateArgument)
 	setHexFlag(cmd, cmdFlagTokenID, nil, "token identifier")
-	err := cmd.MarkFlagRequired(cmdFlagTokenID)
-	if err != nil {
-		return nil
-	}
 	cmd.Flags().StringP(args.AddressCmdName, "a", "", "compressed secp256k1 public key of the receiver in hexadecimal format, must start with 0x and be 68 characters in length")
-	err = cmd.MarkFlagRequired(args.AddressCmdName)
-	if err != nil {
-		return nil
-	}
+	setHexFlag(cmd, cmdFlagAddressHash, nil, "SHA256 hash of the public key of the receiver in hexadecimal format, alternative to --address when the public key is not known")
+	cmd.Flags().String(cmdFlagTransfersFile, "", "file with multiple transfers, one \"<token identifier>,<receiver public key>\" pair per line, lines starting with # are ignored")
+	cmd.Flags().String(cmdFlagAddressFile, "", "file with the public keys of the receivers, one per line, lines starting with # are ignored; every receiver is sent one unlocked token of the --type")
+	setHexFlag(cmd, cmdFlagType, nil, "type unit identifier of the tokens sent to the receivers of the --address-file")
+	cmd.MarkFlagsRequiredTogether(cmdFlagAddressFile, cmdFlagType)
+	cmd.MarkFlagsOneRequired(cmdFlagTokenID, cmdFlagTransfersFile, cmdFlagAddressFile)
+	cmd.MarkFlagsMutuallyExclusive(cmdFlagTokenID, cmdFlagTransfersFile, cmdFlagAddressFile)
+	cmd.MarkFlagsMutuallyExclusive(args.AddressCmdName, cmdFlagTransfersFile, cmdFlagAddressFile)
+	cmd.MarkFlagsMutuallyExclusive(cmdFlagAddressHash, cmdFlagTransfersFile, cmdFlagAddressFile)
+	cmd.MarkFlagsMutuallyExclusive(args.AddressCmdName, cmdFlagAddressHash)
 	return addCommonAccountFlags(cmd)
 }
 
 func execTokenCmdSendNonFungible(cmd *cobra.Command, config *types.WalletConfig) error {
-	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
-	if err != nil {
-		return err
+	if cmd.Flags().Changed(cmdFlagTokenID) && !cmd.Flags().Changed(args.AddressCmdName) && !cmd.Flags().Changed(cmdFlagAddressHash) {
+		return fmt.Errorf("one of the flags %q or %q must be set with %q", args.AddressCmdName, cmdFlagAddressHash, cmdFlagTokenID)
 	}
 	tw, err := initTokensWallet(cmd, config)
 	if err != nil {
 		return err
 	}
 	defer tw.Close()
-
-	tokenID, err := getHexFlag(cmd, cmdFlagTokenID)
+	accountNumber, err := accountNumberArg(cmd, tw.GetAccountManager())
 	if err != nil {
 		return err
 	}
 
-	pubKey, err := getPubKeyBytes(cmd, args.AddressCmdName)
+	typeOwnerPredicateInputs, err := readPredicateInputs(cmd, cmdFlagInheritBearerClauseInput, accountNumber, tw.GetAccountManager())
 	if err != nil {
 		return err
 	}
 
-	typeOwnerPredicateInputs, err := readPredicateInputs(cmd, cmdFlagInheritBearerClauseInput, accountNumber, tw.GetAccountManager())
+	ownerPredicateInput, err := readSinglePredicateInput(cmd, cmdFlagBearerClauseInput, accountNumber, tw.GetAccountManager())
 	if err != nil {
 		return err
 	}
 
-	ownerPredicateInput, err := readSinglePredicateInput(cmd, cmdFlagBearerClauseInput, accountNumber, tw.GetAccountManager())
+	transfersFile, err := cmd.Flags().GetString(cmdFlagTransfersFile)
+	if err != nil {
+		return err
+	}
+	addressFile, err := cmd.Flags().GetString(cmdFlagAddressFile)
 	if err != nil {
 		return err
 	}
+	if transfersFile != "" || addressFile != "" {
+		var transfers []tokenswallet.NFTTransfer
+		if transfersFile != "" {
+			transfers, err = readNFTTransfersFile(transfersFile)
+		} else {
+			transfers, err = nftTransfersToAddresses(cmd, tw, accountNumber, addressFile)
+		}
+		if err != nil {
+			return err
+		}
+		for i := range transfers {
+			transfers[i].OwnerPredicateInput = ownerPredicateInput
+			transfers[i].TypeOwnerPredicateInputs = typeOwnerPredicateInputs
+		}
+		results, err := tw.TransferNFTs(cmd.Context(), accountNumber, transfers)
+		var feeSum uint64
+		var proofs []*basetypes.TxRecordProof
+		for i, result := range results {
+			if result.SkipReason != nil {
+				config.Base.ConsoleWriter.Println(fmt.Sprintf("Skipped token %s: %v", transfers[i].TokenID, result.SkipReason))
+				continue
+			}
+			printTxIDs(config, result)
+			feeSum += result.FeeSum
+			proofs = append(proofs, result.GetProofs()...)
+		}
+		if err != nil {
+			return ownerProofHint(err)
+		}
+		if feeSum > 0 {
+			config.Base.ConsoleWriter.Println(fmt.Sprintf("Paid %s fees for transaction(s).", config.FormatAmount(feeSum, 8)))
+		}
+		printMaxFeeWarning(config, tw)
+		if err := saveTxProofs(cmd, accountNumber, proofs, config.Base.ConsoleWriter); err != nil {
+			return fmt.Errorf("saving transaction proof(s): %w", err)
+		}
+		return nil
+	}
 
-	result, err := tw.TransferNFT(cmd.Context(), accountNumber, tokenID, pubKey, typeOwnerPredicateInputs, ownerPredicateInput)
+	tokenID, err := getHexFlag(cmd, cmdFlagTokenID)
 	if err != nil {
 		return err
 	}
+
+	var result *tokenswallet.SubmissionResult
+	if cmd.Flags().Changed(cmdFlagAddressHash) {
+		pubKeyHash, err := getHexFlag(cmd, cmdFlagAddressHash)
+		if err != nil {
+			return err
+		}
+		if result, err = tw.TransferNFTToHash(cmd.Context(), accountNumber, tokenID, pubKeyHash, typeOwnerPredicateInputs, ownerPredicateInput); err != nil {
+			return ownerProofHint(err)
+		}
+	} else {
+		pubKey, err := getPubKeyBytes(cmd, args.AddressCmdName)
+		if err != nil {
+			return err
+		}
+		if result, err = tw.TransferNFT(cmd.Context(), accountNumber, tokenID, pubKey, typeOwnerPredicateInputs, ownerPredicateInput); err != nil {
+			return ownerProofHint(err)
+		}
+	}
+	printTxIDs(config, result)
 	if result.FeeSum > 0 {
-		config.Base.ConsoleWriter.Println(fmt.Sprintf("Paid %s fees for transaction(s).", util.AmountToString(result.FeeSum, 8)))
+		config.Base.ConsoleWriter.Println(fmt.Sprintf("Paid %s fees for transaction(s).", config.FormatAmount(result.FeeSum, 8)))
 	}
-	if err := saveTxProofs(cmd, result.GetProofs(), config.Base.ConsoleWriter); err != nil {
+	printMaxFeeWarning(config, tw)
+	if err := saveTxProofs(cmd, accountNumber, result.GetProofs(), config.Base.ConsoleWriter); err != nil {
 		return fmt.Errorf("saving transaction proof(s): %w", err)
 	}
 	return err
 }
 
+/*
+nftTransfersToAddresses returns transfers of the unlocked tokens of the --type owned by the
+account, one token per receiver of the address file. The tokens are assigned in the order
+returned by FindNonFungibleTokens (ie sorted by name).
+*/
+func nftTransfersToAddresses(cmd *cobra.Command, tw *tokenswallet.Wallet, accountNumber uint64, addressFile string) ([]tokenswallet.NFTTransfer, error) {
+	pubKeys, err := readAddressFile(addressFile)
+	if err != nil {
+		return nil, err
+	}
+	typeID, err := getHexFlag(cmd, cmdFlagType)
+	if err != nil {
+		return nil, err
+	}
+	tokenz, err := tw.FindNonFungibleTokens(cmd.Context(), accountNumber, tokenswallet.TokenFilter{TypeID: typeID})
+	if err != nil {
+		return nil, err
+	}
+	transfers := make([]tokenswallet.NFTTransfer, 0, len(pubKeys))
+	for _, t := range tokenz {
+		if len(transfers) == len(pubKeys) {
+			break
+		}
+		if t.LockStatus != 0 {
+			continue
+		}
+		transfers = append(transfers, tokenswallet.NFTTransfer{TokenID: t.ID, ReceiverPubKey: pubKeys[len(transfers)]})
+	}
+	if len(transfers) < len(pubKeys) {
+		return nil, fmt.Errorf("account has %d unlocked tokens of type %s, %d needed for the receivers of the %s", len(transfers), sdktypes.TokenTypeID(typeID), len(pubKeys), cmdFlagAddressFile)
+	}
+	return transfers, nil
+}
+
+/*
+readAddressFile reads the receiver public keys from the file where each line is a
+public key in hexadecimal format, empty lines and lines starting with # are ignored.
+*/
+func readAddressFile(path string) ([][]byte, error) {
+	data, err := readFile(path, cmdFlagAddressFile, maxTransfersFile1MiB)
+	if err != nil {
+		return nil, err
+	}
+	var pubKeys [][]byte
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		pubKey, ok := cliaccount.PubKeyHexToBytes(line)
+		if !ok {
+			return nil, fmt.Errorf("%s line %d: address in not in valid format: %s", cmdFlagAddressFile, i+1, line)
+		}
+		pubKeys = append(pubKeys, pubKey)
+	}
+	if len(pubKeys) == 0 {
+		return nil, fmt.Errorf("%s: no addresses", cmdFlagAddressFile)
+	}
+	return pubKeys, nil
+}
+
+/*
+readNFTTransfersFile reads transfers from the file where each line is a
+"<token identifier>,<receiver public key>" pair, empty lines and lines
+starting with # are ignored.
+*/
+func readNFTTransfersFile(path string) ([]tokenswallet.NFTTransfer, error) {
+	data, err := readFile(path, cmdFlagTransfersFile, maxTransfersFile1MiB)
+	if err != nil {
+		return nil, err
+	}
+	var transfers []tokenswallet.NFTTransfer
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		tokenIDHex, pubKeyHex, ok := strings.Cut(line, ",")
+		if !ok {
+			return nil, fmt.Errorf("%s line %d: expected \"<token identifier>,<receiver public key>\"", cmdFlagTransfersFile, i+1)
+		}
+		tokenID, err := tokenswallet.DecodeHexOrEmpty(strings.TrimSpace(tokenIDHex))
+		if err != nil || len(tokenID) == 0 {
+			return nil, fmt.Errorf("%s line %d: invalid token identifier: %q", cmdFlagTransfersFile, i+1, tokenIDHex)
+		}
+		pubKey, ok := cliaccount.PubKeyHexToBytes(strings.TrimSpace(pubKeyHex))
+		if !ok {
+			return nil, fmt.Errorf("%s line %d: address in not in valid format: %s", cmdFlagTransfersFile, i+1, pubKeyHex)
+		}
+		transfers = append(transfers, tokenswallet.NFTTransfer{TokenID: tokenID, ReceiverPubKey: pubKey})
+	}
+	if len(transfers) == 0 {
+		return nil, fmt.Errorf("%s: no transfers", cmdFlagTransfersFile)
+	}
+	return transfers, nil
+}
+
 func tokenCmdDC(config *types.WalletConfig, runner runTokenCmdDC) *cobra.Command {
 	var accountNumber uint64
 
@@ -717,6 +1064,7 @@ func tokenCmdDC(config *types.WalletConfig, runner runTokenCmdDC) *cobra.Command
 	cmd.Flags().StringSlice(cmdFlagType, nil, "type unit identifier (hex)")
 	cmd.Flags().StringSlice(cmdFlagInheritBearerClauseInput, []string{predicateTrue}, "input to satisfy the owner predicates inherited from types. "+helpPredicateArgument)
 	cmd.Flags().String(cmdFlagBearerClauseInput, predicatePtpkh, "input to satisfy the bearer clause. "+helpPredicateArgument)
+	cmd.Flags().Int(cmdFlagMaxBatch, 0, "maximum number of tokens joined in a single swap, 0 for the default (100)")
 
 	if err := cmd.MarkFlagRequired(cmdFlagType); err != nil {
 		panic(err)
@@ -759,7 +1107,18 @@ func execTokenCmdDC(cmd *cobra.Command, config *types.WalletConfig, accountNumbe
 		return err
 	}
 
-	results, err := tw.CollectDust(cmd.Context(), *accountNumber, typez, ownerPredicateInput, ib)
+	maxBatch, err := cmd.Flags().GetInt(cmdFlagMaxBatch)
+	if err != nil {
+		return err
+	}
+	if maxBatch < 0 {
+		return fmt.Errorf("invalid value %d for flag %q (must not be negative)", maxBatch, cmdFlagMaxBatch)
+	}
+
+	progress := tokenswallet.WithProgress(func(accountNumber uint64, swapsDone, swapsTotal int) {
+		config.Base.ConsoleWriter.Println(fmt.Sprintf("Account number %d: dust collection swap %d of %d done", accountNumber, swapsDone, swapsTotal))
+	})
+	results, err := tw.CollectDust(cmd.Context(), *accountNumber, typez, ownerPredicateInput, ib, tokenswallet.WithMaxTokensPerSwap(maxBatch), progress)
 	if err != nil {
 		return err
 	}
@@ -768,10 +1127,12 @@ func execTokenCmdDC(cmd *cobra.Command, config *types.WalletConfig, accountNumbe
 			config.Base.ConsoleWriter.Println(fmt.Sprintf("Nothing to swap on account #%d", idx+1))
 		} else {
 			for _, dcResult := range result {
-				config.Base.ConsoleWriter.Println(fmt.Sprintf("Paid %s fees for dust collection on Account number %d.", util.AmountToString(dcResult.FeeSum, 8), idx+1))
+				config.Base.ConsoleWriter.Println(fmt.Sprintf("Paid %s fees for dust collection on Account number %d.", config.FormatAmount(dcResult.FeeSum, 8), idx+1))
+				printFailedTxs(config, dcResult)
 			}
 		}
 	}
+	printMaxFeeWarning(config, tw)
 	return err
 }
 
@@ -789,23 +1150,20 @@ func tokenCmdUpdateNFTData(config *types.WalletConfig) *cobra.Command {
 	}
 
 	addDataFlags(cmd)
-	cmd.Flags().String(cmdFlagTokenDataUpdateClauseInput, predicateTrue, "input to satisfy the token's data-update clause. "+helpPredicateArgument)
+	cmd.Flags().String(cmdFlagTokenDataUpdateClauseInput, predicateTrue, "input to satisfy the token's data-update clause, when the clause is a custom predicate "+
+		"the argument must be loaded from file (@<filename>), the file content is used verbatim as the predicate argument. "+helpPredicateArgument)
 	cmd.Flags().StringSlice(cmdFlagInheritTokenDataUpdateClauseInput, []string{predicateTrue}, "input to satisfy the data-update clauses of inherited types. "+helpPredicateArgument)
+	cmd.Flags().Uint64(cmdFlagExpectedCounter, 0, "current counter of the token, the update is not sent when the token has changed (optional)")
 	return addCommonAccountFlags(cmd)
 }
 
 func execTokenCmdUpdateNFTData(cmd *cobra.Command, config *types.WalletConfig) error {
-	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
-	if err != nil {
-		return err
-	}
-
 	tokenID, err := getHexFlag(cmd, cmdFlagTokenID)
 	if err != nil {
 		return err
 	}
 
-	data, err := readNFTData(cmd, true)
+	data, err := readNFTData(cmd, true, config.Base.Logger)
 	if err != nil {
 		return err
 	}
@@ -815,6 +1173,10 @@ func execTokenCmdUpdateNFTData(cmd *cobra.Command, config *types.WalletConfig) e
 		return err
 	}
 	defer tw.Close()
+	accountNumber, err := accountNumberArg(cmd, tw.GetAccountManager())
+	if err != nil {
+		return err
+	}
 
 	tokenDataUpdatePredicateInput, err := readSinglePredicateInput(cmd, cmdFlagTokenDataUpdateClauseInput, accountNumber, tw.GetAccountManager())
 	if err != nil {
@@ -826,14 +1188,28 @@ func execTokenCmdUpdateNFTData(cmd *cobra.Command, config *types.WalletConfig) e
 		return err
 	}
 
-	result, err := tw.UpdateNFTData(cmd.Context(), accountNumber, tokenID, data, tokenDataUpdatePredicateInput, tokenTypeDataUpdatePredicateInputs)
+	var expectedCounter *uint64
+	if cmd.Flags().Changed(cmdFlagExpectedCounter) {
+		counter, err := cmd.Flags().GetUint64(cmdFlagExpectedCounter)
+		if err != nil {
+			return err
+		}
+		expectedCounter = &counter
+	}
+
+	result, err := tw.UpdateNFTData(cmd.Context(), accountNumber, tokenID, data, tokenDataUpdatePredicateInput, tokenTypeDataUpdatePredicateInputs, expectedCounter)
 	if err != nil {
+		if errors.Is(err, tokenswallet.ErrDataUpdateProofRequired) {
+			return fmt.Errorf("%w, provide it using the --%s @<filename> flag", err, cmdFlagTokenDataUpdateClauseInput)
+		}
 		return err
 	}
+	printTxIDs(config, result)
 	if result.FeeSum > 0 {
-		config.Base.ConsoleWriter.Println(fmt.Sprintf("Paid %s fees for transaction(s).", util.AmountToString(result.FeeSum, 8)))
+		config.Base.ConsoleWriter.Println(fmt.Sprintf("Paid %s fees for transaction(s).", config.FormatAmount(result.FeeSum, 8)))
 	}
-	if err := saveTxProofs(cmd, result.GetProofs(), config.Base.ConsoleWriter); err != nil {
+	printMaxFeeWarning(config, tw)
+	if err := saveTxProofs(cmd, accountNumber, result.GetProofs(), config.Base.ConsoleWriter); err != nil {
 		return fmt.Errorf("saving transaction proof(s): %w", err)
 	}
 	return err
@@ -856,6 +1232,10 @@ func tokenCmdList(config *types.WalletConfig, runner runTokenListCmd) *cobra.Com
 	cmd.Flags().Bool(cmdFlagWithTypeName, false, "Show type name field")
 	cmd.Flags().Bool(cmdFlagWithTokenURI, false, "Show non-fungible token URI field")
 	cmd.Flags().Bool(cmdFlagWithTokenData, false, "Show non-fungible token data field")
+	cmd.PersistentFlags().Bool(cmdFlagValidateTypes, false, "Resolve the type of each token and report tokens with unresolvable or mismatching types")
+	cmd.PersistentFlags().String(cmdFlagOutput, outputText, "output format [text|json], the json output has all the fields of the tokens")
+	var typeID types.BytesHex
+	cmd.PersistentFlags().Var(&typeID, cmdFlagType, "list only tokens of the given type (hex)")
 
 	// add sub commands
 	cmd.AddCommand(tokenCmdListFungible(config, runner, &accountNumber))
@@ -864,34 +1244,118 @@ func tokenCmdList(config *types.WalletConfig, runner runTokenListCmd) *cobra.Com
 	return cmd
 }
 
-func tokenCmdListFungible(config *types.WalletConfig, runner runTokenListCmd, accountNumber *uint64) *cobra.Command {
+func tokenCmdCount(config *types.WalletConfig) *cobra.Command {
+	var accountNumber uint64
 	cmd := &cobra.Command{
-		Use:   "fungible",
-		Short: "lists fungible tokens",
+		Use:   "count",
+		Short: "counts fungible and non-fungible tokens without listing them",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runner(cmd, config, accountNumber, Fungible)
+			return execTokenCmdCount(cmd, config, accountNumber)
 		},
 	}
-
-	cmd.Flags().Bool(cmdFlagWithAll, false, "Show all available fields for each token")
-	cmd.Flags().Bool(cmdFlagWithTypeName, false, "Show type name field")
-
+	cmd.Flags().BoolP(args.PasswordPromptCmdName, "p", false, args.PasswordPromptUsage)
+	cmd.Flags().String(args.PasswordArgCmdName, "", args.PasswordArgUsage)
+	cmd.Flags().Uint64VarP(&accountNumber, args.KeyCmdName, "k", allAccounts, "which account tokens to count (0 for all accounts)")
 	return cmd
 }
 
-func tokenCmdListNonFungible(config *types.WalletConfig, runner runTokenListCmd, accountNumber *uint64) *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "non-fungible",
-		Short: "lists non-fungible tokens",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			return runner(cmd, config, accountNumber, NonFungible)
-		},
+func execTokenCmdCount(cmd *cobra.Command, config *types.WalletConfig, accountNumber uint64) error {
+	tw, err := initTokensWallet(cmd, config)
+	if err != nil {
+		return err
 	}
+	defer tw.Close()
 
-	cmd.Flags().Bool(cmdFlagWithAll, false, "Show all available fields for each token")
+	fungible, nonFungible, err := tw.CountTokens(cmd.Context(), accountNumber)
+	if err != nil {
+		return err
+	}
+	config.Base.ConsoleWriter.Println(fmt.Sprintf("Fungible tokens: %d", fungible))
+	config.Base.ConsoleWriter.Println(fmt.Sprintf("Non-fungible tokens: %d", nonFungible))
+	return nil
+}
+
+func tokenCmdWatch(config *types.WalletConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "watches the account for incoming tokens until interrupted",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execTokenCmdWatch(cmd, config)
+		},
+	}
+	cmd.Flags().Uint64P(args.KeyCmdName, "k", 1, "which account to watch")
+	cmd.Flags().Duration(cmdFlagInterval, 5*time.Second, "how often the tokens of the account are polled, ie 10s or 1m")
+	cmd.Flags().BoolP(args.PasswordPromptCmdName, "p", false, args.PasswordPromptUsage)
+	cmd.Flags().String(args.PasswordArgCmdName, "", args.PasswordArgUsage)
+	return cmd
+}
+
+func execTokenCmdWatch(cmd *cobra.Command, config *types.WalletConfig) error {
+	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
+	if err != nil {
+		return err
+	}
+	interval, err := cmd.Flags().GetDuration(cmdFlagInterval)
+	if err != nil {
+		return err
+	}
+
+	tw, err := initTokensWallet(cmd, config)
+	if err != nil {
+		return err
+	}
+	defer tw.Close()
+
+	events, err := tw.WatchIncomingTokens(cmd.Context(), accountNumber, interval)
+	if err != nil {
+		return err
+	}
+	config.Base.ConsoleWriter.Println(fmt.Sprintf("Watching account #%d for incoming tokens...", accountNumber))
+	// the channel is closed when the command is interrupted or times out
+	for ev := range events {
+		switch t := ev.Token.(type) {
+		case *sdktypes.FungibleToken:
+			amount := config.FormatAmount(t.Amount, t.DecimalPlaces)
+			config.Base.ConsoleWriter.Println(fmt.Sprintf("Received fungible token ID='%s', symbol='%s', amount='%s', token-type='%s'",
+				t.ID, t.Symbol, amount, t.TypeID))
+		case *sdktypes.NonFungibleToken:
+			config.Base.ConsoleWriter.Println(fmt.Sprintf("Received non-fungible token ID='%s', symbol='%s', name='%s', token-type='%s'",
+				t.ID, t.Symbol, t.Name, t.TypeID))
+		}
+	}
+	return nil
+}
+
+func tokenCmdListFungible(config *types.WalletConfig, runner runTokenListCmd, accountNumber *uint64) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fungible",
+		Short: "lists fungible tokens",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runner(cmd, config, accountNumber, Fungible)
+		},
+	}
+
+	cmd.Flags().Bool(cmdFlagWithAll, false, "Show all available fields for each token")
+	cmd.Flags().Bool(cmdFlagWithTypeName, false, "Show type name field")
+
+	return cmd
+}
+
+func tokenCmdListNonFungible(config *types.WalletConfig, runner runTokenListCmd, accountNumber *uint64) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "non-fungible",
+		Short: "lists non-fungible tokens",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runner(cmd, config, accountNumber, NonFungible)
+		},
+	}
+
+	cmd.Flags().Bool(cmdFlagWithAll, false, "Show all available fields for each token")
 	cmd.Flags().Bool(cmdFlagWithTypeName, false, "Show type name field")
 	cmd.Flags().Bool(cmdFlagWithTokenURI, false, "Show token URI field")
 	cmd.Flags().Bool(cmdFlagWithTokenData, false, "Show token data field")
+	cmd.Flags().String(cmdFlagNamePrefix, "", "list only tokens whose name starts with the given prefix, the tokens are sorted by name")
+	cmd.Flags().String(cmdFlagSymbol, "", "list only tokens with the given symbol, the tokens are sorted by name")
 
 	return cmd
 }
@@ -926,6 +1390,32 @@ func execTokenCmdList(cmd *cobra.Command, config *types.WalletConfig, accountNum
 		}
 	}
 
+	validateTypes, err := cmd.Flags().GetBool(cmdFlagValidateTypes)
+	if err != nil {
+		return err
+	}
+	jsonOutput, err := getJSONOutputFlag(cmd)
+	if err != nil {
+		return err
+	}
+	if jsonOutput && validateTypes {
+		return fmt.Errorf("flag %q is not supported with json output", cmdFlagValidateTypes)
+	}
+
+	typeID, err := getHexFlag(cmd, cmdFlagType)
+	if err != nil {
+		return err
+	}
+	nftFilter := tokenswallet.TokenFilter{TypeID: typeID}
+	if kind == NonFungible {
+		if nftFilter.NamePrefix, err = cmd.Flags().GetString(cmdFlagNamePrefix); err != nil {
+			return err
+		}
+		if nftFilter.Symbol, err = cmd.Flags().GetString(cmdFlagSymbol); err != nil {
+			return err
+		}
+	}
+
 	var firstAccountNumber, lastAccountNumber uint64
 	if *accountNumber == allAccounts {
 		firstAccountNumber = 1
@@ -939,16 +1429,24 @@ func execTokenCmdList(cmd *cobra.Command, config *types.WalletConfig, accountNum
 		lastAccountNumber = *accountNumber
 	}
 
+	if jsonOutput {
+		return listTokensJSON(cmd.Context(), config, tw, firstAccountNumber, lastAccountNumber, kind, typeID, nftFilter)
+	}
+
 	atLeastOneFound := false
+	var unresolved []*tokenswallet.UnresolvedTypeToken
 	for accountNumber := firstAccountNumber; accountNumber <= lastAccountNumber; accountNumber++ {
 		ownerAccount := fmt.Sprintf("Tokens owned by account #%v", accountNumber)
 		atLeastOneFoundForAccount := false
+		var fts []*sdktypes.FungibleToken
+		var nfts []*sdktypes.NonFungibleToken
 
 		if kind == Any || kind == Fungible {
-			tokens, err := tw.ListFungibleTokens(cmd.Context(), accountNumber)
+			tokens, err := listFungibleTokens(cmd.Context(), tw, accountNumber, typeID)
 			if err != nil {
 				return err
 			}
+			fts = tokens
 			if len(tokens) > 0 {
 				atLeastOneFound = true
 				atLeastOneFoundForAccount = true
@@ -959,17 +1457,18 @@ func execTokenCmdList(cmd *cobra.Command, config *types.WalletConfig, accountNum
 				if withAll || withTypeName {
 					typeName = fmt.Sprintf(", token-type-name='%s'", t.TypeName)
 				}
-				amount := util.AmountToString(t.Amount, t.DecimalPlaces)
+				amount := config.FormatAmount(t.Amount, t.DecimalPlaces)
 				config.Base.ConsoleWriter.Println(fmt.Sprintf("ID='%s', symbol='%s', amount='%v', token-type='%s', lockStatus='%d (%s)'",
 					t.ID, t.Symbol, amount, t.TypeID, t.LockStatus, wallet.LockReason(t.LockStatus).String()) + typeName + " (fungible)")
 			}
 		}
 
 		if kind == Any || kind == NonFungible {
-			tokens, err := tw.ListNonFungibleTokens(cmd.Context(), accountNumber)
+			tokens, err := listNonFungibleTokens(cmd.Context(), tw, accountNumber, nftFilter)
 			if err != nil {
 				return err
 			}
+			nfts = tokens
 			if len(tokens) > 0 {
 				atLeastOneFound = true
 				if !atLeastOneFoundForAccount {
@@ -992,13 +1491,340 @@ func execTokenCmdList(cmd *cobra.Command, config *types.WalletConfig, accountNum
 					t.ID, t.Symbol, t.Name, t.TypeID, t.LockStatus, wallet.LockReason(t.LockStatus).String()) + typeName + nftURI + nftData + " (nft)")
 			}
 		}
+
+		if validateTypes {
+			res, err := tw.FindUnresolvedTypeTokens(cmd.Context(), fts, nfts)
+			if err != nil {
+				return err
+			}
+			unresolved = append(unresolved, res...)
+		}
 	}
 	if !atLeastOneFound {
 		config.Base.ConsoleWriter.Println("No tokens")
 	}
+	if validateTypes {
+		if len(unresolved) == 0 {
+			config.Base.ConsoleWriter.Println("All token types resolved")
+			return nil
+		}
+		config.Base.ConsoleWriter.Println("Tokens with unresolvable or mismatching types:")
+		for _, t := range unresolved {
+			config.Base.ConsoleWriter.Println(fmt.Sprintf("ID='%s', token-type='%s', reason='%v'", t.ID, t.TypeID, t.Reason))
+		}
+	}
 	return nil
 }
 
+func listTokensJSON(ctx context.Context, config *types.WalletConfig, tw *tokenswallet.Wallet, firstAccountNumber, lastAccountNumber uint64, kind Kind, typeID sdktypes.TokenTypeID, nftFilter tokenswallet.TokenFilter) error {
+	jsonTokens := []*tokenJSON{}
+	for accountNumber := firstAccountNumber; accountNumber <= lastAccountNumber; accountNumber++ {
+		if kind == Any || kind == Fungible {
+			tokens, err := listFungibleTokens(ctx, tw, accountNumber, typeID)
+			if err != nil {
+				return err
+			}
+			for _, t := range tokens {
+				jsonTokens = append(jsonTokens, newFungibleTokenJSON(accountNumber, t))
+			}
+		}
+		if kind == Any || kind == NonFungible {
+			tokens, err := listNonFungibleTokens(ctx, tw, accountNumber, nftFilter)
+			if err != nil {
+				return err
+			}
+			for _, t := range tokens {
+				jsonTokens = append(jsonTokens, newNonFungibleTokenJSON(accountNumber, t))
+			}
+		}
+	}
+	return printJSON(config, jsonTokens)
+}
+
+func listFungibleTokens(ctx context.Context, tw *tokenswallet.Wallet, accountNumber uint64, typeID sdktypes.TokenTypeID) ([]*sdktypes.FungibleToken, error) {
+	if len(typeID) > 0 {
+		return tw.ListFungibleTokensByType(ctx, accountNumber, typeID)
+	}
+	return tw.ListFungibleTokens(ctx, accountNumber)
+}
+
+func listNonFungibleTokens(ctx context.Context, tw *tokenswallet.Wallet, accountNumber uint64, filter tokenswallet.TokenFilter) ([]*sdktypes.NonFungibleToken, error) {
+	if filter.NamePrefix != "" || filter.Symbol != "" {
+		return tw.FindNonFungibleTokens(ctx, accountNumber, filter)
+	}
+	if len(filter.TypeID) > 0 {
+		return tw.ListNonFungibleTokensByType(ctx, accountNumber, filter.TypeID)
+	}
+	return tw.ListNonFungibleTokens(ctx, accountNumber)
+}
+
+// tokenJSON is a token in the json output of the token list command.
+type tokenJSON struct {
+	AccountNumber   uint64           `json:"accountNumber"`
+	Kind            string           `json:"kind"` // "fungible" or "nft"
+	ID              basetypes.UnitID `json:"id"`
+	Symbol          string           `json:"symbol"`
+	Name            string           `json:"name,omitempty"`
+	TypeID          basetypes.UnitID `json:"typeId"`
+	TypeName        string           `json:"typeName,omitempty"`
+	Amount          uint64           `json:"amount,omitempty,string"`
+	AmountFormatted string           `json:"amountFormatted,omitempty"` // amount with the decimal places of the type
+	DecimalPlaces   uint32           `json:"decimalPlaces,omitempty"`
+	LockStatus      uint64           `json:"lockStatus"`
+	LockReason      string           `json:"lockReason"`
+	URI             string           `json:"uri,omitempty"`
+	Data            hex.Bytes        `json:"data,omitempty"`
+}
+
+func newFungibleTokenJSON(accountNumber uint64, t *sdktypes.FungibleToken) *tokenJSON {
+	return &tokenJSON{
+		AccountNumber:   accountNumber,
+		Kind:            "fungible",
+		ID:              t.ID,
+		Symbol:          t.Symbol,
+		TypeID:          t.TypeID,
+		TypeName:        t.TypeName,
+		Amount:          t.Amount,
+		AmountFormatted: util.FormatAmount(t.Amount, t.DecimalPlaces, util.AmountFormatPlain),
+		DecimalPlaces:   t.DecimalPlaces,
+		LockStatus:      t.LockStatus,
+		LockReason:      wallet.LockReason(t.LockStatus).String(),
+	}
+}
+
+func newNonFungibleTokenJSON(accountNumber uint64, t *sdktypes.NonFungibleToken) *tokenJSON {
+	return &tokenJSON{
+		AccountNumber: accountNumber,
+		Kind:          "nft",
+		ID:            t.ID,
+		Symbol:        t.Symbol,
+		Name:          t.Name,
+		TypeID:        t.TypeID,
+		TypeName:      t.TypeName,
+		LockStatus:    t.LockStatus,
+		LockReason:    wallet.LockReason(t.LockStatus).String(),
+		URI:           t.URI,
+		Data:          t.Data,
+	}
+}
+
+// getJSONOutputFlag returns true if the output format flag of the command is set to json.
+func getJSONOutputFlag(cmd *cobra.Command) (bool, error) {
+	output, err := cmd.Flags().GetString(cmdFlagOutput)
+	if err != nil {
+		return false, err
+	}
+	switch output {
+	case outputText:
+		return false, nil
+	case outputJSON:
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid value %q for flag %q (must be %q or %q)", output, cmdFlagOutput, outputText, outputJSON)
+	}
+}
+
+func printJSON(config *types.WalletConfig, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding output as json: %w", err)
+	}
+	config.Base.ConsoleWriter.Println(string(data))
+	return nil
+}
+
+func tokenCmdShow(config *types.WalletConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "shows non-fungible token details",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execTokenCmdShow(cmd, config)
+		},
+	}
+	setHexFlag(cmd, cmdFlagTokenID, nil, "token identifier")
+	if err := cmd.MarkFlagRequired(cmdFlagTokenID); err != nil {
+		panic(err)
+	}
+	cmd.Flags().Bool(cmdFlagResolveMetadata, false, "fetch and show the JSON metadata the token URI points to (requires network access to the URI host)")
+	cmd.Flags().String(cmdFlagIPFSGateway, tokenswallet.DefaultIPFSGateway, "IPFS gateway used to resolve ipfs:// URIs")
+	cmd.Flags().BoolP(args.PasswordPromptCmdName, "p", false, args.PasswordPromptUsage)
+	cmd.Flags().String(args.PasswordArgCmdName, "", args.PasswordArgUsage)
+	return cmd
+}
+
+func execTokenCmdShow(cmd *cobra.Command, config *types.WalletConfig) error {
+	tokenID, err := getHexFlag(cmd, cmdFlagTokenID)
+	if err != nil {
+		return err
+	}
+	resolveMetadata, err := cmd.Flags().GetBool(cmdFlagResolveMetadata)
+	if err != nil {
+		return err
+	}
+	ipfsGateway, err := cmd.Flags().GetString(cmdFlagIPFSGateway)
+	if err != nil {
+		return err
+	}
+
+	tw, err := initTokensWallet(cmd, config)
+	if err != nil {
+		return err
+	}
+	defer tw.Close()
+
+	t, err := tw.GetNonFungibleToken(cmd.Context(), tokenID)
+	if err != nil {
+		return err
+	}
+	config.Base.ConsoleWriter.Println(fmt.Sprintf("ID='%s', symbol='%s', name='%s', token-type='%s', lockStatus='%d (%s)', URI='%s', data='%X'",
+		t.ID, t.Symbol, t.Name, t.TypeID, t.LockStatus, wallet.LockReason(t.LockStatus).String(), t.URI, t.Data))
+	if !resolveMetadata {
+		return nil
+	}
+
+	md, err := tokenswallet.NewMetadataResolver(tokenswallet.WithIPFSGateway(ipfsGateway)).ResolveMetadata(cmd.Context(), t)
+	if err != nil {
+		return fmt.Errorf("resolving token metadata: %w", err)
+	}
+	config.Base.ConsoleWriter.Println(fmt.Sprintf("Metadata: name='%s', description='%s', image='%s'", md.Name, md.Description, md.Image))
+	return nil
+}
+
+func tokenCmdDescribeType(config *types.WalletConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "describe-type",
+		Short: "shows token type details and describes its predicates",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execTokenCmdDescribeType(cmd, config)
+		},
+	}
+	setHexFlag(cmd, cmdFlagType, nil, "token type identifier")
+	if err := cmd.MarkFlagRequired(cmdFlagType); err != nil {
+		panic(err)
+	}
+	cmd.Flags().BoolP(args.PasswordPromptCmdName, "p", false, args.PasswordPromptUsage)
+	cmd.Flags().String(args.PasswordArgCmdName, "", args.PasswordArgUsage)
+	return cmd
+}
+
+func execTokenCmdDescribeType(cmd *cobra.Command, config *types.WalletConfig) error {
+	typeID, err := getHexFlag(cmd, cmdFlagType)
+	if err != nil {
+		return err
+	}
+
+	tw, err := initTokensWallet(cmd, config)
+	if err != nil {
+		return err
+	}
+	defer tw.Close()
+
+	desc, err := tw.DescribeTokenType(cmd.Context(), typeID)
+	if err != nil {
+		return err
+	}
+	kind := Fungible
+	if !desc.Fungible {
+		kind = NonFungible
+	}
+	config.Base.ConsoleWriter.Println(fmt.Sprintf("ID='%s', symbol='%s', name='%s', kind=%s", desc.ID, desc.Symbol, desc.Name, kind))
+	if desc.Fungible {
+		config.Base.ConsoleWriter.Println(fmt.Sprintf("Decimal places: %d", desc.DecimalPlaces))
+	}
+	if len(desc.ParentTypeID) > 0 {
+		config.Base.ConsoleWriter.Println(fmt.Sprintf("Parent type: %s", desc.ParentTypeID))
+	} else {
+		config.Base.ConsoleWriter.Println("Parent type: none")
+	}
+	config.Base.ConsoleWriter.Println(fmt.Sprintf("Sub-type creation predicate: %s", desc.SubTypeCreationPredicate))
+	config.Base.ConsoleWriter.Println(fmt.Sprintf("Token minting predicate: %s", desc.TokenMintingPredicate))
+	config.Base.ConsoleWriter.Println(fmt.Sprintf("Token type owner predicate: %s", desc.TokenTypeOwnerPredicate))
+	if !desc.Fungible {
+		config.Base.ConsoleWriter.Println(fmt.Sprintf("Data update predicate: %s", desc.DataUpdatePredicate))
+	}
+	return nil
+}
+
+func tokenCmdIcon(config *types.WalletConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "icon",
+		Aliases: []string{"get-icon"},
+		Short:   "writes the icon of the token type to a file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execTokenCmdIcon(cmd, config)
+		},
+	}
+	setHexFlag(cmd, cmdFlagType, nil, "token type identifier")
+	cmd.Flags().String(cmdFlagOutput, "", "output file name, extension is inferred from the MIME type of the icon if the name has none")
+	if err := cmd.MarkFlagRequired(cmdFlagType); err != nil {
+		panic(err)
+	}
+	if err := cmd.MarkFlagRequired(cmdFlagOutput); err != nil {
+		panic(err)
+	}
+	cmd.Flags().BoolP(args.PasswordPromptCmdName, "p", false, args.PasswordPromptUsage)
+	cmd.Flags().String(args.PasswordArgCmdName, "", args.PasswordArgUsage)
+	return cmd
+}
+
+func execTokenCmdIcon(cmd *cobra.Command, config *types.WalletConfig) error {
+	typeID, err := getHexFlag(cmd, cmdFlagType)
+	if err != nil {
+		return err
+	}
+	outputPath, err := cmd.Flags().GetString(cmdFlagOutput)
+	if err != nil {
+		return err
+	}
+
+	tw, err := initTokensWallet(cmd, config)
+	if err != nil {
+		return err
+	}
+	defer tw.Close()
+
+	icon, err := tw.GetTokenTypeIcon(cmd.Context(), typeID)
+	if err != nil {
+		return err
+	}
+	if icon == nil {
+		config.Base.ConsoleWriter.Println(fmt.Sprintf("Token type %s has no icon", typeID))
+		return nil
+	}
+	if filepath.Ext(outputPath) == "" {
+		ext, err := iconFileExt(icon.Type)
+		if err != nil {
+			return err
+		}
+		outputPath += ext
+	}
+	if err := os.WriteFile(outputPath, icon.Data, 0644); err != nil {
+		return fmt.Errorf("writing icon file: %w", err)
+	}
+	config.Base.ConsoleWriter.Println(fmt.Sprintf("Icon (%s, %d bytes) written to %s", icon.Type, len(icon.Data), outputPath))
+	return nil
+}
+
+// iconFileExt returns the file extension for the MIME type of the icon, the extension matching the
+// MIME subtype is preferred when the type has multiple extensions (ie ".jpeg" for "image/jpeg").
+func iconFileExt(iconType string) (string, error) {
+	mime.AddExtensionType(iconFileExtSvgz, iconFileExtSvgzType)
+	if iconType == iconFileExtSvgzType {
+		return iconFileExtSvgz, nil
+	}
+	exts, err := mime.ExtensionsByType(iconType)
+	if err != nil {
+		return "", fmt.Errorf("invalid icon MIME type %q: %w", iconType, err)
+	}
+	if len(exts) == 0 {
+		return "", fmt.Errorf("could not determine file extension for icon MIME type %q", iconType)
+	}
+	if _, subtype, ok := strings.Cut(iconType, "/"); ok && slices.Contains(exts, "."+subtype) {
+		return "." + subtype, nil
+	}
+	return exts[0], nil
+}
+
 func tokenCmdListTypes(config *types.WalletConfig, runner runTokenListTypesCmd) *cobra.Command {
 	var accountNumber uint64
 	cmd := &cobra.Command{
@@ -1012,6 +1838,7 @@ func tokenCmdListTypes(config *types.WalletConfig, runner runTokenListTypesCmd)
 	cmd.PersistentFlags().BoolP(args.PasswordPromptCmdName, "p", false, args.PasswordPromptUsage)
 	cmd.PersistentFlags().String(args.PasswordArgCmdName, "", args.PasswordArgUsage)
 	cmd.PersistentFlags().Uint64VarP(&accountNumber, args.KeyCmdName, "k", 0, "show types created from a specific key, 0 for all keys")
+	cmd.PersistentFlags().String(cmdFlagOutput, outputText, "output format [text|json]")
 	// add optional sub-commands to filter fungible and non-fungible types
 	cmd.AddCommand(&cobra.Command{
 		Use:   "fungible",
@@ -1031,12 +1858,20 @@ func tokenCmdListTypes(config *types.WalletConfig, runner runTokenListTypesCmd)
 }
 
 func execTokenCmdListTypes(cmd *cobra.Command, config *types.WalletConfig, accountNumber *uint64, kind Kind) error {
+	jsonOutput, err := getJSONOutputFlag(cmd)
+	if err != nil {
+		return err
+	}
 	tw, err := initTokensWallet(cmd, config)
 	if err != nil {
 		return err
 	}
 	defer tw.Close()
 
+	if jsonOutput {
+		return listTokenTypesJSON(cmd.Context(), config, tw, *accountNumber, kind)
+	}
+
 	printTokenType := func(id basetypes.UnitID, symbol, name string, kind Kind) {
 		optionalName := ""
 		if name != "" {
@@ -1068,6 +1903,64 @@ func execTokenCmdListTypes(cmd *cobra.Command, config *types.WalletConfig, accou
 	return nil
 }
 
+// tokenTypeJSON is a token type in the json output of the list-types command.
+type tokenTypeJSON struct {
+	ID                     basetypes.UnitID `json:"id"`
+	Symbol                 string           `json:"symbol"`
+	Name                   string           `json:"name,omitempty"`
+	Kind                   string           `json:"kind"` // "fungible" or "nft"
+	ParentTypeID           basetypes.UnitID `json:"parentTypeId,omitempty"`
+	DecimalPlaces          *uint32          `json:"decimalPlaces,omitempty"`          // fungible types only
+	HasDataUpdatePredicate *bool            `json:"hasDataUpdatePredicate,omitempty"` // non-fungible types only
+}
+
+func listTokenTypesJSON(ctx context.Context, config *types.WalletConfig, tw *tokenswallet.Wallet, accountNumber uint64, kind Kind) error {
+	jsonTypes := []*tokenTypeJSON{}
+	if kind == Any || kind == Fungible {
+		res, err := tw.ListFungibleTokenTypes(ctx, accountNumber)
+		if err != nil {
+			return err
+		}
+		for _, tt := range res {
+			jsonTypes = append(jsonTypes, newFungibleTokenTypeJSON(tt))
+		}
+	}
+	if kind == Any || kind == NonFungible {
+		res, err := tw.ListNonFungibleTokenTypes(ctx, accountNumber)
+		if err != nil {
+			return err
+		}
+		for _, tt := range res {
+			jsonTypes = append(jsonTypes, newNonFungibleTokenTypeJSON(tt))
+		}
+	}
+	return printJSON(config, jsonTypes)
+}
+
+func newFungibleTokenTypeJSON(tt *sdktypes.FungibleTokenType) *tokenTypeJSON {
+	decimalPlaces := tt.DecimalPlaces
+	return &tokenTypeJSON{
+		ID:            tt.ID,
+		Symbol:        tt.Symbol,
+		Name:          tt.Name,
+		Kind:          "fungible",
+		ParentTypeID:  tt.ParentTypeID,
+		DecimalPlaces: &decimalPlaces,
+	}
+}
+
+func newNonFungibleTokenTypeJSON(tt *sdktypes.NonFungibleTokenType) *tokenTypeJSON {
+	hasDataUpdatePredicate := len(tt.DataUpdatePredicate) > 0
+	return &tokenTypeJSON{
+		ID:                     tt.ID,
+		Symbol:                 tt.Symbol,
+		Name:                   tt.Name,
+		Kind:                   "nft",
+		ParentTypeID:           tt.ParentTypeID,
+		HasDataUpdatePredicate: &hasDataUpdatePredicate,
+	}
+}
+
 func tokenCmdLock(config *types.WalletConfig) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "lock",
@@ -1085,10 +1978,6 @@ func tokenCmdLock(config *types.WalletConfig) *cobra.Command {
 }
 
 func execTokenCmdLock(cmd *cobra.Command, config *types.WalletConfig) error {
-	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
-	if err != nil {
-		return err
-	}
 	tokenID, err := getHexFlag(cmd, cmdFlagTokenID)
 	if err != nil {
 		return err
@@ -1099,6 +1988,10 @@ func execTokenCmdLock(cmd *cobra.Command, config *types.WalletConfig) error {
 		return err
 	}
 	defer tw.Close()
+	accountNumber, err := accountNumberArg(cmd, tw.GetAccountManager())
+	if err != nil {
+		return err
+	}
 
 	ownerPredicateInput, err := readSinglePredicateInput(cmd, cmdFlagBearerClauseInput, accountNumber, tw.GetAccountManager())
 	if err != nil {
@@ -1107,12 +2000,14 @@ func execTokenCmdLock(cmd *cobra.Command, config *types.WalletConfig) error {
 
 	result, err := tw.LockToken(cmd.Context(), accountNumber, tokenID, ownerPredicateInput)
 	if err != nil {
-		return err
+		return ownerProofHint(err)
 	}
+	printTxIDs(config, result)
 	if result.FeeSum > 0 {
-		config.Base.ConsoleWriter.Println(fmt.Sprintf("Paid %s fees for transaction(s).", util.AmountToString(result.FeeSum, 8)))
+		config.Base.ConsoleWriter.Println(fmt.Sprintf("Paid %s fees for transaction(s).", config.FormatAmount(result.FeeSum, 8)))
 	}
-	if err := saveTxProofs(cmd, result.GetProofs(), config.Base.ConsoleWriter); err != nil {
+	printMaxFeeWarning(config, tw)
+	if err := saveTxProofs(cmd, accountNumber, result.GetProofs(), config.Base.ConsoleWriter); err != nil {
 		return fmt.Errorf("saving transaction proof(s): %w", err)
 	}
 	return nil
@@ -1127,19 +2022,19 @@ func tokenCmdUnlock(config *types.WalletConfig) *cobra.Command {
 		},
 	}
 	setHexFlag(cmd, cmdFlagTokenID, nil, "token identifier")
-	if err := cmd.MarkFlagRequired(cmdFlagTokenID); err != nil {
-		panic(err)
-	}
+	cmd.Flags().Bool(cmdFlagAll, false, "unlock all locked tokens of the account")
+	cmd.MarkFlagsOneRequired(cmdFlagTokenID, cmdFlagAll)
+	cmd.MarkFlagsMutuallyExclusive(cmdFlagTokenID, cmdFlagAll)
 	cmd.Flags().String(cmdFlagBearerClauseInput, predicatePtpkh, "input to satisfy the bearer clause. "+helpPredicateArgument)
 	return addCommonAccountFlags(cmd)
 }
 
 func execTokenCmdUnlock(cmd *cobra.Command, config *types.WalletConfig) error {
-	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
+	tokenID, err := getHexFlag(cmd, cmdFlagTokenID)
 	if err != nil {
 		return err
 	}
-	tokenID, err := getHexFlag(cmd, cmdFlagTokenID)
+	all, err := cmd.Flags().GetBool(cmdFlagAll)
 	if err != nil {
 		return err
 	}
@@ -1149,25 +2044,242 @@ func execTokenCmdUnlock(cmd *cobra.Command, config *types.WalletConfig) error {
 		return err
 	}
 	defer tw.Close()
+	accountNumber, err := accountNumberArg(cmd, tw.GetAccountManager())
+	if err != nil {
+		return err
+	}
 
 	ownerPredicateInput, err := readSinglePredicateInput(cmd, cmdFlagBearerClauseInput, accountNumber, tw.GetAccountManager())
 	if err != nil {
 		return err
 	}
 
+	if all {
+		return execTokenCmdUnlockAll(cmd, config, tw, accountNumber, ownerPredicateInput)
+	}
+
 	result, err := tw.UnlockToken(cmd.Context(), accountNumber, tokenID, ownerPredicateInput)
 	if err != nil {
-		return err
+		return ownerProofHint(err)
 	}
+	printTxIDs(config, result)
 	if result.FeeSum > 0 {
-		config.Base.ConsoleWriter.Println(fmt.Sprintf("Paid %s fees for transaction(s).", util.AmountToString(result.FeeSum, 8)))
+		config.Base.ConsoleWriter.Println(fmt.Sprintf("Paid %s fees for transaction(s).", config.FormatAmount(result.FeeSum, 8)))
 	}
-	if err := saveTxProofs(cmd, result.GetProofs(), config.Base.ConsoleWriter); err != nil {
+	printMaxFeeWarning(config, tw)
+	if err := saveTxProofs(cmd, accountNumber, result.GetProofs(), config.Base.ConsoleWriter); err != nil {
 		return fmt.Errorf("saving transaction proof(s): %w", err)
 	}
 	return err
 }
 
+func tokenCmdResume(config *types.WalletConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resume",
+		Short: "resumes interrupted multi-transaction token operations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execTokenCmdResume(cmd, config)
+		},
+	}
+	cmd.Flags().BoolP(args.PasswordPromptCmdName, "p", false, args.PasswordPromptUsage)
+	cmd.Flags().String(args.PasswordArgCmdName, "", args.PasswordArgUsage)
+	return cmd
+}
+
+func execTokenCmdResume(cmd *cobra.Command, config *types.WalletConfig) error {
+	tw, err := initTokensWallet(cmd, config)
+	if err != nil {
+		return err
+	}
+	defer tw.Close()
+
+	results, err := tw.ResumeTokenOperations(cmd.Context())
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		config.Base.ConsoleWriter.Println("No interrupted token operations")
+		return nil
+	}
+	for _, res := range results {
+		config.Base.ConsoleWriter.Println(fmt.Sprintf("Operation %s (%s) of account #%d:", res.Operation.ID, res.Operation.Kind, res.Operation.AccountNumber))
+		for _, sub := range res.Submissions {
+			if sub.Confirmed() && !sub.Success() {
+				config.Base.ConsoleWriter.Println(fmt.Sprintf("  Transaction ID: %s failed", sub.TxID()))
+				continue
+			}
+			config.Base.ConsoleWriter.Println(fmt.Sprintf("  Transaction ID: %s confirmed", sub.TxID()))
+		}
+		for _, unitID := range res.Skipped {
+			config.Base.ConsoleWriter.Println(fmt.Sprintf("  Unit %s has changed, transaction not re-sent", unitID))
+		}
+		if res.Err != nil {
+			config.Base.ConsoleWriter.Println(fmt.Sprintf("  Failed to resume: %v", res.Err))
+		}
+	}
+	return nil
+}
+
+func tokenCmdExportProofs(config *types.WalletConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-proofs",
+		Short: "exports the transaction proofs of the tokens and token types of the account to a CBOR file",
+		Long: "Exports the proofs of the transactions of the tokens owned by the account and of the token types " +
+			"created by the account to a file as a CBOR array. The proofs are collected by scanning the blocks " +
+			"of the partition, use the since flag to limit the scan to recent rounds.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execTokenCmdExportProofs(cmd, config)
+		},
+	}
+	cmd.Flags().Uint64P(args.KeyCmdName, "k", 1, "which account proofs to export")
+	cmd.Flags().String(cmdFlagOutput, "", "output file name")
+	cmd.Flags().Uint64(cmdFlagSince, 1, "round number to start scanning the blocks from")
+	if err := cmd.MarkFlagRequired(cmdFlagOutput); err != nil {
+		panic(err)
+	}
+	cmd.Flags().BoolP(args.PasswordPromptCmdName, "p", false, args.PasswordPromptUsage)
+	cmd.Flags().String(args.PasswordArgCmdName, "", args.PasswordArgUsage)
+	return cmd
+}
+
+func execTokenCmdExportProofs(cmd *cobra.Command, config *types.WalletConfig) error {
+	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
+	if err != nil {
+		return err
+	}
+	outputPath, err := cmd.Flags().GetString(cmdFlagOutput)
+	if err != nil {
+		return err
+	}
+	since, err := cmd.Flags().GetUint64(cmdFlagSince)
+	if err != nil {
+		return err
+	}
+	format, err := proofFormatArg(cmd)
+	if err != nil {
+		return err
+	}
+
+	tw, err := initTokensWallet(cmd, config)
+	if err != nil {
+		return err
+	}
+	defer tw.Close()
+
+	proofs, err := tw.GetAccountHistory(cmd.Context(), accountNumber, since)
+	if err != nil {
+		return err
+	}
+	if proofs == nil {
+		proofs = []*basetypes.TxRecordProof{}
+	}
+	w, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("creating file for transaction proofs: %w", err)
+	}
+	defer w.Close()
+	if err := encodeTxProofs(w, proofs, format); err != nil {
+		return err
+	}
+	config.Base.ConsoleWriter.Println(fmt.Sprintf("Exported %d transaction proof(s) to file: %s", len(proofs), outputPath))
+	return nil
+}
+
+func tokenCmdVerifyProof(config *types.WalletConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify-proof",
+		Short: "verifies the transaction proofs saved to a file",
+		Long: "Verifies each transaction proof of the file against the root trust base of the network, ie that " +
+			"the unicity certificate of the proof is signed by the root validators and that the transaction is " +
+			"included in the certified block and was executed successfully. Both the CBOR and JSON proof files " +
+			"are accepted, with or without the metadata of the operation.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execTokenCmdVerifyProof(cmd, config)
+		},
+	}
+	cmd.Flags().String(cmdFlagFile, "", "file of the transaction proofs")
+	cmd.Flags().String(cmdFlagTrustBaseFile, "", "root trust base file of the network")
+	if err := cmd.MarkFlagRequired(cmdFlagFile); err != nil {
+		panic(err)
+	}
+	if err := cmd.MarkFlagRequired(cmdFlagTrustBaseFile); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func execTokenCmdVerifyProof(cmd *cobra.Command, config *types.WalletConfig) error {
+	proofFile, err := cmd.Flags().GetString(cmdFlagFile)
+	if err != nil {
+		return err
+	}
+	trustBaseFile, err := cmd.Flags().GetString(cmdFlagTrustBaseFile)
+	if err != nil {
+		return err
+	}
+	trustBase, err := basetypes.NewTrustBaseFromFile(trustBaseFile)
+	if err != nil {
+		return fmt.Errorf("loading root trust base: %w", err)
+	}
+	archive, err := loadTxProofs(proofFile)
+	if err != nil {
+		return err
+	}
+	return verifyTxProofs(config.Base.ConsoleWriter, archive.Proofs, trustBase)
+}
+
+// verifyTxProofs verifies the proofs against the trust base and prints the result of each proof,
+// an error is returned when any of the proofs is not valid.
+func verifyTxProofs(out types.ConsoleWrapper, proofs []*basetypes.TxRecordProof, trustBase basetypes.RootTrustBase) error {
+	if len(proofs) == 0 {
+		return errors.New("no transaction proofs in the file")
+	}
+	var invalid int
+	for i, proof := range proofs {
+		txID := "<unknown>"
+		if tx, err := proof.GetTransactionOrderV1(); err == nil {
+			if txHash, err := tx.Hash(crypto.SHA256); err == nil {
+				txID = util.FormatTxID(txHash)
+			}
+		}
+		if err := basetypes.VerifyTxProof(proof, trustBase, crypto.SHA256); err != nil {
+			invalid++
+			out.Println(fmt.Sprintf("Proof #%d of transaction %s is invalid: %v", i+1, txID, err))
+			continue
+		}
+		out.Println(fmt.Sprintf("Proof #%d of transaction %s is valid", i+1, txID))
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d transaction proof(s) are invalid", invalid, len(proofs))
+	}
+	return nil
+}
+
+func execTokenCmdUnlockAll(cmd *cobra.Command, config *types.WalletConfig, tw *tokenswallet.Wallet, accountNumber uint64, ownerPredicateInput *tokenswallet.PredicateInput) error {
+	results, unlockErr := tw.UnlockAllTokens(cmd.Context(), accountNumber, ownerPredicateInput)
+	if len(results) == 0 && unlockErr == nil {
+		config.Base.ConsoleWriter.Println("No locked tokens")
+		return nil
+	}
+
+	var feeSum uint64
+	var proofs []*basetypes.TxRecordProof
+	for _, result := range results {
+		feeSum += result.FeeSum
+		proofs = append(proofs, result.GetProofs()...)
+		config.Base.ConsoleWriter.Println(fmt.Sprintf("Unlock transaction sent for token %s", result.GetUnit()))
+		printTxIDs(config, result)
+	}
+	if feeSum > 0 {
+		config.Base.ConsoleWriter.Println(fmt.Sprintf("Paid %s fees for transaction(s).", config.FormatAmount(feeSum, 8)))
+	}
+	printMaxFeeWarning(config, tw)
+	if err := saveTxProofs(cmd, accountNumber, proofs, config.Base.ConsoleWriter); err != nil {
+		return fmt.Errorf("saving transaction proof(s): %w", err)
+	}
+	return unlockErr
+}
+
 func initTokensWallet(cmd *cobra.Command, config *types.WalletConfig) (*tokenswallet.Wallet, error) {
 	rpcUrl, err := cmd.Flags().GetString(args.RpcUrl)
 	if err != nil {
@@ -1190,7 +2302,39 @@ func initTokensWallet(cmd *cobra.Command, config *types.WalletConfig) (*tokenswa
 		return nil, fmt.Errorf("failed to dial rpc client: %w", err)
 	}
 
-	return tokenswallet.New(tokensClient, am, confirmTx, nil, maxFee, config.Base.Logger)
+	dryRun, err := cmd.Flags().GetBool(cmdFlagDryRun)
+	if err != nil {
+		return nil, err
+	}
+	timeoutRounds, err := cmd.Flags().GetUint64(cmdFlagTimeoutRounds)
+	if err != nil {
+		return nil, err
+	}
+	confirmTimeout, err := cmd.Flags().GetDuration(cmdFlagConfirmTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if confirmTimeout < 0 {
+		return nil, fmt.Errorf("invalid parameter \"%s\" for \"--%s\": must not be negative", confirmTimeout, cmdFlagConfirmTimeout)
+	}
+
+	opStore, err := tokenswallet.NewOperationDB(config.WalletHomeDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open token operation db: %w", err)
+	}
+	opts := []tokenswallet.Option{tokenswallet.WithOperationStore(opStore), tokenswallet.WithTimeoutRounds(timeoutRounds), tokenswallet.WithConfirmTimeout(confirmTimeout)}
+	if dryRun {
+		opts = append(opts, tokenswallet.WithDryRun())
+	}
+	tw, err := tokenswallet.New(tokensClient, am, confirmTx, nil, maxFee, config.Base.Logger, opts...)
+	if err != nil {
+		_ = opStore.Close()
+		if errors.Is(err, tokenswallet.ErrInvalidMaxFee) {
+			return nil, fmt.Errorf("invalid parameter for \"--%s\": %w", args.MaxFeeFlagName, err)
+		}
+		return nil, err
+	}
+	return tw, nil
 }
 
 func readParentTypeInfo(cmd *cobra.Command, keyNr uint64, am account.Manager) (sdktypes.TokenTypeID, []*tokenswallet.PredicateInput, error) {
@@ -1273,7 +2417,7 @@ func parsePredicateClauseCmd(cmd *cobra.Command, flag string, keyNr uint64, am a
 	return buf, nil
 }
 
-func readNFTData(cmd *cobra.Command, required bool) ([]byte, error) {
+func readNFTData(cmd *cobra.Command, required bool, log *slog.Logger) ([]byte, error) {
 	if required && !cmd.Flags().Changed(cmdFlagTokenData) && !cmd.Flags().Changed(cmdFlagTokenDataFile) {
 		return nil, fmt.Errorf("either of ['--%s', '--%s'] flags must be specified", cmdFlagTokenData, cmdFlagTokenDataFile)
 	}
@@ -1286,10 +2430,12 @@ func readNFTData(cmd *cobra.Command, required bool) ([]byte, error) {
 		return nil, err
 	}
 	if len(dataFilePath) > 0 {
-		data, err = readFile(dataFilePath, cmdFlagTokenDataFile, maxBinaryFile64KiB)
+		var hash []byte
+		data, hash, err = readFileChunked(dataFilePath, cmdFlagTokenDataFile, maxBinaryFile64KiB)
 		if err != nil {
 			return nil, err
 		}
+		log.Debug("read NFT data file", "path", dataFilePath, "size", len(data), "sha256", fmt.Sprintf("%X", hash))
 	}
 	return data, nil
 }
@@ -1331,18 +2477,40 @@ func readIconFile(iconFilePath string) (*tokens.Icon, error) {
 }
 
 func readFile(path string, flag string, sizeLimit int64) ([]byte, error) {
+	data, _, err := readFileChunked(path, flag, sizeLimit)
+	return data, err
+}
+
+/*
+readFileChunked reads the file in chunks and returns its content and SHA256 hash. Files over
+the size limit are rejected based on the file size before reading, and while reading in case the
+file has grown or the file system does not report the size (ie named pipes), so that the memory
+use is bounded by the size limit.
+*/
+func readFileChunked(path string, flag string, sizeLimit int64) ([]byte, []byte, error) {
 	size, err := getFileSize(path)
 	if err != nil {
-		return nil, fmt.Errorf("%s read error: %w", flag, err)
+		return nil, nil, fmt.Errorf("%s read error: %w", flag, err)
 	}
 	if size > sizeLimit {
-		return nil, fmt.Errorf("%s read error: file size over %vKiB limit", flag, sizeLimit/1024)
+		return nil, nil, fmt.Errorf("%s read error: file size over %vKiB limit", flag, sizeLimit/1024)
 	}
-	data, err := os.ReadFile(path)
+	f, err := os.Open(path)
 	if err != nil {
-		return nil, fmt.Errorf("%s read error: %w", flag, err)
+		return nil, nil, fmt.Errorf("%s read error: %w", flag, err)
 	}
-	return data, nil
+	defer f.Close()
+
+	buf := bytes.NewBuffer(make([]byte, 0, size))
+	hasher := sha256.New()
+	n, err := io.CopyBuffer(io.MultiWriter(buf, hasher), io.LimitReader(f, sizeLimit+1), make([]byte, fileReadChunkSize))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s read error: %w", flag, err)
+	}
+	if n > sizeLimit {
+		return nil, nil, fmt.Errorf("%s read error: file size over %vKiB limit", flag, sizeLimit/1024)
+	}
+	return buf.Bytes(), hasher.Sum(nil), nil
 }
 
 func getFileSize(filepath string) (int64, error) {
@@ -1354,10 +2522,69 @@ func getFileSize(filepath string) (int64, error) {
 	return fi.Size(), nil
 }
 
+// ownerProofHint points the user to the bearer clause input flag when the token
+// has a custom owner predicate and the owner proof was not provided.
+func ownerProofHint(err error) error {
+	if errors.Is(err, tokenswallet.ErrOwnerProofRequired) {
+		return fmt.Errorf("%w, provide it using the --%s flag", err, cmdFlagBearerClauseInput)
+	}
+	return err
+}
+
+// parseTokenAmount converts the amount string to the amount of the token with the given number of
+// decimal places.
+func parseTokenAmount(amountStr string, decimals uint32) (uint64, error) {
+	amount, err := util.StringToAmount(amountStr, decimals)
+	if errors.Is(err, util.ErrInvalidPrecision) {
+		return 0, fmt.Errorf("amount '%s' has more than %d decimal places allowed by token type", amountStr, decimals)
+	}
+	return amount, err
+}
+
+// printTxIDs prints the IDs of the transactions of the submission result, the transactions which
+// were confirmed but failed are reported too. In dry run mode the size of the transactions and
+// their max fee are printed.
+func printTxIDs(config *types.WalletConfig, result *tokenswallet.SubmissionResult) {
+	out := config.Base.ConsoleWriter
+	if !result.DryRun {
+		for _, txID := range result.TxIDs() {
+			out.Println(fmt.Sprintf("Transaction ID: %s", txID))
+		}
+		printFailedTxs(config, result)
+		return
+	}
+	for _, sub := range result.Submissions {
+		size, err := sdktypes.EncodedSize(sub.Transaction)
+		if err != nil {
+			out.Println(fmt.Sprintf("Transaction ID: %s", sub.TxID()))
+			continue
+		}
+		out.Println(fmt.Sprintf("Transaction ID: %s (%d bytes)", sub.TxID(), size))
+	}
+	out.Println(fmt.Sprintf("Dry run, transaction(s) not sent. Max fee: %s", config.FormatAmount(result.MaxFeeSum(), 8)))
+}
+
+// printFailedTxs prints the transactions of the result which were confirmed with a failed status,
+// the fee of such transactions is charged while the transaction had no other effect.
+func printFailedTxs(config *types.WalletConfig, result *tokenswallet.SubmissionResult) {
+	for _, sub := range result.FailedSubmissions() {
+		config.Base.ConsoleWriter.Println(fmt.Sprintf("Transaction %s failed, paid %s fees", sub.TxID(), config.FormatAmount(sub.Proof.ActualFee(), 8)))
+	}
+}
+
+// printMaxFeeWarning prints a warning when the transactions confirmed by the wallet have used up
+// the whole max fee, ie the following transactions are likely to fail.
+func printMaxFeeWarning(config *types.WalletConfig, tw *tokenswallet.Wallet) {
+	if ok, observedMax := tw.CheckMaxFee(); !ok {
+		config.Base.ConsoleWriter.Println(fmt.Sprintf("Warning: recent transactions used up the max fee (%s), consider increasing the max fee", config.FormatAmount(observedMax, 8)))
+	}
+}
+
 /*
-saveTxProofs saves the tx proofs into file when the cmd has appropriate flag set.
+saveTxProofs saves the tx proofs into file when the cmd has appropriate flag set. The accountNumber
+is the account the transactions were sent from, it is recorded in the metadata of the proofs.
 */
-func saveTxProofs(cmd *cobra.Command, proofs []*basetypes.TxRecordProof, out types.ConsoleWrapper) error {
+func saveTxProofs(cmd *cobra.Command, accountNumber uint64, proofs []*basetypes.TxRecordProof, out types.ConsoleWrapper) error {
 	_, proofFile, err := args.WaitForProofArg(cmd)
 	if err != nil {
 		return err
@@ -1366,17 +2593,98 @@ func saveTxProofs(cmd *cobra.Command, proofs []*basetypes.TxRecordProof, out typ
 		return nil
 	}
 
+	withMetadata, err := cmd.Flags().GetBool(cmdFlagProofMetadata)
+	if err != nil {
+		return err
+	}
+	format, err := proofFormatArg(cmd)
+	if err != nil {
+		return err
+	}
+
 	w, err := os.Create(proofFile)
 	if err != nil {
 		return fmt.Errorf("creating file for transaction proofs: %w", err)
 	}
+	defer w.Close()
+	if withMetadata {
+		archive, err := newProofArchive(cmd, accountNumber, proofs)
+		if err != nil {
+			return err
+		}
+		encode := tokenswallet.EncodeProofArchive
+		if format == proofFormatJSON {
+			encode = tokenswallet.EncodeProofArchiveJSON
+		}
+		if err := encode(w, archive); err != nil {
+			return err
+		}
+	} else if err := encodeTxProofs(w, proofs, format); err != nil {
+		return err
+	}
+	out.Println("Transaction proof(s) saved to file:" + proofFile)
+	return nil
+}
+
+// loadTxProofs reads the tx proofs saved by saveTxProofs from the file, both the CBOR and JSON
+// formats are accepted. The bare list of proofs is returned as an archive without the metadata.
+func loadTxProofs(filename string) (*tokenswallet.ProofArchive, error) {
+	f, err := os.Open(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf("opening transaction proofs file: %w", err)
+	}
+	defer f.Close()
+	return tokenswallet.DecodeProofArchive(f)
+}
+
+// proofFormatArg returns the value of the "proof-format" flag.
+func proofFormatArg(cmd *cobra.Command) (string, error) {
+	format, err := cmd.Flags().GetString(cmdFlagProofFormat)
+	if err != nil {
+		return "", err
+	}
+	switch format {
+	case proofFormatCBOR, proofFormatJSON:
+		return format, nil
+	}
+	return "", fmt.Errorf("invalid value %q for flag %q, must be one of: %s, %s", format, cmdFlagProofFormat, proofFormatCBOR, proofFormatJSON)
+}
+
+// encodeTxProofs writes the bare list of tx proofs to w in the given format.
+func encodeTxProofs(w io.Writer, proofs []*basetypes.TxRecordProof, format string) error {
+	if format == proofFormatJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(proofs); err != nil {
+			return fmt.Errorf("encoding transaction proofs as JSON: %w", err)
+		}
+		return nil
+	}
 	if err := basetypes.Cbor.Encode(w, proofs); err != nil {
 		return fmt.Errorf("encoding transaction proofs as CBOR: %w", err)
 	}
-	out.Println("Transaction proof(s) saved to file:" + proofFile)
 	return nil
 }
 
+// newProofArchive returns archive of the proofs sent from the given account with the metadata of
+// the operation taken from the flags of the command.
+func newProofArchive(cmd *cobra.Command, accountNumber uint64, proofs []*basetypes.TxRecordProof) (*tokenswallet.ProofArchive, error) {
+	archive, err := tokenswallet.NewProofArchive(cmd.CommandPath(), time.Now().Unix(), proofs)
+	if err != nil {
+		return nil, err
+	}
+	archive.AccountNumber = accountNumber
+	if f := cmd.Flags().Lookup(cmdFlagType); f != nil {
+		if typeID, ok := f.Value.(*types.BytesHex); ok {
+			archive.TypeID = sdktypes.TokenTypeID(*typeID)
+		}
+	}
+	if f := cmd.Flags().Lookup(cmdFlagAmount); f != nil {
+		archive.Amount = f.Value.String()
+	}
+	return archive, nil
+}
+
 func (kind Kind) String() string {
 	switch kind {
 	case Any:
@@ -1,25 +1,36 @@
 package tokens
 
 import (
+	"crypto"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 
+	testsig "github.com/alphabill-org/alphabill-go-base/testutils/sig"
+	basetypes "github.com/alphabill-org/alphabill-go-base/types"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
 
 	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/testutils"
 	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/types"
 	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/wallet/args"
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"github.com/alphabill-org/alphabill-wallet/util"
 )
 
 func TestListTokensCommandInputs(t *testing.T) {
 	tests := []struct {
-		name          string
-		args          []string
-		accountNumber uint64
-		expectedKind  Kind
-		expectedPass  string
-		expectedFlags []string
+		name             string
+		args             []string
+		accountNumber    uint64
+		expectedKind     Kind
+		expectedPass     string
+		expectedFlags    []string
+		expectedStrFlags map[string]string
+		expectedTypeID   []byte
 	}{
 		{
 			name:          "list all tokens",
@@ -33,6 +44,12 @@ func TestListTokensCommandInputs(t *testing.T) {
 			expectedKind:  Any,
 			expectedFlags: []string{cmdFlagWithAll, cmdFlagWithTypeName, cmdFlagWithTokenURI, cmdFlagWithTokenData},
 		},
+		{
+			name:          "list all tokens and validate types",
+			args:          []string{"--validate-types"},
+			expectedKind:  Any,
+			expectedFlags: []string{cmdFlagValidateTypes},
+		},
 		{
 			name:          "list all tokens, encrypted wallet",
 			args:          []string{"--pn", "some pass phrase"},
@@ -71,6 +88,12 @@ func TestListTokensCommandInputs(t *testing.T) {
 			expectedKind:  Fungible,
 			expectedFlags: []string{cmdFlagWithAll, cmdFlagWithTypeName},
 		},
+		{
+			name:          "list all fungible tokens and validate types",
+			args:          []string{"fungible", "--validate-types"},
+			expectedKind:  Fungible,
+			expectedFlags: []string{cmdFlagValidateTypes},
+		},
 		{
 			name:          "list all non-fungible tokens",
 			args:          []string{"non-fungible"},
@@ -83,6 +106,31 @@ func TestListTokensCommandInputs(t *testing.T) {
 			expectedKind:  NonFungible,
 			expectedFlags: []string{cmdFlagWithAll, cmdFlagWithTypeName, cmdFlagWithTokenURI, cmdFlagWithTokenData},
 		},
+		{
+			name:             "list all tokens as json",
+			args:             []string{"--output", "json"},
+			expectedKind:     Any,
+			expectedStrFlags: map[string]string{cmdFlagOutput: outputJSON},
+		},
+		{
+			name:             "list all non-fungible tokens with filter",
+			args:             []string{"non-fungible", "--name-prefix", "cat", "--symbol", "CAT"},
+			expectedKind:     NonFungible,
+			expectedStrFlags: map[string]string{cmdFlagNamePrefix: "cat", cmdFlagSymbol: "CAT"},
+		},
+		{
+			name:           "list all tokens of type",
+			args:           []string{"--type", "0x0102"},
+			expectedKind:   Any,
+			expectedTypeID: []byte{1, 2},
+		},
+		{
+			name:           "list account fungible tokens of type",
+			args:           []string{"fungible", "--key", "4", "--type", "0A"},
+			accountNumber:  4,
+			expectedKind:   Fungible,
+			expectedTypeID: []byte{0x0a},
+		},
 		{
 			name:          "list account non-fungible tokens",
 			args:          []string{"non-fungible", "--key", "5"},
@@ -115,6 +163,14 @@ func TestListTokensCommandInputs(t *testing.T) {
 						require.True(t, flagValue)
 					}
 				}
+				for flag, expected := range tt.expectedStrFlags {
+					flagValue, err := cmd.Flags().GetString(flag)
+					require.NoError(t, err)
+					require.Equal(t, expected, flagValue)
+				}
+				typeID, err := getHexFlag(cmd, cmdFlagType)
+				require.NoError(t, err)
+				require.EqualValues(t, tt.expectedTypeID, typeID)
 				exec = true
 				return nil
 			})
@@ -126,13 +182,57 @@ func TestListTokensCommandInputs(t *testing.T) {
 	}
 }
 
+func TestTokenJSON(t *testing.T) {
+	ft := &sdktypes.FungibleToken{ID: []byte{1}, Symbol: "AB", TypeID: []byte{2}, Amount: 1234567, DecimalPlaces: 2, LockStatus: 0}
+	data, err := json.Marshal(newFungibleTokenJSON(1, ft))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"accountNumber":1,"kind":"fungible","id":"0x01","symbol":"AB","typeId":"0x02","amount":"1234567","amountFormatted":"12345.67","decimalPlaces":2,"lockStatus":0,"lockReason":"unlocked"}`, string(data))
+
+	nft := &sdktypes.NonFungibleToken{ID: []byte{3}, Symbol: "NFT", Name: "cat", TypeID: []byte{4}, URI: "https://cat", Data: []byte{5}, LockStatus: 1}
+	data, err = json.Marshal(newNonFungibleTokenJSON(2, nft))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"accountNumber":2,"kind":"nft","id":"0x03","symbol":"NFT","name":"cat","typeId":"0x04","lockStatus":1,"lockReason":"locked for adding fees","uri":"https://cat","data":"0x05"}`, string(data))
+}
+
+func TestTokenTypeJSON(t *testing.T) {
+	ft := &sdktypes.FungibleTokenType{ID: []byte{1}, ParentTypeID: []byte{2}, Symbol: "AB", Name: "Alphabill", DecimalPlaces: 0}
+	data, err := json.Marshal(newFungibleTokenTypeJSON(ft))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":"0x01","symbol":"AB","name":"Alphabill","kind":"fungible","parentTypeId":"0x02","decimalPlaces":0}`, string(data))
+
+	nft := &sdktypes.NonFungibleTokenType{ID: []byte{3}, Symbol: "NFT", DataUpdatePredicate: []byte{0x83, 0x00, 0x41, 0x01, 0xf6}}
+	data, err = json.Marshal(newNonFungibleTokenTypeJSON(nft))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":"0x03","symbol":"NFT","kind":"nft","hasDataUpdatePredicate":true}`, string(data))
+}
+
+func TestGetJSONOutputFlag(t *testing.T) {
+	newCmd := func(value string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String(cmdFlagOutput, outputText, "")
+		require.NoError(t, cmd.Flags().Set(cmdFlagOutput, value))
+		return cmd
+	}
+	jsonOutput, err := getJSONOutputFlag(newCmd("text"))
+	require.NoError(t, err)
+	require.False(t, jsonOutput)
+
+	jsonOutput, err = getJSONOutputFlag(newCmd("json"))
+	require.NoError(t, err)
+	require.True(t, jsonOutput)
+
+	_, err = getJSONOutputFlag(newCmd("yaml"))
+	require.ErrorContains(t, err, `invalid value "yaml" for flag "output"`)
+}
+
 func TestListTokensTypesCommandInputs(t *testing.T) {
 	tests := []struct {
-		name          string
-		args          []string
-		expectedAccNr uint64
-		expectedKind  Kind
-		expectedPass  string
+		name           string
+		args           []string
+		expectedAccNr  uint64
+		expectedKind   Kind
+		expectedPass   string
+		expectedOutput string
 	}{
 		{
 			name:         "list all tokens",
@@ -171,6 +271,12 @@ func TestListTokensTypesCommandInputs(t *testing.T) {
 			expectedPass:  "test pass phrase",
 			expectedAccNr: 2,
 		},
+		{
+			name:           "list all non-fungible types as json",
+			args:           []string{"non-fungible", "--output", "json"},
+			expectedKind:   NonFungible,
+			expectedOutput: outputJSON,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -178,6 +284,11 @@ func TestListTokensTypesCommandInputs(t *testing.T) {
 			cmd := tokenCmdListTypes(&types.WalletConfig{}, func(cmd *cobra.Command, config *types.WalletConfig, accountNumber *uint64, kind Kind) error {
 				require.Equal(t, tt.expectedAccNr, *accountNumber)
 				require.Equal(t, tt.expectedKind, kind)
+				if tt.expectedOutput != "" {
+					output, err := cmd.Flags().GetString(cmdFlagOutput)
+					require.NoError(t, err)
+					require.Equal(t, tt.expectedOutput, output)
+				}
 				if len(tt.expectedPass) != 0 {
 					passwordFromArg, err := cmd.Flags().GetString(args.PasswordArgCmdName)
 					require.NoError(t, err)
@@ -306,6 +417,15 @@ func TestWalletTokenCollectDustCmd_Flags(t *testing.T) {
 			name: "ok",
 			args: []string{"--type", "123456789abcdef"},
 		},
+		{
+			name: "max batch",
+			args: []string{"--type", "123456789abcdef", "--max-batch", "5"},
+		},
+		{
+			name:    "invalid max batch",
+			args:    []string{"--type", "123456789abcdef", "--max-batch", "five"},
+			wantErr: "invalid argument \"five\" for \"--max-batch\" flag",
+		},
 		{
 			name:    "type flag is required",
 			args:    []string{},
@@ -328,3 +448,332 @@ func TestWalletTokenCollectDustCmd_Flags(t *testing.T) {
 		})
 	}
 }
+
+func TestWalletTokenShowCmd_Flags(t *testing.T) {
+	tokensCmd := testutils.NewSubCmdExecutor(NewTokenCmd, "show")
+	tokensCmd.ExecWithError(t, "required flag(s) \"token-identifier\" not set", "--resolve-metadata")
+}
+
+func TestWalletTokenDescribeTypeCmd_Flags(t *testing.T) {
+	tokensCmd := testutils.NewSubCmdExecutor(NewTokenCmd, "describe-type")
+	tokensCmd.ExecWithError(t, "required flag(s) \"type\" not set")
+	tokensCmd.ExecWithError(t, "invalid argument \"foo\" for \"--type\" flag", "--type", "foo")
+}
+
+func TestWalletTokenIconCmd_Flags(t *testing.T) {
+	tokensCmd := testutils.NewSubCmdExecutor(NewTokenCmd, "icon")
+	tokensCmd.ExecWithError(t, "required flag(s) \"output\", \"type\" not set")
+	tokensCmd.ExecWithError(t, "required flag(s) \"output\" not set", "--type", "01")
+
+	tokensCmd = testutils.NewSubCmdExecutor(NewTokenCmd, "get-icon")
+	tokensCmd.ExecWithError(t, "required flag(s) \"output\", \"type\" not set")
+}
+
+func TestWalletTokenExportProofsCmd_Flags(t *testing.T) {
+	tokensCmd := testutils.NewSubCmdExecutor(NewTokenCmd, "export-proofs")
+	tokensCmd.ExecWithError(t, "required flag(s) \"output\" not set")
+	tokensCmd.ExecWithError(t, "invalid argument \"foo\" for \"--since\" flag", "--output", "proofs.cbor", "--since", "foo")
+}
+
+func TestWalletTokenVerifyProofCmd_Flags(t *testing.T) {
+	tokensCmd := testutils.NewSubCmdExecutor(NewTokenCmd, "verify-proof")
+	tokensCmd.ExecWithError(t, "required flag(s) \"file\", \"trust-base-file\" not set")
+	tokensCmd.ExecWithError(t, "loading root trust base", "--file", "proofs.cbor", "--trust-base-file", filepath.Join(t.TempDir(), "missing.json"))
+}
+
+func TestVerifyTxProofs(t *testing.T) {
+	out := &testutils.TestConsoleWriter{}
+	require.EqualError(t, verifyTxProofs(out, nil, nil), "no transaction proofs in the file")
+
+	tx := &basetypes.TransactionOrder{Version: 1, Payload: basetypes.Payload{
+		PartitionID:    2,
+		UnitID:         []byte{1, 2, 3},
+		ClientMetadata: &basetypes.ClientMetadata{Timeout: 10},
+	}}
+	txBytes, err := tx.MarshalCBOR()
+	require.NoError(t, err)
+	txHash, err := tx.Hash(crypto.SHA256)
+	require.NoError(t, err)
+	proofs := []*basetypes.TxRecordProof{{
+		TxRecord: &basetypes.TransactionRecord{Version: 1, TransactionOrder: txBytes, ServerMetadata: &basetypes.ServerMetadata{ActualFee: 1}},
+	}}
+
+	err = verifyTxProofs(out, proofs, nil)
+	require.EqualError(t, err, "1 of 1 transaction proof(s) are invalid")
+	testutils.VerifyStdout(t, out, fmt.Sprintf("Proof #1 of transaction %s is invalid: verify tx inclusion: transaction proof is nil", util.FormatTxID(txHash)))
+
+	proof, trustBase := newSignedTxProof(t, tx)
+	out = &testutils.TestConsoleWriter{}
+	require.NoError(t, verifyTxProofs(out, []*basetypes.TxRecordProof{proof}, trustBase))
+	testutils.VerifyStdout(t, out, fmt.Sprintf("Proof #1 of transaction %s is valid", util.FormatTxID(txHash)))
+}
+
+// newSignedTxProof returns the proof of the successful transaction in a block certified by the
+// single validator of the returned trust base.
+func newSignedTxProof(t *testing.T, tx *basetypes.TransactionOrder) (*basetypes.TxRecordProof, basetypes.RootTrustBase) {
+	t.Helper()
+	txBytes, err := tx.MarshalCBOR()
+	require.NoError(t, err)
+	uc, err := (&basetypes.UnicityCertificate{Version: 1, InputRecord: &basetypes.InputRecord{
+		Version:      1,
+		PreviousHash: []byte{0, 0, 1},
+		Hash:         []byte{0, 0, 2},
+		SummaryValue: []byte{0, 0, 3},
+		RoundNumber:  1,
+		Timestamp:    basetypes.NewTimestamp(),
+	}}).MarshalCBOR()
+	require.NoError(t, err)
+	block := &basetypes.Block{
+		Header: &basetypes.Header{Version: 1, PartitionID: tx.PartitionID, ProposerID: "test", PreviousBlockHash: []byte{1}},
+		Transactions: []*basetypes.TransactionRecord{{
+			Version:          1,
+			TransactionOrder: txBytes,
+			ServerMetadata:   &basetypes.ServerMetadata{ActualFee: 1, SuccessIndicator: basetypes.TxStatusSuccessful},
+		}},
+		UnicityCertificate: uc,
+	}
+	ir, err := block.CalculateBlockHash(crypto.SHA256)
+	require.NoError(t, err)
+
+	// certify the block with a unicity certificate signed by the root node "test"
+	trHash := make([]byte, 32)
+	shardTree, err := basetypes.CreateShardTree(basetypes.ShardingScheme{}, []basetypes.ShardTreeInput{{IR: ir, TRHash: trHash}}, crypto.SHA256)
+	require.NoError(t, err)
+	shardCert, err := shardTree.Certificate(basetypes.ShardID{})
+	require.NoError(t, err)
+	pdrHash, err := (&basetypes.PartitionDescriptionRecord{Version: 1, PartitionID: tx.PartitionID}).Hash(crypto.SHA256)
+	require.NoError(t, err)
+	unicityTree, err := basetypes.NewUnicityTree(crypto.SHA256, []*basetypes.UnicityTreeData{{
+		Partition:     tx.PartitionID,
+		ShardTreeRoot: shardTree.RootHash(),
+		PDRHash:       pdrHash,
+	}})
+	require.NoError(t, err)
+	unicityTreeCert, err := unicityTree.Certificate(tx.PartitionID)
+	require.NoError(t, err)
+	seal := &basetypes.UnicitySeal{
+		Version:              1,
+		RootChainRoundNumber: 1,
+		Timestamp:            basetypes.NewTimestamp(),
+		PreviousHash:         make([]byte, 32),
+		Hash:                 unicityTree.RootHash(),
+	}
+	signer, verifier := testsig.CreateSignerAndVerifier(t)
+	require.NoError(t, seal.Sign("test", signer))
+	block.UnicityCertificate, err = (&basetypes.UnicityCertificate{
+		Version:                1,
+		InputRecord:            ir,
+		TRHash:                 trHash,
+		ShardTreeCertificate:   shardCert,
+		UnicityTreeCertificate: unicityTreeCert,
+		UnicitySeal:            seal,
+	}).MarshalCBOR()
+	require.NoError(t, err)
+
+	proof, err := basetypes.NewTxRecordProof(block, 0, crypto.SHA256)
+	require.NoError(t, err)
+	trustBase, err := basetypes.NewTrustBaseGenesis([]*basetypes.NodeInfo{basetypes.NewNodeInfo("test", 1, verifier)}, []byte{1})
+	require.NoError(t, err)
+	return proof, trustBase
+}
+
+func TestWalletTokenWatchCmd_Flags(t *testing.T) {
+	tokensCmd := testutils.NewSubCmdExecutor(NewTokenCmd, "watch")
+	tokensCmd.ExecWithError(t, "invalid argument \"foo\" for \"--interval\" flag", "--interval", "foo")
+}
+
+func TestIconFileExt(t *testing.T) {
+	ext, err := iconFileExt("image/png")
+	require.NoError(t, err)
+	require.Equal(t, ".png", ext)
+
+	ext, err = iconFileExt("image/jpeg")
+	require.NoError(t, err)
+	require.Equal(t, ".jpeg", ext)
+
+	ext, err = iconFileExt("image/svg+xml")
+	require.NoError(t, err)
+	require.Equal(t, ".svg", ext)
+
+	ext, err = iconFileExt(iconFileExtSvgzType)
+	require.NoError(t, err)
+	require.Equal(t, iconFileExtSvgz, ext)
+
+	_, err = iconFileExt("application/x-unknown-icon")
+	require.ErrorContains(t, err, "could not determine file extension")
+
+	_, err = iconFileExt("")
+	require.ErrorContains(t, err, "invalid icon MIME type")
+}
+
+func TestWalletTokenSendNonFungibleCmd_Flags(t *testing.T) {
+	tokensCmd := testutils.NewSubCmdExecutor(NewTokenCmd, "send", "non-fungible")
+	tokensCmd.ExecWithError(t, "at least one of the flags in the group [token-identifier transfers-file address-file] is required")
+	tokensCmd.ExecWithError(t, "one of the flags \"address\" or \"address-hash\" must be set with \"token-identifier\"", "--token-identifier", "01")
+	tokensCmd.ExecWithError(t, "if any flags in the group [address address-hash] are set none of the others can be", "--token-identifier", "01", "--address", "0x01", "--address-hash", "01")
+	tokensCmd.ExecWithError(t, "if any flags in the group [address-hash transfers-file address-file] are set none of the others can be", "--address-hash", "01", "--transfers-file", "transfers.csv")
+	tokensCmd.ExecWithError(t, "if any flags in the group [address transfers-file address-file] are set none of the others can be", "--token-identifier", "01", "--address", "0x01", "--transfers-file", "transfers.csv")
+	tokensCmd.ExecWithError(t, "if any flags in the group [address-file type] are set they must all be set; missing [type]", "--address-file", "addresses.txt")
+}
+
+func TestWalletTokenSendFungibleCmd_Flags(t *testing.T) {
+	tokensCmd := testutils.NewSubCmdExecutor(NewTokenCmd, "send", "fungible")
+	tokensCmd.ExecWithError(t, "at least one of the flags in the group [address address-file] is required", "--type", "01", "--amount", "1")
+	tokensCmd.ExecWithError(t, "if any flags in the group [address address-file] are set none of the others can be", "--type", "01", "--amount", "1", "--address", "0x01", "--address-file", "addresses.txt")
+	tokensCmd.ExecWithError(t, "if any flags in the group [address-file change-bearer-clause] are set none of the others can be", "--type", "01", "--amount", "1", "--address-file", "addresses.txt", "--change-bearer-clause", "true")
+	tokensCmd.ExecWithError(t, "if any flags in the group [key key-hash] are set none of the others can be", "--type", "01", "--amount", "1", "--address", "0x01", "-k", "2", "--key-hash", "01")
+}
+
+func TestParseTokenAmount(t *testing.T) {
+	amount, err := parseTokenAmount("1.23", 2)
+	require.NoError(t, err)
+	require.EqualValues(t, 123, amount)
+
+	_, err = parseTokenAmount("1.234", 2)
+	require.EqualError(t, err, "amount '1.234' has more than 2 decimal places allowed by token type")
+
+	// other errors are returned as is
+	_, err = parseTokenAmount("1.", 2)
+	require.ErrorContains(t, err, "missing fraction part")
+}
+
+func TestSaveAndLoadTxProofs(t *testing.T) {
+	tx := &basetypes.TransactionOrder{Version: 1, Payload: basetypes.Payload{
+		UnitID:         []byte{1, 2, 3},
+		ClientMetadata: &basetypes.ClientMetadata{Timeout: 10},
+	}}
+	txBytes, err := tx.MarshalCBOR()
+	require.NoError(t, err)
+	proofs := []*basetypes.TxRecordProof{{
+		TxRecord: &basetypes.TransactionRecord{Version: 1, TransactionOrder: txBytes, ServerMetadata: &basetypes.ServerMetadata{ActualFee: 1}},
+		TxProof:  &basetypes.TxProof{Version: 1},
+	}}
+
+	newCmd := func(t *testing.T, flags ...string) (*cobra.Command, string) {
+		filename := filepath.Join(t.TempDir(), "proofs")
+		cmd := &cobra.Command{Use: "send"}
+		args.AddWaitForProofFlags(cmd, cmd.Flags())
+		cmd.Flags().Bool(cmdFlagProofMetadata, false, "")
+		cmd.Flags().String(cmdFlagProofFormat, proofFormatCBOR, "")
+		require.NoError(t, cmd.ParseFlags(append([]string{"--proof-output", filename}, flags...)))
+		return cmd, filename
+	}
+	save := func(t *testing.T, flags ...string) string {
+		cmd, filename := newCmd(t, flags...)
+		out := &testutils.TestConsoleWriter{}
+		require.NoError(t, saveTxProofs(cmd, 2, proofs, out))
+		testutils.VerifyStdout(t, out, "Transaction proof(s) saved to file:"+filename)
+		return filename
+	}
+
+	for _, format := range []string{proofFormatCBOR, proofFormatJSON} {
+		t.Run(format, func(t *testing.T) {
+			archive, err := loadTxProofs(save(t, "--proof-format", format))
+			require.NoError(t, err)
+			require.Zero(t, archive.Version)
+			require.Len(t, archive.Proofs, 1)
+			require.EqualValues(t, 1, archive.Proofs[0].ActualFee())
+
+			archive, err = loadTxProofs(save(t, "--proof-format", format, "--proof-metadata"))
+			require.NoError(t, err)
+			require.Equal(t, "send", archive.Operation)
+			require.EqualValues(t, 2, archive.AccountNumber)
+			require.Equal(t, []basetypes.UnitID{tx.GetUnitID()}, archive.UnitIDs)
+			require.Len(t, archive.Proofs, 1)
+		})
+	}
+
+	t.Run("JSON is human readable", func(t *testing.T) {
+		data, err := os.ReadFile(save(t, "--proof-format", proofFormatJSON, "--proof-metadata"))
+		require.NoError(t, err)
+		require.Contains(t, string(data), `"operation": "send"`)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		cmd, _ := newCmd(t, "--proof-format", "xml")
+		err := saveTxProofs(cmd, 2, proofs, &testutils.TestConsoleWriter{})
+		require.EqualError(t, err, `invalid value "xml" for flag "proof-format", must be one of: cbor, json`)
+	})
+
+	_, err = loadTxProofs(filepath.Join(t.TempDir(), "missing"))
+	require.ErrorContains(t, err, "opening transaction proofs file")
+}
+
+func TestReadAddressFile(t *testing.T) {
+	receiver := "0x0290a43bc454babf1ea8b0b76fcbb01a8f27a989047cf6d6d76397cc4756321e64"
+	writeFile := func(t *testing.T, content string) string {
+		filename := filepath.Join(t.TempDir(), "addresses.txt")
+		require.NoError(t, os.WriteFile(filename, []byte(content), 0600))
+		return filename
+	}
+
+	pubKeys, err := readAddressFile(writeFile(t, "# receivers\n"+receiver+"\n\n "+receiver+" \n"))
+	require.NoError(t, err)
+	require.Len(t, pubKeys, 2)
+	require.Len(t, pubKeys[1], 33)
+
+	_, err = readAddressFile(writeFile(t, receiver+"\n# comment\n0x02"))
+	require.ErrorContains(t, err, "address-file line 3: address in not in valid format: 0x02")
+
+	_, err = readAddressFile(writeFile(t, "# nothing\n"))
+	require.ErrorContains(t, err, "address-file: no addresses")
+}
+
+func TestReadNFTTransfersFile(t *testing.T) {
+	receiver := "0x0290a43bc454babf1ea8b0b76fcbb01a8f27a989047cf6d6d76397cc4756321e64"
+	writeFile := func(t *testing.T, content string) string {
+		filename := filepath.Join(t.TempDir(), "transfers.csv")
+		require.NoError(t, os.WriteFile(filename, []byte(content), 0600))
+		return filename
+	}
+
+	transfers, err := readNFTTransfersFile(writeFile(t, "# token,receiver\n0x01AB,"+receiver+"\n\n 02 , "+receiver+" \n"))
+	require.NoError(t, err)
+	require.Len(t, transfers, 2)
+	require.EqualValues(t, []byte{0x01, 0xAB}, transfers[0].TokenID)
+	require.EqualValues(t, []byte{0x02}, transfers[1].TokenID)
+	require.Len(t, transfers[1].ReceiverPubKey, 33)
+
+	_, err = readNFTTransfersFile(writeFile(t, "0x01"))
+	require.ErrorContains(t, err, `transfers-file line 1: expected "<token identifier>,<receiver public key>"`)
+
+	_, err = readNFTTransfersFile(writeFile(t, "# comment\n0xZZ,"+receiver))
+	require.ErrorContains(t, err, "transfers-file line 2: invalid token identifier")
+
+	_, err = readNFTTransfersFile(writeFile(t, "0x01,0x02"))
+	require.ErrorContains(t, err, "transfers-file line 1: address in not in valid format")
+
+	_, err = readNFTTransfersFile(writeFile(t, "# nothing\n"))
+	require.ErrorContains(t, err, "transfers-file: no transfers")
+}
+
+func TestReadFileChunked(t *testing.T) {
+	dir := t.TempDir()
+	data := make([]byte, 3*fileReadChunkSize+1)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	path := filepath.Join(dir, "data.bin")
+	require.NoError(t, os.WriteFile(path, data, 0600))
+
+	content, hash, err := readFileChunked(path, cmdFlagTokenDataFile, maxBinaryFile64KiB)
+	require.NoError(t, err)
+	require.Equal(t, data, content)
+	expectedHash := sha256.Sum256(data)
+	require.Equal(t, expectedHash[:], hash)
+
+	// file exactly at the limit is accepted, one byte over is rejected
+	_, _, err = readFileChunked(path, cmdFlagTokenDataFile, int64(len(data)))
+	require.NoError(t, err)
+	_, _, err = readFileChunked(path, cmdFlagTokenDataFile, int64(len(data)-1))
+	require.ErrorContains(t, err, "data-file read error: file size over")
+
+	// empty file
+	path = filepath.Join(dir, "empty.bin")
+	require.NoError(t, os.WriteFile(path, nil, 0600))
+	content, hash, err = readFileChunked(path, cmdFlagTokenDataFile, maxBinaryFile64KiB)
+	require.NoError(t, err)
+	require.Empty(t, content)
+	expectedHash = sha256.Sum256(nil)
+	require.Equal(t, expectedHash[:], hash)
+}
@@ -262,7 +262,7 @@ func TestWalletCreateFungibleTokenTypeAndTokenAndSendCmd_IntegrationTest(t *test
 	newFungibleCmd.ExecWithError(t, "missing integer part", "--amount", ".00")
 	newFungibleCmd.ExecWithError(t, "invalid amount string", "--amount", "a.00")
 	newFungibleCmd.ExecWithError(t, "invalid amount string", "--amount", "0.0a")
-	newFungibleCmd.ExecWithError(t, "invalid precision", "--amount", "1.1111")
+	newFungibleCmd.ExecWithError(t, "amount '1.1111' has more than 3 decimal places allowed by token type", "--amount", "1.1111")
 	// out of range because decimals = 3 the value is equal to 18446744073709551615000
 	newFungibleCmd.ExecWithError(t, "out of range", "--amount", "18446744073709551615")
 
@@ -293,7 +293,7 @@ func TestWalletCreateFungibleTokenTypeAndTokenAndSendCmd_IntegrationTest(t *test
 	sendFungibleCmd.ExecWithError(t, "more than one comma", "--amount", "00.0.00")
 	sendFungibleCmd.ExecWithError(t, "missing integer part", "--amount", ".00")
 	sendFungibleCmd.ExecWithError(t, "invalid amount string", "--amount", "a.00")
-	sendFungibleCmd.ExecWithError(t, "invalid precision", "--amount", "1.1111")
+	sendFungibleCmd.ExecWithError(t, "amount '1.1111' has more than 3 decimal places allowed by token type", "--amount", "1.1111")
 }
 
 func TestFungibleTokens_CollectDust_Integration(t *testing.T) {
@@ -2,10 +2,12 @@ package wallet
 
 import (
 	"context"
+	"crypto"
 	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -25,7 +27,9 @@ import (
 	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/wallet/permissioned"
 	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/wallet/tokens"
 	"github.com/alphabill-org/alphabill-wallet/client"
+	clienttypes "github.com/alphabill-org/alphabill-wallet/client/types"
 	"github.com/alphabill-org/alphabill-wallet/util"
+	sdkwallet "github.com/alphabill-org/alphabill-wallet/wallet"
 	"github.com/alphabill-org/alphabill-wallet/wallet/account"
 	"github.com/alphabill-org/alphabill-wallet/wallet/fees"
 	"github.com/alphabill-org/alphabill-wallet/wallet/money"
@@ -57,6 +61,7 @@ func NewWalletCmd(baseConfig *types.BaseConfiguration) *cobra.Command {
 	walletCmd.AddCommand(GetBalanceCmd(config))
 	walletCmd.AddCommand(CollectDustCmd(config))
 	walletCmd.AddCommand(AddKeyCmd(config))
+	walletCmd.AddCommand(RoundsCmd(config))
 	walletCmd.AddCommand(tokens.NewTokenCmd(config))
 	walletCmd.AddCommand(evm.NewEvmCmd(config))
 	walletCmd.AddCommand(orchestration.NewCmd(config))
@@ -67,6 +72,7 @@ func NewWalletCmd(baseConfig *types.BaseConfiguration) *cobra.Command {
 	walletCmd.PersistentFlags().BoolVarP(&config.PromptPassword, args.PasswordPromptCmdName, "p", false, args.PasswordPromptUsage)
 	walletCmd.PersistentFlags().StringVar(&config.PasswordFromArg, args.PasswordArgCmdName, "", args.PasswordArgUsage)
 	walletCmd.PersistentFlags().StringVarP(&config.WalletHomeDir, args.WalletLocationCmdName, "l", "", "wallet home directory (default $AB_HOME/wallet)")
+	walletCmd.PersistentFlags().String(args.AmountFormatCmdName, string(util.AmountFormatGrouped), "format of the amounts in output, one of: grouped (thousands separated by apostrophe), plain")
 	return walletCmd
 }
 
@@ -220,9 +226,18 @@ func ExecSendCmd(ctx context.Context, cmd *cobra.Command, config *types.WalletCo
 
 		var feeSum uint64
 		for _, proof := range proofs {
+			tx, err := proof.GetTransactionOrderV1()
+			if err != nil {
+				return fmt.Errorf("decoding transaction order: %w", err)
+			}
+			txHash, err := tx.Hash(crypto.SHA256)
+			if err != nil {
+				return fmt.Errorf("hashing transaction: %w", err)
+			}
+			config.Base.ConsoleWriter.Println(fmt.Sprintf("Transaction ID: %s", util.FormatTxID(txHash)))
 			feeSum += proof.TxRecord.ServerMetadata.GetActualFee()
 		}
-		config.Base.ConsoleWriter.Println("Paid", util.AmountToString(feeSum, 8), "fees for transaction(s).")
+		config.Base.ConsoleWriter.Println("Paid", config.FormatAmount(feeSum, 8), "fees for transaction(s).")
 		if proofFile != "" {
 			w, err := os.Create(proofFile)
 			if err != nil {
@@ -313,10 +328,10 @@ func ExecGetBalanceCmd(cmd *cobra.Command, config *types.WalletConfig) error {
 		}
 		if !total {
 			for i, v := range totals {
-				config.Base.ConsoleWriter.Println(fmt.Sprintf("#%d %s", i+1, util.AmountToString(v, 8)))
+				config.Base.ConsoleWriter.Println(fmt.Sprintf("#%d %s", i+1, config.FormatAmount(v, 8)))
 			}
 		}
-		sumStr := util.AmountToString(sum, 8)
+		sumStr := config.FormatAmount(sum, 8)
 		if quiet {
 			config.Base.ConsoleWriter.Println(sumStr)
 		} else {
@@ -327,7 +342,7 @@ func ExecGetBalanceCmd(cmd *cobra.Command, config *types.WalletConfig) error {
 		if err != nil {
 			return err
 		}
-		balanceStr := util.AmountToString(balance, 8)
+		balanceStr := config.FormatAmount(balance, 8)
 		if quiet {
 			config.Base.ConsoleWriter.Println(balanceStr)
 		} else {
@@ -450,9 +465,9 @@ func ExecCollectDust(cmd *cobra.Command, config *types.WalletConfig) error {
 					"ALPHA into an existing target bill with unit identifier 0x%s. Paid %s fees for transaction(s).",
 				dcResult.AccountIndex+1,
 				len(attr.DustTransferProofs),
-				util.AmountToString(swapAmount, 8),
+				config.FormatAmount(swapAmount, 8),
 				swapTx.GetUnitID(),
-				util.AmountToString(feeSum, 8),
+				config.FormatAmount(feeSum, 8),
 			))
 		} else {
 			config.Base.ConsoleWriter.Println(fmt.Sprintf("Nothing to swap on account #%d", dcResult.AccountIndex+1))
@@ -487,6 +502,63 @@ func ExecAddKeyCmd(cmd *cobra.Command, config *types.WalletConfig) error {
 	return nil
 }
 
+func RoundsCmd(config *types.WalletConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rounds",
+		Short: "shows the current round of the money partition and the given token partitions",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ExecRoundsCmd(cmd, config)
+		},
+	}
+	cmd.Flags().StringP(args.RpcUrl, "r", args.DefaultMoneyRpcUrl, "money rpc node url")
+	cmd.Flags().StringSliceP(args.PartitionRpcUrlCmdName, "m", nil, "token partition rpc node url(s)")
+	return cmd
+}
+
+func ExecRoundsCmd(cmd *cobra.Command, config *types.WalletConfig) error {
+	rpcUrl, err := cmd.Flags().GetString(args.RpcUrl)
+	if err != nil {
+		return err
+	}
+	tokensRpcUrls, err := cmd.Flags().GetStringSlice(args.PartitionRpcUrlCmdName)
+	if err != nil {
+		return err
+	}
+
+	moneyClient, err := client.NewMoneyPartitionClient(cmd.Context(), args.BuildRpcUrl(rpcUrl))
+	if err != nil {
+		return fmt.Errorf("failed to dial money rpc url: %w", err)
+	}
+	defer moneyClient.Close()
+	clients := []clienttypes.PartitionClient{moneyClient}
+	for _, url := range tokensRpcUrls {
+		tokensClient, err := client.NewTokensPartitionClient(cmd.Context(), args.BuildRpcUrl(url))
+		if err != nil {
+			return fmt.Errorf("failed to dial tokens rpc url %s: %w", url, err)
+		}
+		defer tokensClient.Close()
+		clients = append(clients, tokensClient)
+	}
+
+	partitions, err := sdkwallet.NewPartitions(cmd.Context(), clients...)
+	if err != nil {
+		return err
+	}
+	rounds, err := partitions.CurrentRounds(cmd.Context())
+	ids := make([]sdktypes.PartitionID, 0, len(rounds))
+	for id := range rounds {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		config.Base.ConsoleWriter.Println(fmt.Sprintf("Partition %s round %d", id, rounds[id]))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch current rounds: %w", err)
+	}
+	return nil
+}
+
 func InitWalletConfig(cmd *cobra.Command, config *types.WalletConfig) error {
 	walletLocation, err := cmd.Flags().GetString(args.WalletLocationCmdName)
 	if err != nil {
@@ -497,6 +569,13 @@ func InitWalletConfig(cmd *cobra.Command, config *types.WalletConfig) error {
 	} else {
 		config.WalletHomeDir = filepath.Join(config.Base.HomeDir, "wallet")
 	}
+	amountFormat, err := cmd.Flags().GetString(args.AmountFormatCmdName)
+	if err != nil {
+		return err
+	}
+	if config.AmountFormat, err = util.ParseAmountFormat(amountFormat); err != nil {
+		return err
+	}
 	return nil
 }
 
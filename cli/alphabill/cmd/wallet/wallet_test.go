@@ -1,6 +1,7 @@
 package wallet
 
 import (
+	"fmt"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -9,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	moneyid "github.com/alphabill-org/alphabill-go-base/testutils/money"
+	tokenid "github.com/alphabill-org/alphabill-go-base/testutils/tokens"
 	"github.com/alphabill-org/alphabill-go-base/txsystem/fc"
 	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
 	abtypes "github.com/alphabill-org/alphabill-go-base/types"
@@ -137,6 +139,41 @@ func TestWalletGetBalanceCmdTotalWithKeyFlag(t *testing.T) {
 	testutils.VerifyStdoutNotExists(t, stdout, "Total 15")
 }
 
+func TestWalletGetBalanceCmdAmountFormatFlag(t *testing.T) {
+	pdr := moneyid.PDR()
+	homedir := testutils.CreateNewTestWallet(t, testutils.WithDefaultMnemonic())
+	rpcUrl := mocksrv.StartStateApiServer(t, &pdr, mocksrv.NewStateServiceMock(mocksrv.WithOwnerUnit(testutils.TestPubKey0Hash(t),
+		&sdktypes.Unit[any]{
+			UnitID: moneyid.NewBillID(t),
+			Data:   money.BillData{Value: 15000 * 1e8},
+		})))
+
+	walletCmd := newWalletCmdExecutor("--rpc-url", rpcUrl).WithHome(homedir)
+	stdout := walletCmd.Exec(t, "get-balance")
+	testutils.VerifyStdout(t, stdout, "#1 15'000", "Total 15'000")
+
+	stdout = walletCmd.Exec(t, "get-balance", "--amount-format", "plain")
+	testutils.VerifyStdout(t, stdout, "#1 15000", "Total 15000")
+
+	walletCmd.ExecWithError(t, `invalid amount format "locale"`, "get-balance", "--amount-format", "locale")
+}
+
+func TestWalletRoundsCmd(t *testing.T) {
+	moneyPDR := moneyid.PDR()
+	tokensPDR := tokenid.PDR()
+	moneyRpcUrl := mocksrv.StartStateApiServer(t, &moneyPDR, mocksrv.NewStateServiceMock(mocksrv.WithRoundNumber(100)))
+	tokensRpcUrl := mocksrv.StartStateApiServer(t, &tokensPDR, mocksrv.NewStateServiceMock(mocksrv.WithRoundNumber(7)))
+
+	walletCmd := newWalletCmdExecutor("--rpc-url", moneyRpcUrl)
+	stdout := walletCmd.Exec(t, "rounds", "--partition-rpc-url", tokensRpcUrl)
+	testutils.VerifyStdout(t, stdout,
+		fmt.Sprintf("Partition %s round 100", moneyPDR.PartitionID),
+		fmt.Sprintf("Partition %s round 7", tokensPDR.PartitionID))
+
+	// money partition url given as token partition url
+	walletCmd.ExecWithError(t, "failed to dial tokens rpc url", "rounds", "--partition-rpc-url", moneyRpcUrl)
+}
+
 func TestWalletGetBalanceCmdQuietFlag(t *testing.T) {
 	pdr := moneyid.PDR()
 	homedir := testutils.CreateNewTestWallet(t, testutils.WithDefaultMnemonic())
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+
+	"github.com/alphabill-org/alphabill-go-base/types"
+)
+
+// maxBlockScanRange is the maximum number of rounds a single ScanBlocks call is allowed to walk.
+const maxBlockScanRange = 10000
+
+// ScanBlocks walks the blocks of rounds [fromRound, toRound] and returns proofs of the transactions
+// accepted by the filter, a nil filter accepts all transactions. If toRound is greater than the latest
+// round of the partition then the scan stops at the latest round. Rounds without a block are skipped.
+// If the context is cancelled the scan terminates early, returning the proofs collected so far
+// together with the context error.
+func (c *partitionClient) ScanBlocks(ctx context.Context, fromRound, toRound uint64, filter func(*types.TransactionOrder) bool) ([]*types.TxRecordProof, error) {
+	if fromRound > toRound {
+		return nil, fmt.Errorf("invalid round range: from round %d is greater than to round %d", fromRound, toRound)
+	}
+	if toRound-fromRound >= maxBlockScanRange {
+		return nil, fmt.Errorf("block scan range too large: %d rounds, maximum is %d", toRound-fromRound+1, maxBlockScanRange)
+	}
+	roundInfo, err := c.GetRoundInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch round info: %w", err)
+	}
+	toRound = min(toRound, roundInfo.RoundNumber)
+
+	var proofs []*types.TxRecordProof
+	for round := fromRound; round <= toRound; round++ {
+		if err := ctx.Err(); err != nil {
+			return proofs, err
+		}
+		block, err := c.GetBlock(ctx, round)
+		if err != nil {
+			return proofs, fmt.Errorf("failed to fetch block %d: %w", round, err)
+		}
+		if block == nil {
+			continue
+		}
+		for i, txr := range block.Transactions {
+			if filter != nil {
+				tx, err := txr.GetTransactionOrderV1()
+				if err != nil {
+					return proofs, fmt.Errorf("failed to decode transaction %d of block %d: %w", i, round, err)
+				}
+				if !filter(tx) {
+					continue
+				}
+			}
+			proof, err := types.NewTxRecordProof(block, i, crypto.SHA256)
+			if err != nil {
+				return proofs, fmt.Errorf("failed to create proof for transaction %d of block %d: %w", i, round, err)
+			}
+			proofs = append(proofs, proof)
+		}
+	}
+	return proofs, nil
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	moneyid "github.com/alphabill-org/alphabill-go-base/testutils/money"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alphabill-org/alphabill-wallet/client/rpc/mocksrv"
+)
+
+func TestScanBlocks(t *testing.T) {
+	pdr := moneyid.PDR()
+	service := mocksrv.NewStateServiceMock()
+	srv := mocksrv.StartStateApiServer(t, &pdr, service)
+	client, err := newPartitionClient(context.Background(), "http://"+srv, pdr.PartitionTypeID)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+
+	// the mock returns the same block for every round
+	setBlock := func(t *testing.T, txTypes ...uint16) {
+		block := &types.Block{Header: &types.Header{Version: 1, PartitionID: pdr.PartitionID}}
+		for _, txType := range txTypes {
+			txBytes, err := (&types.TransactionOrder{Version: 1, Payload: types.Payload{UnitID: []byte{1}, Type: txType}}).MarshalCBOR()
+			require.NoError(t, err)
+			block.Transactions = append(block.Transactions, &types.TransactionRecord{
+				Version:          1,
+				TransactionOrder: txBytes,
+				ServerMetadata:   &types.ServerMetadata{ActualFee: 1, SuccessIndicator: types.TxStatusSuccessful},
+			})
+		}
+		blockBytes, err := types.Cbor.Marshal(block)
+		require.NoError(t, err)
+		service.Block = blockBytes
+	}
+
+	t.Run("filter by transaction type", func(t *testing.T) {
+		service.Reset()
+		service.RoundNumber = 10
+		setBlock(t, 1, 2, 1)
+
+		proofs, err := client.ScanBlocks(context.Background(), 1, 3, func(tx *types.TransactionOrder) bool {
+			return tx.Type == 1
+		})
+		require.NoError(t, err)
+		require.Len(t, proofs, 6)
+		for _, proof := range proofs {
+			tx, err := proof.GetTransactionOrderV1()
+			require.NoError(t, err)
+			require.EqualValues(t, 1, tx.Type)
+		}
+	})
+
+	t.Run("nil filter accepts all transactions", func(t *testing.T) {
+		service.Reset()
+		service.RoundNumber = 10
+		setBlock(t, 1, 2)
+
+		proofs, err := client.ScanBlocks(context.Background(), 5, 5, nil)
+		require.NoError(t, err)
+		require.Len(t, proofs, 2)
+	})
+
+	t.Run("range is capped to latest round", func(t *testing.T) {
+		service.Reset()
+		service.RoundNumber = 2
+		setBlock(t, 1)
+
+		proofs, err := client.ScanBlocks(context.Background(), 1, 100, nil)
+		require.NoError(t, err)
+		require.Len(t, proofs, 2)
+	})
+
+	t.Run("empty rounds are skipped", func(t *testing.T) {
+		service.Reset()
+		service.RoundNumber = 10
+
+		proofs, err := client.ScanBlocks(context.Background(), 1, 10, nil)
+		require.NoError(t, err)
+		require.Empty(t, proofs)
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		_, err := client.ScanBlocks(context.Background(), 2, 1, nil)
+		require.ErrorContains(t, err, "invalid round range: from round 2 is greater than to round 1")
+
+		_, err = client.ScanBlocks(context.Background(), 1, maxBlockScanRange+1, nil)
+		require.ErrorContains(t, err, "block scan range too large")
+	})
+
+	t.Run("cancelled context terminates scan", func(t *testing.T) {
+		service.Reset()
+		service.RoundNumber = 10
+		setBlock(t, 1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		proofs, err := client.ScanBlocks(ctx, 1, 10, func(tx *types.TransactionOrder) bool {
+			cancel()
+			return true
+		})
+		require.ErrorIs(t, err, context.Canceled)
+		require.Len(t, proofs, 1)
+	})
+}
@@ -37,8 +37,8 @@ type (
 )
 
 // NewEvmPartitionClient creates an evm partition client for the given RPC URL.
-func NewEvmPartitionClient(ctx context.Context, rpcUrl string) (sdktypes.PartitionClient, error) {
-	partitionClient, err := newPartitionClient(ctx, rpcUrl, evm.PartitionTypeID)
+func NewEvmPartitionClient(ctx context.Context, rpcUrl string, opts ...Option) (sdktypes.PartitionClient, error) {
+	partitionClient, err := newPartitionClient(ctx, rpcUrl, evm.PartitionTypeID, opts...)
 	if err != nil {
 		return nil, err
 	}
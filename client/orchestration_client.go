@@ -19,8 +19,8 @@ type (
 )
 
 // NewOrchestrationPartitionClient creates an orchestration partition client for the given RPC URL.
-func NewOrchestrationPartitionClient(ctx context.Context, rpcUrl string) (sdktypes.PartitionClient, error) {
-	partitionClient, err := newPartitionClient(ctx, rpcUrl, orchestration.PartitionTypeID)
+func NewOrchestrationPartitionClient(ctx context.Context, rpcUrl string, opts ...Option) (sdktypes.PartitionClient, error) {
+	partitionClient, err := newPartitionClient(ctx, rpcUrl, orchestration.PartitionTypeID, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"runtime/debug"
 
 	"github.com/alphabill-org/alphabill-go-base/txsystem/fc"
 	"github.com/alphabill-org/alphabill-go-base/types"
@@ -12,7 +13,11 @@ import (
 	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
 )
 
-const defaultBatchItemLimit int = 100
+const (
+	defaultBatchItemLimit int = 100
+	defaultClientID           = "alphabill-wallet"
+	walletModulePath          = "github.com/alphabill-org/alphabill-wallet"
+)
 
 type (
 	partitionClient struct {
@@ -25,6 +30,7 @@ type (
 
 	Options struct {
 		BatchItemLimit int
+		ClientID       string
 	}
 
 	Option func(*Options)
@@ -36,14 +42,25 @@ func WithBatchItemLimit(batchItemLimit int) Option {
 	}
 }
 
+// WithClientID sets the client identifier sent to the rpc node in the User-Agent header,
+// the wallet version is appended to the identifier.
+func WithClientID(clientID string) Option {
+	return func(os *Options) {
+		os.ClientID = clientID
+	}
+}
+
 // newPartitionClient creates a generic partition client for the given RPC URL.
 func newPartitionClient(ctx context.Context, rpcUrl string, kind types.PartitionTypeID, opts ...Option) (*partitionClient, error) {
+	o := optionsWithDefaults(opts)
+	userAgent := ethrpc.WithHeader("User-Agent", o.userAgent())
+
 	// TODO: duplicate underlying rpc clients, could use one?
-	stateApiClient, err := rpc.NewStateAPIClient(ctx, rpcUrl)
+	stateApiClient, err := rpc.NewStateAPIClient(ctx, rpcUrl, userAgent)
 	if err != nil {
 		return nil, err
 	}
-	adminApiClient, err := rpc.NewAdminAPIClient(ctx, rpcUrl)
+	adminApiClient, err := rpc.NewAdminAPIClient(ctx, rpcUrl, userAgent)
 	if err != nil {
 		return nil, err
 	}
@@ -56,7 +73,6 @@ func newPartitionClient(ctx context.Context, rpcUrl string, kind types.Partition
 		return nil, fmt.Errorf("expected node partition type %x but it is %x", kind, info.PartitionTypeID)
 	}
 
-	o := optionsWithDefaults(opts)
 	return &partitionClient{
 		AdminAPIClient: adminApiClient,
 		StateAPIClient: stateApiClient,
@@ -136,9 +152,34 @@ func (c *partitionClient) Close() {
 func optionsWithDefaults(opts []Option) *Options {
 	res := &Options{
 		BatchItemLimit: defaultBatchItemLimit,
+		ClientID:       defaultClientID,
 	}
 	for _, opt := range opts {
 		opt(res)
 	}
 	return res
 }
+
+func (o *Options) userAgent() string {
+	if o.ClientID == "" || o.ClientID == defaultClientID {
+		return defaultClientID + "/" + walletVersion()
+	}
+	return fmt.Sprintf("%s %s/%s", o.ClientID, defaultClientID, walletVersion())
+}
+
+// walletVersion returns the version of the wallet module from the build info of the binary.
+func walletVersion() string {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	if bi.Main.Path == walletModulePath && bi.Main.Version != "" {
+		return bi.Main.Version
+	}
+	for _, dep := range bi.Deps {
+		if dep.Path == walletModulePath && dep.Version != "" {
+			return dep.Version
+		}
+	}
+	return "unknown"
+}
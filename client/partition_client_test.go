@@ -2,6 +2,9 @@ package client
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 
 	moneyid "github.com/alphabill-org/alphabill-go-base/testutils/money"
@@ -48,6 +51,55 @@ func TestBatchCallWithLimit(t *testing.T) {
 	batchCallWithLimit(12)
 }
 
+func TestClientID(t *testing.T) {
+	pdr := moneyid.PDR()
+	server := rpc.NewServer()
+	t.Cleanup(server.Stop)
+	require.NoError(t, server.RegisterName("state", mocksrv.NewStateServiceMock()))
+	require.NoError(t, server.RegisterName("admin", mocksrv.NewAdminServiceMock(mocksrv.WithInfoResponse(&sdktypes.NodeInfoResponse{
+		NetworkID:       pdr.NetworkID,
+		PartitionID:     pdr.PartitionID,
+		PartitionTypeID: pdr.PartitionTypeID,
+	}))))
+
+	var mu sync.Mutex
+	var userAgents []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		userAgents = append(userAgents, r.Header.Get("User-Agent"))
+		mu.Unlock()
+		server.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	requireUserAgent := func(t *testing.T, expected string) {
+		mu.Lock()
+		defer mu.Unlock()
+		require.NotEmpty(t, userAgents)
+		for _, ua := range userAgents {
+			require.Equal(t, expected, ua)
+		}
+		userAgents = nil
+	}
+
+	t.Run("default client id", func(t *testing.T) {
+		client, err := newPartitionClient(context.Background(), srv.URL, pdr.PartitionTypeID)
+		require.NoError(t, err)
+		t.Cleanup(client.Close)
+		_, err = client.GetRoundInfo(context.Background())
+		require.NoError(t, err)
+		requireUserAgent(t, "alphabill-wallet/"+walletVersion())
+	})
+
+	t.Run("custom client id", func(t *testing.T) {
+		client, err := newPartitionClient(context.Background(), srv.URL, pdr.PartitionTypeID, WithClientID("my-app/1.2"))
+		require.NoError(t, err)
+		t.Cleanup(client.Close)
+		_, err = client.GetRoundInfo(context.Background())
+		require.NoError(t, err)
+		requireUserAgent(t, "my-app/1.2 alphabill-wallet/"+walletVersion())
+	})
+}
+
 func createUnit(id types.UnitID) *sdktypes.Unit[any] {
 	return &sdktypes.Unit[any]{
 		PartitionID: money.DefaultPartitionID,
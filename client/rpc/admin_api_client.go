@@ -14,8 +14,8 @@ type AdminAPIClient struct {
 }
 
 // NewAdminAPIClient creates a new admin API client connected to the given URL.
-func NewAdminAPIClient(ctx context.Context, url string) (*AdminAPIClient, error) {
-	rpcClient, err := rpc.DialContext(ctx, url)
+func NewAdminAPIClient(ctx context.Context, url string, opts ...rpc.ClientOption) (*AdminAPIClient, error) {
+	rpcClient, err := rpc.DialOptions(ctx, url, opts...)
 	if err != nil {
 		return nil, err
 	}
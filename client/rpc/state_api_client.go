@@ -2,7 +2,9 @@ package rpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 
 	"github.com/alphabill-org/alphabill-go-base/types"
 	"github.com/alphabill-org/alphabill-go-base/types/hex"
@@ -11,6 +13,9 @@ import (
 	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
 )
 
+// blockSubscriptionName is the name of the "state" namespace subscription of new blocks.
+const blockSubscriptionName = "blocks"
+
 type (
 	// StateAPIClient defines typed wrappers for the Alphabill State RPC API.
 	StateAPIClient struct {
@@ -19,8 +24,8 @@ type (
 )
 
 // NewStateAPIClient creates a new state API client connected to the given URL.
-func NewStateAPIClient(ctx context.Context, url string) (*StateAPIClient, error) {
-	rpcClient, err := rpc.DialContext(ctx, url)
+func NewStateAPIClient(ctx context.Context, url string, opts ...rpc.ClientOption) (*StateAPIClient, error) {
+	rpcClient, err := rpc.DialOptions(ctx, url, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -98,6 +103,67 @@ func (c *StateAPIClient) GetUnits(ctx context.Context, unitTypeID *uint32) ([]ty
 	return res, err
 }
 
+// NodeCapabilities probes the node for optional features so that callers can fall back
+// gracefully when a feature is not available. A feature is reported as unsupported when
+// the node responds to the probe with an error, transport errors are returned as errors.
+func (c *StateAPIClient) NodeCapabilities(ctx context.Context) (sdktypes.Capabilities, error) {
+	var caps sdktypes.Capabilities
+	var err error
+	if caps.UnitListing, err = c.probeUnitListing(ctx); err != nil {
+		return caps, fmt.Errorf("probing unit listing: %w", err)
+	}
+	if caps.BatchRequests, err = c.probeBatchRequests(ctx); err != nil {
+		return caps, fmt.Errorf("probing batch requests: %w", err)
+	}
+	if caps.BlockSubscription, err = c.probeBlockSubscription(ctx); err != nil {
+		return caps, fmt.Errorf("probing block subscription: %w", err)
+	}
+	return caps, nil
+}
+
+func (c *StateAPIClient) probeUnitListing(ctx context.Context) (bool, error) {
+	// unit type which shouldn't exist in any partition keeps the response small
+	unitTypeID := uint32(math.MaxUint32)
+	var res []types.UnitID
+	return supportedOrErr(c.RpcClient.CallContext(ctx, &res, "state_getUnits", &unitTypeID))
+}
+
+func (c *StateAPIClient) probeBatchRequests(ctx context.Context) (bool, error) {
+	var res *sdktypes.RoundInfo
+	batch := []rpc.BatchElem{{Method: "state_getRoundInfo", Result: &res}}
+	if err := c.RpcClient.BatchCallContext(ctx, batch); err != nil {
+		return supportedOrErr(err)
+	}
+	return supportedOrErr(batch[0].Error)
+}
+
+func (c *StateAPIClient) probeBlockSubscription(ctx context.Context) (bool, error) {
+	sub, err := c.RpcClient.Subscribe(ctx, "state", make(chan hex.Bytes), blockSubscriptionName)
+	if errors.Is(err, rpc.ErrNotificationsUnsupported) {
+		return false, nil
+	}
+	if err != nil {
+		return supportedOrErr(err)
+	}
+	sub.Unsubscribe()
+	return true, nil
+}
+
+/*
+supportedOrErr interprets the result of a feature probe: error returned by the node
+means that the feature is not supported, any other error is returned as is.
+*/
+func supportedOrErr(err error) (bool, error) {
+	if err == nil {
+		return true, nil
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return false, nil
+	}
+	return false, err
+}
+
 func encodeCbor(v interface{}) (hex.Bytes, error) {
 	data, err := types.Cbor.Marshal(v)
 	if err != nil {
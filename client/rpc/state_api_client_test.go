@@ -146,6 +146,52 @@ func TestRpcClient(t *testing.T) {
 	})
 }
 
+func TestNodeCapabilities(t *testing.T) {
+	t.Run("all probed features", func(t *testing.T) {
+		service := mocksrv.NewStateServiceMock()
+		client := startStateServer(t, service)
+
+		caps, err := client.NodeCapabilities(context.Background())
+		require.NoError(t, err)
+		// notifications are not supported over http
+		require.Equal(t, sdktypes.Capabilities{UnitListing: true, BatchRequests: true}, caps)
+	})
+	t.Run("unit listing disabled", func(t *testing.T) {
+		service := mocksrv.NewStateServiceMock()
+		client := startStateServer(t, service)
+		service.Err = errors.New("state_getUnits is disabled")
+
+		caps, err := client.NodeCapabilities(context.Background())
+		require.NoError(t, err)
+		require.False(t, caps.UnitListing)
+	})
+	t.Run("method not available", func(t *testing.T) {
+		srv := mocksrv.StartServer(t, map[string]interface{}{"state": &roundInfoService{}})
+		client, err := NewStateAPIClient(context.Background(), "http://"+srv)
+		require.NoError(t, err)
+		t.Cleanup(client.Close)
+
+		caps, err := client.NodeCapabilities(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, sdktypes.Capabilities{BatchRequests: true}, caps)
+	})
+	t.Run("context cancelled", func(t *testing.T) {
+		client := startStateServer(t, mocksrv.NewStateServiceMock())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := client.NodeCapabilities(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+// roundInfoService is a "state" service of a node which supports only the mandatory methods.
+type roundInfoService struct{}
+
+func (s *roundInfoService) GetRoundInfo(ctx context.Context) (*sdktypes.RoundInfo, error) {
+	return &sdktypes.RoundInfo{RoundNumber: 1}, nil
+}
+
 func startStateServer(t *testing.T, service *mocksrv.StateServiceMock) *StateAPIClient {
 	srv := mocksrv.StartServer(t, map[string]interface{}{"state": service})
 
@@ -295,7 +295,7 @@ func (c *TokensPartitionClient) GetFungibleTokenTypeHierarchy(ctx context.Contex
 			return nil, fmt.Errorf("failed to fetch token type: %w", err)
 		}
 		if tokenType == nil {
-			return nil, fmt.Errorf("fungible token type %s not found", typeID)
+			return nil, fmt.Errorf("fungible token type %s %w", typeID, sdktypes.ErrNotFound)
 		}
 		tokenTypes = append(tokenTypes, tokenType)
 		typeID = tokenType.ParentTypeID
@@ -312,7 +312,7 @@ func (c *TokensPartitionClient) GetNonFungibleTokenTypeHierarchy(ctx context.Con
 			return nil, fmt.Errorf("failed to fetch token type: %w", err)
 		}
 		if tokenType == nil {
-			return nil, fmt.Errorf("non-fungible token type %s not found", typeID)
+			return nil, fmt.Errorf("non-fungible token type %s %w", typeID, sdktypes.ErrNotFound)
 		}
 		tokenTypes = append(tokenTypes, tokenType)
 		typeID = tokenType.ParentTypeID
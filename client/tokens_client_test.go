@@ -245,6 +245,7 @@ func TestTokensRpcClient(t *testing.T) {
 
 		typeHierarchy, err := client.GetFungibleTokenTypeHierarchy(context.Background(), typeID)
 		require.ErrorContains(t, err, fmt.Sprintf("fungible token type %s not found", typeID.String()))
+		require.ErrorIs(t, err, types.ErrNotFound)
 		require.Nil(t, typeHierarchy)
 	})
 
@@ -19,6 +19,7 @@ type (
 		ConfirmTransaction(ctx context.Context, tx *types.TransactionOrder, log *slog.Logger) (*types.TxRecordProof, error)
 		GetTransactionProof(ctx context.Context, txHash hex.Bytes) (*types.TxRecordProof, error)
 		GetFeeCreditRecordByOwnerID(ctx context.Context, ownerID []byte) (*FeeCreditRecord, error)
+		ScanBlocks(ctx context.Context, fromRound, toRound uint64, filter func(*types.TransactionOrder) bool) ([]*types.TxRecordProof, error)
 		Close()
 	}
 
@@ -51,6 +52,19 @@ type (
 		EpochNumber uint64 `json:"epochNumber"`
 	}
 
+	// Capabilities lists the optional features of the rpc node, different node
+	// versions and configurations support different sets of features.
+	Capabilities struct {
+		// UnitListing is true when the node serves "state_getUnits" (must be explicitly
+		// enabled on the validator node).
+		UnitListing bool
+		// BatchRequests is true when the node accepts JSON-RPC batch requests.
+		BatchRequests bool
+		// BlockSubscription is true when the connection supports notifications and
+		// the node serves block subscriptions.
+		BlockSubscription bool
+	}
+
 	PeerInfo struct {
 		Identifier string   `json:"identifier"`
 		Addresses  []string `json:"addresses"`
@@ -2,14 +2,21 @@ package types
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/alphabill-org/alphabill-go-base/hash"
 	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
 	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
 )
 
-var NoParent = TokenTypeID(nil)
+var (
+	NoParent = TokenTypeID(nil)
+
+	// ErrNotFound is returned when the requested token type does not exist.
+	ErrNotFound = errors.New("not found")
+)
 
 type (
 	TokensPartitionClient interface {
@@ -24,6 +31,8 @@ type (
 		GetNonFungibleTokens(ctx context.Context, ownerID []byte) ([]*NonFungibleToken, error)
 		GetNonFungibleTokenTypes(ctx context.Context, creator PubKey) ([]*NonFungibleTokenType, error)
 		GetNonFungibleTokenTypeHierarchy(ctx context.Context, typeID TokenTypeID) ([]*NonFungibleTokenType, error)
+
+		GetUnitsByOwnerID(ctx context.Context, ownerID hex.Bytes) ([]types.UnitID, error)
 	}
 
 	FungibleTokenType struct {
@@ -14,6 +14,7 @@ type (
 		FeeCreditRecordID types.UnitID
 		MaxFee            uint64
 		ReferenceNumber   []byte
+		StateLock         *types.StateLock
 	}
 
 	Option func(*Options)
@@ -34,6 +35,7 @@ func NewTransactionOrder(networkID types.NetworkID, partitionID types.PartitionI
 			UnitID:      unitID,
 			Type:        txType,
 			Attributes:  attrBytes,
+			StateLock:   o.StateLock,
 			ClientMetadata: &types.ClientMetadata{
 				Timeout:           o.Timeout,
 				MaxTransactionFee: o.MaxFee,
@@ -68,6 +70,14 @@ func WithMaxFee(maxFee uint64) Option {
 	}
 }
 
+// WithStateLock makes the transaction state locked, ie it's executed or discarded only when the
+// execution or rollback predicate of the lock is satisfied.
+func WithStateLock(stateLock *types.StateLock) Option {
+	return func(os *Options) {
+		os.StateLock = stateLock
+	}
+}
+
 func OptionsWithDefaults(txOptions []Option) *Options {
 	opts := &Options{
 		MaxFee: 10,
@@ -78,6 +88,16 @@ func OptionsWithDefaults(txOptions []Option) *Options {
 	return opts
 }
 
+// EncodedSize returns the size of the CBOR encoding of the transaction order in bytes, ie the
+// size of the transaction as it is sent to the partition.
+func EncodedSize(tx *types.TransactionOrder) (int, error) {
+	b, err := types.Cbor.Marshal(tx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode transaction order: %w", err)
+	}
+	return len(b), nil
+}
+
 // NewP2pkhAuthProofSignature creates a standard P2PKH predicate signature for AuthProof.
 func NewP2pkhAuthProofSignature(txo *types.TransactionOrder, signer crypto.Signer) ([]byte, error) {
 	return NewP2pkhSignature(signer, txo.AuthProofSigBytes)
@@ -0,0 +1,30 @@
+package types
+
+import (
+	"testing"
+
+	tokenid "github.com/alphabill-org/alphabill-go-base/testutils/tokens"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodedSize(t *testing.T) {
+	attr := &tokens.UpdateNonFungibleTokenAttributes{Data: []byte{1, 2, 3}, Counter: 1}
+	tx, err := NewTransactionOrder(types.NetworkLocal, tokens.DefaultPartitionID, tokenid.NewNonFungibleTokenID(t), tokens.TransactionTypeUpdateNFT, attr, WithTimeout(10))
+	require.NoError(t, err)
+
+	txBytes, err := types.Cbor.Marshal(tx)
+	require.NoError(t, err)
+	size, err := EncodedSize(tx)
+	require.NoError(t, err)
+	require.Equal(t, len(txBytes), size)
+
+	// size grows with the data of the transaction
+	attr.Data = make([]byte, 100)
+	tx, err = NewTransactionOrder(types.NetworkLocal, tokens.DefaultPartitionID, tokenid.NewNonFungibleTokenID(t), tokens.TransactionTypeUpdateNFT, attr, WithTimeout(10))
+	require.NoError(t, err)
+	biggerSize, err := EncodedSize(tx)
+	require.NoError(t, err)
+	require.Greater(t, biggerSize, size+90)
+}
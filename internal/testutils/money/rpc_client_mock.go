@@ -29,6 +29,7 @@ type (
 		OwnerFeeCreditRecords []*sdktypes.FeeCreditRecord
 		RoundNumber           uint64
 		TxProofs              map[string]*types.TxRecordProof
+		Blocks                map[uint64]*types.Block
 
 		RecordedTxs []*types.TransactionOrder
 	}
@@ -42,6 +43,7 @@ type (
 		OwnerBills            []*sdktypes.Bill
 		FeeCreditRecords      map[string]*sdktypes.FeeCreditRecord
 		OwnerFeeCreditRecords []*sdktypes.FeeCreditRecord
+		Blocks                map[uint64]*types.Block
 	}
 
 	Option func(*Options)
@@ -54,6 +56,7 @@ func NewRpcClientMock(opts ...Option) *RpcClientMock {
 		Bills:            map[string]*sdktypes.Bill{},
 		FeeCreditRecords: map[string]*sdktypes.FeeCreditRecord{},
 		TxProofs:         map[string]*types.TxRecordProof{},
+		Blocks:           map[uint64]*types.Block{},
 	}
 	for _, option := range opts {
 		option(options)
@@ -67,6 +70,7 @@ func NewRpcClientMock(opts ...Option) *RpcClientMock {
 		FeeCreditRecords:      options.FeeCreditRecords,
 		OwnerFeeCreditRecords: options.OwnerFeeCreditRecords,
 		TxProofs:              options.TxProofs,
+		Blocks:                options.Blocks,
 	}
 }
 
@@ -96,6 +100,12 @@ func WithTxProof(txHash []byte, txProof *types.TxRecordProof) Option {
 	}
 }
 
+func WithBlock(roundNumber uint64, block *types.Block) Option {
+	return func(o *Options) {
+		o.Blocks[roundNumber] = block
+	}
+}
+
 func WithRoundNumber(roundNumber uint64) Option {
 	return func(o *Options) {
 		o.RoundNumber = roundNumber
@@ -219,6 +229,29 @@ func (c *RpcClientMock) GetBlock(ctx context.Context, blockNumber uint64) (*type
 	return &types.Block{}, nil
 }
 
+func (c *RpcClientMock) ScanBlocks(ctx context.Context, fromRound, toRound uint64, filter func(*types.TransactionOrder) bool) ([]*types.TxRecordProof, error) {
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	var proofs []*types.TxRecordProof
+	for round := fromRound; round <= toRound; round++ {
+		block, ok := c.Blocks[round]
+		if !ok {
+			continue
+		}
+		for _, txr := range block.Transactions {
+			tx, err := txr.GetTransactionOrderV1()
+			if err != nil {
+				return nil, err
+			}
+			if filter == nil || filter(tx) {
+				proofs = append(proofs, &types.TxRecordProof{TxRecord: txr, TxProof: &types.TxProof{}})
+			}
+		}
+	}
+	return proofs, nil
+}
+
 func (c *RpcClientMock) Close() {
 	// Nothing to close
 }
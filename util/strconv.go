@@ -1,12 +1,17 @@
 package util
 
 import (
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
 )
 
+// ErrInvalidPrecision is returned by StringToAmount when the amount has more fraction digits than decimals.
+var ErrInvalidPrecision = errors.New("invalid precision")
+
 // StringToAmount converts string and decimals to uint64 amount
 func StringToAmount(amountIn string, decimals uint32) (uint64, error) {
 	if amountIn == "" {
@@ -37,7 +42,7 @@ func StringToAmount(amountIn string, decimals uint32) (uint64, error) {
 	}
 	// there is a comma in the value
 	if uint32(len(fractionStr)) > decimals {
-		return 0, fmt.Errorf("invalid precision: %s", amountIn)
+		return 0, fmt.Errorf("%w: %s", ErrInvalidPrecision, amountIn)
 	}
 	// pad with 0's in input is smaller than decimals
 	if uint32(len(fractionStr)) < decimals {
@@ -70,6 +75,41 @@ func AmountToString(amount uint64, decimals uint32) string {
 	return "0." + InsertSeparator(resultStr+amountStr, true)
 }
 
+// AmountFormat defines how amounts are formatted for output.
+type AmountFormat string
+
+const (
+	// AmountFormatGrouped inserts apostrophe as thousands separator, see AmountToString.
+	AmountFormatGrouped AmountFormat = "grouped"
+	// AmountFormatPlain formats amount as plain decimal number without separators.
+	AmountFormatPlain AmountFormat = "plain"
+)
+
+// ParseAmountFormat validates the amount format name.
+func ParseAmountFormat(format string) (AmountFormat, error) {
+	switch f := AmountFormat(format); f {
+	case AmountFormatGrouped, AmountFormatPlain:
+		return f, nil
+	default:
+		return "", fmt.Errorf("invalid amount format %q, expected one of: %s, %s", format, AmountFormatGrouped, AmountFormatPlain)
+	}
+}
+
+// FormatAmount converts amount to string with specified decimals using the given format.
+// Unknown format defaults to AmountFormatGrouped.
+func FormatAmount(amount uint64, decimals uint32, format AmountFormat) string {
+	if format == AmountFormatPlain {
+		return strings.ReplaceAll(AmountToString(amount, decimals), "'", "")
+	}
+	return AmountToString(amount, decimals)
+}
+
+// FormatTxID returns the canonical display form of the transaction hash, a 0x-prefixed
+// lowercase hex string as accepted by the RPC API.
+func FormatTxID(txHash []byte) string {
+	return "0x" + hex.EncodeToString(txHash)
+}
+
 // InsertSeparator inserts apostrophe as thousands separator. The reverse flag defines the direction in which the insertion should happen
 // InsertSeparator("1234", false) => 1'234 (for the integral part)
 // InsertSeparator("1234", true) => 123'4 (for the fractional part)
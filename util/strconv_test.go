@@ -227,3 +227,21 @@ func Test_amountToString(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatAmount(t *testing.T) {
+	require.Equal(t, "1'234'567.890'12", FormatAmount(123456789012, 5, AmountFormatGrouped))
+	require.Equal(t, "1234567.89012", FormatAmount(123456789012, 5, AmountFormatPlain))
+	require.Equal(t, "0.00000001", FormatAmount(1, 8, AmountFormatPlain))
+	require.Equal(t, "1'000", FormatAmount(1000, 0, ""))
+
+	f, err := ParseAmountFormat("plain")
+	require.NoError(t, err)
+	require.Equal(t, AmountFormatPlain, f)
+	_, err = ParseAmountFormat("locale")
+	require.ErrorContains(t, err, `invalid amount format "locale"`)
+}
+
+func TestFormatTxID(t *testing.T) {
+	require.Equal(t, "0x01abff", FormatTxID([]byte{0x01, 0xAB, 0xFF}))
+	require.Equal(t, "0x", FormatTxID(nil))
+}
@@ -0,0 +1,74 @@
+package wallet
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"time"
+
+	"github.com/alphabill-org/alphabill-go-base/types"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+)
+
+type (
+	// Clock is the source of the waits between the polls of WaitForConfirmation.
+	Clock interface {
+		After(d time.Duration) <-chan time.Time
+	}
+
+	ConfirmationOption func(*confirmationOptions)
+
+	confirmationOptions struct {
+		clock Clock
+	}
+
+	realClock struct{}
+)
+
+// WithClock sets the clock used to wait between the polls, by default real time is used.
+func WithClock(clock Clock) ConfirmationOption {
+	return func(o *confirmationOptions) {
+		o.clock = clock
+	}
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// WaitForConfirmation polls the partition for the proof of the transaction every pollInterval until
+// the proof is found or the transaction times out, nil proof is returned in the latter case.
+func WaitForConfirmation(ctx context.Context, partitionClient sdktypes.PartitionClient, tx *types.TransactionOrder, pollInterval time.Duration, opts ...ConfirmationOption) (*types.TxRecordProof, error) {
+	o := &confirmationOptions{clock: realClock{}}
+	for _, opt := range opts {
+		opt(o)
+	}
+	txHash, err := tx.Hash(crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash tx: %w", err)
+	}
+	for {
+		// fetch round number before proof to ensure that we cannot miss the proof
+		roundInfo, err := partitionClient.GetRoundInfo(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch round info: %w", err)
+		}
+		proof, err := partitionClient.GetTransactionProof(ctx, txHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch tx proof: %w", err)
+		}
+		if proof != nil {
+			return proof, nil
+		}
+		if roundInfo.RoundNumber >= tx.Timeout() {
+			return nil, nil
+		}
+
+		select {
+		case <-o.clock.After(pollInterval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package wallet
+
+import (
+	"context"
+	"crypto"
+	"testing"
+	"time"
+
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
+	"github.com/stretchr/testify/require"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+)
+
+func TestWaitForConfirmation(t *testing.T) {
+	tx := &types.TransactionOrder{Version: 1, Payload: types.Payload{
+		UnitID:         []byte{1, 2, 3},
+		ClientMetadata: &types.ClientMetadata{Timeout: 5},
+	}}
+	txHash, err := tx.Hash(crypto.SHA256)
+	require.NoError(t, err)
+	proof := &types.TxRecordProof{}
+
+	var waits []time.Duration
+	clock := testClock(func(d time.Duration) <-chan time.Time {
+		waits = append(waits, d)
+		ch := make(chan time.Time, 1)
+		ch <- time.Time{}
+		return ch
+	})
+
+	t.Run("proof found", func(t *testing.T) {
+		waits = nil
+		client := &confirmClientMock{txHash: txHash, proof: proof, proofRound: 3}
+		res, err := WaitForConfirmation(context.Background(), client, tx, time.Second, WithClock(clock))
+		require.NoError(t, err)
+		require.Equal(t, proof, res)
+		require.Equal(t, []time.Duration{time.Second, time.Second}, waits)
+	})
+
+	t.Run("proof of the last round before timeout is not missed", func(t *testing.T) {
+		waits = nil
+		// proof appears right after the round info of the timeout round was fetched
+		client := &confirmClientMock{txHash: txHash, proof: proof, proofRound: 5}
+		res, err := WaitForConfirmation(context.Background(), client, tx, time.Second, WithClock(clock))
+		require.NoError(t, err)
+		require.Equal(t, proof, res)
+	})
+
+	t.Run("tx times out", func(t *testing.T) {
+		waits = nil
+		client := &confirmClientMock{}
+		res, err := WaitForConfirmation(context.Background(), client, tx, time.Second, WithClock(clock))
+		require.NoError(t, err)
+		require.Nil(t, res)
+		// rounds 1..4 are below the timeout, poll interval is waited after each
+		require.Len(t, waits, 4)
+	})
+
+	t.Run("context cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		res, err := WaitForConfirmation(ctx, &confirmClientMock{}, tx, time.Minute)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Nil(t, res)
+	})
+}
+
+// testClock returns the channels of the wrapped function, ie doesn't wait in real time.
+type testClock func(d time.Duration) <-chan time.Time
+
+func (c testClock) After(d time.Duration) <-chan time.Time {
+	return c(d)
+}
+
+// confirmClientMock advances the round number each time round info is requested, the proof
+// of the transaction is returned from the proofRound on.
+type confirmClientMock struct {
+	sdktypes.PartitionClient
+	round      uint64
+	txHash     []byte
+	proof      *types.TxRecordProof
+	proofRound uint64
+}
+
+func (c *confirmClientMock) GetRoundInfo(ctx context.Context) (*sdktypes.RoundInfo, error) {
+	c.round++
+	return &sdktypes.RoundInfo{RoundNumber: c.round}, nil
+}
+
+func (c *confirmClientMock) GetTransactionProof(ctx context.Context, txHash hex.Bytes) (*types.TxRecordProof, error) {
+	if c.proof != nil && c.round >= c.proofRound && string(txHash) == string(c.txHash) {
+		return c.proof, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,56 @@
+package fees
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// accountLocks serializes the fee credit processes of an account. A process reads the write-ahead
+// log of the account and updates it in multiple steps, two processes running concurrently could
+// both find no pending process and add (or reclaim) fee credit twice.
+//
+// The locks are in-process only, the fee manager DB file itself is locked exclusively by the
+// process that opens it so processes can't share the DB concurrently.
+type accountLocks struct {
+	mu    sync.Mutex
+	locks map[string]chan struct{}
+}
+
+// lock acquires the lock of the account, waiting until the lock is released by the current
+// holder or ctx is done. The returned func must be called to release the lock.
+func (l *accountLocks) lock(ctx context.Context, accountID []byte) (func(), error) {
+	ch := l.lockChan(accountID)
+	select {
+	case ch <- struct{}{}:
+		return func() { <-ch }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for another fee credit process of the account to complete: %w", ctx.Err())
+	}
+}
+
+// tryLock acquires the lock of the account without waiting, false is returned if the lock is
+// held by another process. The returned func must be called to release the lock.
+func (l *accountLocks) tryLock(accountID []byte) (func(), bool) {
+	ch := l.lockChan(accountID)
+	select {
+	case ch <- struct{}{}:
+		return func() { <-ch }, true
+	default:
+		return nil, false
+	}
+}
+
+func (l *accountLocks) lockChan(accountID []byte) chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.locks == nil {
+		l.locks = make(map[string]chan struct{})
+	}
+	ch, ok := l.locks[string(accountID)]
+	if !ok {
+		ch = make(chan struct{}, 1)
+		l.locks[string(accountID)] = ch
+	}
+	return ch
+}
@@ -0,0 +1,24 @@
+package fees
+
+import (
+	"time"
+
+	"github.com/alphabill-org/alphabill-wallet/wallet"
+)
+
+// Clock is the source of time of the FeeManager, allows tests to control the passage of time
+// instead of relying on real sleeps.
+type Clock interface {
+	wallet.Clock
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
@@ -6,7 +6,10 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"math/bits"
 	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	abcrypto "github.com/alphabill-org/alphabill-go-base/crypto"
@@ -17,17 +20,31 @@ import (
 	"github.com/alphabill-org/alphabill-wallet/util"
 	"github.com/alphabill-org/alphabill-wallet/wallet"
 	"github.com/alphabill-org/alphabill-wallet/wallet/account"
+	"github.com/alphabill-org/alphabill-wallet/wallet/txsubmitter"
 )
 
 const (
 	txTimeoutBlockCount          = 10
-	transferFCLatestAdditionTime = 65536 // relative timeout after which transferFC unit becomes unusable
+	transferFCLatestAdditionTime = 65536 // default relative timeout after which transferFC unit becomes unusable
+	confPollInterval             = time.Second
+)
+
+const (
+	PendingAdd     = "add"
+	PendingReclaim = "reclaim"
 )
 
 var (
 	ErrMinimumFeeAmount    = errors.New("insufficient fee amount")
 	ErrInsufficientBalance = errors.New("insufficient balance for transaction")
 	ErrInvalidPartition    = errors.New("pending fee credit process for another partition")
+	ErrInvalidFcrUnitType  = errors.New("invalid fee credit record unit type")
+	ErrNotAbortable        = errors.New("pending fee credit process can not be aborted")
+	ErrFeeCreditInProgress = errors.New("fee credit process in progress")
+	// ErrReclaimTargetBillSpent is returned when the target bill of the reclaim process was spent
+	// before reclaimFC was confirmed. The closeFC transaction commits to the target bill ID and
+	// counter, so the closed fee credit can not be reclaimed to any other bill.
+	ErrReclaimTargetBillSpent = errors.New("reclaimFC target bill is no longer usable")
 )
 
 type (
@@ -38,6 +55,9 @@ type (
 		GetAddFeeContext(accountID []byte) (*AddFeeCreditCtx, error)
 		SetAddFeeContext(accountID []byte, feeCtx *AddFeeCreditCtx) error
 		DeleteAddFeeContext(accountID []byte) error
+		GetAddFeeProgress(accountID []byte) (*AddFeeProgress, error)
+		SetAddFeeProgress(accountID []byte, progress *AddFeeProgress) error
+		DeleteAddFeeProgress(accountID []byte) error
 		GetReclaimFeeContext(accountID []byte) (*ReclaimFeeCreditCtx, error)
 		SetReclaimFeeContext(accountID []byte, feeCtx *ReclaimFeeCreditCtx) error
 		DeleteReclaimFeeContext(accountID []byte) error
@@ -58,19 +78,47 @@ type (
 		targetPartitionID      types.PartitionID
 		targetPartitionClient  sdktypes.PartitionClient
 		targetPartitionFcrIDFn GenerateFcrID
+		// guards targetPartitionFcrIDFn, see SetTargetFcrIDFn
+		fcrIDFnMu sync.Mutex
+		// if set then the unit type of generated target partition fee credit record IDs is validated
+		targetPartitionPDR         *types.PartitionDescriptionRecord
+		targetPartitionFcrUnitType uint32
 
 		maxFee    uint64
 		networkID types.NetworkID
+		clock     Clock
+		// number of target partition rounds the transferFC can be added to the fee credit record
+		latestAdditionTime uint64
+		// serializes the add, reclaim and abort processes of an account
+		accountLocks accountLocks
+		// options of the batches used to send the transactions, nil to use ConfirmTransaction of the partition client
+		batchOpts []txsubmitter.BatchOption
+		// if non-zero then overrides the minimum amounts derived from maxFee
+		minAddFeeAmount     uint64
+		minReclaimFeeAmount uint64
 	}
 
+	Option func(*FeeManager)
+
 	GetFeeCreditCmd struct {
 		AccountIndex uint64
 	}
 
+	GetFeeCreditRecordIDCmd struct {
+		AccountIndex       uint64
+		LatestAdditionTime uint64 // if zero then current target partition round + configured latest addition time window is used
+	}
+
 	AddFeeCmd struct {
 		AccountIndex   uint64
 		Amount         uint64
 		DisableLocking bool // if true then lockFC transaction is not sent before adding fee credit
+		// LockSkipBelow if set, the lockFC transaction is not sent when the balance of the fee credit
+		// record is below this amount, ie locking a trivial balance is not worth the fee
+		LockSkipBelow uint64
+		// SourceBillID if set, the fee credit is added from this bill only, instead of the
+		// bills of the account selected largest first
+		SourceBillID types.UnitID
 	}
 
 	ReclaimFeeCmd struct {
@@ -87,10 +135,65 @@ type (
 		AccountIndex uint64
 	}
 
+	// ProvisioningCmd describes a planned sequence of adding fee credit followed by target
+	// partition transactions paid from the added credit.
+	ProvisioningCmd struct {
+		TransferAmount uint64 // money sent along with the fee credit, ie the value of a bill the user is funded with
+		TxCount        uint64 // number of target partition transactions paid from the added fee credit
+		Reclaimable    bool   // if true then the fee credit left over must be reclaimable
+	}
+
+	// ProvisioningCost is the money needed for the planned ProvisioningCmd, all fees are maximum fees.
+	ProvisioningCost struct {
+		AddFeeCreditFees uint64 // fees of the transferFC and addFC transactions
+		TransactionFees  uint64 // fees of the planned target partition transactions
+		ReclaimBuffer    uint64 // fee credit left for the closeFC and reclaimFC transactions, zero if not reclaimable
+		FeeCreditAmount  uint64 // the amount to add to fee credit, ie AddFeeCmd.Amount
+		TotalAmount      uint64 // the total money needed, ie TransferAmount + FeeCreditAmount
+	}
+
+	// ReclaimPreview describes the outcome of a fee credit reclaim without executing it.
+	ReclaimPreview struct {
+		TargetBillID       types.UnitID // the bill the reclaimed fee credit is added to
+		TargetBillValue    uint64       // current value of the target bill
+		FeeCreditBalance   uint64       // fee credit record balance to be reclaimed
+		ExpectedFees       uint64       // maximum fees of the closeFC and reclaimFC transactions
+		ProjectedBillValue uint64       // minimum value of the target bill after reclaim
+	}
+
+	// AddFeePlan describes the bills AddFeeCredit would use, without executing it.
+	AddFeePlan struct {
+		Bills  []*AddFeePlanBill // bills in the order they are used, a transferFC and an addFC transaction per bill
+		Amount uint64            // total amount added to fee credit
+		Fees   uint64            // maximum fees of the lockFC, transferFC and addFC transactions
+	}
+
+	AddFeePlanBill struct {
+		Bill   *sdktypes.Bill
+		Amount uint64 // the amount taken from the bill
+	}
+
+	// PendingProcess summarizes an unfinished add or reclaim fee credit process.
+	PendingProcess struct {
+		Kind              string            // PendingAdd or PendingReclaim
+		TargetPartitionID types.PartitionID // partition the fee credit is added to or reclaimed from
+		TargetBillID      types.UnitID      // the bill the fee credit is added from or reclaimed to
+		Amount            uint64            // amount being added or reclaimed, zero if the reclaimed amount is not known yet
+		LastTx            string            // name of the last transaction sent by the process, empty if none has been sent
+	}
+
+	// AddFeeCmdResponse holds the proofs of the bills added to fee credit, the fees paid are returned by TotalFees.
 	AddFeeCmdResponse struct {
 		Proofs []*AddFeeTxProofs
 	}
 
+	// BulkAddFeeError is returned by AddFeeCreditBulk when adding fee credit failed for some of the accounts.
+	BulkAddFeeError struct {
+		Cmds []AddFeeCmd
+		Errs []error // errors in the order of the commands, nil for the commands that succeeded
+	}
+
+	// ReclaimFeeCmdResponse holds the proofs of the reclaim, the fees paid are returned by Proofs.GetFees.
 	ReclaimFeeCmdResponse struct {
 		Proofs *ReclaimFeeTxProofs
 	}
@@ -99,6 +202,9 @@ type (
 		LockFC     *types.TxRecordProof
 		TransferFC *types.TxRecordProof
 		AddFC      *types.TxRecordProof
+		// LatestAdditionTime is the target partition round number of the transferFC after which
+		// the transferred amount can no longer be added to the fee credit record
+		LatestAdditionTime uint64
 	}
 
 	ReclaimFeeTxProofs struct {
@@ -108,18 +214,32 @@ type (
 	}
 
 	AddFeeCreditCtx struct {
-		TargetPartitionID types.PartitionID       `json:"targetPartitionId"`           // target partition id where the fee is being added to
-		TargetBillID      types.UnitID            `json:"targetBillId"`                // transferFC target bill id
-		TargetBillCounter uint64                  `json:"targetBillCounter"`           // transferFC target bill counter
-		TargetAmount      uint64                  `json:"targetAmount"`                // the amount to add to the fee credit record
-		LockingDisabled   bool                    `json:"lockingDisabled,omitempty"`   // user defined flag if we should lock fee credit record when adding fees
-		FeeCreditRecordID types.UnitID            `json:"feeCreditRecordId,omitempty"` // the fee credit record id used in current fee credit process
-		LockFCTx          *types.TransactionOrder `json:"lockFCTx,omitempty"`
-		LockFCProof       *types.TxRecordProof    `json:"lockFCProof,omitempty"`
-		TransferFCTx      *types.TransactionOrder `json:"transferFCTx,omitempty"`
-		TransferFCProof   *types.TxRecordProof    `json:"transferFCProof,omitempty"`
-		AddFCTx           *types.TransactionOrder `json:"addFCTx,omitempty"`
-		AddFCProof        *types.TxRecordProof    `json:"addFCProof,omitempty"`
+		TargetPartitionID  types.PartitionID       `json:"targetPartitionId"`           // target partition id where the fee is being added to
+		TargetBillID       types.UnitID            `json:"targetBillId"`                // transferFC target bill id
+		TargetBillCounter  uint64                  `json:"targetBillCounter"`           // transferFC target bill counter
+		TargetAmount       uint64                  `json:"targetAmount"`                // the amount to add to the fee credit record
+		LockingDisabled    bool                    `json:"lockingDisabled,omitempty"`   // user defined flag if we should lock fee credit record when adding fees
+		LockSkipBelow      uint64                  `json:"lockSkipBelow,omitempty"`     // fee credit record is not locked if its balance is below this amount
+		FeeCreditRecordID  types.UnitID            `json:"feeCreditRecordId,omitempty"` // the fee credit record id used in current fee credit process
+		LockFCTx           *types.TransactionOrder `json:"lockFCTx,omitempty"`
+		LockFCProof        *types.TxRecordProof    `json:"lockFCProof,omitempty"`
+		TransferFCTx       *types.TransactionOrder `json:"transferFCTx,omitempty"`
+		TransferFCProof    *types.TxRecordProof    `json:"transferFCProof,omitempty"`
+		AddFCTx            *types.TransactionOrder `json:"addFCTx,omitempty"`
+		AddFCProof         *types.TxRecordProof    `json:"addFCProof,omitempty"`
+		AddedBefore        uint64                  `json:"addedBefore,omitempty"`        // amount added by the previous bills of the process
+		LatestAdditionTime uint64                  `json:"latestAdditionTime,omitempty"` // latest addition time of the transferFC, zero in contexts stored by older versions
+	}
+
+	// AddFeeProgress tracks the add fee credit process across the bills used by it, so that
+	// an interrupted process is continued toward the amount of the original AddFeeCmd.
+	AddFeeProgress struct {
+		TargetPartitionID types.PartitionID `json:"targetPartitionId"`
+		TargetAmount      uint64            `json:"targetAmount"` // the total amount of the AddFeeCmd
+		AddedAmount       uint64            `json:"addedAmount"`  // the amount added by the completed bills
+		LockingDisabled   bool              `json:"lockingDisabled,omitempty"`
+		LockSkipBelow     uint64            `json:"lockSkipBelow,omitempty"`
+		SourceBillID      types.UnitID      `json:"sourceBillId,omitempty"`
 	}
 
 	ReclaimFeeCreditCtx struct {
@@ -152,6 +272,8 @@ type (
 //   - rpc node client
 //   - fee credit record id generation function
 //   - fee credit record unit type part
+//
+// - options, see WithTargetPartitionFcrUnitType, WithLatestAdditionTime and WithClock
 func NewFeeManager(
 	networkID types.NetworkID,
 	am account.Manager,
@@ -164,8 +286,9 @@ func NewFeeManager(
 	targetPartitionFcrIDFn GenerateFcrID,
 	maxFee uint64,
 	log *slog.Logger,
+	opts ...Option,
 ) *FeeManager {
-	return &FeeManager{
+	w := &FeeManager{
 		networkID:              networkID,
 		am:                     am,
 		db:                     db,
@@ -177,19 +300,148 @@ func NewFeeManager(
 		targetPartitionFcrIDFn: targetPartitionFcrIDFn,
 		log:                    log,
 		maxFee:                 maxFee,
+		clock:                  realClock{},
+		latestAdditionTime:     transferFCLatestAdditionTime,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// WithClock sets the clock used to wait for transaction confirmations, by default real time is used.
+func WithClock(clock Clock) Option {
+	return func(w *FeeManager) {
+		w.clock = clock
+	}
+}
+
+// WithLatestAdditionTime sets the number of target partition rounds after which the transferFC
+// becomes unusable, ie the transferFC must be added to the fee credit record within that many rounds.
+// By default 65536 rounds is used, zero is not a valid window and keeps the default.
+func WithLatestAdditionTime(rounds uint64) Option {
+	return func(w *FeeManager) {
+		if rounds > 0 {
+			w.latestAdditionTime = rounds
+		}
+	}
+}
+
+// WithSendRetries makes the fee manager retry sending a transaction up to maxRetries times, with
+// exponential backoff starting from backoff, when the node returns a transient error.
+func WithSendRetries(maxRetries int, backoff time.Duration) Option {
+	return func(w *FeeManager) {
+		w.batchOpts = append(w.batchOpts, txsubmitter.WithMaxRetries(maxRetries), txsubmitter.WithRetryBackoff(backoff))
+	}
+}
+
+// WithTargetPartitionFcrUnitType sets the expected unit type of the target partition fee credit records,
+// fee credit record IDs generated for the target partition are validated against it before use.
+func WithTargetPartitionFcrUnitType(pdr *types.PartitionDescriptionRecord, unitType uint32) Option {
+	return func(w *FeeManager) {
+		w.targetPartitionPDR = pdr
+		w.targetPartitionFcrUnitType = unitType
+	}
+}
+
+// WithMinAddFeeAmount overrides the minimum amount of fee credit that can be added, by default
+// the minimum is derived from the max fee. Useful for partitions that run with near-zero fees.
+// Zero keeps the default.
+func WithMinAddFeeAmount(amount uint64) Option {
+	return func(w *FeeManager) {
+		w.minAddFeeAmount = amount
+	}
+}
+
+// WithMinReclaimFeeAmount overrides the minimum fee credit balance that can be reclaimed, by default
+// the minimum is derived from the max fee. Zero keeps the default.
+func WithMinReclaimFeeAmount(amount uint64) Option {
+	return func(w *FeeManager) {
+		w.minReclaimFeeAmount = amount
+	}
+}
+
+// SetTargetFcrIDFn replaces the function generating the target partition fee credit record IDs, ie when
+// the wallet migrates to another fee credit record derivation scheme. An add fee credit process must be
+// completed with the fee credit record it was started with, so the function can't be replaced while any
+// account has a running or unfinished add fee credit process, ErrFeeCreditInProgress is returned then.
+func (w *FeeManager) SetTargetFcrIDFn(fn GenerateFcrID) error {
+	if fn == nil {
+		return errors.New("fee credit record ID function must not be nil")
+	}
+	accountKeys, err := w.am.GetAccountKeys()
+	if err != nil {
+		return fmt.Errorf("failed to load account keys: %w", err)
+	}
+	// the locks of all the accounts are held, so that no process starts while the function is replaced
+	for i, accountKey := range accountKeys {
+		unlock, ok := w.accountLocks.tryLock(accountKey.PubKey)
+		if !ok {
+			return fmt.Errorf("%w: account #%d has a running fee credit process", ErrFeeCreditInProgress, i+1)
+		}
+		defer unlock()
+
+		addFeeCtx, err := w.db.GetAddFeeContext(accountKey.PubKey)
+		if err != nil {
+			return fmt.Errorf("failed to load add fee context: %w", err)
+		}
+		progress, err := w.db.GetAddFeeProgress(accountKey.PubKey)
+		if err != nil {
+			return fmt.Errorf("failed to load add fee progress: %w", err)
+		}
+		if addFeeCtx != nil || progress != nil {
+			return fmt.Errorf("%w: account #%d has unfinished add fee credit process", ErrFeeCreditInProgress, i+1)
+		}
 	}
+	w.fcrIDFnMu.Lock()
+	w.targetPartitionFcrIDFn = fn
+	w.fcrIDFnMu.Unlock()
+	return nil
 }
 
 func (w *FeeManager) MinAddFeeAmount() uint64 {
+	if w.minAddFeeAmount > 0 {
+		return w.minAddFeeAmount
+	}
 	// transFC + addFC transaction fees + at least 1 tema left for fcr balance
 	return 2*w.maxFee + 1
 }
 
 func (w *FeeManager) MinReclaimFeeAmount() uint64 {
+	if w.minReclaimFeeAmount > 0 {
+		return w.minReclaimFeeAmount
+	}
 	// closeFC + reclFC transaction fees + at least 1 tema left for target bill
 	return 2*w.maxFee + 1
 }
 
+// EstimateProvisioning computes the money needed to add fee credit and to pay for the planned
+// target partition transactions, so that the fee credit can be provisioned with a single
+// AddFeeCredit call. The fee of the lockFC transaction is not included as it's paid from the
+// existing fee credit.
+func (w *FeeManager) EstimateProvisioning(cmd ProvisioningCmd) (*ProvisioningCost, error) {
+	hi, txFees := bits.Mul64(cmd.TxCount, w.maxFee)
+	if hi != 0 {
+		return nil, fmt.Errorf("transaction fees overflow: %d transactions", cmd.TxCount)
+	}
+	res := &ProvisioningCost{
+		AddFeeCreditFees: 2 * w.maxFee,
+		TransactionFees:  txFees,
+	}
+	if cmd.Reclaimable {
+		res.ReclaimBuffer = w.MinReclaimFeeAmount()
+	}
+	var c1, c2, c3 uint64
+	res.FeeCreditAmount, c1 = bits.Add64(res.AddFeeCreditFees, res.TransactionFees, 0)
+	res.FeeCreditAmount, c2 = bits.Add64(res.FeeCreditAmount, res.ReclaimBuffer, 0)
+	res.FeeCreditAmount = max(res.FeeCreditAmount, w.MinAddFeeAmount())
+	res.TotalAmount, c3 = bits.Add64(res.FeeCreditAmount, cmd.TransferAmount, 0)
+	if c1|c2|c3 != 0 {
+		return nil, errors.New("provisioning amount overflow")
+	}
+	return res, nil
+}
+
 // AddFeeCredit creates fee credit for the given amount. If the wallet does not have a bill large enough for the
 // required amount, multiple bills are used until the target amount is reached. In case of partial add
 // (the add process was previously left in an incomplete state) only the partial bill is added to fee credit.
@@ -202,6 +454,11 @@ func (w *FeeManager) AddFeeCredit(ctx context.Context, cmd AddFeeCmd) (*AddFeeCm
 	if err != nil {
 		return nil, fmt.Errorf("failed to load account key: %w", err)
 	}
+	unlock, err := w.accountLocks.lock(ctx, accountKey.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 
 	// if partial reclaim exists, ask user to finish the reclaim process first
 	reclaimFeeContext, err := w.db.GetReclaimFeeContext(accountKey.PubKey)
@@ -216,6 +473,14 @@ func (w *FeeManager) AddFeeCredit(ctx context.Context, cmd AddFeeCmd) (*AddFeeCm
 	if err != nil {
 		return nil, fmt.Errorf("failed to load fee manager context: %w", err)
 	}
+	progress, err := w.db.GetAddFeeProgress(accountKey.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load add fee progress: %w", err)
+	}
+	if progress != nil && progress.TargetPartitionID != w.targetPartitionID {
+		return nil, fmt.Errorf("%w: pendingProcessPartitionID=%s, providedPartitionID=%s",
+			ErrInvalidPartition, progress.TargetPartitionID, w.targetPartitionID)
+	}
 	if addFeeCtx != nil {
 		// verify fee ctx exists for current partition
 		if addFeeCtx.TargetPartitionID != w.targetPartitionID {
@@ -228,11 +493,26 @@ func (w *FeeManager) AddFeeCredit(ctx context.Context, cmd AddFeeCmd) (*AddFeeCm
 		if err != nil {
 			return nil, fmt.Errorf("failed to complete pending fee credit addition process: %w", err)
 		}
-		// delete fee context
-		if err := w.db.DeleteAddFeeContext(accountKey.PubKey); err != nil {
-			return nil, fmt.Errorf("failed to delete add fee context: %w", err)
+		if err := w.completeAddFeeContext(accountKey, progress, addFeeCtx); err != nil {
+			return nil, err
+		}
+		res := &AddFeeCmdResponse{Proofs: []*AddFeeTxProofs{feeTxProofs}}
+		if progress == nil {
+			return res, nil
+		}
+		// the interrupted process may have more bills to add
+		if res, err = w.continueAddFees(ctx, accountKey, progress, res); err != nil {
+			return nil, fmt.Errorf("failed to complete pending fee credit addition process: %w", err)
+		}
+		return res, nil
+	}
+	if progress != nil {
+		// the process was interrupted between the bills
+		res, err := w.continueAddFees(ctx, accountKey, progress, &AddFeeCmdResponse{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to complete pending fee credit addition process: %w", err)
 		}
-		return &AddFeeCmdResponse{Proofs: []*AddFeeTxProofs{feeTxProofs}}, nil
+		return res, nil
 	}
 
 	// if no fee context found, run normal fee process
@@ -243,6 +523,92 @@ func (w *FeeManager) AddFeeCredit(ctx context.Context, cmd AddFeeCmd) (*AddFeeCm
 	return fees, nil
 }
 
+// AddFeeCreditBulk runs AddFeeCredit for each of the commands sequentially, one failed command does not
+// stop the others from being processed. The returned responses are in the order of the commands, the
+// response of a failed command is nil. If any of the commands failed then *BulkAddFeeError is returned
+// along with the responses.
+func (w *FeeManager) AddFeeCreditBulk(ctx context.Context, cmds []AddFeeCmd) ([]*AddFeeCmdResponse, error) {
+	res := make([]*AddFeeCmdResponse, len(cmds))
+	bulkErr := &BulkAddFeeError{Cmds: cmds, Errs: make([]error, len(cmds))}
+	failed := false
+	for i, cmd := range cmds {
+		if res[i], bulkErr.Errs[i] = w.AddFeeCredit(ctx, cmd); bulkErr.Errs[i] != nil {
+			w.log.WarnContext(ctx, "adding fee credit failed", slog.Uint64("accountIndex", cmd.AccountIndex), slog.Any("error", bulkErr.Errs[i]))
+			failed = true
+		}
+	}
+	if failed {
+		return res, bulkErr
+	}
+	return res, nil
+}
+
+// PlanAddFeeCredit returns the bills AddFeeCredit would use for the cmd and the amount taken from each bill,
+// without sending any transactions. If an add process is pending then the plan describes the pending process,
+// as AddFeeCredit completes it instead of starting a new one.
+func (w *FeeManager) PlanAddFeeCredit(ctx context.Context, cmd AddFeeCmd) (*AddFeePlan, error) {
+	if cmd.Amount < w.MinAddFeeAmount() {
+		return nil, ErrMinimumFeeAmount
+	}
+	accountKey, err := w.am.GetAccountKey(cmd.AccountIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load account key: %w", err)
+	}
+	reclaimFeeContext, err := w.db.GetReclaimFeeContext(accountKey.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reclaim fee context: %w", err)
+	}
+	if reclaimFeeContext != nil {
+		return nil, errors.New("wallet contains unreclaimed fee credit, run the reclaim command before adding fee credit")
+	}
+	addFeeCtx, err := w.db.GetAddFeeContext(accountKey.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fee manager context: %w", err)
+	}
+	if addFeeCtx != nil {
+		return w.planPendingAddFees(ctx, addFeeCtx)
+	}
+	progress, err := w.db.GetAddFeeProgress(accountKey.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load add fee progress: %w", err)
+	}
+	if progress != nil && progress.AddedAmount < progress.TargetAmount {
+		// AddFeeCredit continues the interrupted process instead of starting a new one
+		return w.planAddFees(ctx, accountKey, progress)
+	}
+	return w.planAddFees(ctx, accountKey, &AddFeeProgress{
+		TargetPartitionID: w.targetPartitionID,
+		TargetAmount:      cmd.Amount,
+		LockingDisabled:   cmd.DisableLocking,
+		LockSkipBelow:     cmd.LockSkipBelow,
+		SourceBillID:      cmd.SourceBillID,
+	})
+}
+
+func (w *FeeManager) planPendingAddFees(ctx context.Context, feeCtx *AddFeeCreditCtx) (*AddFeePlan, error) {
+	if feeCtx.TargetPartitionID != w.targetPartitionID {
+		return nil, fmt.Errorf("%w: pendingProcessPartitionID=%s, providedPartitionID=%s",
+			ErrInvalidPartition, feeCtx.TargetPartitionID, w.targetPartitionID)
+	}
+	targetBill, err := w.moneyClient.GetBill(ctx, feeCtx.TargetBillID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch target bill: %w", err)
+	}
+	if targetBill == nil {
+		// the pending transferFC has been executed, only addFC is left
+		targetBill = &sdktypes.Bill{ID: feeCtx.TargetBillID, Counter: feeCtx.TargetBillCounter}
+	}
+	plan := &AddFeePlan{
+		Bills:  []*AddFeePlanBill{{Bill: targetBill, Amount: feeCtx.TargetAmount}},
+		Amount: feeCtx.TargetAmount,
+		Fees:   2 * w.maxFee,
+	}
+	if !feeCtx.LockingDisabled && feeCtx.LockFCTx != nil {
+		plan.Fees += w.maxFee
+	}
+	return plan, nil
+}
+
 // ReclaimFeeCredit reclaims fee credit i.e. reclaims entire fee credit record balance back to the main balance.
 // Reclaimed fee credit is added to the largest bill in wallet.
 // Returns transaction proofs that were used to reclaim fee credit.
@@ -251,6 +617,11 @@ func (w *FeeManager) ReclaimFeeCredit(ctx context.Context, cmd ReclaimFeeCmd) (*
 	if err != nil {
 		return nil, fmt.Errorf("failed to load account key: %w", err)
 	}
+	unlock, err := w.accountLocks.lock(ctx, accountKey.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 
 	// if partial add process exists, finish it first
 	addFeeCtx, err := w.db.GetAddFeeContext(accountKey.PubKey)
@@ -260,6 +631,13 @@ func (w *FeeManager) ReclaimFeeCredit(ctx context.Context, cmd ReclaimFeeCmd) (*
 	if addFeeCtx != nil {
 		return nil, errors.New("wallet contains unadded fee credit, run the add command before reclaiming fee credit")
 	}
+	addFeeProgress, err := w.db.GetAddFeeProgress(accountKey.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load add fee progress: %w", err)
+	}
+	if addFeeProgress != nil {
+		return nil, errors.New("wallet contains unfinished add fee credit process, run the add command before reclaiming fee credit")
+	}
 
 	reclaimFeeCtx, err := w.db.GetReclaimFeeContext(accountKey.PubKey)
 	if err != nil {
@@ -292,6 +670,101 @@ func (w *FeeManager) ReclaimFeeCredit(ctx context.Context, cmd ReclaimFeeCmd) (*
 	return fees, err
 }
 
+// PreviewReclaim returns the target bill and the expected outcome of reclaiming the fee credit of the given account,
+// without sending any transactions. If a reclaim process is pending then the preview describes the pending process.
+func (w *FeeManager) PreviewReclaim(ctx context.Context, accountIndex uint64) (*ReclaimPreview, error) {
+	accountKey, err := w.am.GetAccountKey(accountIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load account key: %w", err)
+	}
+	addFeeCtx, err := w.db.GetAddFeeContext(accountKey.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fee manager context: %w", err)
+	}
+	if addFeeCtx != nil {
+		return nil, errors.New("wallet contains unadded fee credit, run the add command before reclaiming fee credit")
+	}
+	addFeeProgress, err := w.db.GetAddFeeProgress(accountKey.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load add fee progress: %w", err)
+	}
+	if addFeeProgress != nil {
+		return nil, errors.New("wallet contains unfinished add fee credit process, run the add command before reclaiming fee credit")
+	}
+	reclaimFeeCtx, err := w.db.GetReclaimFeeContext(accountKey.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fee context: %w", err)
+	}
+	if reclaimFeeCtx != nil {
+		return w.previewPendingReclaim(ctx, accountKey, reclaimFeeCtx)
+	}
+
+	fcr, err := w.fetchTargetPartitionFCR(ctx, accountKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch fee credit record: %w", err)
+	}
+	if fcr == nil {
+		return nil, errors.New("fee credit record not found")
+	}
+	if fcr.LockStatus != 0 {
+		return nil, errors.New("fee credit record is locked")
+	}
+	if fcr.Balance < w.MinReclaimFeeAmount() {
+		return nil, ErrMinimumFeeAmount
+	}
+	targetBill, err := w.selectReclaimTargetBill(ctx, accountKey)
+	if err != nil {
+		return nil, err
+	}
+	return w.newReclaimPreview(targetBill, fcr.Balance), nil
+}
+
+func (w *FeeManager) previewPendingReclaim(ctx context.Context, accountKey *account.AccountKey, feeCtx *ReclaimFeeCreditCtx) (*ReclaimPreview, error) {
+	if feeCtx.TargetPartitionID != w.targetPartitionID {
+		return nil, fmt.Errorf("%w: pendingProcessPartitionID=%s, providedPartitionID=%s",
+			ErrInvalidPartition, feeCtx.TargetPartitionID, w.targetPartitionID)
+	}
+	targetBill, err := w.moneyClient.GetBill(ctx, feeCtx.TargetBillID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch target bill: %w", err)
+	}
+	if targetBill == nil {
+		return nil, fmt.Errorf("target bill %s of the pending reclaim process not found", types.UnitID(feeCtx.TargetBillID))
+	}
+	// once closeFC has been sent the fee credit record balance no longer reflects the reclaimed amount
+	if feeCtx.CloseFCTx != nil {
+		attr := &fc.CloseFeeCreditAttributes{}
+		if err := feeCtx.CloseFCTx.UnmarshalAttributes(attr); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal closeFC attributes: %w", err)
+		}
+		return w.newReclaimPreview(targetBill, attr.Amount), nil
+	}
+	fcr, err := w.fetchTargetPartitionFCR(ctx, accountKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch fee credit record: %w", err)
+	}
+	if fcr == nil {
+		return nil, errors.New("fee credit record not found")
+	}
+	return w.newReclaimPreview(targetBill, fcr.Balance), nil
+}
+
+func (w *FeeManager) newReclaimPreview(targetBill *sdktypes.Bill, fcrBalance uint64) *ReclaimPreview {
+	// closeFC and reclaimFC fees are paid from the reclaimed amount
+	expectedFees := 2 * w.maxFee
+	var reclaimedAmount uint64
+	if fcrBalance > expectedFees {
+		reclaimedAmount = fcrBalance - expectedFees
+	}
+	return &ReclaimPreview{
+		TargetBillID:       targetBill.ID,
+		TargetBillValue:    targetBill.Value,
+		FeeCreditBalance:   fcrBalance,
+		ExpectedFees:       expectedFees,
+		ProjectedBillValue: targetBill.Value + reclaimedAmount,
+	}
+}
+
 // GetFeeCredit returns fee credit record for given account, returns nil if fee credit record has not been created yet.
 func (w *FeeManager) GetFeeCredit(ctx context.Context, cmd GetFeeCreditCmd) (*sdktypes.FeeCreditRecord, error) {
 	accountKey, err := w.am.GetAccountKey(cmd.AccountIndex)
@@ -301,6 +774,88 @@ func (w *FeeManager) GetFeeCredit(ctx context.Context, cmd GetFeeCreditCmd) (*sd
 	return w.fetchTargetPartitionFCR(ctx, accountKey)
 }
 
+// GetFeeCreditBalances returns the fee credit balances of the given account in the money partition
+// and in the target partition, the balance is zero if the fee credit record does not exist.
+func (w *FeeManager) GetFeeCreditBalances(ctx context.Context, accountIndex uint64) (money, target uint64, err error) {
+	accountKey, err := w.am.GetAccountKey(accountIndex)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to load account key: %w", err)
+	}
+	moneyFCR, err := w.fetchMoneyPartitionFCR(ctx, accountKey)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to fetch money partition fee credit record: %w", err)
+	}
+	targetFCR, err := w.fetchTargetPartitionFCR(ctx, accountKey)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to fetch target partition fee credit record: %w", err)
+	}
+	if moneyFCR != nil {
+		money = moneyFCR.Balance
+	}
+	if targetFCR != nil {
+		target = targetFCR.Balance
+	}
+	return money, target, nil
+}
+
+// GetFeeCreditRecordID generates the fee credit record ID for given account using the target partition
+// fee credit record ID generation function. If the account has a pending add fee credit process then the
+// fee credit record ID stored in the process context is returned instead, as that is the ID the wallet
+// expects the record to have.
+func (w *FeeManager) GetFeeCreditRecordID(ctx context.Context, cmd GetFeeCreditRecordIDCmd) (types.UnitID, error) {
+	accountKey, err := w.am.GetAccountKey(cmd.AccountIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load account key: %w", err)
+	}
+	if cmd.LatestAdditionTime == 0 {
+		feeCtx, err := w.db.GetAddFeeContext(accountKey.PubKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load add fee context: %w", err)
+		}
+		if feeCtx != nil && feeCtx.TargetPartitionID == w.targetPartitionID && len(feeCtx.FeeCreditRecordID) > 0 {
+			return feeCtx.FeeCreditRecordID, nil
+		}
+		roundInfo, err := w.targetPartitionClient.GetRoundInfo(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch target partition round info: %w", err)
+		}
+		cmd.LatestAdditionTime = roundInfo.RoundNumber + w.latestAdditionTime
+	}
+	fcrID, err := w.generateTargetPartitionFcrID(accountKey.PubKey, cmd.LatestAdditionTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate fee credit record id: %w", err)
+	}
+	return fcrID, nil
+}
+
+// GetFeeSpending returns the sum of actual fees paid from the fee credit record of the given account in the
+// target partition blocks of rounds [fromRound, toRound]. Returns zero if the account does not have a fee credit
+// record.
+func (w *FeeManager) GetFeeSpending(ctx context.Context, accountIndex, fromRound, toRound uint64) (uint64, error) {
+	accountKey, err := w.am.GetAccountKey(accountIndex)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load account key: %w", err)
+	}
+	fcr, err := w.fetchTargetPartitionFCR(ctx, accountKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch fee credit record: %w", err)
+	}
+	if fcr == nil {
+		return 0, nil
+	}
+	proofs, err := w.targetPartitionClient.ScanBlocks(ctx, fromRound, toRound, func(tx *types.TransactionOrder) bool {
+		return fcr.ID.Eq(tx.FeeCreditRecordID())
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan blocks: %w", err)
+	}
+	var sum uint64
+	for _, proof := range proofs {
+		sum += proof.ActualFee()
+	}
+	return sum, nil
+}
+
 // LockFeeCredit locks fee credit record for given account, returns error if fee credit record has not been created yet
 // or is already locked.
 func (w *FeeManager) LockFeeCredit(ctx context.Context, cmd LockFeeCreditCmd) (*types.TxRecordProof, error) {
@@ -338,7 +893,7 @@ func (w *FeeManager) LockFeeCredit(ctx context.Context, cmd LockFeeCreditCmd) (*
 		return nil, fmt.Errorf("failed to sign tx auth proof: %w", err)
 	}
 
-	proof, err := w.targetPartitionClient.ConfirmTransaction(ctx, tx, w.log)
+	proof, err := w.confirmTransaction(ctx, w.targetPartitionClient, tx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send lockFC transaction: %w", err)
 	}
@@ -382,13 +937,209 @@ func (w *FeeManager) UnlockFeeCredit(ctx context.Context, cmd UnlockFeeCreditCmd
 		return nil, fmt.Errorf("failed to sign tx auth proof: %w", err)
 	}
 
-	proof, err := w.targetPartitionClient.ConfirmTransaction(ctx, tx, w.log)
+	proof, err := w.confirmTransaction(ctx, w.targetPartitionClient, tx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send unlockFC transaction: %w", err)
 	}
 	return proof, nil
 }
 
+// PendingProcesses returns the contexts of the unfinished add and reclaim fee credit processes of the
+// given account, nil is returned for the process that is not pending.
+func (w *FeeManager) PendingProcesses(accountIndex uint64) (*AddFeeCreditCtx, *ReclaimFeeCreditCtx, error) {
+	accountKey, err := w.am.GetAccountKey(accountIndex)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load account key: %w", err)
+	}
+	addFeeCtx, err := w.db.GetAddFeeContext(accountKey.PubKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load add fee context: %w", err)
+	}
+	reclaimFeeCtx, err := w.db.GetReclaimFeeContext(accountKey.PubKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load reclaim fee context: %w", err)
+	}
+	return addFeeCtx, reclaimFeeCtx, nil
+}
+
+// AbortPending aborts the pending add and reclaim fee credit processes of the given account, if it can be
+// done without losing funds. Locks set by the process are released and the process context is deleted.
+// A process whose irreversible step (transferFC or closeFC) has been confirmed can not be aborted and
+// must be completed instead, ErrNotAbortable is returned for such processes. Transactions of the process
+// that are still in flight are waited for to time out or to be confirmed before deciding.
+func (w *FeeManager) AbortPending(ctx context.Context, accountIndex uint64) error {
+	accountKey, err := w.am.GetAccountKey(accountIndex)
+	if err != nil {
+		return fmt.Errorf("failed to load account key: %w", err)
+	}
+	unlock, err := w.accountLocks.lock(ctx, accountKey.PubKey)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	var errs []error
+	if _, err := w.abortAddFees(ctx, accountKey); err != nil {
+		errs = append(errs, fmt.Errorf("add fee credit process: %w", err))
+	}
+	if _, err := w.abortReclaimFees(ctx, accountKey); err != nil {
+		errs = append(errs, fmt.Errorf("reclaim fee credit process: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// AbortAddFeeCredit aborts the pending add fee credit process of the given account, see AbortPending.
+// Returns the proof of the transaction that unlocked the fee credit record locked by the process, nil
+// proof is returned when the record was not locked or there was no pending process.
+func (w *FeeManager) AbortAddFeeCredit(ctx context.Context, accountIndex uint64) (*types.TxRecordProof, error) {
+	accountKey, err := w.am.GetAccountKey(accountIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load account key: %w", err)
+	}
+	unlock, err := w.accountLocks.lock(ctx, accountKey.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return w.abortAddFees(ctx, accountKey)
+}
+
+// AbortReclaimFeeCredit aborts the pending reclaim fee credit process of the given account, see AbortPending.
+// Returns the proof of the transaction that unlocked the target bill locked by the process, nil proof is
+// returned when the bill was not locked or there was no pending process.
+func (w *FeeManager) AbortReclaimFeeCredit(ctx context.Context, accountIndex uint64) (*types.TxRecordProof, error) {
+	accountKey, err := w.am.GetAccountKey(accountIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load account key: %w", err)
+	}
+	unlock, err := w.accountLocks.lock(ctx, accountKey.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return w.abortReclaimFees(ctx, accountKey)
+}
+
+func (w *FeeManager) abortAddFees(ctx context.Context, accountKey *account.AccountKey) (*types.TxRecordProof, error) {
+	feeCtx, err := w.db.GetAddFeeContext(accountKey.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load add fee context: %w", err)
+	}
+	if feeCtx == nil {
+		// the process may have been interrupted between the bills
+		progress, err := w.db.GetAddFeeProgress(accountKey.PubKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load add fee progress: %w", err)
+		}
+		if progress == nil {
+			return nil, nil
+		}
+		if progress.TargetPartitionID != w.targetPartitionID {
+			return nil, fmt.Errorf("%w: pendingProcessPartitionID=%s, providedPartitionID=%s",
+				ErrInvalidPartition, progress.TargetPartitionID, w.targetPartitionID)
+		}
+		if err := w.db.DeleteAddFeeProgress(accountKey.PubKey); err != nil {
+			return nil, fmt.Errorf("failed to delete add fee progress: %w", err)
+		}
+		w.log.InfoContext(ctx, "aborted pending add fee credit process", slog.String("partitionID", w.targetPartitionID.String()))
+		return nil, nil
+	}
+	if feeCtx.TargetPartitionID != w.targetPartitionID {
+		return nil, fmt.Errorf("%w: pendingProcessPartitionID=%s, providedPartitionID=%s",
+			ErrInvalidPartition, feeCtx.TargetPartitionID, w.targetPartitionID)
+	}
+	// transferFC moves the funds to fee credit, it can only be completed with addFC
+	if feeCtx.TransferFCProof == nil && feeCtx.TransferFCTx != nil {
+		proof, err := waitForConf(ctx, w.clock, w.moneyClient, feeCtx.TransferFCTx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to wait for transferFC confirmation: %w", err)
+		}
+		if proof != nil {
+			feeCtx.TransferFCProof = proof
+			if err := w.db.SetAddFeeContext(accountKey.PubKey, feeCtx); err != nil {
+				return nil, fmt.Errorf("failed to store transferFC proof: %w", err)
+			}
+		}
+	}
+	if feeCtx.TransferFCProof != nil {
+		return nil, fmt.Errorf("%w: transferFC is confirmed, add fee credit must be completed", ErrNotAbortable)
+	}
+
+	if feeCtx.LockFCProof == nil && feeCtx.LockFCTx != nil {
+		proof, err := waitForConf(ctx, w.clock, w.targetPartitionClient, feeCtx.LockFCTx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to wait for lockFC confirmation: %w", err)
+		}
+		feeCtx.LockFCProof = proof
+	}
+	var unlockProof *types.TxRecordProof
+	if feeCtx.LockFCProof != nil {
+		if unlockProof, err = w.unlockFeeCreditRecord(ctx, accountKey); err != nil {
+			return nil, fmt.Errorf("failed to unlock fee credit record: %w", err)
+		}
+	}
+	if err := w.db.DeleteAddFeeContext(accountKey.PubKey); err != nil {
+		return nil, fmt.Errorf("failed to delete add fee context: %w", err)
+	}
+	if err := w.db.DeleteAddFeeProgress(accountKey.PubKey); err != nil {
+		return nil, fmt.Errorf("failed to delete add fee progress: %w", err)
+	}
+	w.log.InfoContext(ctx, "aborted pending add fee credit process", slog.String("partitionID", w.targetPartitionID.String()))
+	return unlockProof, nil
+}
+
+func (w *FeeManager) abortReclaimFees(ctx context.Context, accountKey *account.AccountKey) (*types.TxRecordProof, error) {
+	feeCtx, err := w.db.GetReclaimFeeContext(accountKey.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reclaim fee context: %w", err)
+	}
+	if feeCtx == nil {
+		return nil, nil
+	}
+	if feeCtx.TargetPartitionID != w.targetPartitionID {
+		return nil, fmt.Errorf("%w: pendingProcessPartitionID=%s, providedPartitionID=%s",
+			ErrInvalidPartition, feeCtx.TargetPartitionID, w.targetPartitionID)
+	}
+	// closeFC closes the fee credit, it can only be completed with reclaimFC
+	if feeCtx.CloseFCProof == nil && feeCtx.CloseFCTx != nil {
+		proof, err := waitForConf(ctx, w.clock, w.targetPartitionClient, feeCtx.CloseFCTx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to wait for closeFC confirmation: %w", err)
+		}
+		if proof != nil {
+			feeCtx.CloseFCProof = proof
+			if err := w.db.SetReclaimFeeContext(accountKey.PubKey, feeCtx); err != nil {
+				return nil, fmt.Errorf("failed to store closeFC proof: %w", err)
+			}
+		}
+	}
+	if feeCtx.CloseFCProof != nil {
+		return nil, fmt.Errorf("%w: closeFC is confirmed, reclaim fee credit must be completed", ErrNotAbortable)
+	}
+
+	if feeCtx.LockTxProof == nil && feeCtx.LockTx != nil {
+		proof, err := waitForConf(ctx, w.clock, w.moneyClient, feeCtx.LockTx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to wait for lock confirmation: %w", err)
+		}
+		feeCtx.LockTxProof = proof
+	}
+	var unlockProof *types.TxRecordProof
+	if feeCtx.LockTxProof != nil {
+		targetBill, err := w.moneyClient.GetBill(ctx, feeCtx.TargetBillID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch target bill: %w", err)
+		}
+		if unlockProof, err = w.unlockBill(ctx, accountKey, targetBill); err != nil {
+			return nil, fmt.Errorf("failed to unlock target bill: %w", err)
+		}
+	}
+	if err := w.db.DeleteReclaimFeeContext(accountKey.PubKey); err != nil {
+		return nil, fmt.Errorf("failed to delete reclaim fee context: %w", err)
+	}
+	w.log.InfoContext(ctx, "aborted pending reclaim fee credit process", slog.String("partitionID", w.targetPartitionID.String()))
+	return unlockProof, nil
+}
+
 // Close propagates call to all dependencies
 func (w *FeeManager) Close() {
 	_ = w.db.Close()
@@ -400,6 +1151,79 @@ func (w *FeeManager) Close() {
 
 // addFees runs normal fee credit creation process for multiple bills
 func (w *FeeManager) addFees(ctx context.Context, accountKey *account.AccountKey, cmd AddFeeCmd) (*AddFeeCmdResponse, error) {
+	progress := &AddFeeProgress{
+		TargetPartitionID: w.targetPartitionID,
+		TargetAmount:      cmd.Amount,
+		LockingDisabled:   cmd.DisableLocking,
+		LockSkipBelow:     cmd.LockSkipBelow,
+		SourceBillID:      cmd.SourceBillID,
+	}
+	return w.continueAddFees(ctx, accountKey, progress, &AddFeeCmdResponse{})
+}
+
+// continueAddFees adds the amount not yet added by the process to fee credit, one bill at a time. The
+// progress is stored before the first bill and updated after each bill, so that an interrupted process
+// continues toward the original amount. The proofs of the bills are appended to res.
+func (w *FeeManager) continueAddFees(ctx context.Context, accountKey *account.AccountKey, progress *AddFeeProgress, res *AddFeeCmdResponse) (*AddFeeCmdResponse, error) {
+	if progress.AddedAmount < progress.TargetAmount {
+		plan, err := w.planAddFees(ctx, accountKey, progress)
+		if err != nil {
+			return nil, err
+		}
+		if err := w.db.SetAddFeeProgress(accountKey.PubKey, progress); err != nil {
+			return nil, fmt.Errorf("failed to store add fee progress: %w", err)
+		}
+
+		// send fee credit transactions
+		for _, planBill := range plan.Bills {
+			feeCtx := &AddFeeCreditCtx{
+				TargetPartitionID: w.targetPartitionID,
+				TargetBillID:      planBill.Bill.ID,
+				TargetBillCounter: planBill.Bill.Counter,
+				TargetAmount:      planBill.Amount,
+				LockingDisabled:   progress.LockingDisabled,
+				LockSkipBelow:     progress.LockSkipBelow,
+				AddedBefore:       progress.AddedAmount,
+			}
+			if err := w.db.SetAddFeeContext(accountKey.PubKey, feeCtx); err != nil {
+				return nil, fmt.Errorf("failed to initialise fee context: %w", err)
+			}
+			proofs, err := w.addFeeCredit(ctx, accountKey, feeCtx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to add fee credit: %w", err)
+			}
+			res.Proofs = append(res.Proofs, proofs)
+			if err := w.completeAddFeeContext(accountKey, progress, feeCtx); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := w.db.DeleteAddFeeProgress(accountKey.PubKey); err != nil {
+		return nil, fmt.Errorf("failed to delete add fee progress: %w", err)
+	}
+	return res, nil
+}
+
+// completeAddFeeContext records the amount of the completed bill in the progress of the process (if any)
+// and deletes the context of the bill. The added amount is derived from the context, so completing the
+// same context again does not count the bill twice.
+func (w *FeeManager) completeAddFeeContext(accountKey *account.AccountKey, progress *AddFeeProgress, feeCtx *AddFeeCreditCtx) error {
+	if progress != nil {
+		progress.AddedAmount = feeCtx.AddedBefore + feeCtx.TargetAmount
+		if err := w.db.SetAddFeeProgress(accountKey.PubKey, progress); err != nil {
+			return fmt.Errorf("failed to store add fee progress: %w", err)
+		}
+	}
+	if err := w.db.DeleteAddFeeContext(accountKey.PubKey); err != nil {
+		return fmt.Errorf("failed to delete add fee context: %w", err)
+	}
+	return nil
+}
+
+// planAddFees selects the bills used to add the amount not yet added by the process to fee credit,
+// if the source bill of the process is set then only that bill is used.
+func (w *FeeManager) planAddFees(ctx context.Context, accountKey *account.AccountKey, progress *AddFeeProgress) (*AddFeePlan, error) {
+	targetAmount := progress.TargetAmount - progress.AddedAmount
 	fcr, err := w.fetchTargetPartitionFCR(ctx, accountKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch fee credit record: %w", err)
@@ -408,6 +1232,10 @@ func (w *FeeManager) addFees(ctx context.Context, accountKey *account.AccountKey
 	if fcr != nil && fcr.LockStatus != 0 {
 		return nil, fmt.Errorf("fee credit record is locked")
 	}
+	var fcrBalance uint64
+	if fcr != nil {
+		fcrBalance = fcr.Balance
+	}
 
 	bills, err := w.fetchBills(ctx, accountKey)
 	if err != nil {
@@ -419,6 +1247,15 @@ func (w *FeeManager) addFees(ctx context.Context, accountKey *account.AccountKey
 		return nil, errors.New("wallet does not contain any bills")
 	}
 
+	if len(progress.SourceBillID) > 0 {
+		plan, err := w.planAddFeesFromBill(bills, targetAmount, progress.SourceBillID)
+		if err != nil {
+			return nil, err
+		}
+		plan.Fees += w.lockFCFee(progress, fcrBalance)
+		return plan, nil
+	}
+
 	// filter locked bills
 	bills, _ = util.FilterSlice(bills, func(b *sdktypes.Bill) (bool, error) {
 		return b.LockStatus == 0, nil
@@ -433,42 +1270,57 @@ func (w *FeeManager) addFees(ctx context.Context, accountKey *account.AccountKey
 	balance := w.sumValues(bills)
 
 	// verify enough balance for all transactions
-	var targetAmount = cmd.Amount
 	if balance < targetAmount {
 		return nil, ErrInsufficientBalance
 	}
 
-	// send fee credit transactions
-	res := &AddFeeCmdResponse{}
-	var totalTransferredAmount uint64
+	plan := &AddFeePlan{}
 	for _, targetBill := range bills {
-		if totalTransferredAmount >= targetAmount {
+		if plan.Amount >= targetAmount {
 			break
 		}
-		// send fee credit transactions
-		amount := min(targetBill.Value, targetAmount-totalTransferredAmount)
-		totalTransferredAmount += amount
+		amount := min(targetBill.Value, targetAmount-plan.Amount)
+		plan.Amount += amount
+		plan.Fees += 2*w.maxFee + w.lockFCFee(progress, fcrBalance)
+		plan.Bills = append(plan.Bills, &AddFeePlanBill{Bill: targetBill, Amount: amount})
+		// the fee credit record has the amount of the previous bills when the next one is added
+		fcrBalance += amount
+	}
+	return plan, nil
+}
 
-		feeCtx := &AddFeeCreditCtx{
-			TargetPartitionID: w.targetPartitionID,
-			TargetBillID:      targetBill.ID,
-			TargetBillCounter: targetBill.Counter,
-			TargetAmount:      amount,
-			LockingDisabled:   cmd.DisableLocking,
-		}
-		if err := w.db.SetAddFeeContext(accountKey.PubKey, feeCtx); err != nil {
-			return nil, fmt.Errorf("failed to initialise fee context: %w", err)
-		}
-		proofs, err := w.addFeeCredit(ctx, accountKey, feeCtx)
-		if err != nil {
-			return nil, fmt.Errorf("failed to add fee credit: %w", err)
-		}
-		res.Proofs = append(res.Proofs, proofs)
-		if err := w.db.DeleteAddFeeContext(accountKey.PubKey); err != nil {
-			return nil, fmt.Errorf("failed to delete add fee context: %w", err)
+// lockFCFee returns the max fee of the lockFC transaction sent before adding fee credit to the
+// record of the given balance, zero if the record is not locked by the process.
+func (w *FeeManager) lockFCFee(progress *AddFeeProgress, fcrBalance uint64) uint64 {
+	if progress.LockingDisabled || fcrBalance == 0 || fcrBalance < progress.LockSkipBelow {
+		return 0
+	}
+	return w.maxFee
+}
+
+// planAddFeesFromBill returns the plan of adding the target amount to fee credit from the given bill only.
+func (w *FeeManager) planAddFeesFromBill(bills []*sdktypes.Bill, targetAmount uint64, billID types.UnitID) (*AddFeePlan, error) {
+	var bill *sdktypes.Bill
+	for _, b := range bills {
+		if b.ID.Eq(billID) {
+			bill = b
+			break
 		}
 	}
-	return res, nil
+	if bill == nil {
+		return nil, fmt.Errorf("bill %s not found", billID)
+	}
+	if bill.LockStatus != 0 {
+		return nil, fmt.Errorf("bill %s is locked", billID)
+	}
+	if bill.Value < targetAmount {
+		return nil, fmt.Errorf("%w: bill %s value %d is less than the fee credit amount %d", ErrInsufficientBalance, billID, bill.Value, targetAmount)
+	}
+	return &AddFeePlan{
+		Bills:  []*AddFeePlanBill{{Bill: bill, Amount: targetAmount}},
+		Amount: targetAmount,
+		Fees:   2 * w.maxFee,
+	}, nil
 }
 
 // addFeeCredit runs the add fee credit process for single bill, stores the process status in WriteAheadLog which can be
@@ -483,13 +1335,35 @@ func (w *FeeManager) addFeeCredit(ctx context.Context, accountKey *account.Accou
 	if err := w.sendAddFCTx(ctx, accountKey, feeCtx); err != nil {
 		return nil, fmt.Errorf("failed to addFC: %w", err)
 	}
+	latestAdditionTime := feeCtx.LatestAdditionTime
+	if latestAdditionTime == 0 {
+		// the addFC is already confirmed, failing to read the latest addition time must not fail the process
+		var err error
+		if latestAdditionTime, err = getLatestAdditionTime(feeCtx.TransferFCProof); err != nil {
+			w.log.WarnContext(ctx, "reading latest addition time of the transferFC", slog.Any("error", err))
+		}
+	}
 	return &AddFeeTxProofs{
-		LockFC:     feeCtx.LockFCProof,
-		TransferFC: feeCtx.TransferFCProof,
-		AddFC:      feeCtx.AddFCProof,
+		LockFC:             feeCtx.LockFCProof,
+		TransferFC:         feeCtx.TransferFCProof,
+		AddFC:              feeCtx.AddFCProof,
+		LatestAdditionTime: latestAdditionTime,
 	}, nil
 }
 
+// getLatestAdditionTime returns the latest addition time of the transferFC transaction of the proof.
+func getLatestAdditionTime(transferFCProof *types.TxRecordProof) (uint64, error) {
+	feeTx, err := transferFCProof.TxRecord.GetTransactionOrderV1()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get transferFC transaction order: %w", err)
+	}
+	transferFCAttr := &fc.TransferFeeCreditAttributes{}
+	if err := feeTx.UnmarshalAttributes(transferFCAttr); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal transferFC attributes: %w", err)
+	}
+	return transferFCAttr.LatestAdditionTime, nil
+}
+
 func (w *FeeManager) sendLockFCTx(ctx context.Context, accountKey *account.AccountKey, feeCtx *AddFeeCreditCtx) error {
 	if feeCtx.LockingDisabled {
 		return nil
@@ -502,7 +1376,7 @@ func (w *FeeManager) sendLockFCTx(ctx context.Context, accountKey *account.Accou
 	// if confirmed => store proof
 	// if not confirmed => create new transaction
 	if feeCtx.LockFCTx != nil {
-		proof, err := waitForConf(ctx, w.targetPartitionClient, feeCtx.LockFCTx)
+		proof, err := waitForConf(ctx, w.clock, w.targetPartitionClient, feeCtx.LockFCTx)
 		if err != nil {
 			return fmt.Errorf("failed to wait for confirmation: %w", err)
 		}
@@ -511,7 +1385,7 @@ func (w *FeeManager) sendLockFCTx(ctx context.Context, accountKey *account.Accou
 			if err != nil {
 				return fmt.Errorf("failed to hash lockFC tx: %w", err)
 			}
-			w.log.InfoContext(ctx, fmt.Sprintf("lockFC tx '%x' confirmed", txHash))
+			w.log.InfoContext(ctx, "lockFC tx confirmed", slog.String("txHash", util.FormatTxID(txHash)))
 			feeCtx.LockFCProof = proof
 			if err := w.db.SetAddFeeContext(accountKey.PubKey, feeCtx); err != nil {
 				return fmt.Errorf("failed to store lockFC proof: %w", err)
@@ -528,6 +1402,11 @@ func (w *FeeManager) sendLockFCTx(ctx context.Context, accountKey *account.Accou
 		w.log.Info("skipping lockFC transaction, target partition fee credit record does not exist or has zero value")
 		return nil
 	}
+	if fcr.Balance < feeCtx.LockSkipBelow {
+		w.log.InfoContext(ctx, "skipping lockFC transaction, target partition fee credit record balance is below the lock threshold",
+			slog.Uint64("balance", fcr.Balance), slog.Uint64("threshold", feeCtx.LockSkipBelow))
+		return nil
+	}
 	// verify fee credit record is not locked
 	if fcr.LockStatus != 0 {
 		return errors.New("fee credit record is locked")
@@ -540,7 +1419,6 @@ func (w *FeeManager) sendLockFCTx(ctx context.Context, accountKey *account.Accou
 	}
 
 	// create lockFC
-	w.log.InfoContext(ctx, "sending lock fee credit transaction")
 	tx, err := fcr.Lock(wallet.LockReasonAddFees,
 		sdktypes.WithTimeout(targetPartitionTimeout),
 		sdktypes.WithMaxFee(w.maxFee),
@@ -564,7 +1442,8 @@ func (w *FeeManager) sendLockFCTx(ctx context.Context, accountKey *account.Accou
 	}
 
 	// send lockFC transaction
-	proof, err := w.targetPartitionClient.ConfirmTransaction(ctx, tx, w.log)
+	w.log.InfoContext(ctx, "sending lock fee credit transaction", txLogAttrs(tx)...)
+	proof, err := w.confirmTransaction(ctx, w.targetPartitionClient, tx)
 	if err != nil {
 		return fmt.Errorf("failed to send lockFC transaction: %w", err)
 	}
@@ -586,7 +1465,7 @@ func (w *FeeManager) sendTransferFCTx(ctx context.Context, accountKey *account.A
 	//   if confirmed => store proof
 	//   if not confirmed => verify target bill and create new transaction, or return error
 	if feeCtx.TransferFCTx != nil {
-		proof, err := waitForConf(ctx, w.moneyClient, feeCtx.TransferFCTx)
+		proof, err := waitForConf(ctx, w.clock, w.moneyClient, feeCtx.TransferFCTx)
 		if err != nil {
 			return fmt.Errorf("failed to wait for confirmation: %w", err)
 		}
@@ -631,16 +1510,15 @@ func (w *FeeManager) sendTransferFCTx(ctx context.Context, accountKey *account.A
 	if err != nil {
 		return fmt.Errorf("failed to fetch target partition round info: %w", err)
 	}
-	latestAdditionTime := targetRoundInfo.RoundNumber + transferFCLatestAdditionTime
+	latestAdditionTime := targetRoundInfo.RoundNumber + w.latestAdditionTime
 
 	// create transferFC transaction
-	w.log.InfoContext(ctx, "sending transfer fee credit transaction")
 	fcr, err := w.fetchTargetPartitionFCR(ctx, accountKey)
 	if err != nil {
 		return fmt.Errorf("failed to fetch fee credit record: %w", err)
 	}
 	if fcr == nil {
-		fcrID, err := w.targetPartitionFcrIDFn(types.ShardID{}, accountKey.PubKey, latestAdditionTime)
+		fcrID, err := w.generateTargetPartitionFcrID(accountKey.PubKey, latestAdditionTime)
 		if err != nil {
 			return fmt.Errorf("failed to generate fee credit record id: %w", err)
 		}
@@ -674,12 +1552,16 @@ func (w *FeeManager) sendTransferFCTx(ctx context.Context, accountKey *account.A
 	// store transferFC transaction write-ahead log
 	feeCtx.TransferFCTx = tx
 	feeCtx.FeeCreditRecordID = fcr.ID
+	feeCtx.LatestAdditionTime = latestAdditionTime
 	if err := w.db.SetAddFeeContext(accountKey.PubKey, feeCtx); err != nil {
 		return fmt.Errorf("failed to store transferFC write-ahead log: %w", err)
 	}
 
 	// send transferFC transaction to money partition
-	proof, err := w.moneyClient.ConfirmTransaction(ctx, tx, w.log)
+	w.log.InfoContext(ctx, "sending transfer fee credit transaction", append(txLogAttrs(tx),
+		slog.Uint64("amount", feeCtx.TargetAmount),
+		slog.String("targetPartitionID", w.targetPartitionID.String()))...)
+	proof, err := w.confirmTransaction(ctx, w.moneyClient, tx)
 	if err != nil {
 		return fmt.Errorf("failed to send transferFC transaction: %w", err)
 	}
@@ -704,7 +1586,7 @@ func (w *FeeManager) sendAddFCTx(ctx context.Context, accountKey *account.Accoun
 	//     if yes => create new addFC with existing transferFC proof
 	//     if not => unlock remote fee credit record and delete fee context
 	if feeCtx.AddFCTx != nil {
-		proof, err := waitForConf(ctx, w.targetPartitionClient, feeCtx.AddFCTx)
+		proof, err := waitForConf(ctx, w.clock, w.targetPartitionClient, feeCtx.AddFCTx)
 		if err != nil {
 			return fmt.Errorf("failed to wait for confirmation: %w", err)
 		}
@@ -715,19 +1597,15 @@ func (w *FeeManager) sendAddFCTx(ctx context.Context, accountKey *account.Accoun
 			}
 			return nil
 		}
-		feeTx, err := feeCtx.TransferFCProof.TxRecord.GetTransactionOrderV1()
+		latestAdditionTime, err := getLatestAdditionTime(feeCtx.TransferFCProof)
 		if err != nil {
-			return fmt.Errorf("failed to get transferFC transaction order: %w", err)
-		}
-		transferFCAttr := &fc.TransferFeeCreditAttributes{}
-		if err := feeTx.UnmarshalAttributes(transferFCAttr); err != nil {
-			return fmt.Errorf("failed to unmarshal transferFC attributes: %w", err)
+			return err
 		}
 		roundInfo, err := w.targetPartitionClient.GetRoundInfo(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to fetch target partition round info: %w", err)
 		}
-		if roundInfo.RoundNumber >= transferFCAttr.LatestAdditionTime {
+		if roundInfo.RoundNumber >= latestAdditionTime {
 			_, err := w.unlockFeeCreditRecord(ctx, accountKey)
 			if err != nil {
 				return fmt.Errorf("failed to unlock remote fee credit record: %w", err)
@@ -777,8 +1655,8 @@ func (w *FeeManager) sendAddFCTx(ctx context.Context, accountKey *account.Accoun
 	}
 
 	// send addFC transaction
-	w.log.InfoContext(ctx, "sending add fee credit transaction")
-	proof, err := w.targetPartitionClient.ConfirmTransaction(ctx, addFCTx, w.log)
+	w.log.InfoContext(ctx, "sending add fee credit transaction", txLogAttrs(addFCTx)...)
+	proof, err := w.confirmTransaction(ctx, w.targetPartitionClient, addFCTx)
 	if err != nil {
 		return fmt.Errorf("failed to send addFC transaction: %w", err)
 	}
@@ -809,18 +1687,10 @@ func (w *FeeManager) reclaimFees(ctx context.Context, accountKey *account.Accoun
 		return nil, ErrMinimumFeeAmount
 	}
 
-	// select largest bill as the target
-	bills, err := w.fetchBills(ctx, accountKey)
+	targetBill, err := w.selectReclaimTargetBill(ctx, accountKey)
 	if err != nil {
 		return nil, err
 	}
-	bills, _ = util.FilterSlice(bills, func(b *sdktypes.Bill) (bool, error) {
-		return b.LockStatus == 0, nil
-	})
-	if len(bills) == 0 {
-		return nil, errors.New("wallet must have a source bill to which to add reclaimed fee credits")
-	}
-	targetBill := bills[0]
 
 	// create fee ctx to track reclaim process
 	feeCtx := &ReclaimFeeCreditCtx{
@@ -842,6 +1712,21 @@ func (w *FeeManager) reclaimFees(ctx context.Context, accountKey *account.Accoun
 	return &ReclaimFeeCmdResponse{Proofs: feeTxProofs}, nil
 }
 
+// selectReclaimTargetBill returns the largest unlocked bill of the account.
+func (w *FeeManager) selectReclaimTargetBill(ctx context.Context, accountKey *account.AccountKey) (*sdktypes.Bill, error) {
+	bills, err := w.fetchBills(ctx, accountKey)
+	if err != nil {
+		return nil, err
+	}
+	bills, _ = util.FilterSlice(bills, func(b *sdktypes.Bill) (bool, error) {
+		return b.LockStatus == 0, nil
+	})
+	if len(bills) == 0 {
+		return nil, errors.New("wallet must have a source bill to which to add reclaimed fee credits")
+	}
+	return bills[0], nil
+}
+
 // reclaimFeeCredit runs the reclaim fee credit process for single bill, stores the process status in WriteAheadLog
 // which can be used to continue the process later, in case of any errors.
 func (w *FeeManager) reclaimFeeCredit(ctx context.Context, accountKey *account.AccountKey, feeCtx *ReclaimFeeCreditCtx) (*ReclaimFeeTxProofs, error) {
@@ -871,7 +1756,7 @@ func (w *FeeManager) sendLockTx(ctx context.Context, accountKey *account.Account
 	}
 	// if lock tx already exists then wait for confirmation => if confirmed store proof else create new transaction
 	if feeCtx.LockTx != nil {
-		proof, err := waitForConf(ctx, w.moneyClient, feeCtx.LockTx)
+		proof, err := waitForConf(ctx, w.clock, w.moneyClient, feeCtx.LockTx)
 		if err != nil {
 			return fmt.Errorf("failed to wait for confirmation: %w", err)
 		}
@@ -880,7 +1765,7 @@ func (w *FeeManager) sendLockTx(ctx context.Context, accountKey *account.Account
 			if err != nil {
 				return fmt.Errorf("failed to hash lockFC tx: %w", err)
 			}
-			w.log.InfoContext(ctx, fmt.Sprintf("lock tx '%x' confirmed", txHash))
+			w.log.InfoContext(ctx, "lock tx confirmed", slog.String("txHash", util.FormatTxID(txHash)))
 			feeCtx.LockTxProof = proof
 			feeCtx.TargetBillCounter += 1
 			if err := w.db.SetReclaimFeeContext(accountKey.PubKey, feeCtx); err != nil {
@@ -934,8 +1819,8 @@ func (w *FeeManager) sendLockTx(ctx context.Context, accountKey *account.Account
 	}
 
 	// send lock transaction
-	w.log.InfoContext(ctx, "sending lock transaction")
-	proof, err := w.moneyClient.ConfirmTransaction(ctx, tx, w.log)
+	w.log.InfoContext(ctx, "sending lock transaction", txLogAttrs(tx)...)
+	proof, err := w.confirmTransaction(ctx, w.moneyClient, tx)
 	if err != nil {
 		return fmt.Errorf("failed to send lock transaction: %w", err)
 	}
@@ -958,7 +1843,7 @@ func (w *FeeManager) sendCloseFCTx(ctx context.Context, accountKey *account.Acco
 	// if confirmed => store proof
 	// if not confirmed => create new transaction
 	if feeCtx.CloseFCTx != nil {
-		proof, err := waitForConf(ctx, w.targetPartitionClient, feeCtx.CloseFCTx)
+		proof, err := waitForConf(ctx, w.clock, w.targetPartitionClient, feeCtx.CloseFCTx)
 		if err != nil {
 			return fmt.Errorf("failed to wait for confirmation: %w", err)
 		}
@@ -1012,8 +1897,9 @@ func (w *FeeManager) sendCloseFCTx(ctx context.Context, accountKey *account.Acco
 	}
 
 	// send closeFC transaction to target partition
-	w.log.InfoContext(ctx, "sending close fee credit transaction")
-	proof, err := w.targetPartitionClient.ConfirmTransaction(ctx, tx, w.log)
+	w.log.InfoContext(ctx, "sending close fee credit transaction", append(txLogAttrs(tx),
+		slog.String("targetBillID", types.UnitID(feeCtx.TargetBillID).String()))...)
+	proof, err := w.confirmTransaction(ctx, w.targetPartitionClient, tx)
 	if err != nil {
 		return fmt.Errorf("failed to send closeFC transaction: %w", err)
 	}
@@ -1036,9 +1922,10 @@ func (w *FeeManager) sendReclaimFCTx(ctx context.Context, accountKey *account.Ac
 	// if not confirmed =>
 	//   check if closeFC proof is still usable =>
 	//     if yes => create new reclaimFC with existing closeFC proof
-	//     if not => unlock target bill and delete fee context
+	//     if not => unlock target bill and delete fee context, the closeFC proof is bound to
+	//       the target bill ID and counter, it can not be used to reclaim to another bill
 	if feeCtx.ReclaimFCTx != nil {
-		proof, err := waitForConf(ctx, w.moneyClient, feeCtx.ReclaimFCTx)
+		proof, err := waitForConf(ctx, w.clock, w.moneyClient, feeCtx.ReclaimFCTx)
 		if err != nil {
 			return fmt.Errorf("failed to wait for confirmation: %w", err)
 		}
@@ -1063,7 +1950,7 @@ func (w *FeeManager) sendReclaimFCTx(ctx context.Context, accountKey *account.Ac
 			if err := w.db.DeleteReclaimFeeContext(accountKey.PubKey); err != nil {
 				return fmt.Errorf("failed to delete reclaim fee context: %w", err)
 			}
-			return errors.New("reclaimFC target bill is no longer usable")
+			return ErrReclaimTargetBillSpent
 		}
 		w.log.InfoContext(ctx, "reclaimFC timed out, but closeFC is still valid, sending new reclaimFC transaction")
 	}
@@ -1102,8 +1989,8 @@ func (w *FeeManager) sendReclaimFCTx(ctx context.Context, accountKey *account.Ac
 	}
 
 	// send reclaimFC transaction
-	w.log.InfoContext(ctx, "sending reclaim fee credit transaction")
-	proof, err := w.moneyClient.ConfirmTransaction(ctx, reclaimFC, w.log)
+	w.log.InfoContext(ctx, "sending reclaim fee credit transaction", txLogAttrs(reclaimFC)...)
+	proof, err := w.confirmTransaction(ctx, w.moneyClient, reclaimFC)
 	if err != nil {
 		return fmt.Errorf("failed to send reclaimFC transaction: %w", err)
 	}
@@ -1152,6 +2039,29 @@ func (w *FeeManager) sumValues(bills []*sdktypes.Bill) uint64 {
 	return sum
 }
 
+// generateTargetPartitionFcrID generates the target partition fee credit record ID and, if the fee credit record
+// unit type is configured, verifies that the generated ID has the expected unit type.
+func (w *FeeManager) generateTargetPartitionFcrID(pubKey []byte, latestAdditionTime uint64) (types.UnitID, error) {
+	w.fcrIDFnMu.Lock()
+	generateFcrID := w.targetPartitionFcrIDFn
+	w.fcrIDFnMu.Unlock()
+	fcrID, err := generateFcrID(types.ShardID{}, pubKey, latestAdditionTime)
+	if err != nil {
+		return nil, err
+	}
+	if w.targetPartitionPDR == nil {
+		return fcrID, nil
+	}
+	unitType, err := w.targetPartitionPDR.ExtractUnitType(fcrID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFcrUnitType, err)
+	}
+	if unitType != w.targetPartitionFcrUnitType {
+		return nil, fmt.Errorf("%w: generated id %s has unit type %d, expected %d", ErrInvalidFcrUnitType, fcrID, unitType, w.targetPartitionFcrUnitType)
+	}
+	return fcrID, nil
+}
+
 func (w *FeeManager) fetchTargetPartitionFCR(ctx context.Context, accountKey *account.AccountKey) (*sdktypes.FeeCreditRecord, error) {
 	return w.targetPartitionClient.GetFeeCreditRecordByOwnerID(ctx, accountKey.PubKeyHash.Sha256)
 }
@@ -1196,7 +2106,7 @@ func (w *FeeManager) unlockFeeCreditRecord(ctx context.Context, accountKey *acco
 		return nil, fmt.Errorf("failed to sign tx fee proof: %w", err)
 	}
 
-	proof, err := w.targetPartitionClient.ConfirmTransaction(ctx, tx, w.log)
+	proof, err := w.confirmTransaction(ctx, w.targetPartitionClient, tx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send unlockFC tx: %w", err)
 	}
@@ -1235,7 +2145,7 @@ func (w *FeeManager) unlockBill(ctx context.Context, accountKey *account.Account
 			return nil, fmt.Errorf("failed to sign tx: %w", err)
 		}
 
-		proof, err := w.moneyClient.ConfirmTransaction(ctx, unlockTx, w.log)
+		proof, err := w.confirmTransaction(ctx, w.moneyClient, unlockTx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to send unlock tx: %w", err)
 		}
@@ -1244,6 +2154,19 @@ func (w *FeeManager) unlockBill(ctx context.Context, accountKey *account.Account
 	return nil, nil
 }
 
+// TotalFees returns the sum of the fees paid for the transactions of all the bills of the response.
+func (r *AddFeeCmdResponse) TotalFees() uint64 {
+	if r == nil {
+		return 0
+	}
+	var sum uint64
+	for _, proofs := range r.Proofs {
+		sum += proofs.GetFees()
+	}
+	return sum
+}
+
+// GetFees returns the sum of the fees paid for the lockFC, transferFC and addFC transactions.
 func (p *AddFeeTxProofs) GetFees() uint64 {
 	if p == nil {
 		return 0
@@ -1251,6 +2174,8 @@ func (p *AddFeeTxProofs) GetFees() uint64 {
 	return p.LockFC.ActualFee() + p.TransferFC.ActualFee() + p.AddFC.ActualFee()
 }
 
+// GetFees returns the sum of the fees paid for the lock, closeFC and reclaimFC transactions, ie
+// the total fees of a ReclaimFeeCmdResponse.
 func (p *ReclaimFeeTxProofs) GetFees() uint64 {
 	if p == nil {
 		return 0
@@ -1258,34 +2183,111 @@ func (p *ReclaimFeeTxProofs) GetFees() uint64 {
 	return p.Lock.ActualFee() + p.CloseFC.ActualFee() + p.ReclaimFC.ActualFee()
 }
 
-func waitForConf(ctx context.Context, partitionClient sdktypes.PartitionClient, tx *types.TransactionOrder) (*types.TxRecordProof, error) {
-	txHash, err := tx.Hash(crypto.SHA256)
-	if err != nil {
-		return nil, fmt.Errorf("failed to hash tx: %w", err)
-	}
-	for {
-		// fetch round number before proof to ensure that we cannot miss the proof
-		roundInfo, err := partitionClient.GetRoundInfo(ctx)
+func (e *BulkAddFeeError) Error() string {
+	var failed []string
+	for i, err := range e.Errs {
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch target partition round info: %w", err)
+			failed = append(failed, fmt.Sprintf("account #%d: %v", e.Cmds[i].AccountIndex+1, err))
 		}
-		proof, err := partitionClient.GetTransactionProof(ctx, txHash)
+	}
+	return fmt.Sprintf("adding fee credit failed for %d of %d accounts: %s", len(failed), len(e.Errs), strings.Join(failed, "; "))
+}
+
+func (e *BulkAddFeeError) Unwrap() []error {
+	var errs []error
+	for _, err := range e.Errs {
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch tx proof: %w", err)
-		}
-		if proof != nil {
-			return proof, nil
+			errs = append(errs, err)
 		}
-		if roundInfo.RoundNumber >= tx.Timeout() {
-			break
+	}
+	return errs
+}
+
+// Succeeded returns the account indexes of the commands that succeeded.
+func (e *BulkAddFeeError) Succeeded() []uint64 {
+	var res []uint64
+	for i, err := range e.Errs {
+		if err == nil {
+			res = append(res, e.Cmds[i].AccountIndex)
 		}
+	}
+	return res
+}
+
+// Summary returns the user-friendly summary of the pending add fee credit process.
+func (c *AddFeeCreditCtx) Summary() *PendingProcess {
+	p := &PendingProcess{
+		Kind:              PendingAdd,
+		TargetPartitionID: c.TargetPartitionID,
+		TargetBillID:      c.TargetBillID,
+		Amount:            c.TargetAmount,
+	}
+	switch {
+	case c.AddFCTx != nil:
+		p.LastTx = "addFC"
+	case c.TransferFCTx != nil:
+		p.LastTx = "transferFC"
+	case c.LockFCTx != nil:
+		p.LastTx = "lockFC"
+	}
+	return p
+}
 
-		select {
-		case <-time.After(time.Second):
-		case <-ctx.Done():
-			return nil, errors.New("context canceled")
+// Summary returns the user-friendly summary of the pending reclaim fee credit process, the reclaimed
+// amount is known only after the closeFC transaction has been created.
+func (c *ReclaimFeeCreditCtx) Summary() *PendingProcess {
+	p := &PendingProcess{
+		Kind:              PendingReclaim,
+		TargetPartitionID: c.TargetPartitionID,
+		TargetBillID:      c.TargetBillID,
+	}
+	if c.CloseFCTx != nil {
+		attr := &fc.CloseFeeCreditAttributes{}
+		if err := c.CloseFCTx.UnmarshalAttributes(attr); err == nil {
+			p.Amount = attr.Amount
 		}
 	}
+	switch {
+	case c.ReclaimFCTx != nil:
+		p.LastTx = "reclaimFC"
+	case c.CloseFCTx != nil:
+		p.LastTx = "closeFC"
+	case c.LockTx != nil:
+		p.LastTx = "lock"
+	}
+	return p
+}
 
-	return nil, nil
+// txLogAttrs returns the log attributes identifying the transaction: its hash, type, unit and partition.
+func txLogAttrs(tx *types.TransactionOrder) []any {
+	attrs := make([]any, 0, 4)
+	if txHash, err := tx.Hash(crypto.SHA256); err == nil {
+		attrs = append(attrs, slog.String("txHash", util.FormatTxID(txHash)))
+	}
+	return append(attrs,
+		slog.Uint64("txType", uint64(tx.Type)),
+		slog.String("unitID", tx.GetUnitID().String()),
+		slog.String("partitionID", tx.GetPartitionID().String()))
+}
+
+// confirmTransaction sends the transaction and waits for its confirmation.
+func (w *FeeManager) confirmTransaction(ctx context.Context, partitionClient sdktypes.PartitionClient, tx *types.TransactionOrder) (*types.TxRecordProof, error) {
+	if len(w.batchOpts) == 0 {
+		return partitionClient.ConfirmTransaction(ctx, tx, w.log)
+	}
+	sub, err := txsubmitter.New(tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tx submission: %w", err)
+	}
+	batch := sub.ToBatch(partitionClient, w.log, w.batchOpts...)
+	if err := batch.SendTx(ctx, true); err != nil {
+		return nil, err
+	}
+	return sub.Proof, nil
+}
+
+// waitForConf polls the partition for the proof of the transaction until the proof is found or the transaction
+// times out, returns nil proof in the latter case.
+func waitForConf(ctx context.Context, clock Clock, partitionClient sdktypes.PartitionClient, tx *types.TransactionOrder) (*types.TxRecordProof, error) {
+	return wallet.WaitForConfirmation(ctx, partitionClient, tx, confPollInterval, wallet.WithClock(clock))
 }
@@ -17,6 +17,7 @@ const (
 var (
 	bucketAccounts       = []byte("account")
 	addFeeContextKey     = []byte("addFeeContext")
+	addFeeProgressKey    = []byte("addFeeProgress")
 	reclaimFeeContextKey = []byte("reclaimFeeContext")
 )
 
@@ -92,6 +93,52 @@ func (s *BoltStore) DeleteAddFeeContext(accountID []byte) error {
 	})
 }
 
+func (s *BoltStore) GetAddFeeProgress(accountID []byte) (*AddFeeProgress, error) {
+	var progress *AddFeeProgress
+	err := s.db.View(func(tx *bolt.Tx) error {
+		accountBucket := tx.Bucket(bucketAccounts).Bucket(accountID)
+		if accountBucket == nil {
+			return nil
+		}
+		progressBytes := accountBucket.Get(addFeeProgressKey)
+		if progressBytes == nil {
+			return nil
+		}
+		if err := json.Unmarshal(progressBytes, &progress); err != nil {
+			return fmt.Errorf("failed to deserialize add fee progress json: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+func (s *BoltStore) SetAddFeeProgress(accountID []byte, progress *AddFeeProgress) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		accountBucket, err := tx.Bucket(bucketAccounts).CreateBucketIfNotExists(accountID)
+		if err != nil {
+			return fmt.Errorf("failed to create account bucket: %x", accountID)
+		}
+		progressBytes, err := json.Marshal(progress)
+		if err != nil {
+			return fmt.Errorf("failed to serialize add fee progress to json: %w", err)
+		}
+		return accountBucket.Put(addFeeProgressKey, progressBytes)
+	})
+}
+
+func (s *BoltStore) DeleteAddFeeProgress(accountID []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		accountBucket := tx.Bucket(bucketAccounts).Bucket(accountID)
+		if accountBucket == nil {
+			return nil
+		}
+		return accountBucket.Delete(addFeeProgressKey)
+	})
+}
+
 func (s *BoltStore) GetReclaimFeeContext(accountID []byte) (*ReclaimFeeCreditCtx, error) {
 	var feeCtx *ReclaimFeeCreditCtx
 	err := s.db.View(func(tx *bolt.Tx) error {
@@ -36,6 +36,33 @@ func TestDB_GetSetDeleteAddFeeCtx(t *testing.T) {
 	require.Nil(t, feeCtx)
 }
 
+func TestDB_GetSetDeleteAddFeeProgress(t *testing.T) {
+	s := createFeeManagerDB(t)
+	accountID := []byte{4}
+
+	// verify missing account returns nil and no error
+	progress, err := s.GetAddFeeProgress(accountID)
+	require.NoError(t, err)
+	require.Nil(t, progress)
+
+	// store progress, it's kept apart from the fee context
+	progress = &AddFeeProgress{TargetPartitionID: 1, TargetAmount: 400, AddedAmount: 100, SourceBillID: []byte{5}}
+	require.NoError(t, s.SetAddFeeProgress(accountID, progress))
+	require.NoError(t, s.SetAddFeeContext(accountID, &AddFeeCreditCtx{TargetAmount: 300, AddedBefore: 100}))
+	require.NoError(t, s.DeleteAddFeeContext(accountID))
+
+	// verify stored equals actual
+	storedProgress, err := s.GetAddFeeProgress(accountID)
+	require.NoError(t, err)
+	require.Equal(t, progress, storedProgress)
+
+	// delete progress
+	require.NoError(t, s.DeleteAddFeeProgress(accountID))
+	progress, err = s.GetAddFeeProgress(accountID)
+	require.NoError(t, err)
+	require.Nil(t, progress)
+}
+
 func TestDB_GetSetDeleteReclaimFeeCtx(t *testing.T) {
 	s := createFeeManagerDB(t)
 	accountID := []byte{4}
@@ -2,8 +2,12 @@ package fees
 
 import (
 	"context"
+	"crypto"
+	"fmt"
 	"log/slog"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -16,6 +20,7 @@ import (
 
 	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
 	"github.com/alphabill-org/alphabill-wallet/internal/testutils/logger"
+	"github.com/alphabill-org/alphabill-wallet/util"
 	"github.com/alphabill-org/alphabill-wallet/wallet"
 	"github.com/alphabill-org/alphabill-wallet/wallet/account"
 )
@@ -64,6 +69,55 @@ func TestAddFeeCredit_OK(t *testing.T) {
 	require.EqualValues(t, 100000000, attr.Amount)
 }
 
+func TestAddFeeCredit_ConcurrentCallsAreSerialized(t *testing.T) {
+	am := newAccountManager(t)
+	moneyClient := testmoney.NewRpcClientMock(
+		testmoney.WithOwnerBill(testmoney.NewBill(t, 100000000, 20)))
+	feeManager := newMoneyPartitionFeeManager(am, createFeeManagerDB(t), moneyClient, logger.New(t))
+	pk, err := am.GetPublicKey(0)
+	require.NoError(t, err)
+
+	// simulate another fee credit process of the account running concurrently
+	unlock, err := feeManager.accountLocks.lock(context.Background(), pk)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = feeManager.AddFeeCredit(ctx, AddFeeCmd{Amount: 100000000})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	_, err = feeManager.ReclaimFeeCredit(ctx, ReclaimFeeCmd{})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorIs(t, feeManager.AbortPending(ctx, 0), context.DeadlineExceeded)
+	require.Empty(t, moneyClient.RecordedTxs)
+
+	// the waiting call proceeds once the running process completes
+	done := make(chan error, 1)
+	go func() {
+		_, err := feeManager.AddFeeCredit(context.Background(), AddFeeCmd{Amount: 100000000})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		t.Fatalf("AddFeeCredit did not wait for the lock: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlock()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("AddFeeCredit did not complete after the lock was released")
+	}
+
+	// locks of different accounts are independent
+	unlockOther, err := feeManager.accountLocks.lock(context.Background(), []byte{1, 2, 3})
+	require.NoError(t, err)
+	defer unlockOther()
+	unlock, err = feeManager.accountLocks.lock(context.Background(), pk)
+	require.NoError(t, err)
+	unlock()
+}
+
 func TestAddFeeCredit_TokensPartitionOK(t *testing.T) {
 	// create fee manager
 	am := newAccountManager(t)
@@ -96,6 +150,69 @@ func TestAddFeeCredit_TokensPartitionOK(t *testing.T) {
 	err = getTxoV1(t, res.Proofs[0].TransferFC).UnmarshalAttributes(&attr)
 	require.NoError(t, err)
 	require.EqualValues(t, 1000+transferFCLatestAdditionTime, attr.LatestAdditionTime)
+	require.EqualValues(t, 1000+transferFCLatestAdditionTime, res.Proofs[0].LatestAdditionTime)
+}
+
+func TestAddFeeCredit_LatestAdditionTime(t *testing.T) {
+	am := newAccountManager(t)
+	accountKey, err := am.GetAccountKey(0)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		rounds   uint64
+		expected uint64
+	}{
+		{rounds: 500, expected: 1000 + 500},
+		{rounds: 0, expected: 1000 + transferFCLatestAdditionTime},
+	} {
+		moneyClient := testmoney.NewRpcClientMock(
+			testmoney.WithOwnerBill(testmoney.NewBill(t, 100000000, 2)),
+			testmoney.WithOwnerFeeCreditRecord(newMoneyFCR(t, accountKey, &fc.FeeCreditRecord{Balance: 1e8, Counter: 111})),
+			testmoney.WithRoundNumber(100),
+		)
+		tokensClient := testmoney.NewRpcClientMock(
+			testmoney.WithOwnerFeeCreditRecord(newMoneyFCR(t, accountKey, &fc.FeeCreditRecord{Balance: 1e8, Counter: 222})),
+			testmoney.WithRoundNumber(1000),
+		)
+		feeManager := NewFeeManager(types.NetworkLocal, am, createFeeManagerDB(t), moneyPartitionID, moneyClient, testFeeCreditRecordIDFromPublicKey,
+			tokensPartitionID, tokensClient, testFeeCreditRecordIDFromPublicKey, maxFee, logger.New(t), WithLatestAdditionTime(tc.rounds))
+
+		res, err := feeManager.AddFeeCredit(context.Background(), AddFeeCmd{Amount: 100000000, DisableLocking: true})
+		require.NoError(t, err)
+
+		var attr *fc.TransferFeeCreditAttributes
+		require.NoError(t, getTxoV1(t, res.Proofs[0].TransferFC).UnmarshalAttributes(&attr))
+		require.EqualValues(t, tc.expected, attr.LatestAdditionTime)
+		require.EqualValues(t, tc.expected, res.Proofs[0].LatestAdditionTime)
+	}
+}
+
+func TestEstimateProvisioning(t *testing.T) {
+	feeManager := newMoneyPartitionFeeManager(newAccountManager(t), createFeeManagerDB(t), testmoney.NewRpcClientMock(), logger.New(t))
+
+	// transferFC + addFC + 5 transactions + closeFC and reclaimFC with at least 1 tema left to reclaim
+	res, err := feeManager.EstimateProvisioning(ProvisioningCmd{TransferAmount: 100, TxCount: 5, Reclaimable: true})
+	require.NoError(t, err)
+	require.Equal(t, &ProvisioningCost{
+		AddFeeCreditFees: 2 * maxFee,
+		TransactionFees:  5 * maxFee,
+		ReclaimBuffer:    2*maxFee + 1,
+		FeeCreditAmount:  9*maxFee + 1,
+		TotalAmount:      100 + 9*maxFee + 1,
+	}, res)
+
+	// fee credit amount is never below the minimum add fee amount
+	res, err = feeManager.EstimateProvisioning(ProvisioningCmd{})
+	require.NoError(t, err)
+	require.Equal(t, feeManager.MinAddFeeAmount(), res.FeeCreditAmount)
+	require.Equal(t, feeManager.MinAddFeeAmount(), res.TotalAmount)
+	require.Zero(t, res.ReclaimBuffer)
+
+	_, err = feeManager.EstimateProvisioning(ProvisioningCmd{TxCount: math.MaxUint64})
+	require.ErrorContains(t, err, "transaction fees overflow")
+
+	_, err = feeManager.EstimateProvisioning(ProvisioningCmd{TransferAmount: math.MaxUint64, TxCount: 1})
+	require.ErrorContains(t, err, "provisioning amount overflow")
 }
 
 /*
@@ -176,6 +293,290 @@ func TestAddFeeCredit_MultipleBills(t *testing.T) {
 	require.EqualValues(t, 200000000-100000003, secondTransFCAttr.Amount)
 }
 
+func TestAddFeeCmdResponse_TotalFees(t *testing.T) {
+	newProof := func(fee uint64) *types.TxRecordProof {
+		return &types.TxRecordProof{TxRecord: &types.TransactionRecord{ServerMetadata: &types.ServerMetadata{ActualFee: fee}}}
+	}
+	var nilRes *AddFeeCmdResponse
+	require.Zero(t, nilRes.TotalFees())
+	require.Zero(t, (&AddFeeCmdResponse{}).TotalFees())
+
+	res := &AddFeeCmdResponse{Proofs: []*AddFeeTxProofs{
+		{LockFC: newProof(1), TransferFC: newProof(2), AddFC: newProof(3)},
+		nil,
+		{TransferFC: newProof(4), AddFC: newProof(5)},
+	}}
+	require.EqualValues(t, 15, res.TotalFees())
+}
+
+func TestAddFeeCredit_MultipleBills_Resume(t *testing.T) {
+	am := newAccountManager(t)
+	accountKey, err := am.GetAccountKey(0)
+	require.NoError(t, err)
+
+	largestBill := testmoney.NewBill(t, 100000003, 3)
+	secondLargestBill := testmoney.NewBill(t, 100000002, 2)
+	getTransferFCAmount := func(t *testing.T, proofs *AddFeeTxProofs) (types.UnitID, uint64) {
+		attr := &fc.TransferFeeCreditAttributes{}
+		require.NoError(t, getTxoV1(t, proofs.TransferFC).UnmarshalAttributes(attr))
+		return getTxoV1(t, proofs.TransferFC).GetUnitID(), attr.Amount
+	}
+
+	t.Run("interrupted between the bills", func(t *testing.T) {
+		// the largest bill was added to fee credit before the interruption
+		moneyClient := testmoney.NewRpcClientMock(
+			testmoney.WithOwnerBill(testmoney.NewBill(t, 100000001, 1)),
+			testmoney.WithOwnerBill(secondLargestBill),
+			testmoney.WithOwnerFeeCreditRecord(newMoneyFCR(t, accountKey, &fc.FeeCreditRecord{Balance: 100000004, Counter: 4})),
+		)
+		db := createFeeManagerDB(t)
+		require.NoError(t, db.SetAddFeeProgress(accountKey.PubKey, &AddFeeProgress{
+			TargetPartitionID: moneyPartitionID,
+			TargetAmount:      200000000,
+			AddedAmount:       100000003,
+		}))
+		feeManager := newMoneyPartitionFeeManager(am, db, moneyClient, logger.New(t))
+
+		// the re-run continues toward the original amount
+		plan, err := feeManager.PlanAddFeeCredit(context.Background(), AddFeeCmd{Amount: 100000000})
+		require.NoError(t, err)
+		require.EqualValues(t, 200000000-100000003, plan.Amount)
+		res, err := feeManager.AddFeeCredit(context.Background(), AddFeeCmd{Amount: 100000000})
+		require.NoError(t, err)
+		require.Len(t, res.Proofs, 1)
+		billID, amount := getTransferFCAmount(t, res.Proofs[0])
+		require.Equal(t, secondLargestBill.ID, billID)
+		require.EqualValues(t, 200000000-100000003, amount)
+
+		progress, err := db.GetAddFeeProgress(accountKey.PubKey)
+		require.NoError(t, err)
+		require.Nil(t, progress)
+	})
+
+	t.Run("interrupted during the first bill", func(t *testing.T) {
+		moneyClient := testmoney.NewRpcClientMock(
+			testmoney.WithOwnerBill(testmoney.NewBill(t, 100000001, 1)),
+			testmoney.WithOwnerBill(secondLargestBill),
+			testmoney.WithOwnerBill(largestBill),
+			testmoney.WithOwnerFeeCreditRecord(newMoneyFCR(t, accountKey, &fc.FeeCreditRecord{Balance: 100000004, Counter: 4})),
+		)
+		db := createFeeManagerDB(t)
+		require.NoError(t, db.SetAddFeeProgress(accountKey.PubKey, &AddFeeProgress{
+			TargetPartitionID: moneyPartitionID,
+			TargetAmount:      200000000,
+		}))
+		require.NoError(t, db.SetAddFeeContext(accountKey.PubKey, &AddFeeCreditCtx{
+			TargetPartitionID: moneyPartitionID,
+			TargetBillID:      largestBill.ID,
+			TargetBillCounter: largestBill.Counter,
+			TargetAmount:      100000003,
+		}))
+		feeManager := newMoneyPartitionFeeManager(am, db, moneyClient, logger.New(t))
+
+		// the pending bill is completed and the process continues with the next bill
+		res, err := feeManager.AddFeeCredit(context.Background(), AddFeeCmd{Amount: 100000000})
+		require.NoError(t, err)
+		require.Len(t, res.Proofs, 2)
+		billID, amount := getTransferFCAmount(t, res.Proofs[0])
+		require.Equal(t, largestBill.ID, billID)
+		require.EqualValues(t, 100000003, amount)
+		_, amount = getTransferFCAmount(t, res.Proofs[1])
+		require.EqualValues(t, 200000000-100000003, amount)
+
+		feeCtx, err := db.GetAddFeeContext(accountKey.PubKey)
+		require.NoError(t, err)
+		require.Nil(t, feeCtx)
+		progress, err := db.GetAddFeeProgress(accountKey.PubKey)
+		require.NoError(t, err)
+		require.Nil(t, progress)
+	})
+
+	t.Run("interrupted process can be aborted", func(t *testing.T) {
+		db := createFeeManagerDB(t)
+		require.NoError(t, db.SetAddFeeProgress(accountKey.PubKey, &AddFeeProgress{
+			TargetPartitionID: moneyPartitionID,
+			TargetAmount:      200000000,
+			AddedAmount:       100000003,
+		}))
+		feeManager := newMoneyPartitionFeeManager(am, db, testmoney.NewRpcClientMock(), logger.New(t))
+
+		_, err := feeManager.ReclaimFeeCredit(context.Background(), ReclaimFeeCmd{})
+		require.ErrorContains(t, err, "wallet contains unfinished add fee credit process")
+		require.NoError(t, feeManager.AbortPending(context.Background(), 0))
+		progress, err := db.GetAddFeeProgress(accountKey.PubKey)
+		require.NoError(t, err)
+		require.Nil(t, progress)
+	})
+}
+
+func TestAddFeeCredit_SourceBill(t *testing.T) {
+	am := newAccountManager(t)
+	accountKey, err := am.GetAccountKey(0)
+	require.NoError(t, err)
+
+	largestBill := testmoney.NewBill(t, 100000003, 3)
+	secondLargestBill := testmoney.NewBill(t, 100000002, 2)
+	lockedBill := testmoney.NewLockedBill(t, 100000005, 5, 1)
+	moneyClient := testmoney.NewRpcClientMock(
+		testmoney.WithOwnerBill(testmoney.NewBill(t, 100000001, 1)),
+		testmoney.WithOwnerBill(secondLargestBill),
+		testmoney.WithOwnerBill(largestBill),
+		testmoney.WithOwnerBill(lockedBill),
+		testmoney.WithOwnerFeeCreditRecord(newMoneyFCR(t, accountKey, &fc.FeeCreditRecord{Balance: 100000004, Counter: 4})),
+	)
+
+	t.Run("fee credit is added from the source bill only", func(t *testing.T) {
+		feeManager := newMoneyPartitionFeeManager(am, createFeeManagerDB(t), moneyClient, logger.New(t))
+		res, err := feeManager.AddFeeCredit(context.Background(), AddFeeCmd{Amount: 50000000, SourceBillID: secondLargestBill.ID})
+		require.NoError(t, err)
+		require.Len(t, res.Proofs, 1)
+
+		transFCAttr := &fc.TransferFeeCreditAttributes{}
+		err = getTxoV1(t, res.Proofs[0].TransferFC).UnmarshalAttributes(transFCAttr)
+		require.NoError(t, err)
+		require.Equal(t, secondLargestBill.ID, getTxoV1(t, res.Proofs[0].TransferFC).GetUnitID())
+		require.EqualValues(t, 50000000, transFCAttr.Amount)
+	})
+
+	t.Run("source bill too small", func(t *testing.T) {
+		feeManager := newMoneyPartitionFeeManager(am, createFeeManagerDB(t), moneyClient, logger.New(t))
+		_, err := feeManager.AddFeeCredit(context.Background(), AddFeeCmd{Amount: 200000000, SourceBillID: secondLargestBill.ID})
+		require.ErrorIs(t, err, ErrInsufficientBalance)
+	})
+
+	t.Run("source bill locked", func(t *testing.T) {
+		feeManager := newMoneyPartitionFeeManager(am, createFeeManagerDB(t), moneyClient, logger.New(t))
+		_, err := feeManager.AddFeeCredit(context.Background(), AddFeeCmd{Amount: 50000000, SourceBillID: lockedBill.ID})
+		require.ErrorContains(t, err, "is locked")
+	})
+
+	t.Run("source bill not found", func(t *testing.T) {
+		feeManager := newMoneyPartitionFeeManager(am, createFeeManagerDB(t), moneyClient, logger.New(t))
+		_, err := feeManager.AddFeeCredit(context.Background(), AddFeeCmd{Amount: 50000000, SourceBillID: testmoney.NewBill(t, 1, 1).ID})
+		require.ErrorContains(t, err, "not found")
+	})
+}
+
+func TestAddFeeCreditBulk(t *testing.T) {
+	am := newAccountManager(t)
+	_, _, err := am.AddAccount()
+	require.NoError(t, err)
+	_, _, err = am.AddAccount()
+	require.NoError(t, err)
+
+	moneyClient := testmoney.NewRpcClientMock(
+		testmoney.WithOwnerBill(testmoney.NewBill(t, 100000003, 3)),
+	)
+	feeManager := newMoneyPartitionFeeManager(am, createFeeManagerDB(t), moneyClient, logger.New(t))
+
+	t.Run("all accounts succeed", func(t *testing.T) {
+		res, err := feeManager.AddFeeCreditBulk(context.Background(), []AddFeeCmd{
+			{AccountIndex: 1, Amount: 1000},
+			{AccountIndex: 2, Amount: 1000},
+		})
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+		require.Len(t, res[0].Proofs, 1)
+		require.Len(t, res[1].Proofs, 1)
+	})
+
+	t.Run("failure does not stop the other accounts", func(t *testing.T) {
+		res, err := feeManager.AddFeeCreditBulk(context.Background(), []AddFeeCmd{
+			{AccountIndex: 1, Amount: 1},
+			{AccountIndex: 2, Amount: 1000},
+			{AccountIndex: 5, Amount: 1000},
+		})
+		var bulkErr *BulkAddFeeError
+		require.ErrorAs(t, err, &bulkErr)
+		require.ErrorIs(t, err, ErrMinimumFeeAmount)
+		require.ErrorContains(t, err, "adding fee credit failed for 2 of 3 accounts")
+		require.Equal(t, []uint64{2}, bulkErr.Succeeded())
+		require.Len(t, res, 3)
+		require.Nil(t, res[0])
+		require.Len(t, res[1].Proofs, 1)
+		require.Nil(t, res[2])
+	})
+}
+
+func TestPlanAddFeeCredit(t *testing.T) {
+	am := newAccountManager(t)
+	accountKey, err := am.GetAccountKey(0)
+	require.NoError(t, err)
+
+	largestBill := testmoney.NewBill(t, 100000003, 3)
+	secondLargestBill := testmoney.NewBill(t, 100000002, 2)
+	moneyClient := testmoney.NewRpcClientMock(
+		testmoney.WithOwnerBill(testmoney.NewBill(t, 100000001, 1)),
+		testmoney.WithOwnerBill(secondLargestBill),
+		testmoney.WithOwnerBill(largestBill),
+		testmoney.WithOwnerFeeCreditRecord(newMoneyFCR(t, accountKey, &fc.FeeCreditRecord{Balance: 100000004, Counter: 4})),
+	)
+
+	t.Run("bills are used in the same order as by AddFeeCredit", func(t *testing.T) {
+		db := createFeeManagerDB(t)
+		feeManager := newMoneyPartitionFeeManager(am, db, moneyClient, logger.New(t))
+
+		plan, err := feeManager.PlanAddFeeCredit(context.Background(), AddFeeCmd{Amount: 200000000})
+		require.NoError(t, err)
+		require.Len(t, plan.Bills, 2)
+		require.Equal(t, largestBill.ID, plan.Bills[0].Bill.ID)
+		require.EqualValues(t, 100000003, plan.Bills[0].Amount)
+		require.Equal(t, secondLargestBill.ID, plan.Bills[1].Bill.ID)
+		require.EqualValues(t, 200000000-100000003, plan.Bills[1].Amount)
+		require.EqualValues(t, 200000000, plan.Amount)
+		// the fee credit record is locked before adding each bill
+		require.EqualValues(t, 6*maxFee, plan.Fees)
+
+		// planning does not start the add process
+		feeCtx, err := db.GetAddFeeContext(accountKey.PubKey)
+		require.NoError(t, err)
+		require.Nil(t, feeCtx)
+	})
+
+	t.Run("lockFC fee is not included when locking does not apply", func(t *testing.T) {
+		feeManager := newMoneyPartitionFeeManager(am, createFeeManagerDB(t), moneyClient, logger.New(t))
+
+		plan, err := feeManager.PlanAddFeeCredit(context.Background(), AddFeeCmd{Amount: 200000000, DisableLocking: true})
+		require.NoError(t, err)
+		require.EqualValues(t, 4*maxFee, plan.Fees)
+
+		// the first bill is added without locking the fee credit record of balance below the threshold
+		plan, err = feeManager.PlanAddFeeCredit(context.Background(), AddFeeCmd{Amount: 200000000, LockSkipBelow: 200000000})
+		require.NoError(t, err)
+		require.EqualValues(t, 5*maxFee, plan.Fees)
+
+		plan, err = feeManager.PlanAddFeeCredit(context.Background(), AddFeeCmd{Amount: 100000000, SourceBillID: largestBill.ID})
+		require.NoError(t, err)
+		require.EqualValues(t, 3*maxFee, plan.Fees)
+	})
+
+	t.Run("pending add process bill is used", func(t *testing.T) {
+		db := createFeeManagerDB(t)
+		err := db.SetAddFeeContext(accountKey.PubKey, &AddFeeCreditCtx{
+			TargetPartitionID: moneyPartitionID,
+			TargetBillID:      secondLargestBill.ID,
+			TargetBillCounter: secondLargestBill.Counter,
+			TargetAmount:      5000,
+		})
+		require.NoError(t, err)
+		feeManager := newMoneyPartitionFeeManager(am, db, moneyClient, logger.New(t))
+
+		plan, err := feeManager.PlanAddFeeCredit(context.Background(), AddFeeCmd{Amount: 200000000})
+		require.NoError(t, err)
+		require.Len(t, plan.Bills, 1)
+		require.Equal(t, secondLargestBill.ID, plan.Bills[0].Bill.ID)
+		require.EqualValues(t, 5000, plan.Amount)
+		require.EqualValues(t, 2*maxFee, plan.Fees)
+	})
+
+	t.Run("insufficient balance", func(t *testing.T) {
+		feeManager := newMoneyPartitionFeeManager(am, createFeeManagerDB(t), moneyClient, logger.New(t))
+
+		_, err := feeManager.PlanAddFeeCredit(context.Background(), AddFeeCmd{Amount: 400000000})
+		require.ErrorIs(t, err, ErrInsufficientBalance)
+	})
+}
+
 /*
 Wallet has no bills.
 Trying to add fee credit should return error "wallet does not contain any bills".
@@ -365,6 +766,69 @@ func TestReclaimFeeCredit_TokensPartitionOK(t *testing.T) {
 	require.NotNil(t, res.Proofs.ReclaimFC)
 }
 
+func TestPreviewReclaim(t *testing.T) {
+	am := newAccountManager(t)
+	accountKey, err := am.GetAccountKey(0)
+	require.NoError(t, err)
+
+	smallBill := testmoney.NewBill(t, 5000, 2)
+	largeBill := testmoney.NewBill(t, 100000000, 2)
+	moneyClient := testmoney.NewRpcClientMock(
+		testmoney.WithOwnerBill(smallBill),
+		testmoney.WithOwnerBill(largeBill),
+		testmoney.WithOwnerFeeCreditRecord(newMoneyFCR(t, accountKey, &fc.FeeCreditRecord{Balance: 1e8, Counter: 111})),
+	)
+
+	t.Run("largest bill is selected as target", func(t *testing.T) {
+		db := createFeeManagerDB(t)
+		feeManager := newMoneyPartitionFeeManager(am, db, moneyClient, logger.New(t))
+
+		preview, err := feeManager.PreviewReclaim(context.Background(), 0)
+		require.NoError(t, err)
+		require.EqualValues(t, largeBill.ID, preview.TargetBillID)
+		require.EqualValues(t, largeBill.Value, preview.TargetBillValue)
+		require.EqualValues(t, 1e8, preview.FeeCreditBalance)
+		require.EqualValues(t, 2*maxFee, preview.ExpectedFees)
+		require.EqualValues(t, largeBill.Value+1e8-2*maxFee, preview.ProjectedBillValue)
+
+		// preview does not start the reclaim process
+		feeCtx, err := db.GetReclaimFeeContext(accountKey.PubKey)
+		require.NoError(t, err)
+		require.Nil(t, feeCtx)
+	})
+
+	t.Run("pending reclaim process target bill is used", func(t *testing.T) {
+		db := createFeeManagerDB(t)
+		closeFCTx, err := newMoneyFCR(t, accountKey, &fc.FeeCreditRecord{Balance: 3000, Counter: 111}).CloseFeeCredit(smallBill.ID, smallBill.Counter)
+		require.NoError(t, err)
+		err = db.SetReclaimFeeContext(accountKey.PubKey, &ReclaimFeeCreditCtx{
+			TargetPartitionID: moneyPartitionID,
+			TargetBillID:      smallBill.ID,
+			TargetBillCounter: smallBill.Counter,
+			CloseFCTx:         closeFCTx,
+		})
+		require.NoError(t, err)
+		feeManager := newMoneyPartitionFeeManager(am, db, moneyClient, logger.New(t))
+
+		preview, err := feeManager.PreviewReclaim(context.Background(), 0)
+		require.NoError(t, err)
+		require.EqualValues(t, smallBill.ID, preview.TargetBillID)
+		require.EqualValues(t, 3000, preview.FeeCreditBalance)
+		require.EqualValues(t, smallBill.Value+3000-2*maxFee, preview.ProjectedBillValue)
+	})
+
+	t.Run("insufficient fee credit", func(t *testing.T) {
+		moneyClient := testmoney.NewRpcClientMock(
+			testmoney.WithOwnerBill(largeBill),
+			testmoney.WithOwnerFeeCreditRecord(newMoneyFCR(t, accountKey, &fc.FeeCreditRecord{Balance: 2, Counter: 111})),
+		)
+		feeManager := newMoneyPartitionFeeManager(am, createFeeManagerDB(t), moneyClient, logger.New(t))
+
+		_, err := feeManager.PreviewReclaim(context.Background(), 0)
+		require.ErrorIs(t, err, ErrMinimumFeeAmount)
+	})
+}
+
 func TestAddAndReclaimWithInsufficientCredit(t *testing.T) {
 	// create fee manager
 	am := newAccountManager(t)
@@ -385,6 +849,32 @@ func TestAddAndReclaimWithInsufficientCredit(t *testing.T) {
 	require.ErrorIs(t, err, ErrMinimumFeeAmount)
 }
 
+func TestMinFeeAmountOverride(t *testing.T) {
+	am := newAccountManager(t)
+	accountKey, err := am.GetAccountKey(0)
+	require.NoError(t, err)
+
+	moneyClient := testmoney.NewRpcClientMock(
+		testmoney.WithOwnerBill(testmoney.NewBill(t, 100000002, 2)),
+		testmoney.WithOwnerFeeCreditRecord(newMoneyFCR(t, accountKey, &fc.FeeCreditRecord{Balance: 2, Counter: 111})),
+	)
+	feeManager := newMoneyPartitionFeeManager(am, createFeeManagerDB(t), moneyClient, logger.New(t))
+	require.EqualValues(t, 2*maxFee+1, feeManager.MinAddFeeAmount())
+	require.EqualValues(t, 2*maxFee+1, feeManager.MinReclaimFeeAmount())
+
+	feeManager = NewFeeManager(types.NetworkLocal, am, createFeeManagerDB(t), moneyPartitionID, moneyClient, testFeeCreditRecordIDFromPublicKey, moneyPartitionID, moneyClient, testFeeCreditRecordIDFromPublicKey, maxFee, logger.New(t),
+		WithMinAddFeeAmount(2), WithMinReclaimFeeAmount(1))
+	require.EqualValues(t, 2, feeManager.MinAddFeeAmount())
+	require.EqualValues(t, 1, feeManager.MinReclaimFeeAmount())
+
+	_, err = feeManager.AddFeeCredit(context.Background(), AddFeeCmd{Amount: 1})
+	require.ErrorIs(t, err, ErrMinimumFeeAmount)
+
+	res, err := feeManager.AddFeeCredit(context.Background(), AddFeeCmd{Amount: 2})
+	require.NoError(t, err)
+	require.Len(t, res.Proofs, 1)
+}
+
 func TestAddWithInsufficientBalance(t *testing.T) {
 	// create fee manager
 	am := newAccountManager(t)
@@ -466,6 +956,36 @@ func TestAddFeeCredit_LockingDisabled(t *testing.T) {
 	require.NotNil(t, res.Proofs[0].AddFC)
 }
 
+func TestAddFeeCredit_LockSkipBelow(t *testing.T) {
+	am := newAccountManager(t)
+	accountKey, err := am.GetAccountKey(0)
+	require.NoError(t, err)
+	newClient := func() *testmoney.RpcClientMock {
+		return testmoney.NewRpcClientMock(
+			testmoney.WithOwnerBill(testmoney.NewBill(t, 100, 1)),
+			testmoney.WithOwnerFeeCreditRecord(newMoneyFCR(t, accountKey, &fc.FeeCreditRecord{Balance: 100, Counter: 111})),
+		)
+	}
+
+	t.Run("balance below the threshold is not locked", func(t *testing.T) {
+		feeManager := newMoneyPartitionFeeManager(am, createFeeManagerDB(t), newClient(), logger.New(t))
+		res, err := feeManager.AddFeeCredit(context.Background(), AddFeeCmd{Amount: 40, LockSkipBelow: 101})
+		require.NoError(t, err)
+		require.Len(t, res.Proofs, 1)
+		require.Nil(t, res.Proofs[0].LockFC)
+		require.NotNil(t, res.Proofs[0].TransferFC)
+		require.NotNil(t, res.Proofs[0].AddFC)
+	})
+
+	t.Run("balance at the threshold is locked", func(t *testing.T) {
+		feeManager := newMoneyPartitionFeeManager(am, createFeeManagerDB(t), newClient(), logger.New(t))
+		res, err := feeManager.AddFeeCredit(context.Background(), AddFeeCmd{Amount: 40, LockSkipBelow: 100})
+		require.NoError(t, err)
+		require.Len(t, res.Proofs, 1)
+		require.NotNil(t, res.Proofs[0].LockFC)
+	})
+}
+
 func TestReclaimFeeCredit_LockingDisabled(t *testing.T) {
 	// create fee manager
 	am := newAccountManager(t)
@@ -1192,6 +1712,41 @@ func TestReclaimFeeCredit_ExistingReclaimFC(t *testing.T) {
 		require.NoError(t, err)
 		require.Nil(t, feeCtx)
 	})
+
+	t.Run("reclaimFC timed out and target bill spent => reclaimFC is not sent to another bill", func(t *testing.T) {
+		// create fee context
+		err := feeManagerDB.SetReclaimFeeContext(accountKey.PubKey, &ReclaimFeeCreditCtx{
+			TargetPartitionID: moneyPartitionID,
+			TargetBillID:      reclaimFCTx.GetUnitID(),
+			TargetBillCounter: 200,
+
+			CloseFCTx:    nil,
+			CloseFCProof: reclaimFCAttr.CloseFeeCreditProof,
+			ReclaimFCTx:  getTxoV1(t, reclaimFCProof),
+		})
+		require.NoError(t, err)
+
+		// mock tx timed out, target bill was spent and the wallet has another bill
+		spentTargetBill := &sdktypes.Bill{PartitionID: targetBill.PartitionID, ID: targetBill.ID, Value: 10, Counter: 201}
+		moneyClient := testmoney.NewRpcClientMock(
+			testmoney.WithRoundNumber(reclaimFCTx.Timeout()+1),
+			testmoney.WithOwnerBill(testmoney.NewBill(t, 100, 1)),
+			testmoney.WithOwnerBill(spentTargetBill),
+		)
+		feeManager := newMoneyPartitionFeeManager(am, feeManagerDB, moneyClient, logger.New(t))
+
+		// when fees are reclaimed
+		// then the closeFC proof can not be used with the other bill
+		res, err := feeManager.ReclaimFeeCredit(context.Background(), ReclaimFeeCmd{})
+		require.ErrorIs(t, err, ErrReclaimTargetBillSpent)
+		require.Nil(t, res)
+		require.Empty(t, moneyClient.RecordedTxs)
+
+		// and reclaim fee context must be cleared
+		feeCtx, err := feeManagerDB.GetReclaimFeeContext(accountKey.PubKey)
+		require.NoError(t, err)
+		require.Nil(t, feeCtx)
+	})
 }
 
 func TestLockFeeCredit(t *testing.T) {
@@ -1301,6 +1856,247 @@ func TestUnlockFeeCredit(t *testing.T) {
 	})
 }
 
+func TestGetFeeCreditBalances(t *testing.T) {
+	am := newAccountManager(t)
+	accountKey, err := am.GetAccountKey(0)
+	require.NoError(t, err)
+
+	t.Run("both fee credit records exist", func(t *testing.T) {
+		moneyClient := testmoney.NewRpcClientMock(testmoney.WithOwnerFeeCreditRecord(newMoneyFCR(t, accountKey, &fc.FeeCreditRecord{Balance: 100, Counter: 1})))
+		tokensClient := testmoney.NewRpcClientMock(testmoney.WithOwnerFeeCreditRecord(newMoneyFCR(t, accountKey, &fc.FeeCreditRecord{Balance: 200, Counter: 2})))
+		feeManager := newTokensPartitionFeeManager(am, createFeeManagerDB(t), moneyClient, tokensClient, logger.New(t))
+
+		money, target, err := feeManager.GetFeeCreditBalances(context.Background(), 0)
+		require.NoError(t, err)
+		require.EqualValues(t, 100, money)
+		require.EqualValues(t, 200, target)
+	})
+
+	t.Run("target fee credit record does not exist", func(t *testing.T) {
+		moneyClient := testmoney.NewRpcClientMock(testmoney.WithOwnerFeeCreditRecord(newMoneyFCR(t, accountKey, &fc.FeeCreditRecord{Balance: 100, Counter: 1})))
+		feeManager := newTokensPartitionFeeManager(am, createFeeManagerDB(t), moneyClient, testmoney.NewRpcClientMock(), logger.New(t))
+
+		money, target, err := feeManager.GetFeeCreditBalances(context.Background(), 0)
+		require.NoError(t, err)
+		require.EqualValues(t, 100, money)
+		require.Zero(t, target)
+	})
+
+	t.Run("fetching fee credit record fails", func(t *testing.T) {
+		tokensClient := testmoney.NewRpcClientMock(testmoney.WithError(fmt.Errorf("tokens partition is down")))
+		feeManager := newTokensPartitionFeeManager(am, createFeeManagerDB(t), testmoney.NewRpcClientMock(), tokensClient, logger.New(t))
+
+		_, _, err := feeManager.GetFeeCreditBalances(context.Background(), 0)
+		require.ErrorContains(t, err, "tokens partition is down")
+	})
+}
+
+func TestGetFeeCreditRecordID(t *testing.T) {
+	am := newAccountManager(t)
+	accountKey, err := am.GetAccountKey(0)
+	require.NoError(t, err)
+
+	t.Run("generated from current round", func(t *testing.T) {
+		moneyClient := testmoney.NewRpcClientMock(testmoney.WithRoundNumber(10))
+		feeManager := newMoneyPartitionFeeManager(am, createFeeManagerDB(t), moneyClient, logger.New(t))
+
+		fcrID, err := feeManager.GetFeeCreditRecordID(context.Background(), GetFeeCreditRecordIDCmd{})
+		require.NoError(t, err)
+		expectedID, err := testFeeCreditRecordIDFromPublicKey(types.ShardID{}, accountKey.PubKey, 10+transferFCLatestAdditionTime)
+		require.NoError(t, err)
+		require.EqualValues(t, expectedID, fcrID)
+	})
+
+	t.Run("generated from given latest addition time", func(t *testing.T) {
+		moneyClient := testmoney.NewRpcClientMock(testmoney.WithRoundNumber(10))
+		feeManager := newMoneyPartitionFeeManager(am, createFeeManagerDB(t), moneyClient, logger.New(t))
+
+		fcrID, err := feeManager.GetFeeCreditRecordID(context.Background(), GetFeeCreditRecordIDCmd{LatestAdditionTime: 42})
+		require.NoError(t, err)
+		expectedID, err := testFeeCreditRecordIDFromPublicKey(types.ShardID{}, accountKey.PubKey, 42)
+		require.NoError(t, err)
+		require.EqualValues(t, expectedID, fcrID)
+	})
+
+	t.Run("pending add fee process", func(t *testing.T) {
+		feeManagerDB := createFeeManagerDB(t)
+		pendingID := testutils.RandomBytes(33)
+		err := feeManagerDB.SetAddFeeContext(accountKey.PubKey, &AddFeeCreditCtx{
+			TargetPartitionID: moneyPartitionID,
+			FeeCreditRecordID: pendingID,
+		})
+		require.NoError(t, err)
+		feeManager := newMoneyPartitionFeeManager(am, feeManagerDB, testmoney.NewRpcClientMock(), logger.New(t))
+
+		fcrID, err := feeManager.GetFeeCreditRecordID(context.Background(), GetFeeCreditRecordIDCmd{})
+		require.NoError(t, err)
+		require.EqualValues(t, pendingID, fcrID)
+	})
+
+	t.Run("account does not exist", func(t *testing.T) {
+		feeManager := newMoneyPartitionFeeManager(am, createFeeManagerDB(t), testmoney.NewRpcClientMock(), logger.New(t))
+
+		_, err := feeManager.GetFeeCreditRecordID(context.Background(), GetFeeCreditRecordIDCmd{AccountIndex: 5})
+		require.ErrorContains(t, err, "failed to load account key")
+	})
+}
+
+func TestTargetPartitionFcrUnitType(t *testing.T) {
+	am := newAccountManager(t)
+	pdr := moneyid.PDR()
+	moneyClient := testmoney.NewRpcClientMock(
+		testmoney.WithOwnerBill(testmoney.NewBill(t, 100000000, 1)),
+		testmoney.WithRoundNumber(10),
+	)
+
+	t.Run("generated id has expected unit type", func(t *testing.T) {
+		feeManager := NewFeeManager(types.NetworkLocal, am, createFeeManagerDB(t),
+			moneyPartitionID, moneyClient, testFeeCreditRecordIDFromPublicKey,
+			moneyPartitionID, moneyClient, testFeeCreditRecordIDFromPublicKey,
+			maxFee, logger.New(t), WithTargetPartitionFcrUnitType(&pdr, money.FeeCreditRecordUnitType))
+
+		fcrID, err := feeManager.GetFeeCreditRecordID(context.Background(), GetFeeCreditRecordIDCmd{})
+		require.NoError(t, err)
+		unitType, err := pdr.ExtractUnitType(fcrID)
+		require.NoError(t, err)
+		require.EqualValues(t, money.FeeCreditRecordUnitType, unitType)
+	})
+
+	t.Run("generated id has wrong unit type", func(t *testing.T) {
+		feeManager := NewFeeManager(types.NetworkLocal, am, createFeeManagerDB(t),
+			moneyPartitionID, moneyClient, testFeeCreditRecordIDFromPublicKey,
+			moneyPartitionID, moneyClient, testFeeCreditRecordIDFromPublicKey,
+			maxFee, logger.New(t), WithTargetPartitionFcrUnitType(&pdr, money.BillUnitType))
+
+		_, err := feeManager.GetFeeCreditRecordID(context.Background(), GetFeeCreditRecordIDCmd{})
+		require.ErrorIs(t, err, ErrInvalidFcrUnitType)
+		require.ErrorContains(t, err, fmt.Sprintf("has unit type %d, expected %d", money.FeeCreditRecordUnitType, money.BillUnitType))
+
+		// the transferFC transaction is not sent with invalid fee credit record id
+		_, err = feeManager.AddFeeCredit(context.Background(), AddFeeCmd{Amount: 100000000, DisableLocking: true})
+		require.ErrorIs(t, err, ErrInvalidFcrUnitType)
+	})
+
+	t.Run("generated id has wrong length", func(t *testing.T) {
+		invalidFcrIDFn := func(shard types.ShardID, pubKey []byte, latestAdditionTime uint64) (types.UnitID, error) {
+			return []byte{1, 2, 3}, nil
+		}
+		feeManager := NewFeeManager(types.NetworkLocal, am, createFeeManagerDB(t),
+			moneyPartitionID, moneyClient, testFeeCreditRecordIDFromPublicKey,
+			moneyPartitionID, moneyClient, invalidFcrIDFn,
+			maxFee, logger.New(t), WithTargetPartitionFcrUnitType(&pdr, money.FeeCreditRecordUnitType))
+
+		_, err := feeManager.GetFeeCreditRecordID(context.Background(), GetFeeCreditRecordIDCmd{})
+		require.ErrorIs(t, err, ErrInvalidFcrUnitType)
+		require.ErrorContains(t, err, "expected unit ID length")
+	})
+}
+
+func TestSetTargetFcrIDFn(t *testing.T) {
+	am := newAccountManager(t)
+	accountKey, err := am.GetAccountKey(0)
+	require.NoError(t, err)
+	newID := testutils.RandomBytes(33)
+	newFcrIDFn := func(shard types.ShardID, pubKey []byte, latestAdditionTime uint64) (types.UnitID, error) {
+		return newID, nil
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		feeManager := newMoneyPartitionFeeManager(am, createFeeManagerDB(t), testmoney.NewRpcClientMock(), logger.New(t))
+		require.NoError(t, feeManager.SetTargetFcrIDFn(newFcrIDFn))
+
+		fcrID, err := feeManager.GetFeeCreditRecordID(context.Background(), GetFeeCreditRecordIDCmd{})
+		require.NoError(t, err)
+		require.EqualValues(t, newID, fcrID)
+	})
+
+	t.Run("nil function", func(t *testing.T) {
+		feeManager := newMoneyPartitionFeeManager(am, createFeeManagerDB(t), testmoney.NewRpcClientMock(), logger.New(t))
+		require.EqualError(t, feeManager.SetTargetFcrIDFn(nil), "fee credit record ID function must not be nil")
+	})
+
+	t.Run("unfinished add fee process", func(t *testing.T) {
+		feeManagerDB := createFeeManagerDB(t)
+		require.NoError(t, feeManagerDB.SetAddFeeContext(accountKey.PubKey, &AddFeeCreditCtx{TargetPartitionID: moneyPartitionID}))
+		feeManager := newMoneyPartitionFeeManager(am, feeManagerDB, testmoney.NewRpcClientMock(), logger.New(t))
+
+		err := feeManager.SetTargetFcrIDFn(newFcrIDFn)
+		require.ErrorIs(t, err, ErrFeeCreditInProgress)
+		require.ErrorContains(t, err, "account #1 has unfinished add fee credit process")
+
+		// the progress of a multi-step add is unfinished process too
+		require.NoError(t, feeManagerDB.DeleteAddFeeContext(accountKey.PubKey))
+		require.NoError(t, feeManagerDB.SetAddFeeProgress(accountKey.PubKey, &AddFeeProgress{TargetPartitionID: moneyPartitionID}))
+		require.ErrorIs(t, feeManager.SetTargetFcrIDFn(newFcrIDFn), ErrFeeCreditInProgress)
+
+		require.NoError(t, feeManagerDB.DeleteAddFeeProgress(accountKey.PubKey))
+		require.NoError(t, feeManager.SetTargetFcrIDFn(newFcrIDFn))
+	})
+
+	t.Run("running fee credit process", func(t *testing.T) {
+		feeManager := newMoneyPartitionFeeManager(am, createFeeManagerDB(t), testmoney.NewRpcClientMock(), logger.New(t))
+		unlock, err := feeManager.accountLocks.lock(context.Background(), accountKey.PubKey)
+		require.NoError(t, err)
+
+		err = feeManager.SetTargetFcrIDFn(newFcrIDFn)
+		require.ErrorIs(t, err, ErrFeeCreditInProgress)
+		require.ErrorContains(t, err, "account #1 has a running fee credit process")
+
+		unlock()
+		require.NoError(t, feeManager.SetTargetFcrIDFn(newFcrIDFn))
+	})
+}
+
+func TestGetFeeSpending(t *testing.T) {
+	am := newAccountManager(t)
+	accountKey, err := am.GetAccountKey(0)
+	require.NoError(t, err)
+	fcr := newMoneyFCR(t, accountKey, &fc.FeeCreditRecord{Balance: 100})
+
+	newBlock := func(t *testing.T, fees map[string]uint64) *types.Block {
+		block := &types.Block{Header: &types.Header{Version: 1}}
+		for fcrID, fee := range fees {
+			tx := &types.TransactionOrder{Version: 1, Payload: types.Payload{
+				UnitID:         testutils.RandomBytes(33),
+				ClientMetadata: &types.ClientMetadata{FeeCreditRecordID: []byte(fcrID)},
+			}}
+			txBytes, err := tx.MarshalCBOR()
+			require.NoError(t, err)
+			block.Transactions = append(block.Transactions, &types.TransactionRecord{
+				Version:          1,
+				TransactionOrder: txBytes,
+				ServerMetadata:   &types.ServerMetadata{ActualFee: fee, SuccessIndicator: types.TxStatusSuccessful},
+			})
+		}
+		return block
+	}
+	otherFcrID := string(testutils.RandomBytes(33))
+	moneyClient := testmoney.NewRpcClientMock(
+		testmoney.WithOwnerFeeCreditRecord(fcr),
+		testmoney.WithBlock(5, newBlock(t, map[string]uint64{string(fcr.ID): 2, otherFcrID: 10})),
+		testmoney.WithBlock(6, newBlock(t, map[string]uint64{otherFcrID: 10})),
+		testmoney.WithBlock(7, newBlock(t, map[string]uint64{string(fcr.ID): 3})),
+	)
+	feeManager := newMoneyPartitionFeeManager(am, createFeeManagerDB(t), moneyClient, logger.New(t))
+
+	spending, err := feeManager.GetFeeSpending(context.Background(), 0, 1, 10)
+	require.NoError(t, err)
+	require.EqualValues(t, 5, spending)
+
+	spending, err = feeManager.GetFeeSpending(context.Background(), 0, 6, 6)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, spending)
+
+	// no fee credit record, nothing spent
+	feeManager = newMoneyPartitionFeeManager(am, createFeeManagerDB(t), testmoney.NewRpcClientMock(), logger.New(t))
+	spending, err = feeManager.GetFeeSpending(context.Background(), 0, 1, 10)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, spending)
+
+	_, err = feeManager.GetFeeSpending(context.Background(), 5, 1, 10)
+	require.ErrorContains(t, err, "failed to load account key")
+}
+
 /*
 Wallet has a single bill but no fee credit record
 */
@@ -1333,6 +2129,231 @@ func TestNonExistingFeeCreditRecord(t *testing.T) {
 	require.ErrorContains(t, err, "not enough fee credit in wallet")
 }
 
+func TestPendingProcesses(t *testing.T) {
+	am := newAccountManager(t)
+	accountKey, err := am.GetAccountKey(0)
+	require.NoError(t, err)
+	feeManagerDB := createFeeManagerDB(t)
+	feeManager := newMoneyPartitionFeeManager(am, feeManagerDB, testmoney.NewRpcClientMock(), logger.New(t))
+
+	addCtx, reclaimCtx, err := feeManager.PendingProcesses(0)
+	require.NoError(t, err)
+	require.Nil(t, addCtx)
+	require.Nil(t, reclaimCtx)
+
+	targetBill := testmoney.NewBill(t, 50, 200)
+	transferFCTx, err := targetBill.TransferToFeeCredit(&sdktypes.FeeCreditRecord{ID: []byte{1}}, 30, 1000, sdktypes.WithTimeout(5))
+	require.NoError(t, err)
+	require.NoError(t, feeManagerDB.SetAddFeeContext(accountKey.PubKey, &AddFeeCreditCtx{
+		TargetPartitionID: moneyPartitionID,
+		TargetBillID:      targetBill.ID,
+		TargetBillCounter: targetBill.Counter,
+		TargetAmount:      30,
+		TransferFCTx:      transferFCTx,
+	}))
+
+	fcrCounter := uint64(1)
+	fcr := sdktypes.FeeCreditRecord{PartitionID: money.DefaultPartitionID, ID: moneyid.NewFeeCreditRecordID(t), Balance: 20, Counter: &fcrCounter}
+	closeFCTx, err := fcr.CloseFeeCredit(targetBill.ID, targetBill.Counter, sdktypes.WithTimeout(5))
+	require.NoError(t, err)
+	require.NoError(t, feeManagerDB.SetReclaimFeeContext(accountKey.PubKey, &ReclaimFeeCreditCtx{
+		TargetPartitionID: moneyPartitionID,
+		TargetBillID:      targetBill.ID,
+		TargetBillCounter: targetBill.Counter,
+		CloseFCTx:         closeFCTx,
+	}))
+
+	addCtx, reclaimCtx, err = feeManager.PendingProcesses(0)
+	require.NoError(t, err)
+	require.Equal(t, &PendingProcess{
+		Kind:              PendingAdd,
+		TargetPartitionID: moneyPartitionID,
+		TargetBillID:      targetBill.ID,
+		Amount:            30,
+		LastTx:            "transferFC",
+	}, addCtx.Summary())
+	require.Equal(t, &PendingProcess{
+		Kind:              PendingReclaim,
+		TargetPartitionID: moneyPartitionID,
+		TargetBillID:      targetBill.ID,
+		Amount:            20,
+		LastTx:            "closeFC",
+	}, reclaimCtx.Summary())
+}
+
+func TestAbortPending(t *testing.T) {
+	am := newAccountManager(t)
+	accountKey, err := am.GetAccountKey(0)
+	require.NoError(t, err)
+	feeManagerDB := createFeeManagerDB(t)
+
+	targetBill := testmoney.NewBill(t, 50, 200)
+	transferFCTx, err := targetBill.Transfer(nil)
+	require.NoError(t, err)
+	transferFCRecord := &types.TransactionRecord{
+		Version:          1,
+		TransactionOrder: txV1ToBytes(t, transferFCTx),
+		ServerMetadata:   &types.ServerMetadata{ActualFee: 1},
+	}
+	transferFCTxHash := testutils.TxHash(t, getTxoV1(t, transferFCRecord))
+	transferFCProof := &types.TxRecordProof{TxRecord: transferFCRecord, TxProof: &types.TxProof{}}
+
+	t.Run("no pending processes", func(t *testing.T) {
+		feeManager := newMoneyPartitionFeeManager(am, feeManagerDB, testmoney.NewRpcClientMock(), logger.New(t))
+		require.NoError(t, feeManager.AbortPending(context.Background(), 0))
+	})
+
+	t.Run("transferFC timed out => add fee context is deleted", func(t *testing.T) {
+		err := feeManagerDB.SetAddFeeContext(accountKey.PubKey, &AddFeeCreditCtx{
+			TargetPartitionID: moneyPartitionID,
+			FeeCreditRecordID: []byte{1},
+			TargetBillID:      targetBill.ID,
+			TargetBillCounter: targetBill.Counter,
+			TransferFCTx:      getTxoV1(t, transferFCRecord),
+		})
+		require.NoError(t, err)
+
+		moneyClient := testmoney.NewRpcClientMock(
+			testmoney.WithRoundNumber(getTxoV1(t, transferFCRecord).Timeout() + 10),
+		)
+		feeManager := newMoneyPartitionFeeManager(am, feeManagerDB, moneyClient, logger.New(t))
+		require.NoError(t, feeManager.AbortPending(context.Background(), 0))
+
+		feeCtx, err := feeManagerDB.GetAddFeeContext(accountKey.PubKey)
+		require.NoError(t, err)
+		require.Nil(t, feeCtx)
+	})
+
+	t.Run("transferFC confirmed => add fee process can not be aborted", func(t *testing.T) {
+		err := feeManagerDB.SetAddFeeContext(accountKey.PubKey, &AddFeeCreditCtx{
+			TargetPartitionID: moneyPartitionID,
+			FeeCreditRecordID: []byte{1},
+			TargetBillID:      targetBill.ID,
+			TargetBillCounter: targetBill.Counter,
+			TransferFCTx:      getTxoV1(t, transferFCRecord),
+		})
+		require.NoError(t, err)
+
+		moneyClient := testmoney.NewRpcClientMock(
+			testmoney.WithTxProof(transferFCTxHash, transferFCProof),
+		)
+		feeManager := newMoneyPartitionFeeManager(am, feeManagerDB, moneyClient, logger.New(t))
+		err = feeManager.AbortPending(context.Background(), 0)
+		require.ErrorIs(t, err, ErrNotAbortable)
+		require.ErrorContains(t, err, "add fee credit process")
+
+		// fee context is kept and the confirmed proof is stored
+		feeCtx, err := feeManagerDB.GetAddFeeContext(accountKey.PubKey)
+		require.NoError(t, err)
+		require.NotNil(t, feeCtx)
+		require.NotNil(t, feeCtx.TransferFCProof)
+		require.NoError(t, feeManagerDB.DeleteAddFeeContext(accountKey.PubKey))
+	})
+
+	t.Run("closeFC confirmed => reclaim fee process can not be aborted", func(t *testing.T) {
+		err := feeManagerDB.SetReclaimFeeContext(accountKey.PubKey, &ReclaimFeeCreditCtx{
+			TargetPartitionID: moneyPartitionID,
+			TargetBillID:      targetBill.ID,
+			TargetBillCounter: targetBill.Counter,
+			CloseFCProof:      transferFCProof,
+		})
+		require.NoError(t, err)
+
+		feeManager := newMoneyPartitionFeeManager(am, feeManagerDB, testmoney.NewRpcClientMock(), logger.New(t))
+		err = feeManager.AbortPending(context.Background(), 0)
+		require.ErrorIs(t, err, ErrNotAbortable)
+		require.ErrorContains(t, err, "reclaim fee credit process")
+
+		feeCtx, err := feeManagerDB.GetReclaimFeeContext(accountKey.PubKey)
+		require.NoError(t, err)
+		require.NotNil(t, feeCtx)
+		require.NoError(t, feeManagerDB.DeleteReclaimFeeContext(accountKey.PubKey))
+	})
+
+	t.Run("nothing sent yet => reclaim fee context is deleted", func(t *testing.T) {
+		err := feeManagerDB.SetReclaimFeeContext(accountKey.PubKey, &ReclaimFeeCreditCtx{
+			TargetPartitionID: moneyPartitionID,
+			TargetBillID:      targetBill.ID,
+			TargetBillCounter: targetBill.Counter,
+		})
+		require.NoError(t, err)
+
+		moneyClient := testmoney.NewRpcClientMock()
+		feeManager := newMoneyPartitionFeeManager(am, feeManagerDB, moneyClient, logger.New(t))
+		require.NoError(t, feeManager.AbortPending(context.Background(), 0))
+		require.Empty(t, moneyClient.RecordedTxs)
+
+		feeCtx, err := feeManagerDB.GetReclaimFeeContext(accountKey.PubKey)
+		require.NoError(t, err)
+		require.Nil(t, feeCtx)
+	})
+}
+
+func TestAbortAddAndReclaimFeeCredit(t *testing.T) {
+	am := newAccountManager(t)
+	accountKey, err := am.GetAccountKey(0)
+	require.NoError(t, err)
+	feeManagerDB := createFeeManagerDB(t)
+	lockProof := &types.TxRecordProof{TxRecord: &types.TransactionRecord{Version: 1}, TxProof: &types.TxProof{}}
+
+	t.Run("no pending processes", func(t *testing.T) {
+		feeManager := newMoneyPartitionFeeManager(am, feeManagerDB, testmoney.NewRpcClientMock(), logger.New(t))
+		proof, err := feeManager.AbortAddFeeCredit(context.Background(), 0)
+		require.NoError(t, err)
+		require.Nil(t, proof)
+		proof, err = feeManager.AbortReclaimFeeCredit(context.Background(), 0)
+		require.NoError(t, err)
+		require.Nil(t, proof)
+	})
+
+	t.Run("locked fee credit record is unlocked", func(t *testing.T) {
+		err := feeManagerDB.SetAddFeeContext(accountKey.PubKey, &AddFeeCreditCtx{
+			TargetPartitionID: moneyPartitionID,
+			FeeCreditRecordID: []byte{1},
+			LockFCProof:       lockProof,
+		})
+		require.NoError(t, err)
+
+		moneyClient := testmoney.NewRpcClientMock(
+			testmoney.WithOwnerFeeCreditRecord(newMoneyFCR(t, accountKey, &fc.FeeCreditRecord{Balance: 3, Counter: 100, Locked: wallet.LockReasonAddFees})),
+		)
+		feeManager := newMoneyPartitionFeeManager(am, feeManagerDB, moneyClient, logger.New(t))
+		proof, err := feeManager.AbortAddFeeCredit(context.Background(), 0)
+		require.NoError(t, err)
+		require.NotNil(t, proof)
+		require.Equal(t, fc.TransactionTypeUnlockFeeCredit, getTxoV1(t, proof).Type)
+
+		feeCtx, err := feeManagerDB.GetAddFeeContext(accountKey.PubKey)
+		require.NoError(t, err)
+		require.Nil(t, feeCtx)
+	})
+
+	t.Run("locked target bill is unlocked", func(t *testing.T) {
+		targetBill := testmoney.NewLockedBill(t, 50, 200, wallet.LockReasonReclaimFees)
+		err := feeManagerDB.SetReclaimFeeContext(accountKey.PubKey, &ReclaimFeeCreditCtx{
+			TargetPartitionID: moneyPartitionID,
+			TargetBillID:      targetBill.ID,
+			TargetBillCounter: targetBill.Counter,
+			LockTxProof:       lockProof,
+		})
+		require.NoError(t, err)
+
+		moneyClient := testmoney.NewRpcClientMock(
+			testmoney.WithOwnerBill(targetBill),
+			testmoney.WithOwnerFeeCreditRecord(newMoneyFCR(t, accountKey, &fc.FeeCreditRecord{Balance: 100, Counter: 1})),
+		)
+		feeManager := newMoneyPartitionFeeManager(am, feeManagerDB, moneyClient, logger.New(t))
+		proof, err := feeManager.AbortReclaimFeeCredit(context.Background(), 0)
+		require.NoError(t, err)
+		require.NotNil(t, proof)
+		require.Equal(t, money.TransactionTypeUnlock, getTxoV1(t, proof).Type)
+
+		feeCtx, err := feeManagerDB.GetReclaimFeeContext(accountKey.PubKey)
+		require.NoError(t, err)
+		require.Nil(t, feeCtx)
+	})
+}
+
 func newMoneyPartitionFeeManager(am account.Manager, db FeeManagerDB, moneyClient sdktypes.MoneyPartitionClient, log *slog.Logger) *FeeManager {
 	return NewFeeManager(types.NetworkLocal, am, db, moneyPartitionID, moneyClient, testFeeCreditRecordIDFromPublicKey, moneyPartitionID, moneyClient, testFeeCreditRecordIDFromPublicKey, maxFee, log)
 }
@@ -1380,3 +2401,80 @@ func txV1ToBytes(t *testing.T, tx *types.TransactionOrder) []byte {
 	require.NoError(t, err)
 	return txoBytes
 }
+
+func TestWaitForConf(t *testing.T) {
+	tx := &types.TransactionOrder{Version: 1, Payload: types.Payload{
+		UnitID:         testutils.RandomBytes(33),
+		ClientMetadata: &types.ClientMetadata{Timeout: 5},
+	}}
+	txHash, err := tx.Hash(crypto.SHA256)
+	require.NoError(t, err)
+
+	t.Run("proof found", func(t *testing.T) {
+		clock := &testClock{}
+		proof := &types.TxRecordProof{}
+		client := &roundProgressingClient{RpcClientMock: testmoney.NewRpcClientMock(testmoney.WithTxProof(txHash, proof))}
+
+		res, err := waitForConf(context.Background(), clock, client, tx)
+		require.NoError(t, err)
+		require.Equal(t, proof, res)
+		require.Empty(t, clock.waits)
+	})
+
+	t.Run("tx times out", func(t *testing.T) {
+		clock := &testClock{}
+		client := &roundProgressingClient{RpcClientMock: testmoney.NewRpcClientMock()}
+
+		res, err := waitForConf(context.Background(), clock, client, tx)
+		require.NoError(t, err)
+		require.Nil(t, res)
+		// rounds 1..4 are below the timeout, poll interval is waited after each
+		require.Equal(t, []time.Duration{confPollInterval, confPollInterval, confPollInterval, confPollInterval}, clock.waits)
+	})
+}
+
+func TestTxLogAttrs(t *testing.T) {
+	tx := &types.TransactionOrder{Version: 1, Payload: types.Payload{
+		PartitionID:    tokensPartitionID,
+		Type:           fc.TransactionTypeAddFeeCredit,
+		UnitID:         testutils.RandomBytes(33),
+		ClientMetadata: &types.ClientMetadata{Timeout: 5},
+	}}
+	txHash, err := tx.Hash(crypto.SHA256)
+	require.NoError(t, err)
+
+	require.Equal(t, []any{
+		slog.String("txHash", util.FormatTxID(txHash)),
+		slog.Uint64("txType", uint64(fc.TransactionTypeAddFeeCredit)),
+		slog.String("unitID", tx.GetUnitID().String()),
+		slog.String("partitionID", tokensPartitionID.String()),
+	}, txLogAttrs(tx))
+}
+
+// testClock advances time instantly when waited upon.
+type testClock struct {
+	now   time.Time
+	waits []time.Duration
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testClock) After(d time.Duration) <-chan time.Time {
+	c.waits = append(c.waits, d)
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+// roundProgressingClient advances the round number each time round info is requested.
+type roundProgressingClient struct {
+	*testmoney.RpcClientMock
+}
+
+func (c *roundProgressingClient) GetRoundInfo(ctx context.Context) (*sdktypes.RoundInfo, error) {
+	c.RoundNumber++
+	return c.RpcClientMock.GetRoundInfo(ctx)
+}
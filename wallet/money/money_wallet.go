@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"sort"
+	"time"
 
 	abcrypto "github.com/alphabill-org/alphabill-go-base/crypto"
 	"github.com/alphabill-org/alphabill-go-base/hash"
@@ -25,6 +26,7 @@ import (
 const (
 	txTimeoutBlockCount       = 10
 	maxBillsForDustCollection = 100
+	reclaimOnCloseTimeout     = time.Minute
 )
 
 type (
@@ -36,8 +38,12 @@ type (
 		dustCollector *dc.DustCollector
 		maxFee        uint64
 		log           *slog.Logger
+
+		reclaimOnClose bool
 	}
 
+	Option func(*Wallet)
+
 	SendCmd struct {
 		Receivers           []ReceiverData
 		WaitForConfirmation bool
@@ -73,7 +79,7 @@ func GenerateKeys(am account.Manager, mnemonic string) error {
 }
 
 // NewWallet creates a new money wallet from specified parameters. The account manager must contain pre-generated keys.
-func NewWallet(ctx context.Context, am account.Manager, feeManagerDB fees.FeeManagerDB, moneyClient sdktypes.MoneyPartitionClient, maxFee uint64, log *slog.Logger) (*Wallet, error) {
+func NewWallet(ctx context.Context, am account.Manager, feeManagerDB fees.FeeManagerDB, moneyClient sdktypes.MoneyPartitionClient, maxFee uint64, log *slog.Logger, opts ...Option) (*Wallet, error) {
 	pdr, err := moneyClient.PartitionDescription(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("loading partition description: %w", err)
@@ -89,9 +95,10 @@ func NewWallet(ctx context.Context, am account.Manager, feeManagerDB fees.FeeMan
 		pdr.PartitionID, moneyClient, fcrGen,
 		pdr.PartitionID, moneyClient, fcrGen,
 		maxFee, log,
+		fees.WithTargetPartitionFcrUnitType(pdr, money.FeeCreditRecordUnitType),
 	)
 	dustCollector := dc.NewDustCollector(maxBillsForDustCollection, txTimeoutBlockCount, moneyClient, maxFee, log)
-	return &Wallet{
+	w := &Wallet{
 		pdr:           pdr,
 		am:            am,
 		moneyClient:   moneyClient,
@@ -99,7 +106,20 @@ func NewWallet(ctx context.Context, am account.Manager, feeManagerDB fees.FeeMan
 		dustCollector: dustCollector,
 		maxFee:        maxFee,
 		log:           log,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w, nil
+}
+
+// WithReclaimOnClose makes the wallet reclaim the fee credit of all accounts back to the account
+// balance when the wallet is closed, meant for short-lived wallets that should not leave fee credit
+// behind. The reclaim is best-effort, failures are logged and do not prevent closing the wallet.
+func WithReclaimOnClose() Option {
+	return func(w *Wallet) {
+		w.reclaimOnClose = true
+	}
 }
 
 func (w *Wallet) GetAccountManager() account.Manager {
@@ -115,13 +135,51 @@ func (w *Wallet) PartitionID() types.PartitionID {
 }
 
 // Close terminates connection to alphabill node, closes account manager and cancels any background goroutines.
+// When the wallet is created with WithReclaimOnClose option the fee credit is reclaimed first, giving
+// up on the reclaim after one minute.
 func (w *Wallet) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), reclaimOnCloseTimeout)
+	defer cancel()
+	w.CloseContext(ctx)
+}
+
+// CloseContext is like Close but the fee credit reclaim of the WithReclaimOnClose option is
+// given up when the context is cancelled.
+func (w *Wallet) CloseContext(ctx context.Context) {
+	if w.reclaimOnClose {
+		w.reclaimAllFeeCredit(ctx)
+	}
 	w.am.Close()
 	w.feeManager.Close()
 	_ = w.dustCollector.Close()
 	w.moneyClient.Close()
 }
 
+// reclaimAllFeeCredit reclaims the fee credit of each account that has at least the minimum
+// reclaimable amount of fee credit, failures are only logged.
+func (w *Wallet) reclaimAllFeeCredit(ctx context.Context) {
+	pubKeys, err := w.am.GetPublicKeys()
+	if err != nil {
+		w.log.WarnContext(ctx, fmt.Sprintf("reclaim on close: failed to load account keys: %v", err))
+		return
+	}
+	for accountIndex := range pubKeys {
+		fcr, err := w.feeManager.GetFeeCredit(ctx, fees.GetFeeCreditCmd{AccountIndex: uint64(accountIndex)})
+		if err != nil {
+			w.log.WarnContext(ctx, fmt.Sprintf("reclaim on close: failed to fetch fee credit record of account #%d: %v", accountIndex+1, err))
+			continue
+		}
+		if fcr == nil || fcr.Balance < w.feeManager.MinReclaimFeeAmount() {
+			continue
+		}
+		if _, err := w.feeManager.ReclaimFeeCredit(ctx, fees.ReclaimFeeCmd{AccountIndex: uint64(accountIndex)}); err != nil {
+			w.log.WarnContext(ctx, fmt.Sprintf("reclaim on close: failed to reclaim fee credit of account #%d: %v", accountIndex+1, err))
+			continue
+		}
+		w.log.InfoContext(ctx, fmt.Sprintf("reclaim on close: reclaimed %d fee credit of account #%d", fcr.Balance, accountIndex+1))
+	}
+}
+
 // GetBalance returns the total value of all bills currently held in the wallet, for the given account,
 // in Tema denomination. Does not count fee credit bills.
 func (w *Wallet) GetBalance(ctx context.Context, cmd GetBalanceCmd) (uint64, error) {
@@ -317,6 +375,16 @@ func (w *Wallet) ReclaimFeeCredit(ctx context.Context, cmd fees.ReclaimFeeCmd) (
 	return w.feeManager.ReclaimFeeCredit(ctx, cmd)
 }
 
+// AbortAllPending aborts the pending fee credit processes of the given account (1-based), releasing the
+// locks the processes have set. Processes that can't be aborted without losing funds are left untouched
+// and reported in the returned error, see fees.FeeManager.AbortPending.
+func (w *Wallet) AbortAllPending(ctx context.Context, accountNumber uint64) error {
+	if accountNumber < 1 {
+		return fmt.Errorf("invalid account number: %d", accountNumber)
+	}
+	return w.feeManager.AbortPending(ctx, accountNumber-1)
+}
+
 // CollectDust starts the dust collector process for the requested accounts in the wallet.
 // Dust collection process joins up to N units into existing target unit, prioritizing small units first.
 // The largest unit in wallet is selected as the target unit.
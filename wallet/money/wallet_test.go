@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/alphabill-org/alphabill-go-base/txsystem/fc"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/stretchr/testify/require"
 
@@ -98,7 +99,59 @@ func TestWallet_GetBalances(t *testing.T) {
 	require.EqualValues(t, 20, sum)
 }
 
-func createTestWallet(t *testing.T, moneyClient sdktypes.MoneyPartitionClient) *Wallet {
+func TestWallet_ReclaimOnClose(t *testing.T) {
+	sentTxTypes := func(rpcClient *testmoney.RpcClientMock) []uint16 {
+		var txTypes []uint16
+		for _, tx := range rpcClient.RecordedTxs {
+			txTypes = append(txTypes, tx.Type)
+		}
+		return txTypes
+	}
+
+	t.Run("fee credit is reclaimed", func(t *testing.T) {
+		rpcClient := testmoney.NewRpcClientMock(
+			testmoney.WithOwnerBill(testmoney.NewBill(t, 100*1e8, 1)),
+			testmoney.WithOwnerFeeCreditRecord(newMoneyFCR(t, testPubKey0Hash, 100, 200)),
+		)
+		w := createTestWallet(t, rpcClient, WithReclaimOnClose())
+		w.Close()
+		txTypes := sentTxTypes(rpcClient)
+		require.Contains(t, txTypes, fc.TransactionTypeCloseFeeCredit)
+		require.Contains(t, txTypes, fc.TransactionTypeReclaimFeeCredit)
+	})
+
+	t.Run("fee credit below minimum is not reclaimed", func(t *testing.T) {
+		rpcClient := testmoney.NewRpcClientMock(
+			testmoney.WithOwnerBill(testmoney.NewBill(t, 100*1e8, 1)),
+			testmoney.WithOwnerFeeCreditRecord(newMoneyFCR(t, testPubKey0Hash, 2*maxFee, 200)),
+		)
+		w := createTestWallet(t, rpcClient, WithReclaimOnClose())
+		w.Close()
+		require.Empty(t, rpcClient.RecordedTxs)
+	})
+
+	t.Run("reclaim failure does not prevent close", func(t *testing.T) {
+		// no bills to reclaim the fee credit to
+		rpcClient := testmoney.NewRpcClientMock(
+			testmoney.WithOwnerFeeCreditRecord(newMoneyFCR(t, testPubKey0Hash, 100, 200)),
+		)
+		w := createTestWallet(t, rpcClient, WithReclaimOnClose())
+		w.Close()
+		require.Empty(t, rpcClient.RecordedTxs)
+	})
+
+	t.Run("option not set", func(t *testing.T) {
+		rpcClient := testmoney.NewRpcClientMock(
+			testmoney.WithOwnerBill(testmoney.NewBill(t, 100*1e8, 1)),
+			testmoney.WithOwnerFeeCreditRecord(newMoneyFCR(t, testPubKey0Hash, 100, 200)),
+		)
+		w := createTestWallet(t, rpcClient)
+		w.Close()
+		require.Empty(t, rpcClient.RecordedTxs)
+	})
+}
+
+func createTestWallet(t *testing.T, moneyClient sdktypes.MoneyPartitionClient, opts ...Option) *Wallet {
 	dir := t.TempDir()
 	am, err := account.NewManager(dir, "", true)
 	require.NoError(t, err)
@@ -109,7 +162,7 @@ func createTestWallet(t *testing.T, moneyClient sdktypes.MoneyPartitionClient) *
 	feeManagerDB, err := fees.NewFeeManagerDB(dir)
 	require.NoError(t, err)
 
-	w, err := NewWallet(context.Background(), am, feeManagerDB, moneyClient, maxFee, logger.New(t))
+	w, err := NewWallet(context.Background(), am, feeManagerDB, moneyClient, maxFee, logger.New(t), opts...)
 	require.NoError(t, err)
 
 	return w
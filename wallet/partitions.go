@@ -0,0 +1,62 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/alphabill-org/alphabill-go-base/types"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+)
+
+// Partitions is a view over all the partitions the wallet is configured with.
+type Partitions struct {
+	clients map[types.PartitionID]sdktypes.PartitionClient
+}
+
+// NewPartitions creates a view over the partitions of the given clients, the clients
+// must be connected to different partitions.
+func NewPartitions(ctx context.Context, clients ...sdktypes.PartitionClient) (*Partitions, error) {
+	p := &Partitions{clients: make(map[types.PartitionID]sdktypes.PartitionClient, len(clients))}
+	for _, c := range clients {
+		pdr, err := c.PartitionDescription(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("loading PDR: %w", err)
+		}
+		if _, ok := p.clients[pdr.PartitionID]; ok {
+			return nil, fmt.Errorf("multiple clients for partition %s", pdr.PartitionID)
+		}
+		p.clients[pdr.PartitionID] = c
+	}
+	return p, nil
+}
+
+// CurrentRounds fetches the current round number of each partition concurrently. The rounds of the
+// partitions that responded are returned even if fetching the round of some partition fails, the
+// errors of the failed partitions are joined into the returned error.
+func (p *Partitions) CurrentRounds(ctx context.Context) (map[types.PartitionID]uint64, error) {
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		errs   []error
+		rounds = make(map[types.PartitionID]uint64, len(p.clients))
+	)
+	for id, c := range p.clients {
+		wg.Add(1)
+		go func(id types.PartitionID, c sdktypes.PartitionClient) {
+			defer wg.Done()
+			roundInfo, err := c.GetRoundInfo(ctx)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("partition %s: %w", id, err))
+				return
+			}
+			rounds[id] = roundInfo.RoundNumber
+		}(id, c)
+	}
+	wg.Wait()
+	return rounds, errors.Join(errs...)
+}
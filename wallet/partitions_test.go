@@ -0,0 +1,51 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/stretchr/testify/require"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+)
+
+func TestPartitions_CurrentRounds(t *testing.T) {
+	moneyClient := &roundClientMock{partitionID: 1, round: 100}
+	tokensClient := &roundClientMock{partitionID: 2, round: 7}
+
+	p, err := NewPartitions(context.Background(), moneyClient, tokensClient)
+	require.NoError(t, err)
+	rounds, err := p.CurrentRounds(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, map[types.PartitionID]uint64{1: 100, 2: 7}, rounds)
+
+	// rounds of the responding partitions are returned along with the error
+	tokensClient.err = errors.New("connection refused")
+	rounds, err = p.CurrentRounds(context.Background())
+	require.ErrorContains(t, err, "partition 00000002: connection refused")
+	require.Equal(t, map[types.PartitionID]uint64{1: 100}, rounds)
+
+	// clients of the same partition
+	_, err = NewPartitions(context.Background(), moneyClient, &roundClientMock{partitionID: 1})
+	require.ErrorContains(t, err, "multiple clients for partition 00000001")
+}
+
+type roundClientMock struct {
+	sdktypes.PartitionClient
+	partitionID types.PartitionID
+	round       uint64
+	err         error
+}
+
+func (c *roundClientMock) PartitionDescription(ctx context.Context) (*types.PartitionDescriptionRecord, error) {
+	return &types.PartitionDescriptionRecord{PartitionID: c.partitionID}, nil
+}
+
+func (c *roundClientMock) GetRoundInfo(ctx context.Context) (*sdktypes.RoundInfo, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &sdktypes.RoundInfo{RoundNumber: c.round}, nil
+}
@@ -7,17 +7,25 @@ import (
 	"fmt"
 	"log/slog"
 	"math"
+	"math/bits"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/alphabill-org/alphabill-go-base/hash"
 	"github.com/alphabill-org/alphabill-go-base/predicates"
 	"github.com/alphabill-org/alphabill-go-base/predicates/templates"
 	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
 	"github.com/alphabill-org/alphabill-go-base/types"
 	"github.com/alphabill-org/alphabill-go-base/util"
 	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	walletutil "github.com/alphabill-org/alphabill-wallet/util"
 	"github.com/alphabill-org/alphabill-wallet/wallet"
 	"github.com/alphabill-org/alphabill-wallet/wallet/account"
 	"github.com/alphabill-org/alphabill-wallet/wallet/fees"
 	"github.com/alphabill-org/alphabill-wallet/wallet/txsubmitter"
+	"golang.org/x/sync/errgroup"
 )
 
 const (
@@ -25,25 +33,64 @@ const (
 	uriMaxSize         = 4 * 1024
 	dataMaxSize        = 64 * 1024
 	nameMaxSize        = 256
+	// maxFetchWorkers is the number of accounts whose units are fetched concurrently
+	maxFetchWorkers = 4
 )
 
 var (
-	ErrNoFeeCredit           = errors.New("no fee credit in token wallet")
-	ErrInsufficientFeeCredit = errors.New("insufficient fee credit balance for transaction(s)")
-	errInvalidURILength      = fmt.Errorf("URI exceeds the maximum allowed size of %v bytes", uriMaxSize)
-	errInvalidDataLength     = fmt.Errorf("data exceeds the maximum allowed size of %v bytes", dataMaxSize)
-	errInvalidNameLength     = fmt.Errorf("name exceeds the maximum allowed size of %v bytes", nameMaxSize)
+	ErrNoFeeCredit             = errors.New("no fee credit in token wallet")
+	ErrInsufficientFeeCredit   = errors.New("insufficient fee credit balance for transaction(s)")
+	ErrTokensLocked            = errors.New("locked tokens must be unlocked to complete the send")
+	ErrTokenTypeExists         = errors.New("token type ID already exists")
+	ErrMintNotSatisfied        = errors.New("token minting clause can not be satisfied")
+	ErrOwnerProofRequired      = errors.New("owner proof must be provided")
+	ErrDataUpdateProofRequired = errors.New("data-update proof must be provided")
+	ErrDryRunNotSupported      = errors.New("operation is not supported in dry run mode")
+	ErrConfirmTimeout          = errors.New("confirmation timed out")
+	ErrInvalidMaxFee           = errors.New("max fee must be greater than zero")
+	errInvalidURILength        = fmt.Errorf("URI exceeds the maximum allowed size of %v bytes", uriMaxSize)
+	errInvalidDataLength       = fmt.Errorf("data exceeds the maximum allowed size of %v bytes", dataMaxSize)
+	errInvalidNameLength       = fmt.Errorf("name exceeds the maximum allowed size of %v bytes", nameMaxSize)
 )
 
 type (
+	// Wallet is safe for concurrent use, the operations submitting transactions on behalf of an
+	// account are serialized per account, see lockAccount.
 	Wallet struct {
 		pdr          *types.PartitionDescriptionRecord
 		am           account.Manager
 		tokensClient sdktypes.TokensPartitionClient
 		confirmTx    bool
-		feeManager   *fees.FeeManager
-		maxFee       uint64
-		log          *slog.Logger
+		dryRun       bool
+		// timeoutRounds is the number of rounds the transactions are valid for, txTimeoutRoundCount when zero
+		timeoutRounds uint64
+		// confirmTimeout limits the time spent sending and confirming a batch, no limit when zero
+		confirmTimeout time.Duration
+		feeManager     *fees.FeeManager
+		maxFee         uint64
+		log            *slog.Logger
+		opStore        OperationStore
+		// batchOpts are the options of the transaction batches sent by the wallet, ie retry policy
+		batchOpts []txsubmitter.BatchOption
+
+		feeMu sync.Mutex
+		// observedMaxFee is the highest actual fee of the transactions confirmed by the wallet
+		observedMaxFee uint64
+
+		typeMu sync.Mutex
+		// typeCache holds the type hierarchies by type ID, token types are immutable
+		typeCache map[string][]*TokenTypeDescription
+		// ftTypeCache and nftTypeCache hold the type hierarchies fetched by GetFungibleTokenType and
+		// GetNonFungibleTokenType, nil unless the cache is enabled with WithTypeCache
+		ftTypeCache  *ttlCache[[]*sdktypes.FungibleTokenType]
+		nftTypeCache *ttlCache[[]*sdktypes.NonFungibleTokenType]
+		// roundCache holds the round number fetched by GetRoundNumber, nil unless the cache is
+		// enabled with WithRoundNumberCache
+		roundCache *ttlCache[uint64]
+
+		accountMu sync.Mutex
+		// accountLocks are the locks of the accounts by account number, see lockAccount
+		accountLocks map[uint64]chan struct{}
 	}
 
 	// SubmissionResult dust collection result for single token type.
@@ -51,8 +98,70 @@ type (
 		Submissions   []*txsubmitter.TxSubmission
 		AccountNumber uint64
 		FeeSum        uint64
+		// SkipReason is set when a batch operation skipped the unit, ie because it's locked.
+		SkipReason error
+		// DryRun is set when the wallet is in dry run mode, the transactions of the submissions
+		// were built and signed but not sent.
+		DryRun bool
+		// DecimalPlaces of the type of the fungible token sent, set by SendFungibleByID so that
+		// the amount of the result can be formatted without fetching the type.
+		DecimalPlaces uint32
+		// pdr of the tokens partition, used to derive the IDs of the units created by the transactions
+		pdr *types.PartitionDescriptionRecord
 	}
 
+	// NFTTransfer is a single transfer of TransferNFTs.
+	NFTTransfer struct {
+		TokenID        sdktypes.TokenID
+		ReceiverPubKey sdktypes.PubKey
+		// OwnerPredicateInput satisfies the owner predicate of the token, by default
+		// the token is expected to be owned by the P2PKH predicate of the account key.
+		OwnerPredicateInput      *PredicateInput
+		TypeOwnerPredicateInputs []*PredicateInput
+	}
+
+	// FungibleTokenBalance is the total amount of the owned unlocked fungible tokens of a type.
+	FungibleTokenBalance struct {
+		TypeID        sdktypes.TokenTypeID
+		Symbol        string
+		DecimalPlaces uint32
+		Amount        uint64
+	}
+
+	// TransferTarget is a single receiver of SendFungibleMulti.
+	TransferTarget struct {
+		PubKey []byte
+		Amount uint64
+	}
+
+	// SendFungibleOptions are the optional parameters of SendFungible.
+	SendFungibleOptions struct {
+		// ChangeOwnerPredicate is the owner predicate of the change when a token has to be split,
+		// nil keeps the owner predicate of the split token.
+		ChangeOwnerPredicate []byte
+		// FailOnLockedTokens makes SendFungible return ErrTokensLocked, listing the locked tokens,
+		// when the send could be completed only if the locked tokens were unlocked.
+		FailOnLockedTokens bool
+	}
+
+	SendFungibleOption func(*SendFungibleOptions)
+
+	// NewTypeOptions are the optional parameters of NewFungibleType and NewNonFungibleType.
+	NewTypeOptions struct {
+		// SkipTypeIDCheck disables the check that the explicitly given type ID is not in use.
+		SkipTypeIDCheck bool
+	}
+
+	NewTypeOption func(*NewTypeOptions)
+
+	// MintOptions are the optional parameters of NewFungibleToken and NewNFT.
+	MintOptions struct {
+		// TokenIDSeed makes the token ID to be derived from the seed instead of the transaction.
+		TokenIDSeed []byte
+	}
+
+	MintOption func(*MintOptions)
+
 	Token interface {
 		GetID() sdktypes.TokenID
 		GetOwnerPredicate() sdktypes.Predicate
@@ -60,9 +169,17 @@ type (
 		Lock(lockStatus uint64, txOptions ...sdktypes.Option) (*types.TransactionOrder, error)
 		Unlock(txOptions ...sdktypes.Option) (*types.TransactionOrder, error)
 	}
+
+	// TokenFilter selects the non-fungible tokens returned by FindNonFungibleTokens, empty fields
+	// match all tokens.
+	TokenFilter struct {
+		NamePrefix string
+		Symbol     string
+		TypeID     sdktypes.TokenTypeID
+	}
 )
 
-func New(tokensClient sdktypes.TokensPartitionClient, am account.Manager, confirmTx bool, feeManager *fees.FeeManager, maxFee uint64, log *slog.Logger) (*Wallet, error) {
+func New(tokensClient sdktypes.TokensPartitionClient, am account.Manager, confirmTx bool, feeManager *fees.FeeManager, maxFee uint64, log *slog.Logger, opts ...Option) (*Wallet, error) {
 	pdr, err := tokensClient.PartitionDescription(context.Background())
 	if err != nil {
 		return nil, fmt.Errorf("loading partition description: %w", err)
@@ -71,7 +188,7 @@ func New(tokensClient sdktypes.TokensPartitionClient, am account.Manager, confir
 		return nil, fmt.Errorf("invalid rpc url: expected tokens partition (%d) node reports partition type %d", tokens.PartitionTypeID, pdr.PartitionTypeID)
 	}
 
-	return &Wallet{
+	w := &Wallet{
 		pdr:          pdr,
 		am:           am,
 		tokensClient: tokensClient,
@@ -79,7 +196,16 @@ func New(tokensClient sdktypes.TokensPartitionClient, am account.Manager, confir
 		feeManager:   feeManager,
 		maxFee:       maxFee,
 		log:          log,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	// with zero max fee every transaction would fail while the fee credit checks pass, dry run
+	// only estimates the fees so it's allowed there
+	if w.maxFee == 0 && !w.dryRun {
+		return nil, ErrInvalidMaxFee
+	}
+	return w, nil
 }
 
 func (w *Wallet) Close() {
@@ -90,10 +216,13 @@ func (w *Wallet) Close() {
 	if w.tokensClient != nil {
 		w.tokensClient.Close()
 	}
+	if w.opStore != nil {
+		_ = w.opStore.Close()
+	}
 }
 
-func newSingleResult(sub *txsubmitter.TxSubmission, accNr uint64) *SubmissionResult {
-	res := &SubmissionResult{AccountNumber: accNr}
+func (w *Wallet) newSingleResult(sub *txsubmitter.TxSubmission, accNr uint64) *SubmissionResult {
+	res := &SubmissionResult{AccountNumber: accNr, DryRun: w.dryRun, pdr: w.pdr}
 	if sub == nil {
 		return res
 	}
@@ -112,6 +241,43 @@ func (r *SubmissionResult) GetProofs() []*types.TxRecordProof {
 	return proofs
 }
 
+// FormatAmount returns the amount as a decimal string using the DecimalPlaces of the result.
+func (r *SubmissionResult) FormatAmount(amount uint64) string {
+	return walletutil.AmountToString(amount, r.DecimalPlaces)
+}
+
+// TxIDs returns the IDs of the submitted transactions in the canonical display format.
+func (r *SubmissionResult) TxIDs() []string {
+	ids := make([]string, len(r.Submissions))
+	for i, sub := range r.Submissions {
+		ids[i] = sub.TxID()
+	}
+	return ids
+}
+
+// FailedSubmissions returns the submissions which were confirmed but whose transaction failed.
+func (r *SubmissionResult) FailedSubmissions() []*txsubmitter.TxSubmission {
+	var failed []*txsubmitter.TxSubmission
+	for _, sub := range r.Submissions {
+		if sub.Confirmed() && !sub.Success() {
+			failed = append(failed, sub)
+		}
+	}
+	return failed
+}
+
+// MaxFeeSum returns the sum of the max fees of the transactions, ie the upper bound of the
+// fees of a dry run.
+func (r *SubmissionResult) MaxFeeSum() uint64 {
+	var sum uint64
+	for _, sub := range r.Submissions {
+		if sub.Transaction != nil && sub.Transaction.ClientMetadata != nil {
+			sum += sub.Transaction.ClientMetadata.MaxTransactionFee
+		}
+	}
+	return sum
+}
+
 func (r *SubmissionResult) GetUnit() types.UnitID {
 	if len(r.Submissions) == 1 {
 		return r.Submissions[0].UnitID
@@ -119,6 +285,28 @@ func (r *SubmissionResult) GetUnit() types.UnitID {
 	return nil
 }
 
+// GetCreatedUnits returns the IDs of the tokens created by the split transactions of the result, ie
+// the tokens of the receivers (and of the change, when it's moved to another owner) in the order of
+// the submissions.
+func (r *SubmissionResult) GetCreatedUnits() []types.UnitID {
+	if r.pdr == nil {
+		return nil
+	}
+	var ids []types.UnitID
+	for _, sub := range r.Submissions {
+		if sub.Transaction == nil || sub.Transaction.Type != tokens.TransactionTypeSplitFT {
+			continue
+		}
+		// the new token ID is derived from the split transaction the same way the partition does it
+		id, err := r.pdr.ComposeUnitID(types.ShardID{}, tokens.FungibleTokenUnitType, tokens.PrndSh(sub.Transaction))
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
 func (w *Wallet) GetAccountManager() account.Manager {
 	return w.am
 }
@@ -131,9 +319,35 @@ func (w *Wallet) PartitionID() types.PartitionID {
 	return w.pdr.PartitionID
 }
 
-func (w *Wallet) NewFungibleType(ctx context.Context, accountNumber uint64, ft *sdktypes.FungibleTokenType, subtypePredicateInputs []*PredicateInput) (*SubmissionResult, error) {
+// WithSkipTypeIDCheck makes NewFungibleType and NewNonFungibleType submit the transaction without
+// checking that the explicitly given type ID is not in use.
+func WithSkipTypeIDCheck() NewTypeOption {
+	return func(o *NewTypeOptions) {
+		o.SkipTypeIDCheck = true
+	}
+}
+
+// WithTokenIDSeed makes NewFungibleToken and NewNFT derive the ID of the new token from the seed,
+// so that the same seed always results in the same token ID (ie for reproducible test fixtures).
+// By default the token ID is generated from the mint transaction.
+func WithTokenIDSeed(seed []byte) MintOption {
+	return func(o *MintOptions) {
+		o.TokenIDSeed = seed
+	}
+}
+
+func (w *Wallet) NewFungibleType(ctx context.Context, accountNumber uint64, ft *sdktypes.FungibleTokenType, subtypePredicateInputs []*PredicateInput, opts ...NewTypeOption) (*SubmissionResult, error) {
+	unlock, err := w.lockAccount(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 	w.log.Info("Creating new FT type")
 
+	o := &NewTypeOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
 	if len(ft.ID) != 0 {
 		if idLen := int(w.pdr.UnitIDLen+w.pdr.TypeIDLen) / 8; idLen != len(ft.ID) {
 			return nil, fmt.Errorf("invalid token type ID: expected hex length is %d characters (%d bytes)", idLen*2, idLen)
@@ -141,6 +355,15 @@ func (w *Wallet) NewFungibleType(ctx context.Context, accountNumber uint64, ft *
 		if ft.ID.TypeMustBe(tokens.FungibleTokenTypeUnitType, w.pdr) != nil {
 			return nil, fmt.Errorf("invalid token type ID: expected unit type is 0x%X", tokens.FungibleTokenTypeUnitType)
 		}
+		if !o.SkipTypeIDCheck {
+			tt, err := w.GetFungibleTokenType(ctx, ft.ID)
+			if err != nil && !errors.Is(err, sdktypes.ErrNotFound) {
+				return nil, fmt.Errorf("failed to check if token type ID is in use: %w", err)
+			}
+			if tt != nil {
+				return nil, fmt.Errorf("%w: %s", ErrTokenTypeExists, ft.ID)
+			}
+		}
 	}
 
 	if ft.ParentTypeID != nil && !bytes.Equal(ft.ParentTypeID, sdktypes.NoParent) {
@@ -169,7 +392,7 @@ func (w *Wallet) NewFungibleType(ctx context.Context, accountNumber uint64, ft *
 	ft.NetworkID = w.pdr.NetworkID
 	ft.PartitionID = w.pdr.PartitionID
 	tx, err := ft.Define(
-		sdktypes.WithTimeout(roundNumber+txTimeoutRoundCount),
+		sdktypes.WithTimeout(w.txTimeout(roundNumber)),
 		sdktypes.WithFeeCreditRecordID(fcrID),
 		sdktypes.WithMaxFee(w.maxFee),
 	)
@@ -205,9 +428,18 @@ func (w *Wallet) NewFungibleType(ctx context.Context, accountNumber uint64, ft *
 	return w.submitTx(ctx, tx, accountNumber)
 }
 
-func (w *Wallet) NewNonFungibleType(ctx context.Context, accountNumber uint64, nft *sdktypes.NonFungibleTokenType, subtypePredicateInputs []*PredicateInput) (*SubmissionResult, error) {
+func (w *Wallet) NewNonFungibleType(ctx context.Context, accountNumber uint64, nft *sdktypes.NonFungibleTokenType, subtypePredicateInputs []*PredicateInput, opts ...NewTypeOption) (*SubmissionResult, error) {
+	unlock, err := w.lockAccount(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 	w.log.Info("Creating new NFT type")
 
+	o := &NewTypeOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
 	if len(nft.ID) != 0 {
 		if idLen := int(w.pdr.UnitIDLen+w.pdr.TypeIDLen) / 8; idLen != len(nft.ID) {
 			return nil, fmt.Errorf("invalid token type ID: expected hex length is %d characters (%d bytes)", idLen*2, idLen)
@@ -215,6 +447,15 @@ func (w *Wallet) NewNonFungibleType(ctx context.Context, accountNumber uint64, n
 		if nft.ID.TypeMustBe(tokens.NonFungibleTokenTypeUnitType, w.pdr) != nil {
 			return nil, fmt.Errorf("invalid token type ID: expected unit type is %#x", tokens.NonFungibleTokenTypeUnitType)
 		}
+		if !o.SkipTypeIDCheck {
+			tt, err := w.GetNonFungibleTokenType(ctx, nft.ID)
+			if err != nil && !errors.Is(err, sdktypes.ErrNotFound) {
+				return nil, fmt.Errorf("failed to check if token type ID is in use: %w", err)
+			}
+			if tt != nil {
+				return nil, fmt.Errorf("%w: %s", ErrTokenTypeExists, nft.ID)
+			}
+		}
 	}
 
 	acc, err := w.getAccount(accountNumber)
@@ -233,7 +474,7 @@ func (w *Wallet) NewNonFungibleType(ctx context.Context, accountNumber uint64, n
 	nft.NetworkID = w.pdr.NetworkID
 	nft.PartitionID = w.pdr.PartitionID
 	tx, err := nft.Define(
-		sdktypes.WithTimeout(roundNumber+txTimeoutRoundCount),
+		sdktypes.WithTimeout(w.txTimeout(roundNumber)),
 		sdktypes.WithFeeCreditRecordID(fcrID),
 		sdktypes.WithMaxFee(w.maxFee),
 	)
@@ -269,13 +510,25 @@ func (w *Wallet) NewNonFungibleType(ctx context.Context, accountNumber uint64, n
 	return w.submitTx(ctx, tx, accountNumber)
 }
 
-func (w *Wallet) NewFungibleToken(ctx context.Context, accountNumber uint64, ft *sdktypes.FungibleToken, mintPredicateInput *PredicateInput) (*SubmissionResult, error) {
+func (w *Wallet) NewFungibleToken(ctx context.Context, accountNumber uint64, ft *sdktypes.FungibleToken, mintPredicateInput *PredicateInput, opts ...MintOption) (*SubmissionResult, error) {
+	unlock, err := w.lockAccount(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 	w.log.Info("Minting new fungible token")
 
+	o := &MintOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
 	acc, err := w.getAccount(accountNumber)
 	if err != nil {
 		return nil, err
 	}
+	if err := w.VerifyMintPredicateInput(ctx, ft.TypeID, mintPredicateInput); err != nil {
+		return nil, err
+	}
 	fcrID, err := w.ensureFeeCredit(ctx, acc.AccountKey, 1)
 	if err != nil {
 		return nil, err
@@ -287,13 +540,19 @@ func (w *Wallet) NewFungibleToken(ctx context.Context, accountNumber uint64, ft
 
 	tx, err := ft.Mint(
 		w.pdr,
-		sdktypes.WithTimeout(roundNumber+txTimeoutRoundCount),
+		sdktypes.WithTimeout(w.txTimeout(roundNumber)),
 		sdktypes.WithFeeCreditRecordID(fcrID),
 		sdktypes.WithMaxFee(w.maxFee),
 	)
 	if err != nil {
 		return nil, err
 	}
+	if len(o.TokenIDSeed) > 0 {
+		if tx.UnitID, err = tokenIDFromSeed(w.pdr, tokens.FungibleTokenUnitType, o.TokenIDSeed); err != nil {
+			return nil, err
+		}
+		ft.ID = tx.UnitID
+	}
 
 	sigBytes, err := tx.AuthProofSigBytes()
 	if err != nil {
@@ -315,7 +574,12 @@ func (w *Wallet) NewFungibleToken(ctx context.Context, accountNumber uint64, ft
 	return w.submitTx(ctx, tx, accountNumber)
 }
 
-func (w *Wallet) NewNFT(ctx context.Context, accountNumber uint64, nft *sdktypes.NonFungibleToken, mintPredicateInput *PredicateInput) (*SubmissionResult, error) {
+func (w *Wallet) NewNFT(ctx context.Context, accountNumber uint64, nft *sdktypes.NonFungibleToken, mintPredicateInput *PredicateInput, opts ...MintOption) (*SubmissionResult, error) {
+	unlock, err := w.lockAccount(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 	w.log.Info("Minting new NFT")
 
 	if len(nft.Name) > nameMaxSize {
@@ -331,10 +595,17 @@ func (w *Wallet) NewNFT(ctx context.Context, accountNumber uint64, nft *sdktypes
 		return nil, errInvalidDataLength
 	}
 
+	o := &MintOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
 	acc, err := w.getAccount(accountNumber)
 	if err != nil {
 		return nil, err
 	}
+	if err := w.VerifyMintPredicateInput(ctx, nft.TypeID, mintPredicateInput); err != nil {
+		return nil, err
+	}
 	fcrID, err := w.ensureFeeCredit(ctx, acc.AccountKey, 1)
 	if err != nil {
 		return nil, err
@@ -346,13 +617,19 @@ func (w *Wallet) NewNFT(ctx context.Context, accountNumber uint64, nft *sdktypes
 
 	tx, err := nft.Mint(
 		w.pdr,
-		sdktypes.WithTimeout(roundNumber+txTimeoutRoundCount),
+		sdktypes.WithTimeout(w.txTimeout(roundNumber)),
 		sdktypes.WithFeeCreditRecordID(fcrID),
 		sdktypes.WithMaxFee(w.maxFee),
 	)
 	if err != nil {
 		return nil, err
 	}
+	if len(o.TokenIDSeed) > 0 {
+		if tx.UnitID, err = tokenIDFromSeed(w.pdr, tokens.NonFungibleTokenUnitType, o.TokenIDSeed); err != nil {
+			return nil, err
+		}
+		nft.ID = tx.UnitID
+	}
 
 	sigBytes, err := tx.AuthProofSigBytes()
 	if err != nil {
@@ -374,28 +651,32 @@ func (w *Wallet) NewNFT(ctx context.Context, accountNumber uint64, nft *sdktypes
 	return w.submitTx(ctx, tx, accountNumber)
 }
 
+// tokenIDFromSeed composes the unit ID of the given unit type deterministically from the seed, the
+// unit part of the ID is the SHA256 hash of the seed.
+func tokenIDFromSeed(pdr *types.PartitionDescriptionRecord, unitType uint32, seed []byte) (types.UnitID, error) {
+	id, err := pdr.ComposeUnitID(types.ShardID{}, unitType, func(buf []byte) error {
+		h := hash.Sum256(seed)
+		if len(buf) > len(h) {
+			return fmt.Errorf("unit ID length %d bytes exceeds the length of the seed hash", len(buf))
+		}
+		copy(buf, h)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("composing token ID from seed: %w", err)
+	}
+	if err = id.TypeMustBe(unitType, pdr); err != nil {
+		return nil, fmt.Errorf("invalid token ID composed from seed: %w", err)
+	}
+	return id, nil
+}
+
 func (w *Wallet) ListFungibleTokenTypes(ctx context.Context, accountNumber uint64) ([]*sdktypes.FungibleTokenType, error) {
 	keys, err := w.getAccounts(accountNumber)
 	if err != nil {
 		return nil, err
 	}
-	allTokenTypes := make([]*sdktypes.FungibleTokenType, 0)
-	fetchForPubKey := func(pubKey []byte) ([]*sdktypes.FungibleTokenType, error) {
-		typez, err := w.tokensClient.GetFungibleTokenTypes(ctx, pubKey)
-		if err != nil {
-			return nil, err
-		}
-		return typez, nil
-	}
-	for _, key := range keys {
-		typez, err := fetchForPubKey(key.PubKey)
-		if err != nil {
-			return nil, err
-		}
-		allTokenTypes = append(allTokenTypes, typez...)
-	}
-
-	return allTokenTypes, nil
+	return fetchPerAccount(ctx, keys, w.tokensClient.GetFungibleTokenTypes)
 }
 
 func (w *Wallet) ListNonFungibleTokenTypes(ctx context.Context, accountNumber uint64) ([]*sdktypes.NonFungibleTokenType, error) {
@@ -403,30 +684,47 @@ func (w *Wallet) ListNonFungibleTokenTypes(ctx context.Context, accountNumber ui
 	if err != nil {
 		return nil, err
 	}
-	allTokenTypes := make([]*sdktypes.NonFungibleTokenType, 0)
-	fetchForPubKey := func(pubKey []byte) ([]*sdktypes.NonFungibleTokenType, error) {
-		typez, err := w.tokensClient.GetNonFungibleTokenTypes(ctx, pubKey)
-		if err != nil {
-			return nil, err
-		}
-		return typez, nil
+	return fetchPerAccount(ctx, keys, w.tokensClient.GetNonFungibleTokenTypes)
+}
+
+// fetchPerAccount calls fetch for the public key of each account concurrently, at most
+// maxFetchWorkers at a time, and returns the results concatenated in the order of the accounts.
+// The first failing fetch cancels the rest and its error is returned.
+func fetchPerAccount[T any](ctx context.Context, keys []*accountKey, fetch func(ctx context.Context, pubKey sdktypes.PubKey) ([]T, error)) ([]T, error) {
+	results := make([][]T, len(keys))
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxFetchWorkers)
+	for i, key := range keys {
+		g.Go(func() error {
+			res, err := fetch(ctx, key.PubKey)
+			if err != nil {
+				return err
+			}
+			results[i] = res
+			return nil
+		})
 	}
-	for _, key := range keys {
-		typez, err := fetchForPubKey(key.PubKey)
-		if err != nil {
-			return nil, err
-		}
-		allTokenTypes = append(allTokenTypes, typez...)
+	if err := g.Wait(); err != nil {
+		return nil, err
 	}
-
-	return allTokenTypes, nil
+	all := make([]T, 0)
+	for _, res := range results {
+		all = append(all, res...)
+	}
+	return all, nil
 }
 
 // GetFungibleTokenType returns FungibleTokenType or nil if not found
 func (w *Wallet) GetFungibleTokenType(ctx context.Context, typeId sdktypes.TokenTypeID) (*sdktypes.FungibleTokenType, error) {
-	typez, err := w.tokensClient.GetFungibleTokenTypeHierarchy(ctx, typeId)
-	if err != nil {
-		return nil, err
+	typez, cached := w.ftTypeCache.get(string(typeId))
+	if !cached {
+		var err error
+		if typez, err = w.tokensClient.GetFungibleTokenTypeHierarchy(ctx, typeId); err != nil {
+			return nil, err
+		}
+		if len(typez) > 0 {
+			w.ftTypeCache.set(string(typeId), typez)
+		}
 	}
 	for i := range typez {
 		if bytes.Equal(typez[i].ID, typeId) {
@@ -438,9 +736,15 @@ func (w *Wallet) GetFungibleTokenType(ctx context.Context, typeId sdktypes.Token
 
 // GetNonFungibleTokenType returns NonFungibleTokenType or nil if not found
 func (w *Wallet) GetNonFungibleTokenType(ctx context.Context, typeId sdktypes.TokenTypeID) (*sdktypes.NonFungibleTokenType, error) {
-	typez, err := w.tokensClient.GetNonFungibleTokenTypeHierarchy(ctx, typeId)
-	if err != nil {
-		return nil, err
+	typez, cached := w.nftTypeCache.get(string(typeId))
+	if !cached {
+		var err error
+		if typez, err = w.tokensClient.GetNonFungibleTokenTypeHierarchy(ctx, typeId); err != nil {
+			return nil, err
+		}
+		if len(typez) > 0 {
+			w.nftTypeCache.set(string(typeId), typez)
+		}
 	}
 	for i := range typez {
 		if bytes.Equal(typez[i].ID, typeId) {
@@ -460,6 +764,80 @@ func (w *Wallet) ListFungibleTokens(ctx context.Context, accountNumber uint64) (
 	return w.tokensClient.GetFungibleTokens(ctx, key.PubKeyHash.Sha256)
 }
 
+// ListFungibleTokensByType returns the fungible tokens of the given type for the given accountNumber.
+// The tokens are currently filtered on the client side.
+func (w *Wallet) ListFungibleTokensByType(ctx context.Context, accountNumber uint64, typeID sdktypes.TokenTypeID) ([]*sdktypes.FungibleToken, error) {
+	tokenz, err := w.ListFungibleTokens(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	var res []*sdktypes.FungibleToken
+	for _, t := range tokenz {
+		if typeID.Eq(t.TypeID) {
+			res = append(res, t)
+		}
+	}
+	return res, nil
+}
+
+// GetFungibleTokenBalances returns the balances of the fungible tokens of the given account (all accounts
+// if accountNumber is AllAccounts) keyed by the hex encoded token type ID. Locked tokens are not included
+// and a balance is capped at math.MaxUint64.
+func (w *Wallet) GetFungibleTokenBalances(ctx context.Context, accountNumber uint64) (map[string]*FungibleTokenBalance, error) {
+	keys, err := w.getAccounts(accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	balances := make(map[string]*FungibleTokenBalance)
+	for _, key := range keys {
+		tokenz, err := w.tokensClient.GetFungibleTokens(ctx, key.PubKeyHash.Sha256)
+		if err != nil {
+			return nil, fmt.Errorf("fetching tokens of account #%d: %w", key.AccountNumber(), err)
+		}
+		for _, token := range tokenz {
+			if token.LockStatus != 0 {
+				continue
+			}
+			balance, ok := balances[token.TypeID.String()]
+			if !ok {
+				balance = &FungibleTokenBalance{TypeID: token.TypeID, Symbol: token.Symbol, DecimalPlaces: token.DecimalPlaces}
+				balances[token.TypeID.String()] = balance
+			}
+			var overflow bool
+			balance.Amount, overflow, _ = util.AddUint64(balance.Amount, token.Amount)
+			if overflow {
+				balance.Amount = math.MaxUint64
+			}
+		}
+	}
+	return balances, nil
+}
+
+// GetSpendableBalance returns the balance of the fungible tokens of the given type of the account split
+// into the spendable balance (unlocked tokens, the ones SendFungible can use) and the balance of the
+// locked tokens. The balances are capped at math.MaxUint64.
+func (w *Wallet) GetSpendableBalance(ctx context.Context, accountNumber uint64, typeID sdktypes.TokenTypeID) (spendable, locked uint64, err error) {
+	tokenz, err := w.ListFungibleTokens(ctx, accountNumber)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, token := range tokenz {
+		if !typeID.Eq(token.TypeID) {
+			continue
+		}
+		balance := &spendable
+		if token.LockStatus != 0 {
+			balance = &locked
+		}
+		var overflow bool
+		*balance, overflow, _ = util.AddUint64(*balance, token.Amount)
+		if overflow {
+			*balance = math.MaxUint64
+		}
+	}
+	return spendable, locked, nil
+}
+
 // ListNonFungibleTokens returns all non-fungible tokens for the given accountNumber
 func (w *Wallet) ListNonFungibleTokens(ctx context.Context, accountNumber uint64) ([]*sdktypes.NonFungibleToken, error) {
 	key, err := w.getAccount(accountNumber)
@@ -470,6 +848,79 @@ func (w *Wallet) ListNonFungibleTokens(ctx context.Context, accountNumber uint64
 	return w.tokensClient.GetNonFungibleTokens(ctx, key.PubKeyHash.Sha256)
 }
 
+// ListNonFungibleTokensByType returns the non-fungible tokens of the given type for the given
+// accountNumber. The tokens are currently filtered on the client side.
+func (w *Wallet) ListNonFungibleTokensByType(ctx context.Context, accountNumber uint64, typeID sdktypes.TokenTypeID) ([]*sdktypes.NonFungibleToken, error) {
+	tokenz, err := w.ListNonFungibleTokens(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	var res []*sdktypes.NonFungibleToken
+	for _, t := range tokenz {
+		if typeID.Eq(t.TypeID) {
+			res = append(res, t)
+		}
+	}
+	return res, nil
+}
+
+// FindNonFungibleTokens returns the non-fungible tokens of the account that match the filter, sorted
+// by name. The filter is applied to the tokens returned by ListNonFungibleTokens.
+func (w *Wallet) FindNonFungibleTokens(ctx context.Context, accountNumber uint64, filter TokenFilter) ([]*sdktypes.NonFungibleToken, error) {
+	tokenz, err := w.ListNonFungibleTokens(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	var res []*sdktypes.NonFungibleToken
+	for _, t := range tokenz {
+		if filter.Match(t) {
+			res = append(res, t)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res, nil
+}
+
+// Match returns true if the token matches all the non-empty fields of the filter.
+func (f TokenFilter) Match(t *sdktypes.NonFungibleToken) bool {
+	if f.NamePrefix != "" && !strings.HasPrefix(t.Name, f.NamePrefix) {
+		return false
+	}
+	if f.Symbol != "" && t.Symbol != f.Symbol {
+		return false
+	}
+	if len(f.TypeID) > 0 && !f.TypeID.Eq(t.TypeID) {
+		return false
+	}
+	return true
+}
+
+// CountTokens returns the number of fungible and non-fungible tokens of the given account (all
+// accounts if accountNumber is AllAccounts). The tokens are counted by the type of the unit IDs
+// owned by the account, the token data is not fetched.
+func (w *Wallet) CountTokens(ctx context.Context, accountNumber uint64) (fungible int, nonFungible int, err error) {
+	keys, err := w.getAccounts(accountNumber)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, key := range keys {
+		unitIDs, err := w.tokensClient.GetUnitsByOwnerID(ctx, key.PubKeyHash.Sha256)
+		if err != nil {
+			return 0, 0, fmt.Errorf("fetching units of account #%d: %w", key.AccountNumber(), err)
+		}
+		for _, unitID := range unitIDs {
+			if unitID.TypeMustBe(tokens.FungibleTokenUnitType, w.pdr) == nil {
+				fungible++
+			} else if unitID.TypeMustBe(tokens.NonFungibleTokenUnitType, w.pdr) == nil {
+				nonFungible++
+			}
+		}
+	}
+	return fungible, nonFungible, nil
+}
+
 type accountKey struct {
 	*account.AccountKey
 	idx uint64
@@ -490,6 +941,32 @@ func (w *Wallet) getAccount(accountNumber uint64) (*accountKey, error) {
 	return &accountKey{AccountKey: key, idx: accountNumber - 1}, nil
 }
 
+/*
+lockAccount blocks until the lock of the account is acquired or the context is cancelled, the
+returned function releases the lock. The transactions of an account are built from the state of
+its units (token counters, fee credit balance), so concurrent operations of the account must not
+interleave the fetching of the units with building and submitting the transactions.
+*/
+func (w *Wallet) lockAccount(ctx context.Context, accountNumber uint64) (unlock func(), err error) {
+	w.accountMu.Lock()
+	if w.accountLocks == nil {
+		w.accountLocks = make(map[uint64]chan struct{})
+	}
+	lock, ok := w.accountLocks[accountNumber]
+	if !ok {
+		lock = make(chan struct{}, 1)
+		w.accountLocks[accountNumber] = lock
+	}
+	w.accountMu.Unlock()
+
+	select {
+	case lock <- struct{}{}:
+		return func() { <-lock }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for the lock of account #%d: %w", accountNumber, ctx.Err())
+	}
+}
+
 func (w *Wallet) getAccounts(accountNumber uint64) ([]*accountKey, error) {
 	if accountNumber > AllAccounts {
 		key, err := w.getAccount(accountNumber)
@@ -531,7 +1008,65 @@ func (w *Wallet) GetNonFungibleToken(ctx context.Context, tokenID sdktypes.Token
 	return token, nil
 }
 
+// getToken fetches the fungible or non-fungible token depending on the unit type of the ID.
+func (w *Wallet) getToken(ctx context.Context, tokenID types.UnitID) (Token, error) {
+	tid, err := w.pdr.ExtractUnitType(tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("extracting token type: %w", err)
+	}
+	switch tid {
+	case tokens.FungibleTokenUnitType:
+		return w.GetFungibleToken(ctx, tokenID)
+	case tokens.NonFungibleTokenUnitType:
+		return w.GetNonFungibleToken(ctx, tokenID)
+	default:
+		return nil, errors.New("invalid token ID")
+	}
+}
+
+// OwnerPredicateForAccount returns the P2PKH owner predicate of the account key, ie the predicate
+// to use as the bearer clause of the tokens sent to the account.
+func (w *Wallet) OwnerPredicateForAccount(accountNumber uint64) ([]byte, error) {
+	acc, err := w.getAccount(accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	return templates.NewP2pkh256BytesFromKey(acc.PubKey), nil
+}
+
+// IsTokenOwnedByAccount returns true if the owner predicate of the token is the P2PKH predicate of
+// the account key, ie the token can be spent by the account without a custom owner proof.
+func (w *Wallet) IsTokenOwnedByAccount(ctx context.Context, accountNumber uint64, tokenID types.UnitID) (bool, error) {
+	ownerPredicate, err := w.OwnerPredicateForAccount(accountNumber)
+	if err != nil {
+		return false, err
+	}
+	token, err := w.getToken(ctx, tokenID)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(token.GetOwnerPredicate(), ownerPredicate), nil
+}
+
 func (w *Wallet) TransferNFT(ctx context.Context, accountNumber uint64, tokenID sdktypes.TokenID, receiverPubKey sdktypes.PubKey, typeOwnerPredicateInputs []*PredicateInput, ownerPredicateInput *PredicateInput) (*SubmissionResult, error) {
+	return w.transferNFT(ctx, accountNumber, tokenID, OwnerPredicateFromPubKey(receiverPubKey), typeOwnerPredicateInputs, ownerPredicateInput)
+}
+
+// TransferNFTToHash transfers the non-fungible token to the P2PKH predicate of the receiver's public
+// key hash, for when the receiver's public key itself is not known.
+func (w *Wallet) TransferNFTToHash(ctx context.Context, accountNumber uint64, tokenID sdktypes.TokenID, pubKeyHash []byte, typeOwnerPredicateInputs []*PredicateInput, ownerPredicateInput *PredicateInput) (*SubmissionResult, error) {
+	if len(pubKeyHash) != 32 {
+		return nil, fmt.Errorf("invalid public key hash length %d, expected 32 bytes", len(pubKeyHash))
+	}
+	return w.transferNFT(ctx, accountNumber, tokenID, ownerPredicateFromHash(pubKeyHash), typeOwnerPredicateInputs, ownerPredicateInput)
+}
+
+func (w *Wallet) transferNFT(ctx context.Context, accountNumber uint64, tokenID sdktypes.TokenID, newOwnerPredicate sdktypes.Predicate, typeOwnerPredicateInputs []*PredicateInput, ownerPredicateInput *PredicateInput) (*SubmissionResult, error) {
+	unlock, err := w.lockAccount(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 	acc, err := w.getAccount(accountNumber)
 	if err != nil {
 		return nil, err
@@ -555,54 +1090,148 @@ func (w *Wallet) TransferNFT(ctx context.Context, accountNumber uint64, tokenID
 		return nil, err
 	}
 
-	tx, err := token.Transfer(OwnerPredicateFromPubKey(receiverPubKey),
-		sdktypes.WithTimeout(roundNumber+txTimeoutRoundCount),
-		sdktypes.WithFeeCreditRecordID(fcrID),
-		sdktypes.WithMaxFee(w.maxFee),
-	)
+	tx, err := w.prepareNFTTransferTx(acc, token, newOwnerPredicate, fcrID, w.txTimeout(roundNumber), ownerPredicateInput, typeOwnerPredicateInputs)
 	if err != nil {
 		return nil, err
 	}
+	return w.submitTx(ctx, tx, accountNumber)
+}
 
-	sigBytes, err := tx.AuthProofSigBytes()
+// TransferNFTs transfers multiple non-fungible tokens, each to its own receiver, in a single batch.
+// All the tokens must belong to the account, locked tokens are skipped. Returns a result for each
+// transfer in the same order as the transfers, the result of a skipped transfer has no submissions
+// and the reason why the token was skipped.
+func (w *Wallet) TransferNFTs(ctx context.Context, accountNumber uint64, transfers []NFTTransfer) ([]*SubmissionResult, error) {
+	unlock, err := w.lockAccount(ctx, accountNumber)
 	if err != nil {
 		return nil, err
 	}
-	typeOwnerProofs, err := newProofs(sigBytes, typeOwnerPredicateInputs)
+	defer unlock()
+	if len(transfers) == 0 {
+		return nil, errors.New("no transfers")
+	}
+	acc, err := w.getAccount(accountNumber)
 	if err != nil {
 		return nil, err
 	}
-	ownerProof, err := ownerPredicateInput.Proof(sigBytes)
+
+	nfts := make([]*sdktypes.NonFungibleToken, len(transfers))
+	results := make([]*SubmissionResult, len(transfers))
+	seen := make(map[string]struct{}, len(transfers))
+	var txCount uint64
+	for i, transfer := range transfers {
+		if _, ok := seen[string(transfer.TokenID)]; ok {
+			return nil, fmt.Errorf("duplicate transfer of token %s", transfer.TokenID)
+		}
+		seen[string(transfer.TokenID)] = struct{}{}
+
+		token, err := w.GetNonFungibleToken(ctx, transfer.TokenID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get token %s: %w", transfer.TokenID, err)
+		}
+		if err = ensureTokenOwnership(acc, token, transfer.ownerPredicateInput(acc)); err != nil {
+			return nil, err
+		}
+		results[i] = &SubmissionResult{AccountNumber: accountNumber, DryRun: w.dryRun}
+		if token.GetLockStatus() != 0 {
+			results[i].SkipReason = fmt.Errorf("token %s is locked", token.ID)
+			continue
+		}
+		nfts[i] = token
+		txCount++
+	}
+	if txCount == 0 {
+		return results, nil
+	}
+
+	fcrID, err := w.ensureFeeCredit(ctx, acc.AccountKey, txCount)
 	if err != nil {
 		return nil, err
 	}
-	err = tx.SetAuthProof(tokens.TransferNonFungibleTokenAuthProof{
-		OwnerProof:           ownerProof,
-		TokenTypeOwnerProofs: typeOwnerProofs,
-	})
+	roundNumber, err := w.GetRoundNumber(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to set auth proof: %w", err)
+		return nil, err
 	}
-	tx.FeeProof, err = sdktypes.NewP2pkhFeeSignatureFromKey(tx, acc.PrivKey)
-	if err != nil {
-		return nil, fmt.Errorf("failed to sign tx fee proof: %w", err)
+	batch := txsubmitter.NewBatch(w.tokensClient, w.log, w.batchOpts...)
+	subs := make([]*txsubmitter.TxSubmission, len(transfers))
+	for i, token := range nfts {
+		if token == nil {
+			continue
+		}
+		tx, err := w.prepareNFTTransferTx(acc, token, OwnerPredicateFromPubKey(transfers[i].ReceiverPubKey), fcrID, w.txTimeout(roundNumber),
+			transfers[i].ownerPredicateInput(acc), transfers[i].TypeOwnerPredicateInputs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prepare transfer of token %s: %w", token.ID, err)
+		}
+		if subs[i], err = txsubmitter.New(tx); err != nil {
+			return nil, err
+		}
+		batch.Add(subs[i])
+	}
+	err = w.sendBatch(ctx, OperationTransferNFTs, accountNumber, batch, w.confirmTx)
+	for i, sub := range subs {
+		if sub != nil {
+			results[i] = w.newSingleResult(sub, accountNumber)
+		}
 	}
+	return results, err
+}
 
-	return w.submitTx(ctx, tx, accountNumber)
+func (t *NFTTransfer) ownerPredicateInput(acc *accountKey) *PredicateInput {
+	if t.OwnerPredicateInput != nil {
+		return t.OwnerPredicateInput
+	}
+	return defaultProof(acc.AccountKey)
+}
+
+// WithChangeOwnerPredicate sets the owner predicate of the change when SendFungible has to split a token.
+// By default the change keeps the owner predicate of the split token.
+func WithChangeOwnerPredicate(predicate []byte) SendFungibleOption {
+	return func(o *SendFungibleOptions) {
+		o.ChangeOwnerPredicate = predicate
+	}
+}
+
+// WithFailOnLockedTokens makes SendFungible return ErrTokensLocked instead of the generic
+// insufficient tokens error when the balance is sufficient only with the locked tokens included.
+func WithFailOnLockedTokens() SendFungibleOption {
+	return func(o *SendFungibleOptions) {
+		o.FailOnLockedTokens = true
+	}
 }
 
-func (w *Wallet) SendFungible(ctx context.Context, accountNumber uint64, typeId sdktypes.TokenTypeID, targetAmount uint64, receiverPubKey []byte, ownerPredicateInput *PredicateInput, typeOwnerPredicateInputs []*PredicateInput) (*SubmissionResult, error) {
+func (w *Wallet) SendFungible(ctx context.Context, accountNumber uint64, typeId sdktypes.TokenTypeID, targetAmount uint64, receiverPubKey []byte, ownerPredicateInput *PredicateInput, typeOwnerPredicateInputs []*PredicateInput, opts ...SendFungibleOption) (*SubmissionResult, error) {
+	unlock, err := w.lockAccount(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 	if targetAmount == 0 {
 		return nil, fmt.Errorf("invalid amount: 0")
 	}
 	if accountNumber < 1 {
 		return nil, fmt.Errorf("invalid account number: %d", accountNumber)
 	}
+	o := &SendFungibleOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	txCount := uint64(1)
+	if o.ChangeOwnerPredicate != nil {
+		if _, err := extractPredicate(o.ChangeOwnerPredicate); err != nil {
+			return nil, fmt.Errorf("invalid change owner predicate: %w", err)
+		}
+		if bytes.Equal(o.ChangeOwnerPredicate, templates.AlwaysFalseBytes()) {
+			return nil, errors.New("invalid change owner predicate: predicate is always false")
+		}
+		// moving the change requires an additional transfer
+		txCount++
+	}
 	acc, err := w.getAccount(accountNumber)
 	if err != nil {
 		return nil, err
 	}
-	fcrID, err := w.ensureFeeCredit(ctx, acc.AccountKey, 1)
+	fcrID, err := w.ensureFeeCredit(ctx, acc.AccountKey, txCount)
 	if err != nil {
 		return nil, err
 	}
@@ -614,18 +1243,24 @@ func (w *Wallet) SendFungible(ctx context.Context, accountNumber uint64, typeId
 		return nil, fmt.Errorf("account %d has no tokens", accountNumber)
 	}
 	var matchingTokens []*sdktypes.FungibleToken
-	var totalBalance uint64
+	var lockedTokens []*sdktypes.FungibleToken
+	var totalBalance, lockedBalance uint64
 	// find the best unit candidate for transfer or split, value must be equal or larger than the target amount
 	var closestMatch *sdktypes.FungibleToken
 	for _, token := range tokenz {
 		if !typeId.Eq(token.TypeID) {
 			continue
 		}
+		var overflow bool
 		if token.LockStatus != 0 {
+			lockedTokens = append(lockedTokens, token)
+			lockedBalance, overflow, _ = util.AddUint64(lockedBalance, token.Amount)
+			if overflow {
+				lockedBalance = math.MaxUint64
+			}
 			continue
 		}
 		matchingTokens = append(matchingTokens, token)
-		var overflow bool
 		totalBalance, overflow, _ = util.AddUint64(totalBalance, token.Amount)
 		if overflow {
 			// capping the total balance to maxUint64 should be enough to perform the transfer
@@ -643,7 +1278,19 @@ func (w *Wallet) SendFungible(ctx context.Context, accountNumber uint64, typeId
 		}
 	}
 	if targetAmount > totalBalance {
-		return nil, fmt.Errorf("insufficient tokens of type %s: got %v, need %v", typeId, totalBalance, targetAmount)
+		if len(lockedTokens) == 0 {
+			return nil, fmt.Errorf("insufficient tokens of type %s: got %v, need %v", typeId, totalBalance, targetAmount)
+		}
+		if o.FailOnLockedTokens && targetAmount-totalBalance <= lockedBalance {
+			ids := make([]string, len(lockedTokens))
+			for i, token := range lockedTokens {
+				ids[i] = token.ID.String()
+			}
+			return nil, fmt.Errorf("%w: got %v unlocked, need %v, locked tokens with total value %v: %s",
+				ErrTokensLocked, totalBalance, targetAmount, lockedBalance, strings.Join(ids, ", "))
+		}
+		return nil, fmt.Errorf("insufficient tokens of type %s: got %v, need %v (excluded %d locked token(s) with total value %v)",
+			typeId, totalBalance, targetAmount, len(lockedTokens), lockedBalance)
 	}
 	// optimization: first try to make a single operation instead of iterating through all tokens in doSendMultiple
 	if closestMatch.Amount >= targetAmount {
@@ -651,18 +1298,42 @@ func (w *Wallet) SendFungible(ctx context.Context, accountNumber uint64, typeId
 		if err != nil {
 			return nil, err
 		}
-		sub, err := w.prepareSplitOrTransferTx(acc, targetAmount, closestMatch, fcrID, receiverPubKey, roundNumber+txTimeoutRoundCount, ownerPredicateInput, typeOwnerPredicateInputs)
+		sub, err := w.prepareSplitOrTransferTx(acc, targetAmount, closestMatch, fcrID, receiverPubKey, w.txTimeout(roundNumber), ownerPredicateInput, typeOwnerPredicateInputs)
 		if err != nil {
 			return nil, err
 		}
-		err = sub.ToBatch(w.tokensClient, w.log).SendTx(ctx, w.confirmTx)
-		return newSingleResult(sub, accountNumber), err
+		moveChange := closestMatch.Amount > targetAmount && o.ChangeOwnerPredicate != nil && !bytes.Equal(closestMatch.OwnerPredicate, o.ChangeOwnerPredicate)
+		err = w.sendTx(ctx, sub.ToBatch(w.tokensClient, w.log, w.batchOpts...), w.confirmTx || moveChange)
+		res := w.newSingleResult(sub, accountNumber)
+		// in dry run mode the split is not executed, so the change can't be moved
+		if err != nil || !moveChange || w.dryRun {
+			return res, err
+		}
+		changeSub, err := w.sendSplitChange(ctx, acc, closestMatch.ID, fcrID, o.ChangeOwnerPredicate, ownerPredicateInput, typeOwnerPredicateInputs)
+		if changeSub != nil {
+			res.Submissions = append(res.Submissions, changeSub)
+			if changeSub.Confirmed() {
+				res.FeeSum += changeSub.Proof.TxRecord.ServerMetadata.ActualFee
+			}
+		}
+		return res, err
 	} else {
-		return w.doSendMultiple(ctx, targetAmount, matchingTokens, acc, fcrID, receiverPubKey, ownerPredicateInput, typeOwnerPredicateInputs)
+		sort.Slice(matchingTokens, func(i, j int) bool {
+			return matchingTokens[i].Amount > matchingTokens[j].Amount
+		})
+		return w.doSendMultiple(ctx, targetAmount, matchingTokens, acc, fcrID, receiverPubKey, o.ChangeOwnerPredicate, ownerPredicateInput, typeOwnerPredicateInputs)
 	}
 }
 
-func (w *Wallet) UpdateNFTData(ctx context.Context, accountNumber uint64, tokenID sdktypes.TokenID, data []byte, tokenDataUpdatePredicateInput *PredicateInput, tokenTypeDataUpdatePredicateInputs []*PredicateInput) (*SubmissionResult, error) {
+// UpdateNFTData updates the data of the non-fungible token. When expectedCounter is not nil the update
+// is not sent unless the current counter of the token equals to it, ie the token hasn't changed since
+// the caller last read it.
+func (w *Wallet) UpdateNFTData(ctx context.Context, accountNumber uint64, tokenID sdktypes.TokenID, data []byte, tokenDataUpdatePredicateInput *PredicateInput, tokenTypeDataUpdatePredicateInputs []*PredicateInput, expectedCounter *uint64) (*SubmissionResult, error) {
+	unlock, err := w.lockAccount(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 	acc, err := w.getAccount(accountNumber)
 	if err != nil {
 		return nil, err
@@ -678,49 +1349,104 @@ func (w *Wallet) UpdateNFTData(ctx context.Context, accountNumber uint64, tokenI
 	if t.GetLockStatus() != 0 {
 		return nil, errors.New("token is locked")
 	}
+	if expectedCounter != nil && t.Counter != *expectedCounter {
+		return nil, fmt.Errorf("token counter is %d, expected %d", t.Counter, *expectedCounter)
+	}
+	if err = ensureDataUpdateProof(t, tokenDataUpdatePredicateInput); err != nil {
+		return nil, err
+	}
 	roundNumber, err := w.GetRoundNumber(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	tx, err := t.Update(data,
-		sdktypes.WithTimeout(roundNumber+txTimeoutRoundCount),
-		sdktypes.WithFeeCreditRecordID(fcrID),
-		sdktypes.WithMaxFee(w.maxFee),
-	)
+	tx, err := w.prepareNFTUpdateTx(acc, t, data, fcrID, w.txTimeout(roundNumber), tokenDataUpdatePredicateInput, tokenTypeDataUpdatePredicateInputs)
 	if err != nil {
 		return nil, err
 	}
+	return w.submitTx(ctx, tx, accountNumber)
+}
 
-	sigBytes, err := tx.AuthProofSigBytes()
+// TransferAndUpdateNFT transfers the NFT to the receiver and updates its data. The transfer and the
+// update are submitted as an ordered batch of two transactions (the update is created for the counter
+// of the transferred token) and the fee credit must cover the max fee of both. The result holds the
+// submissions of both transactions, transfer first. The data-update predicate inputs must satisfy the
+// data-update predicates of the token and its types, the owner of the token is not required to
+// authorize the update.
+func (w *Wallet) TransferAndUpdateNFT(ctx context.Context, accountNumber uint64, tokenID sdktypes.TokenID, receiverPubKey sdktypes.PubKey, data []byte, typeOwnerPredicateInputs []*PredicateInput, ownerPredicateInput *PredicateInput, tokenDataUpdatePredicateInput *PredicateInput, tokenTypeDataUpdatePredicateInputs []*PredicateInput) (*SubmissionResult, error) {
+	unlock, err := w.lockAccount(ctx, accountNumber)
 	if err != nil {
 		return nil, err
 	}
-	tokenDataUpdateProof, err := tokenDataUpdatePredicateInput.Proof(sigBytes)
+	defer unlock()
+	acc, err := w.getAccount(accountNumber)
 	if err != nil {
 		return nil, err
 	}
-	tokenTypeDataUpdateProofs, err := newProofs(sigBytes, tokenTypeDataUpdatePredicateInputs)
+	fcrID, err := w.ensureFeeCredit(ctx, acc.AccountKey, 2)
 	if err != nil {
 		return nil, err
 	}
-	err = tx.SetAuthProof(tokens.UpdateNonFungibleTokenAuthProof{
-		TokenDataUpdateProof:      tokenDataUpdateProof,
-		TokenTypeDataUpdateProofs: tokenTypeDataUpdateProofs,
-	})
+	token, err := w.GetNonFungibleToken(ctx, tokenID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to set auth proof: %w", err)
+		return nil, err
 	}
-	tx.FeeProof, err = sdktypes.NewP2pkhFeeSignatureFromKey(tx, acc.PrivKey)
+	if err = ensureTokenOwnership(acc, token, ownerPredicateInput); err != nil {
+		return nil, err
+	}
+	if token.GetLockStatus() != 0 {
+		return nil, errors.New("token is locked")
+	}
+	if err = ensureDataUpdateProof(token, tokenDataUpdatePredicateInput); err != nil {
+		return nil, err
+	}
+	roundNumber, err := w.GetRoundNumber(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign tx fee proof: %w", err)
+		return nil, err
 	}
+	timeout := w.txTimeout(roundNumber)
 
-	return w.submitTx(ctx, tx, accountNumber)
+	transferTx, err := w.prepareNFTTransferTx(acc, token, OwnerPredicateFromPubKey(receiverPubKey), fcrID, timeout, ownerPredicateInput, typeOwnerPredicateInputs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare transfer of token %s: %w", token.ID, err)
+	}
+	// the transfer increments the counter of the token
+	transferred := *token
+	transferred.Counter++
+	updateTx, err := w.prepareNFTUpdateTx(acc, &transferred, data, fcrID, timeout, tokenDataUpdatePredicateInput, tokenTypeDataUpdatePredicateInputs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare update of token %s: %w", token.ID, err)
+	}
+
+	batch := txsubmitter.NewBatch(w.tokensClient, w.log, w.batchOpts...)
+	for _, tx := range []*types.TransactionOrder{transferTx, updateTx} {
+		sub, err := txsubmitter.New(tx)
+		if err != nil {
+			return nil, err
+		}
+		batch.Add(sub)
+	}
+	err = w.sendTx(ctx, batch, w.confirmTx)
+	res := &SubmissionResult{Submissions: batch.Submissions(), AccountNumber: accountNumber, DryRun: w.dryRun, pdr: w.pdr}
+	for _, sub := range res.Submissions {
+		if sub.Confirmed() {
+			res.FeeSum += sub.Proof.TxRecord.ServerMetadata.ActualFee
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
-// SendFungibleByID sends fungible tokens by given unit ID, if amount matches, does the transfer, otherwise splits the token
+// SendFungibleByID sends fungible tokens by given unit ID, if amount matches, does the transfer, otherwise splits the token.
+// The decimal places of the token type are returned in the result for formatting the amount.
 func (w *Wallet) SendFungibleByID(ctx context.Context, accountNumber uint64, tokenID sdktypes.TokenID, targetAmount uint64, receiverPubKey []byte, typeOwnerPredicateInputs []*PredicateInput) (*SubmissionResult, error) {
+	unlock, err := w.lockAccount(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 	acc, err := w.getAccount(accountNumber)
 	if err != nil {
 		return nil, err
@@ -744,31 +1470,356 @@ func (w *Wallet) SendFungibleByID(ctx context.Context, accountNumber uint64, tok
 		return nil, err
 	}
 
-	sub, err := w.prepareSplitOrTransferTx(acc, targetAmount, token, fcrID, receiverPubKey, roundNumber+txTimeoutRoundCount, defaultProof(acc.AccountKey), typeOwnerPredicateInputs)
+	sub, err := w.prepareSplitOrTransferTx(acc, targetAmount, token, fcrID, receiverPubKey, w.txTimeout(roundNumber), defaultProof(acc.AccountKey), typeOwnerPredicateInputs)
 	if err != nil {
 		return nil, err
 	}
-	err = sub.ToBatch(w.tokensClient, w.log).SendTx(ctx, w.confirmTx)
-	return newSingleResult(sub, accountNumber), err
+	err = w.sendTx(ctx, sub.ToBatch(w.tokensClient, w.log, w.batchOpts...), w.confirmTx)
+	res := w.newSingleResult(sub, accountNumber)
+	res.DecimalPlaces = token.DecimalPlaces
+	return res, err
 }
 
+// BurnFungibleToken burns the fungible token, ie permanently removes its value from circulation. The
+// burn transaction has no target token, so the burned value can't be joined to another token. If
+// ownerPredicateInput is nil the account key is used to sign the transaction.
+func (w *Wallet) BurnFungibleToken(ctx context.Context, accountNumber uint64, tokenID sdktypes.TokenID, ownerPredicateInput *PredicateInput, typeOwnerPredicateInputs []*PredicateInput) (*SubmissionResult, error) {
+	unlock, err := w.lockAccount(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	acc, err := w.getAccount(accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	if ownerPredicateInput == nil {
+		ownerPredicateInput = defaultProof(acc.AccountKey)
+	}
+	fcrID, err := w.ensureFeeCredit(ctx, acc.AccountKey, 1)
+	if err != nil {
+		return nil, err
+	}
+	token, err := w.GetFungibleToken(ctx, tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token with id=%s: %w", tokenID, err)
+	}
+	if err = ensureTokenOwnership(acc, token, ownerPredicateInput); err != nil {
+		return nil, err
+	}
+	if token.LockStatus != 0 {
+		return nil, errors.New("token is locked")
+	}
+	roundNumber, err := w.GetRoundNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := token.Burn(nil, 0,
+		sdktypes.WithTimeout(w.txTimeout(roundNumber)),
+		sdktypes.WithFeeCreditRecordID(fcrID),
+		sdktypes.WithMaxFee(w.maxFee),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare burn tx: %w", err)
+	}
+	sigBytes, err := tx.AuthProofSigBytes()
+	if err != nil {
+		return nil, err
+	}
+	typeOwnerProofs, err := newProofs(sigBytes, typeOwnerPredicateInputs)
+	if err != nil {
+		return nil, err
+	}
+	ownerProof, err := ownerPredicateInput.Proof(sigBytes)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.SetAuthProof(tokens.BurnFungibleTokenAuthProof{
+		OwnerProof:           ownerProof,
+		TokenTypeOwnerProofs: typeOwnerProofs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set auth proof: %w", err)
+	}
+	tx.FeeProof, err = sdktypes.NewP2pkhFeeSignatureFromKey(tx, acc.PrivKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign tx fee proof: %w", err)
+	}
+	return w.submitTx(ctx, tx, accountNumber)
+}
+
+// SendFungibleFromTokens sends targetAmount of fungible tokens using only the given tokens as inputs.
+// The tokens are spent in the given order and the last token needed to cover the amount is split
+// if necessary, tokens not needed to cover the amount are left untouched. All the tokens must be
+// unlocked tokens of the same type owned by the account. If ownerPredicateInput is nil the account
+// key is used to sign the transactions.
+func (w *Wallet) SendFungibleFromTokens(ctx context.Context, accountNumber uint64, tokenIDs []sdktypes.TokenID, targetAmount uint64, receiverPubKey []byte, ownerPredicateInput *PredicateInput, typeOwnerPredicateInputs []*PredicateInput) (*SubmissionResult, error) {
+	unlock, err := w.lockAccount(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	if targetAmount == 0 {
+		return nil, fmt.Errorf("invalid amount: 0")
+	}
+	if len(tokenIDs) == 0 {
+		return nil, errors.New("no input tokens")
+	}
+	acc, err := w.getAccount(accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	if ownerPredicateInput == nil {
+		ownerPredicateInput = defaultProof(acc.AccountKey)
+	}
+	tokenz := make([]*sdktypes.FungibleToken, len(tokenIDs))
+	seen := make(map[string]struct{}, len(tokenIDs))
+	var totalBalance, txCount uint64
+	for i, tokenID := range tokenIDs {
+		if _, ok := seen[string(tokenID)]; ok {
+			return nil, fmt.Errorf("duplicate input token %s", tokenID)
+		}
+		seen[string(tokenID)] = struct{}{}
+
+		token, err := w.GetFungibleToken(ctx, tokenID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get token %s: %w", tokenID, err)
+		}
+		if err = ensureTokenOwnership(acc, token, ownerPredicateInput); err != nil {
+			return nil, err
+		}
+		if token.LockStatus != 0 {
+			return nil, fmt.Errorf("token %s is locked", token.ID)
+		}
+		if i > 0 && !tokenz[0].TypeID.Eq(token.TypeID) {
+			return nil, fmt.Errorf("token %s is of type %s, expected type %s", token.ID, token.TypeID, tokenz[0].TypeID)
+		}
+		tokenz[i] = token
+		if totalBalance < targetAmount {
+			// the token is needed to cover the amount
+			txCount++
+		}
+		var overflow bool
+		totalBalance, overflow, _ = util.AddUint64(totalBalance, token.Amount)
+		if overflow {
+			totalBalance = math.MaxUint64
+		}
+	}
+	if targetAmount > totalBalance {
+		return nil, fmt.Errorf("insufficient value of input tokens: got %v, need %v", totalBalance, targetAmount)
+	}
+	fcrID, err := w.ensureFeeCredit(ctx, acc.AccountKey, txCount)
+	if err != nil {
+		return nil, err
+	}
+	return w.doSendMultiple(ctx, targetAmount, tokenz, acc, fcrID, receiverPubKey, nil, ownerPredicateInput, typeOwnerPredicateInputs)
+}
+
+// SendFungibleMulti sends fungible tokens of the given type to multiple receivers. The unlocked tokens
+// of the account are used greedily, largest first: a token is split for as many targets as its value
+// covers and the remainder is transferred to the next target. Nothing is sent if the balance does not
+// cover the sum of the target amounts.
+func (w *Wallet) SendFungibleMulti(ctx context.Context, accountNumber uint64, typeId sdktypes.TokenTypeID, targets []TransferTarget, ownerPredicateInput *PredicateInput, typeOwnerPredicateInputs []*PredicateInput) (*SubmissionResult, error) {
+	unlock, err := w.lockAccount(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	if len(targets) == 0 {
+		return nil, errors.New("no transfer targets")
+	}
+	if accountNumber < 1 {
+		return nil, fmt.Errorf("invalid account number: %d", accountNumber)
+	}
+	var targetAmount uint64
+	for i, target := range targets {
+		if target.Amount == 0 {
+			return nil, fmt.Errorf("invalid amount of target %d: 0", i)
+		}
+		var overflow bool
+		if targetAmount, overflow, _ = util.AddUint64(targetAmount, target.Amount); overflow {
+			return nil, errors.New("invalid amount: sum of target amounts overflows")
+		}
+	}
+	acc, err := w.getAccount(accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	tokenz, err := w.ListFungibleTokens(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	var matchingTokens []sdktypes.FungibleToken
+	var totalBalance uint64
+	for _, token := range tokenz {
+		if !typeId.Eq(token.TypeID) || token.LockStatus != 0 {
+			continue
+		}
+		matchingTokens = append(matchingTokens, *token)
+		var overflow bool
+		totalBalance, overflow, _ = util.AddUint64(totalBalance, token.Amount)
+		if overflow {
+			totalBalance = math.MaxUint64
+		}
+	}
+	if targetAmount > totalBalance {
+		return nil, fmt.Errorf("insufficient tokens of type %s: got %v, need %v", typeId, totalBalance, targetAmount)
+	}
+	sort.Slice(matchingTokens, func(i, j int) bool {
+		return matchingTokens[i].Amount > matchingTokens[j].Amount
+	})
+
+	// plan the transactions first so that the fee credit is verified for all of them, a split
+	// leaves the remaining value in the token and increments its counter so the same token
+	// can be split again by the next transaction
+	type plannedTx struct {
+		token    sdktypes.FungibleToken
+		amount   uint64
+		receiver []byte
+	}
+	var plan []plannedTx
+	next := 0
+	for _, target := range targets {
+		for remaining := target.Amount; remaining > 0; {
+			token := &matchingTokens[next]
+			if token.Amount > remaining {
+				plan = append(plan, plannedTx{token: *token, amount: remaining, receiver: target.PubKey})
+				token.Amount -= remaining
+				token.Counter++
+				remaining = 0
+			} else {
+				plan = append(plan, plannedTx{token: *token, amount: token.Amount, receiver: target.PubKey})
+				remaining -= token.Amount
+				next++
+			}
+		}
+	}
+
+	fcrID, err := w.ensureFeeCredit(ctx, acc.AccountKey, uint64(len(plan)))
+	if err != nil {
+		return nil, err
+	}
+	roundNumber, err := w.GetRoundNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	batch := txsubmitter.NewBatch(w.tokensClient, w.log, w.batchOpts...)
+	for _, p := range plan {
+		sub, err := w.prepareSplitOrTransferTx(acc, p.amount, &p.token, fcrID, p.receiver, w.txTimeout(roundNumber), ownerPredicateInput, typeOwnerPredicateInputs)
+		if err != nil {
+			return nil, err
+		}
+		batch.Add(sub)
+	}
+	err = w.sendBatch(ctx, OperationSend, accountNumber, batch, w.confirmTx)
+	res := &SubmissionResult{Submissions: batch.Submissions(), AccountNumber: accountNumber, DryRun: w.dryRun, pdr: w.pdr}
+	for _, sub := range res.Submissions {
+		if sub.Confirmed() {
+			res.FeeSum += sub.Proof.TxRecord.ServerMetadata.ActualFee
+		}
+	}
+	return res, err
+}
+
+// GetRoundNumber returns the current round number of the tokens partition. When the cache is
+// enabled with WithRoundNumberCache the round number fetched within the TTL is returned.
 func (w *Wallet) GetRoundNumber(ctx context.Context) (uint64, error) {
+	if roundNumber, ok := w.roundCache.get(""); ok {
+		return roundNumber, nil
+	}
 	roundInfo, err := w.tokensClient.GetRoundInfo(ctx)
 	if err != nil {
 		return 0, err
 	}
+	w.roundCache.set("", roundInfo.RoundNumber)
 	return roundInfo.RoundNumber, nil
 }
 
-// GetFeeCredit returns fee credit record for the given account,
-// can return nil if fee credit record has not been created yet.
-// Deprecated: faucet still uses, will be removed
-func (w *Wallet) GetFeeCredit(ctx context.Context, cmd fees.GetFeeCreditCmd) (*sdktypes.FeeCreditRecord, error) {
-	ac, err := w.am.GetAccountKey(cmd.AccountIndex)
+// GetTokenHistory returns the proofs of the transactions of the token executed since the given round,
+// in chronological order. The blocks are scanned up to the latest round of the partition, so the scan
+// should be bounded with a recent since round when the token is known to be created recently.
+func (w *Wallet) GetTokenHistory(ctx context.Context, tokenID sdktypes.TokenID, since uint64) ([]*types.TxRecordProof, error) {
+	return w.scanHistory(ctx, since, func(tx *types.TransactionOrder) bool {
+		return tokenID.Eq(tx.GetUnitID())
+	})
+}
+
+// GetAccountHistory returns the proofs of the transactions executed since the given round of the
+// tokens currently owned by the account and of the token types created by the account, in
+// chronological order. Like GetTokenHistory the blocks are scanned up to the latest round.
+func (w *Wallet) GetAccountHistory(ctx context.Context, accountNumber uint64, since uint64) ([]*types.TxRecordProof, error) {
+	key, err := w.getAccount(accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	unitIDs, err := w.tokensClient.GetUnitsByOwnerID(ctx, key.PubKeyHash.Sha256)
+	if err != nil {
+		return nil, fmt.Errorf("fetching units of account #%d: %w", accountNumber, err)
+	}
+	units := make(map[string]struct{})
+	for _, unitID := range unitIDs {
+		if unitID.TypeMustBe(tokens.FungibleTokenUnitType, w.pdr) == nil || unitID.TypeMustBe(tokens.NonFungibleTokenUnitType, w.pdr) == nil {
+			units[string(unitID)] = struct{}{}
+		}
+	}
+	ftTypes, err := w.tokensClient.GetFungibleTokenTypes(ctx, key.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("fetching fungible token types of account #%d: %w", accountNumber, err)
+	}
+	for _, tt := range ftTypes {
+		units[string(tt.ID)] = struct{}{}
+	}
+	nftTypes, err := w.tokensClient.GetNonFungibleTokenTypes(ctx, key.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("fetching non-fungible token types of account #%d: %w", accountNumber, err)
+	}
+	for _, tt := range nftTypes {
+		units[string(tt.ID)] = struct{}{}
+	}
+	if len(units) == 0 {
+		return nil, nil
+	}
+	return w.scanHistory(ctx, since, func(tx *types.TransactionOrder) bool {
+		_, ok := units[string(tx.GetUnitID())]
+		return ok
+	})
+}
+
+// scanHistory returns the proofs of the transactions accepted by the filter in the blocks from the
+// since round up to the latest round, scanned historyScanRange rounds at a time.
+func (w *Wallet) scanHistory(ctx context.Context, since uint64, filter func(tx *types.TransactionOrder) bool) ([]*types.TxRecordProof, error) {
+	roundNumber, err := w.GetRoundNumber(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return w.tokensClient.GetFeeCreditRecordByOwnerID(ctx, ac.PubKeyHash.Sha256)
+	var proofs []*types.TxRecordProof
+	for fromRound := since; fromRound <= roundNumber; fromRound += historyScanRange {
+		toRound := min(fromRound+historyScanRange-1, roundNumber)
+		res, err := w.tokensClient.ScanBlocks(ctx, fromRound, toRound, filter)
+		if err != nil {
+			return nil, fmt.Errorf("scanning blocks %d-%d: %w", fromRound, toRound, err)
+		}
+		proofs = append(proofs, res...)
+	}
+	return proofs, nil
+}
+
+// GetFeeCreditRecord returns the fee credit record of the given account, including its counter and
+// balance, can return nil if fee credit record has not been created yet.
+func (w *Wallet) GetFeeCreditRecord(ctx context.Context, accountNumber uint64) (*sdktypes.FeeCreditRecord, error) {
+	acc, err := w.getAccount(accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	return w.tokensClient.GetFeeCreditRecordByOwnerID(ctx, acc.PubKeyHash.Sha256)
+}
+
+// GetFeeCredit returns fee credit record for the given account,
+// can return nil if fee credit record has not been created yet.
+// Deprecated: faucet still uses, will be removed, use GetFeeCreditRecord instead
+func (w *Wallet) GetFeeCredit(ctx context.Context, cmd fees.GetFeeCreditCmd) (*sdktypes.FeeCreditRecord, error) {
+	return w.GetFeeCreditRecord(ctx, cmd.AccountIndex+1)
 }
 
 func (w *Wallet) AddFeeCredit(ctx context.Context, cmd fees.AddFeeCmd) (*fees.AddFeeCmdResponse, error) {
@@ -779,7 +1830,40 @@ func (w *Wallet) ReclaimFeeCredit(ctx context.Context, cmd fees.ReclaimFeeCmd) (
 	return w.feeManager.ReclaimFeeCredit(ctx, cmd)
 }
 
-func (w *Wallet) ensureFeeCredit(ctx context.Context, accountKey *account.AccountKey, txCount int) ([]byte, error) {
+// EstimateProvisioning returns the money needed to add fee credit for the planned number of token
+// transactions, see fees.FeeManager.EstimateProvisioning.
+func (w *Wallet) EstimateProvisioning(cmd fees.ProvisioningCmd) (*fees.ProvisioningCost, error) {
+	return w.feeManager.EstimateProvisioning(cmd)
+}
+
+// AbortAllPending aborts the pending fee credit processes of the given account, releasing the locks
+// the processes have set. Processes that can't be aborted without losing funds are left untouched
+// and reported in the returned error, see fees.FeeManager.AbortPending.
+func (w *Wallet) AbortAllPending(ctx context.Context, accountNumber uint64) error {
+	if accountNumber < 1 {
+		return fmt.Errorf("invalid account number: %d", accountNumber)
+	}
+	return w.feeManager.AbortPending(ctx, accountNumber-1)
+}
+
+// EnsureFeeCreditForTxCount verifies that the fee credit balance of the account covers the max fee of
+// txCount transactions, allowing a multi-step workflow to fail before any transactions are sent.
+func (w *Wallet) EnsureFeeCreditForTxCount(ctx context.Context, accountNumber, txCount uint64) error {
+	if accountNumber < 1 {
+		return fmt.Errorf("invalid account number: %d", accountNumber)
+	}
+	if txCount == 0 {
+		return errors.New("invalid transaction count: 0")
+	}
+	acc, err := w.getAccount(accountNumber)
+	if err != nil {
+		return err
+	}
+	_, err = w.ensureFeeCredit(ctx, acc.AccountKey, txCount)
+	return err
+}
+
+func (w *Wallet) ensureFeeCredit(ctx context.Context, accountKey *account.AccountKey, txCount uint64) ([]byte, error) {
 	fcr, err := w.tokensClient.GetFeeCreditRecordByOwnerID(ctx, accountKey.PubKeyHash.Sha256)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch fee credit record: %w", err)
@@ -787,14 +1871,39 @@ func (w *Wallet) ensureFeeCredit(ctx context.Context, accountKey *account.Accoun
 	if fcr == nil {
 		return nil, ErrNoFeeCredit
 	}
-	maxFee := uint64(txCount) * w.maxFee
+	hi, maxFee := bits.Mul64(txCount, w.maxFee)
+	if hi != 0 {
+		return nil, fmt.Errorf("%w: max fee of %d transactions overflows", ErrInsufficientFeeCredit, txCount)
+	}
 	if fcr.Balance < maxFee {
-		return nil, ErrInsufficientFeeCredit
+		return nil, fmt.Errorf("%w: balance %d, required %d, shortfall %d", ErrInsufficientFeeCredit, fcr.Balance, maxFee, maxFee-fcr.Balance)
 	}
 	return fcr.ID, nil
 }
 
 func (w *Wallet) LockToken(ctx context.Context, accountNumber uint64, tokenID types.UnitID, ownerPredicateInput *PredicateInput) (*SubmissionResult, error) {
+	return w.lockToken(ctx, accountNumber, tokenID, nil, ownerPredicateInput)
+}
+
+/*
+LockTokenWithPredicate locks the token with a state-locked lock transaction, ie the lock is only
+executed when the execution predicate of the state lock is satisfied (or discarded when the
+rollback predicate is satisfied). Use wallet.NewP2PKHStateLock for the common case of a lock
+which is released by the owner of a key.
+*/
+func (w *Wallet) LockTokenWithPredicate(ctx context.Context, accountNumber uint64, tokenID types.UnitID, stateLock *types.StateLock, ownerPredicateInput *PredicateInput) (*SubmissionResult, error) {
+	if stateLock == nil || len(stateLock.ExecutionPredicate) == 0 {
+		return nil, errors.New("state lock execution predicate must not be empty")
+	}
+	return w.lockToken(ctx, accountNumber, tokenID, stateLock, ownerPredicateInput)
+}
+
+func (w *Wallet) lockToken(ctx context.Context, accountNumber uint64, tokenID types.UnitID, stateLock *types.StateLock, ownerPredicateInput *PredicateInput) (*SubmissionResult, error) {
+	unlock, err := w.lockAccount(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 	key, err := w.getAccount(accountNumber)
 	if err != nil {
 		return nil, err
@@ -808,19 +1917,7 @@ func (w *Wallet) LockToken(ctx context.Context, accountNumber uint64, tokenID ty
 		return nil, err
 	}
 
-	tid, err := w.pdr.ExtractUnitType(tokenID)
-	if err != nil {
-		return nil, fmt.Errorf("extracting token type: %w", err)
-	}
-	var token Token
-	switch tid {
-	case tokens.FungibleTokenUnitType:
-		token, err = w.GetFungibleToken(ctx, tokenID)
-	case tokens.NonFungibleTokenUnitType:
-		token, err = w.GetNonFungibleToken(ctx, tokenID)
-	default:
-		return nil, errors.New("invalid token ID")
-	}
+	token, err := w.getToken(ctx, tokenID)
 	if err != nil {
 		return nil, err
 	}
@@ -837,9 +1934,10 @@ func (w *Wallet) LockToken(ctx context.Context, accountNumber uint64, tokenID ty
 	}
 
 	tx, err := token.Lock(wallet.LockReasonManual,
-		sdktypes.WithTimeout(roundNumber+txTimeoutRoundCount),
+		sdktypes.WithTimeout(w.txTimeout(roundNumber)),
 		sdktypes.WithFeeCreditRecordID(fcrID),
 		sdktypes.WithMaxFee(w.maxFee),
+		sdktypes.WithStateLock(stateLock),
 	)
 	if err != nil {
 		return nil, err
@@ -868,6 +1966,11 @@ func (w *Wallet) LockToken(ctx context.Context, accountNumber uint64, tokenID ty
 }
 
 func (w *Wallet) UnlockToken(ctx context.Context, accountNumber uint64, tokenID sdktypes.TokenID, ownerPredicateInput *PredicateInput) (*SubmissionResult, error) {
+	unlock, err := w.lockAccount(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 	key, err := w.getAccount(accountNumber)
 	if err != nil {
 		return nil, err
@@ -881,19 +1984,7 @@ func (w *Wallet) UnlockToken(ctx context.Context, accountNumber uint64, tokenID
 		return nil, err
 	}
 
-	tid, err := w.pdr.ExtractUnitType(tokenID)
-	if err != nil {
-		return nil, fmt.Errorf("extracting token type: %w", err)
-	}
-	var token Token
-	switch tid {
-	case tokens.FungibleTokenUnitType:
-		token, err = w.GetFungibleToken(ctx, tokenID)
-	case tokens.NonFungibleTokenUnitType:
-		token, err = w.GetNonFungibleToken(ctx, tokenID)
-	default:
-		return nil, errors.New("invalid token ID")
-	}
+	token, err := w.getToken(ctx, tokenID)
 	if err != nil {
 		return nil, err
 	}
@@ -904,13 +1995,202 @@ func (w *Wallet) UnlockToken(ctx context.Context, accountNumber uint64, tokenID
 	if token.GetLockStatus() == 0 {
 		return nil, errors.New("token is already unlocked")
 	}
+	if err = verifyUnlockPredicate(token, ownerPredicateInput); err != nil {
+		return nil, err
+	}
+	roundNumber, err := w.GetRoundNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := w.newUnlockTx(acc, token, fcrID, w.txTimeout(roundNumber), ownerPredicateInput)
+	if err != nil {
+		return nil, err
+	}
+	return w.submitTx(ctx, tx, accountNumber)
+}
+
+/*
+UnlockTokens unlocks the tokens of the account in a single batch. Tokens which are already
+unlocked are skipped with a warning, all the other tokens must be owned by the account.
+*/
+func (w *Wallet) UnlockTokens(ctx context.Context, accountNumber uint64, tokenIDs []types.UnitID, ownerPredicateInput *PredicateInput) (*SubmissionResult, error) {
+	unlock, err := w.lockAccount(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	if len(tokenIDs) == 0 {
+		return nil, errors.New("no tokens to unlock")
+	}
+	acc, err := w.getAccount(accountNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	var lockedTokens []Token
+	for _, tokenID := range tokenIDs {
+		token, err := w.getToken(ctx, tokenID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch token %s: %w", tokenID, err)
+		}
+		if err = ensureTokenOwnership(acc, token, ownerPredicateInput); err != nil {
+			return nil, fmt.Errorf("token %s: %w", tokenID, err)
+		}
+		if token.GetLockStatus() == 0 {
+			w.log.WarnContext(ctx, fmt.Sprintf("token %s is already unlocked, skipping", tokenID))
+			continue
+		}
+		if err = verifyUnlockPredicate(token, ownerPredicateInput); err != nil {
+			return nil, fmt.Errorf("token %s: %w", tokenID, err)
+		}
+		lockedTokens = append(lockedTokens, token)
+	}
+	if len(lockedTokens) == 0 {
+		return nil, errors.New("all the tokens are already unlocked")
+	}
+
+	fcrID, err := w.ensureFeeCredit(ctx, acc.AccountKey, uint64(len(lockedTokens)))
+	if err != nil {
+		return nil, err
+	}
+	roundNumber, err := w.GetRoundNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	timeout := w.txTimeout(roundNumber)
+
+	batch := txsubmitter.NewBatch(w.tokensClient, w.log, w.batchOpts...)
+	for _, token := range lockedTokens {
+		tx, err := w.newUnlockTx(acc, token, fcrID, timeout, ownerPredicateInput)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create unlock transaction of token %s: %w", token.GetID(), err)
+		}
+		sub, err := txsubmitter.New(tx)
+		if err != nil {
+			return nil, err
+		}
+		batch.Add(sub)
+	}
+	err = w.sendTx(ctx, batch, w.confirmTx)
+	res := &SubmissionResult{Submissions: batch.Submissions(), AccountNumber: accountNumber, DryRun: w.dryRun, pdr: w.pdr}
+	for _, sub := range res.Submissions {
+		if sub.Confirmed() {
+			res.FeeSum += sub.Proof.TxRecord.ServerMetadata.ActualFee
+		}
+	}
+	return res, err
+}
+
+// ListLockedTokens returns all locked fungible and non-fungible tokens of the given account.
+func (w *Wallet) ListLockedTokens(ctx context.Context, accountNumber uint64) ([]Token, error) {
+	tokenz, err := w.listTokens(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	var lockedTokens []Token
+	for _, token := range tokenz {
+		if token.GetLockStatus() != 0 {
+			lockedTokens = append(lockedTokens, token)
+		}
+	}
+	return lockedTokens, nil
+}
+
+// listTokens returns the fungible tokens followed by the non-fungible tokens of the given account.
+func (w *Wallet) listTokens(ctx context.Context, accountNumber uint64) ([]Token, error) {
+	fts, err := w.ListFungibleTokens(ctx, accountNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fungible tokens: %w", err)
+	}
+	nfts, err := w.ListNonFungibleTokens(ctx, accountNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list non-fungible tokens: %w", err)
+	}
+	tokenz := make([]Token, 0, len(fts)+len(nfts))
+	for _, ft := range fts {
+		tokenz = append(tokenz, ft)
+	}
+	for _, nft := range nfts {
+		tokenz = append(tokenz, nft)
+	}
+	return tokenz, nil
+}
+
+// UnlockAllTokens unlocks all locked tokens of the given account. The unlock transactions are submitted
+// in a single batch, one result is returned per unlock transaction. Tokens which the account can't unlock
+// with the given owner predicate input are skipped and reported in the returned error.
+func (w *Wallet) UnlockAllTokens(ctx context.Context, accountNumber uint64, ownerPredicateInput *PredicateInput) ([]*SubmissionResult, error) {
+	unlock, err := w.lockAccount(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	acc, err := w.getAccount(accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	lockedTokens, err := w.ListLockedTokens(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	var unlockable []Token
+	var skipErrs []error
+	for _, token := range lockedTokens {
+		if err := ensureTokenOwnership(acc, token, ownerPredicateInput); err != nil {
+			skipErrs = append(skipErrs, err)
+			continue
+		}
+		if err := verifyUnlockPredicate(token, ownerPredicateInput); err != nil {
+			skipErrs = append(skipErrs, err)
+			continue
+		}
+		unlockable = append(unlockable, token)
+	}
+	var skipErr error
+	if len(skipErrs) > 0 {
+		skipErr = fmt.Errorf("%d token(s) could not be unlocked: %w", len(skipErrs), errors.Join(skipErrs...))
+	}
+	if len(unlockable) == 0 {
+		return nil, skipErr
+	}
+
+	fcrID, err := w.ensureFeeCredit(ctx, acc.AccountKey, uint64(len(unlockable)))
+	if err != nil {
+		return nil, err
+	}
 	roundNumber, err := w.GetRoundNumber(ctx)
 	if err != nil {
 		return nil, err
 	}
+	batch := txsubmitter.NewBatch(w.tokensClient, w.log, w.batchOpts...)
+	for _, token := range unlockable {
+		tx, err := w.newUnlockTx(acc, token, fcrID, w.txTimeout(roundNumber), ownerPredicateInput)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create unlock transaction for token '%s': %w", token.GetID(), err)
+		}
+		sub, err := txsubmitter.New(tx)
+		if err != nil {
+			return nil, err
+		}
+		batch.Add(sub)
+	}
+	err = w.sendTx(ctx, batch, w.confirmTx)
+
+	results := make([]*SubmissionResult, 0, len(batch.Submissions()))
+	for _, sub := range batch.Submissions() {
+		results = append(results, w.newSingleResult(sub, accountNumber))
+	}
+	if err != nil {
+		return results, err
+	}
+	return results, skipErr
+}
 
+func (w *Wallet) newUnlockTx(acc *accountKey, token Token, fcrID []byte, timeout uint64, ownerPredicateInput *PredicateInput) (*types.TransactionOrder, error) {
 	tx, err := token.Unlock(
-		sdktypes.WithTimeout(roundNumber+txTimeoutRoundCount),
+		sdktypes.WithTimeout(timeout),
 		sdktypes.WithFeeCreditRecordID(fcrID),
 		sdktypes.WithMaxFee(w.maxFee),
 	)
@@ -936,8 +2216,7 @@ func (w *Wallet) UnlockToken(ctx context.Context, accountNumber uint64, tokenID
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign tx fee proof: %w", err)
 	}
-
-	return w.submitTx(ctx, tx, accountNumber)
+	return tx, nil
 }
 
 func (w *Wallet) submitTx(ctx context.Context, tx *types.TransactionOrder, accountNumber uint64) (*SubmissionResult, error) {
@@ -945,28 +2224,175 @@ func (w *Wallet) submitTx(ctx context.Context, tx *types.TransactionOrder, accou
 	if err != nil {
 		return nil, err
 	}
-	if err := sub.ToBatch(w.tokensClient, w.log).SendTx(ctx, w.confirmTx); err != nil {
+	if err := w.sendTx(ctx, sub.ToBatch(w.tokensClient, w.log, w.batchOpts...), w.confirmTx); err != nil {
 		return nil, err
 	}
-	return newSingleResult(sub, accountNumber), nil
+	return w.newSingleResult(sub, accountNumber), nil
+}
+
+// sendTx sends the transactions of the batch, in dry run mode nothing is sent. The actual fees of
+// the confirmed transactions are recorded for CheckMaxFee.
+func (w *Wallet) sendTx(ctx context.Context, batch *txsubmitter.TxSubmissionBatch, confirmTx bool) error {
+	if w.dryRun {
+		return nil
+	}
+	if !confirmTx || w.confirmTimeout == 0 {
+		err := batch.SendTx(ctx, confirmTx)
+		w.observeFees(batch.Submissions())
+		return err
+	}
+	confirmCtx, cancel := context.WithTimeout(ctx, w.confirmTimeout)
+	defer cancel()
+	err := batch.SendTx(confirmCtx, confirmTx)
+	w.observeFees(batch.Submissions())
+	if err != nil && ctx.Err() == nil && errors.Is(confirmCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w after %s: %w", ErrConfirmTimeout, w.confirmTimeout, err)
+	}
+	return err
+}
+
+func (w *Wallet) observeFees(subs []*txsubmitter.TxSubmission) {
+	w.feeMu.Lock()
+	defer w.feeMu.Unlock()
+	for _, sub := range subs {
+		if sub.Confirmed() {
+			w.observedMaxFee = max(w.observedMaxFee, sub.Proof.ActualFee())
+		}
+	}
+}
+
+// CheckMaxFee reports whether the configured max fee is sufficient for the current network conditions.
+// The actual fee of a transaction can not exceed its max fee, so when a transaction confirmed by the
+// wallet has used up the whole max fee the following transactions are likely to fail. Returns the
+// highest actual fee observed, ok is true when no transactions have been confirmed yet.
+func (w *Wallet) CheckMaxFee() (ok bool, observedMax uint64) {
+	w.feeMu.Lock()
+	defer w.feeMu.Unlock()
+	return w.observedMaxFee == 0 || w.observedMaxFee < w.maxFee, w.observedMaxFee
 }
 
 func ensureTokenOwnership(acc *accountKey, token Token, ownerProof *PredicateInput) error {
 	if bytes.Equal(token.GetOwnerPredicate(), templates.NewP2pkh256BytesFromKey(acc.PubKey)) {
 		return nil
 	}
-	predicate, err := extractPredicate(token.GetOwnerPredicate())
+	kind, err := OwnershipKind(token)
 	if err != nil {
 		return err
 	}
-	isP2pkhPredicate := templates.VerifyP2pkhPredicate(predicate) == nil
-	if !isP2pkhPredicate && ownerProof != nil && ownerProof.AccountKey == nil && ownerProof.Argument != nil {
+	hasCustomProof := ownerProof != nil && ownerProof.AccountKey == nil && ownerProof.Argument != nil
+	if kind != OwnershipP2PKH && hasCustomProof {
 		// this must be a "custom predicate" with provided owner proof
 		return nil
 	}
+	if kind == OwnershipCustom {
+		return fmt.Errorf("token '%s' has a custom owner predicate: %w", token.GetID(), ErrOwnerProofRequired)
+	}
 	return fmt.Errorf("token '%s' does not belong to account #%d", token.GetID(), acc.AccountNumber())
 }
 
+// ensureDataUpdateProof checks that the data-update predicate of the token can be satisfied by the
+// input. A custom (non-template) data-update predicate requires the argument to be provided by the
+// caller, the argument is used verbatim as the proof.
+func ensureDataUpdateProof(token *sdktypes.NonFungibleToken, input *PredicateInput) error {
+	if len(token.DataUpdatePredicate) == 0 {
+		return nil
+	}
+	kind, err := predicateKind(token.DataUpdatePredicate)
+	if err != nil {
+		return fmt.Errorf("decoding data-update predicate of token '%s': %w", token.ID, err)
+	}
+	hasCustomProof := input != nil && input.AccountKey == nil && input.Argument != nil
+	if kind == OwnershipCustom && !hasCustomProof {
+		return fmt.Errorf("token '%s' has a custom data-update predicate: %w", token.ID, ErrDataUpdateProofRequired)
+	}
+	return nil
+}
+
+// verifyUnlockPredicate verifies that the owner predicate input is able to satisfy the predicate
+// the token must be unlocked with, so that a fee isn't spent on an unlock transaction that is
+// going to fail. Only predicates whose inputs can be verified locally (P2PKH and templates) are
+// checked, for custom predicates the input is assumed to be correct.
+func verifyUnlockPredicate(token Token, ownerPredicateInput *PredicateInput) error {
+	unlockPredicate := token.GetOwnerPredicate()
+	if bytes.Equal(unlockPredicate, templates.AlwaysFalseBytes()) {
+		return fmt.Errorf("token '%s' can not be unlocked: unlock predicate is always false", token.GetID())
+	}
+	pubKeyHash, err := templates.ExtractPubKeyHashFromP2pkhPredicate(unlockPredicate)
+	if err != nil {
+		// not a P2PKH predicate
+		return nil
+	}
+	if ownerPredicateInput == nil || ownerPredicateInput.AccountKey == nil {
+		return fmt.Errorf("token '%s' is locked with a P2PKH predicate, unlock requires a signature of the owner key", token.GetID())
+	}
+	if !bytes.Equal(ownerPredicateInput.AccountKey.PubKeyHash.Sha256, pubKeyHash) {
+		return fmt.Errorf("token '%s' unlock predicate can not be satisfied by the given key: key hash 0x%X does not match 0x%X",
+			token.GetID(), ownerPredicateInput.AccountKey.PubKeyHash.Sha256, pubKeyHash)
+	}
+	return nil
+}
+
+// VerifyMintPredicateInput fetches the token type and verifies that the mint predicate input is able
+// to satisfy the token minting predicate of the type, so that a fee isn't spent on a mint transaction
+// that is going to fail. Only template predicates are checked, for custom predicates the input is
+// assumed to be correct. The returned error describes the minting clause that can't be satisfied.
+func (w *Wallet) VerifyMintPredicateInput(ctx context.Context, typeID sdktypes.TokenTypeID, mintPredicateInput *PredicateInput) error {
+	mintPredicate, err := w.getTokenMintingPredicate(ctx, typeID)
+	if err != nil {
+		return err
+	}
+	return verifyMintPredicate(typeID, mintPredicate, mintPredicateInput)
+}
+
+func (w *Wallet) getTokenMintingPredicate(ctx context.Context, typeID sdktypes.TokenTypeID) (sdktypes.Predicate, error) {
+	unitType, err := w.pdr.ExtractUnitType(typeID)
+	if err != nil {
+		return nil, fmt.Errorf("extracting unit type: %w", err)
+	}
+	switch unitType {
+	case tokens.FungibleTokenTypeUnitType:
+		tt, err := w.GetFungibleTokenType(ctx, typeID)
+		if err != nil {
+			return nil, fmt.Errorf("fetching fungible token type: %w", err)
+		}
+		if tt == nil {
+			return nil, fmt.Errorf("%w: %s", errTokenTypeNotFound, typeID)
+		}
+		return tt.TokenMintingPredicate, nil
+	case tokens.NonFungibleTokenTypeUnitType:
+		tt, err := w.GetNonFungibleTokenType(ctx, typeID)
+		if err != nil {
+			return nil, fmt.Errorf("fetching non-fungible token type: %w", err)
+		}
+		if tt == nil {
+			return nil, fmt.Errorf("%w: %s", errTokenTypeNotFound, typeID)
+		}
+		return tt.TokenMintingPredicate, nil
+	default:
+		return nil, fmt.Errorf("invalid token type ID: unit type %d", unitType)
+	}
+}
+
+func verifyMintPredicate(typeID sdktypes.TokenTypeID, mintPredicate []byte, mintPredicateInput *PredicateInput) error {
+	if bytes.Equal(mintPredicate, templates.AlwaysFalseBytes()) {
+		return fmt.Errorf("%w: token type '%s' minting clause is %s", ErrMintNotSatisfied, typeID, DescribePredicate(mintPredicate))
+	}
+	pubKeyHash, err := templates.ExtractPubKeyHashFromP2pkhPredicate(mintPredicate)
+	if err != nil {
+		// not a P2PKH predicate
+		return nil
+	}
+	if mintPredicateInput == nil || mintPredicateInput.AccountKey == nil {
+		return fmt.Errorf("%w: token type '%s' minting clause %s requires a signature of the key",
+			ErrMintNotSatisfied, typeID, DescribePredicate(mintPredicate))
+	}
+	if !bytes.Equal(mintPredicateInput.AccountKey.PubKeyHash.Sha256, pubKeyHash) {
+		return fmt.Errorf("%w: token type '%s' minting clause %s does not match the key hash 0x%X",
+			ErrMintNotSatisfied, typeID, DescribePredicate(mintPredicate), mintPredicateInput.AccountKey.PubKeyHash.Sha256)
+	}
+	return nil
+}
+
 func defaultProof(accountKey *account.AccountKey) *PredicateInput {
 	return &PredicateInput{AccountKey: accountKey}
 }
@@ -4,16 +4,21 @@ import (
 	"bytes"
 	"context"
 	"crypto"
+	"errors"
 	"fmt"
 	"log/slog"
 	"math"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/stretchr/testify/require"
 
 	"github.com/alphabill-org/alphabill-go-base/hash"
+	"github.com/alphabill-org/alphabill-go-base/predicates"
 	"github.com/alphabill-org/alphabill-go-base/predicates/templates"
+	"github.com/alphabill-org/alphabill-go-base/predicates/wasm"
 	tokenid "github.com/alphabill-org/alphabill-go-base/testutils/tokens"
 	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
 	"github.com/alphabill-org/alphabill-go-base/types"
@@ -24,6 +29,8 @@ import (
 	"github.com/alphabill-org/alphabill-wallet/internal/testutils/logger"
 	"github.com/alphabill-org/alphabill-wallet/wallet"
 	"github.com/alphabill-org/alphabill-wallet/wallet/account"
+	"github.com/alphabill-org/alphabill-wallet/wallet/fees"
+	"github.com/alphabill-org/alphabill-wallet/wallet/txsubmitter"
 )
 
 const (
@@ -41,7 +48,7 @@ func Test_GetRoundInfo_OK(t *testing.T) {
 			return &sdktypes.RoundInfo{RoundNumber: 42}, nil
 		},
 	}
-	w, err := New(rpcClient, nil, false, nil, 0, logger.New(t))
+	w, err := New(rpcClient, nil, false, nil, 10, logger.New(t))
 	require.NoError(t, err)
 
 	roundNumber, err := w.GetRoundNumber(context.Background())
@@ -49,6 +56,20 @@ func Test_GetRoundInfo_OK(t *testing.T) {
 	require.EqualValues(t, 42, roundNumber)
 }
 
+func TestNew_MaxFee(t *testing.T) {
+	pdr := tokenid.PDR()
+	rpcClient := &mockTokensPartitionClient{pdr: &pdr}
+
+	w, err := New(rpcClient, nil, false, nil, 0, logger.New(t))
+	require.ErrorIs(t, err, ErrInvalidMaxFee)
+	require.Nil(t, w)
+
+	// dry run only estimates the fees
+	w, err = New(rpcClient, nil, false, nil, 0, logger.New(t), WithDryRun())
+	require.NoError(t, err)
+	require.NotNil(t, w)
+}
+
 func TestGetToken_NotFound(t *testing.T) {
 	rpcClient := &mockTokensPartitionClient{
 		getFungibleToken: func(ctx context.Context, id sdktypes.TokenID) (*sdktypes.FungibleToken, error) {
@@ -109,6 +130,51 @@ func Test_ListTokenTypes(t *testing.T) {
 	require.ErrorContains(t, err, "account does not exist")
 }
 
+func Test_ListTokenTypes_MultipleAccounts(t *testing.T) {
+	am := initAccountManager(t)
+	for range 5 {
+		_, _, err := am.AddAccount()
+		require.NoError(t, err)
+	}
+	keys, err := am.GetPublicKeys()
+	require.NoError(t, err)
+	require.Len(t, keys, 6)
+
+	failingKey := keys[2]
+	rpcClient := &mockTokensPartitionClient{
+		getFungibleTokenTypes: func(ctx context.Context, pubKey sdktypes.PubKey) ([]*sdktypes.FungibleTokenType, error) {
+			// later accounts respond faster, the result must still be in the order of the accounts
+			for i, key := range keys {
+				if bytes.Equal(key, pubKey) {
+					time.Sleep(time.Duration(len(keys)-i) * time.Millisecond)
+					break
+				}
+			}
+			return []*sdktypes.FungibleTokenType{{ID: types.UnitID(pubKey)}}, nil
+		},
+		getNonFungibleTokenTypes: func(ctx context.Context, pubKey sdktypes.PubKey) ([]*sdktypes.NonFungibleTokenType, error) {
+			if bytes.Equal(pubKey, failingKey) {
+				return nil, errors.New("fetch failed")
+			}
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+	tw.am = am
+
+	fts, err := tw.ListFungibleTokenTypes(context.Background(), AllAccounts)
+	require.NoError(t, err)
+	require.Len(t, fts, len(keys))
+	for i, ft := range fts {
+		require.EqualValues(t, keys[i], ft.ID)
+	}
+
+	// failing fetch cancels the others and its error is returned
+	_, err = tw.ListNonFungibleTokenTypes(context.Background(), AllAccounts)
+	require.EqualError(t, err, "fetch failed")
+}
+
 func TestNewTypes(t *testing.T) {
 	t.Parallel()
 
@@ -128,7 +194,7 @@ func TestNewTypes(t *testing.T) {
 				}
 				return []*sdktypes.FungibleTokenType{tokenType}, nil
 			}
-			return nil, fmt.Errorf("not found")
+			return nil, fmt.Errorf("token type %s %w", id, sdktypes.ErrNotFound)
 		},
 		getNonFungibleTokenTypeHierarchy: func(ctx context.Context, id sdktypes.TokenTypeID) ([]*sdktypes.NonFungibleTokenType, error) {
 			tx, found := recTxs[string(id)]
@@ -141,7 +207,7 @@ func TestNewTypes(t *testing.T) {
 				}
 				return []*sdktypes.NonFungibleTokenType{tokenType}, nil
 			}
-			return nil, fmt.Errorf("not found")
+			return nil, fmt.Errorf("token type %s %w", id, sdktypes.ErrNotFound)
 		},
 		sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
 			recTxs[string(tx.GetUnitID())] = tx
@@ -185,6 +251,14 @@ func TestNewTypes(t *testing.T) {
 		require.Equal(t, tt1.DecimalPlaces, newFungibleTx.DecimalPlaces)
 		require.EqualValues(t, tx.Timeout(), 11)
 
+		// type ID already in use
+		_, err = tw.NewFungibleType(context.Background(), 1, tt1, nil)
+		require.ErrorIs(t, err, ErrTokenTypeExists)
+		delete(recTxs, string(typeID))
+		_, err = tw.NewFungibleType(context.Background(), 1, tt1, nil, WithSkipTypeIDCheck())
+		require.NoError(t, err)
+		require.Contains(t, recTxs, string(typeID))
+
 		// new subtype
 		tt2 := &sdktypes.FungibleTokenType{
 			Symbol:                   "AB",
@@ -251,6 +325,10 @@ func TestNewTypes(t *testing.T) {
 		require.Equal(t, tt.Icon.Data, newNFTTx.Icon.Data)
 		require.EqualValues(t, tx.Timeout(), 11)
 
+		// type ID already in use
+		_, err = tw.NewNonFungibleType(context.Background(), 1, tt, nil)
+		require.ErrorIs(t, err, ErrTokenTypeExists)
+
 		//check typeId length validation
 		tt.ID = []byte{2}
 		_, err = tw.NewNonFungibleType(context.Background(), 1, tt, nil)
@@ -289,6 +367,9 @@ func TestNewFungibleToken(t *testing.T) {
 			require.NoError(t, err)
 			return []types.UnitID{fcrID}, nil
 		},
+		getFungibleTokenTypeHierarchy: func(ctx context.Context, id sdktypes.TokenTypeID) ([]*sdktypes.FungibleTokenType, error) {
+			return []*sdktypes.FungibleTokenType{{ID: id, TokenMintingPredicate: sdktypes.Predicate(templates.AlwaysTrueBytes())}}, nil
+		},
 	}
 	tw := initTestWallet(t, rpcClient)
 	_, _, err := tw.am.AddAccount()
@@ -310,7 +391,7 @@ func TestNewFungibleToken(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			typeID := test.RandomBytes(33)
+			typeID := tokenid.NewFungibleTokenTypeID(t)
 			amount := uint64(100)
 			key, err := tw.am.GetAccountKey(tt.accountNumber - 1)
 			require.NoError(t, err)
@@ -331,6 +412,9 @@ func TestNewFungibleToken(t *testing.T) {
 			require.NoError(t, tx.GetUnitID().TypeMustBe(tokens.FungibleTokenUnitType, tw.pdr))
 			require.EqualValues(t, tx.GetUnitID(), result.GetUnit())
 			require.EqualValues(t, tx.GetUnitID(), ft.ID)
+			txHash, err := tx.Hash(crypto.SHA256)
+			require.NoError(t, err)
+			require.Equal(t, []string{fmt.Sprintf("0x%x", txHash)}, result.TxIDs())
 
 			require.NoError(t, tx.UnmarshalAttributes(attr))
 			require.Equal(t, ft.TypeID, attr.TypeID)
@@ -403,6 +487,7 @@ func TestSendFungible(t *testing.T) {
 		name               string
 		tokenTypeID        sdktypes.TokenTypeID
 		targetAmount       uint64
+		opts               []SendFungibleOption
 		expectedErrorMsg   string
 		verifyTransactions func(t *testing.T)
 	}{
@@ -505,7 +590,21 @@ func TestSendFungible(t *testing.T) {
 			name:             "locked tokens are ignored",
 			tokenTypeID:      typeId2,
 			targetAmount:     1,
-			expectedErrorMsg: fmt.Sprintf("insufficient tokens of type %s: got 0, need 1", sdktypes.TokenTypeID(typeId2)),
+			expectedErrorMsg: fmt.Sprintf("insufficient tokens of type %s: got 0, need 1 (excluded 1 locked token(s) with total value 1)", sdktypes.TokenTypeID(typeId2)),
+		},
+		{
+			name:             "locked tokens are reported",
+			tokenTypeID:      typeId2,
+			targetAmount:     1,
+			opts:             []SendFungibleOption{WithFailOnLockedTokens()},
+			expectedErrorMsg: "locked tokens must be unlocked to complete the send: got 0 unlocked, need 1, locked tokens with total value 1",
+		},
+		{
+			name:             "locked tokens are not reported if unlocking would not be enough",
+			tokenTypeID:      typeId2,
+			targetAmount:     2,
+			opts:             []SendFungibleOption{WithFailOnLockedTokens()},
+			expectedErrorMsg: fmt.Sprintf("insufficient tokens of type %s: got 0, need 2 (excluded 1 locked token(s) with total value 1)", sdktypes.TokenTypeID(typeId2)),
 		},
 	}
 
@@ -514,7 +613,7 @@ func TestSendFungible(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			recTxs = make([]*types.TransactionOrder, 0)
-			result, err := tw.SendFungible(context.Background(), 1, tt.tokenTypeID, tt.targetAmount, nil, defaultProof(key), nil)
+			result, err := tw.SendFungible(context.Background(), 1, tt.tokenTypeID, tt.targetAmount, nil, defaultProof(key), nil, tt.opts...)
 			if tt.expectedErrorMsg != "" {
 				require.ErrorContains(t, err, tt.expectedErrorMsg)
 				return
@@ -527,6 +626,392 @@ func TestSendFungible(t *testing.T) {
 	}
 }
 
+func TestFindNonFungibleTokens(t *testing.T) {
+	typeID := tokenid.NewNonFungibleTokenTypeID(t)
+	otherTypeID := tokenid.NewNonFungibleTokenTypeID(t)
+	nfts := []*sdktypes.NonFungibleToken{
+		{ID: tokenid.NewNonFungibleTokenID(t), TypeID: typeID, Symbol: "CAT", Name: "cat #2"},
+		{ID: tokenid.NewNonFungibleTokenID(t), TypeID: otherTypeID, Symbol: "DOG", Name: "dog #1"},
+		{ID: tokenid.NewNonFungibleTokenID(t), TypeID: typeID, Symbol: "CAT", Name: "cat #1"},
+		{ID: tokenid.NewNonFungibleTokenID(t), TypeID: otherTypeID, Symbol: "CAT", Name: "cathedral"},
+	}
+	rpcClient := &mockTokensPartitionClient{
+		getNonFungibleTokens: func(ctx context.Context, ownerID []byte) ([]*sdktypes.NonFungibleToken, error) {
+			return nfts, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+
+	names := func(tokenz []*sdktypes.NonFungibleToken) []string {
+		var res []string
+		for _, t := range tokenz {
+			res = append(res, t.Name)
+		}
+		return res
+	}
+
+	res, err := tw.FindNonFungibleTokens(context.Background(), 1, TokenFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"cat #1", "cat #2", "cathedral", "dog #1"}, names(res))
+
+	res, err = tw.FindNonFungibleTokens(context.Background(), 1, TokenFilter{NamePrefix: "cat #"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"cat #1", "cat #2"}, names(res))
+
+	res, err = tw.FindNonFungibleTokens(context.Background(), 1, TokenFilter{Symbol: "CAT", TypeID: otherTypeID})
+	require.NoError(t, err)
+	require.Equal(t, []string{"cathedral"}, names(res))
+
+	res, err = tw.FindNonFungibleTokens(context.Background(), 1, TokenFilter{Symbol: "BIRD"})
+	require.NoError(t, err)
+	require.Empty(t, res)
+}
+
+func TestListTokensByType(t *testing.T) {
+	ftTypeID := tokenid.NewFungibleTokenTypeID(t)
+	nftTypeID := tokenid.NewNonFungibleTokenTypeID(t)
+	fts := []*sdktypes.FungibleToken{
+		newFungibleToken(t, tokenid.NewFungibleTokenID(t), ftTypeID, "AB", 1, 0),
+		newFungibleToken(t, tokenid.NewFungibleTokenID(t), tokenid.NewFungibleTokenTypeID(t), "CD", 2, 0),
+		newFungibleToken(t, tokenid.NewFungibleTokenID(t), ftTypeID, "AB", 3, 0),
+	}
+	nfts := []*sdktypes.NonFungibleToken{
+		{ID: tokenid.NewNonFungibleTokenID(t), TypeID: tokenid.NewNonFungibleTokenTypeID(t), Name: "dog"},
+		{ID: tokenid.NewNonFungibleTokenID(t), TypeID: nftTypeID, Name: "cat"},
+	}
+	rpcClient := &mockTokensPartitionClient{
+		getFungibleTokens: func(ctx context.Context, ownerID []byte) ([]*sdktypes.FungibleToken, error) {
+			return fts, nil
+		},
+		getNonFungibleTokens: func(ctx context.Context, ownerID []byte) ([]*sdktypes.NonFungibleToken, error) {
+			return nfts, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+
+	ftRes, err := tw.ListFungibleTokensByType(context.Background(), 1, ftTypeID)
+	require.NoError(t, err)
+	require.Equal(t, []*sdktypes.FungibleToken{fts[0], fts[2]}, ftRes)
+
+	ftRes, err = tw.ListFungibleTokensByType(context.Background(), 1, nftTypeID)
+	require.NoError(t, err)
+	require.Empty(t, ftRes)
+
+	nftRes, err := tw.ListNonFungibleTokensByType(context.Background(), 1, nftTypeID)
+	require.NoError(t, err)
+	require.Equal(t, []*sdktypes.NonFungibleToken{nfts[1]}, nftRes)
+
+	_, err = tw.ListNonFungibleTokensByType(context.Background(), 0, nftTypeID)
+	require.ErrorContains(t, err, "invalid account number: 0")
+}
+
+func TestCountTokens(t *testing.T) {
+	pdr := tokenid.PDR()
+	var unitIDs []types.UnitID
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getUnitsByOwnerID: func(ctx context.Context, ownerID hex.Bytes) ([]types.UnitID, error) {
+			return unitIDs, nil
+		},
+		getFungibleTokens: func(ctx context.Context, ownerID []byte) ([]*sdktypes.FungibleToken, error) {
+			return nil, fmt.Errorf("unexpected call to fetch tokens")
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+
+	fungible, nonFungible, err := tw.CountTokens(context.Background(), AllAccounts)
+	require.NoError(t, err)
+	require.Zero(t, fungible)
+	require.Zero(t, nonFungible)
+
+	fcrID, err := tokens.NewFeeCreditRecordIDFromPublicKeyHash(&pdr, types.ShardID{}, test.RandomBytes(32), fcrTimeout)
+	require.NoError(t, err)
+	unitIDs = []types.UnitID{
+		tokenid.NewFungibleTokenID(t),
+		tokenid.NewFungibleTokenID(t),
+		tokenid.NewNonFungibleTokenID(t),
+		tokenid.NewFungibleTokenTypeID(t),
+		fcrID,
+	}
+	fungible, nonFungible, err = tw.CountTokens(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, 2, fungible)
+	require.Equal(t, 1, nonFungible)
+
+	_, _, err = tw.CountTokens(context.Background(), 2)
+	require.ErrorContains(t, err, "account does not exist")
+}
+
+func TestGetFungibleTokenBalances(t *testing.T) {
+	pdr := tokenid.PDR()
+	typeID := tokenid.NewFungibleTokenTypeID(t)
+	typeID2 := tokenid.NewFungibleTokenTypeID(t)
+	tokensByOwner := make(map[string][]*sdktypes.FungibleToken)
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getFungibleTokens: func(ctx context.Context, ownerID []byte) ([]*sdktypes.FungibleToken, error) {
+			return tokensByOwner[string(ownerID)], nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+	_, _, err := tw.am.AddAccount()
+	require.NoError(t, err)
+	keys, err := tw.am.GetAccountKeys()
+	require.NoError(t, err)
+
+	newToken := func(typeID sdktypes.TokenTypeID, amount, lockStatus uint64) *sdktypes.FungibleToken {
+		token := newFungibleToken(t, test.RandomBytes(32), typeID, "AB", amount, lockStatus)
+		token.DecimalPlaces = 2
+		return token
+	}
+	tokensByOwner[string(keys[0].PubKeyHash.Sha256)] = []*sdktypes.FungibleToken{
+		newToken(typeID, 5, 0),
+		newToken(typeID, 7, 0),
+		newToken(typeID, 100, 1), // locked
+		newToken(typeID2, math.MaxUint64, 0),
+	}
+	tokensByOwner[string(keys[1].PubKeyHash.Sha256)] = []*sdktypes.FungibleToken{
+		newToken(typeID, 10, 0),
+		newToken(typeID2, 1, 0),
+	}
+
+	balances, err := tw.GetFungibleTokenBalances(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, balances, 2)
+	require.Equal(t, &FungibleTokenBalance{TypeID: typeID, Symbol: "AB", DecimalPlaces: 2, Amount: 12}, balances[typeID.String()])
+	require.EqualValues(t, uint64(math.MaxUint64), balances[typeID2.String()].Amount)
+
+	balances, err = tw.GetFungibleTokenBalances(context.Background(), AllAccounts)
+	require.NoError(t, err)
+	require.Len(t, balances, 2)
+	require.EqualValues(t, 22, balances[typeID.String()].Amount)
+	// balance is capped on overflow
+	require.EqualValues(t, uint64(math.MaxUint64), balances[typeID2.String()].Amount)
+
+	balances, err = tw.GetFungibleTokenBalances(context.Background(), 2)
+	require.NoError(t, err)
+	require.EqualValues(t, 10, balances[typeID.String()].Amount)
+	require.EqualValues(t, 1, balances[typeID2.String()].Amount)
+}
+
+func TestGetSpendableBalance(t *testing.T) {
+	pdr := tokenid.PDR()
+	typeID := tokenid.NewFungibleTokenTypeID(t)
+	typeID2 := tokenid.NewFungibleTokenTypeID(t)
+	var tokenz []*sdktypes.FungibleToken
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getFungibleTokens: func(ctx context.Context, ownerID []byte) ([]*sdktypes.FungibleToken, error) {
+			return tokenz, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+
+	spendable, locked, err := tw.GetSpendableBalance(context.Background(), 1, typeID)
+	require.NoError(t, err)
+	require.Zero(t, spendable)
+	require.Zero(t, locked)
+
+	tokenz = []*sdktypes.FungibleToken{
+		newFungibleToken(t, test.RandomBytes(32), typeID, "AB", 5, 0),
+		newFungibleToken(t, test.RandomBytes(32), typeID, "AB", 7, 0),
+		newFungibleToken(t, test.RandomBytes(32), typeID, "AB", 100, wallet.LockReasonManual),
+		newFungibleToken(t, test.RandomBytes(32), typeID, "AB", 3, wallet.LockReasonCollectDust),
+		newFungibleToken(t, test.RandomBytes(32), typeID2, "CD", math.MaxUint64, 0),
+		newFungibleToken(t, test.RandomBytes(32), typeID2, "CD", 1, 0),
+	}
+	spendable, locked, err = tw.GetSpendableBalance(context.Background(), 1, typeID)
+	require.NoError(t, err)
+	require.EqualValues(t, 12, spendable)
+	require.EqualValues(t, 103, locked)
+
+	// balance is capped on overflow
+	spendable, locked, err = tw.GetSpendableBalance(context.Background(), 1, typeID2)
+	require.NoError(t, err)
+	require.EqualValues(t, uint64(math.MaxUint64), spendable)
+	require.Zero(t, locked)
+
+	_, _, err = tw.GetSpendableBalance(context.Background(), 0, typeID)
+	require.ErrorContains(t, err, "invalid account number: 0")
+}
+
+func TestSendFungibleMulti(t *testing.T) {
+	pdr := tokenid.PDR()
+	typeId := test.RandomBytes(32)
+	big := newFungibleToken(t, test.RandomBytes(32), typeId, "AB", 100, 0)
+	small := newFungibleToken(t, test.RandomBytes(32), typeId, "AB", 30, 0)
+	var recTxs []*types.TransactionOrder
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getFungibleTokens: func(ctx context.Context, ownerID []byte) ([]*sdktypes.FungibleToken, error) {
+			return []*sdktypes.FungibleToken{
+				small,
+				big,
+				newFungibleToken(t, test.RandomBytes(32), typeId, "AB", 1000, 1),
+				newFungibleToken(t, test.RandomBytes(32), test.RandomBytes(32), "CD", 1000, 0),
+			}, nil
+		},
+		getUnitsByOwnerID: func(ctx context.Context, ownerID hex.Bytes) ([]types.UnitID, error) {
+			fcrID, err := tokens.NewFeeCreditRecordIDFromPublicKeyHash(&pdr, types.ShardID{}, ownerID, fcrTimeout)
+			require.NoError(t, err)
+			return []types.UnitID{fcrID}, nil
+		},
+		sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+			recTxs = append(recTxs, tx)
+			return tx.Hash(crypto.SHA256)
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+	ak, err := tw.am.GetAccountKey(0)
+	require.NoError(t, err)
+	receivers := [][]byte{test.RandomBytes(33), test.RandomBytes(33), test.RandomBytes(33)}
+
+	t.Run("tokens are split and transferred to all targets", func(t *testing.T) {
+		recTxs = nil
+		res, err := tw.SendFungibleMulti(context.Background(), 1, typeId, []TransferTarget{
+			{PubKey: receivers[0], Amount: 40},
+			{PubKey: receivers[1], Amount: 50},
+			{PubKey: receivers[2], Amount: 30},
+		}, defaultProof(ak), nil)
+		require.NoError(t, err)
+		require.Len(t, res.Submissions, 4)
+		require.Len(t, recTxs, 4)
+
+		// the big token is split twice, the remainder is transferred to the third target
+		splitAttr := &tokens.SplitFungibleTokenAttributes{}
+		for i, expected := range []struct{ amount, counter uint64 }{{40, 0}, {50, 1}} {
+			require.Equal(t, tokens.TransactionTypeSplitFT, recTxs[i].Type)
+			require.EqualValues(t, big.ID, recTxs[i].GetUnitID())
+			require.NoError(t, recTxs[i].UnmarshalAttributes(splitAttr))
+			require.Equal(t, expected.amount, splitAttr.TargetValue)
+			require.Equal(t, expected.counter, splitAttr.Counter)
+			require.EqualValues(t, templates.NewP2pkh256BytesFromKeyHash(hash.Sum256(receivers[i])), splitAttr.NewOwnerPredicate)
+		}
+		transferAttr := &tokens.TransferFungibleTokenAttributes{}
+		require.Equal(t, tokens.TransactionTypeTransferFT, recTxs[2].Type)
+		require.EqualValues(t, big.ID, recTxs[2].GetUnitID())
+		require.NoError(t, recTxs[2].UnmarshalAttributes(transferAttr))
+		require.EqualValues(t, 10, transferAttr.Value)
+		require.EqualValues(t, 2, transferAttr.Counter)
+		require.EqualValues(t, templates.NewP2pkh256BytesFromKeyHash(hash.Sum256(receivers[2])), transferAttr.NewOwnerPredicate)
+
+		// the rest of the third target is split from the small token
+		require.Equal(t, tokens.TransactionTypeSplitFT, recTxs[3].Type)
+		require.EqualValues(t, small.ID, recTxs[3].GetUnitID())
+		require.NoError(t, recTxs[3].UnmarshalAttributes(splitAttr))
+		require.EqualValues(t, 20, splitAttr.TargetValue)
+		require.EqualValues(t, templates.NewP2pkh256BytesFromKeyHash(hash.Sum256(receivers[2])), splitAttr.NewOwnerPredicate)
+
+		// the listed tokens are not modified
+		require.EqualValues(t, 100, big.Amount)
+		require.EqualValues(t, 0, big.Counter)
+	})
+
+	t.Run("insufficient balance", func(t *testing.T) {
+		recTxs = nil
+		_, err := tw.SendFungibleMulti(context.Background(), 1, typeId, []TransferTarget{
+			{PubKey: receivers[0], Amount: 100},
+			{PubKey: receivers[1], Amount: 31},
+		}, defaultProof(ak), nil)
+		require.ErrorContains(t, err, fmt.Sprintf("insufficient tokens of type %s: got 130, need 131", sdktypes.TokenTypeID(typeId)))
+		require.Empty(t, recTxs)
+	})
+
+	t.Run("invalid targets", func(t *testing.T) {
+		_, err := tw.SendFungibleMulti(context.Background(), 1, typeId, nil, defaultProof(ak), nil)
+		require.ErrorContains(t, err, "no transfer targets")
+
+		_, err = tw.SendFungibleMulti(context.Background(), 1, typeId, []TransferTarget{{PubKey: receivers[0], Amount: 10}, {PubKey: receivers[1]}}, defaultProof(ak), nil)
+		require.ErrorContains(t, err, "invalid amount of target 1: 0")
+
+		_, err = tw.SendFungibleMulti(context.Background(), 1, typeId, []TransferTarget{{Amount: math.MaxUint64}, {Amount: 1}}, defaultProof(ak), nil)
+		require.ErrorContains(t, err, "sum of target amounts overflows")
+		require.Empty(t, recTxs)
+	})
+}
+
+func TestSendFungible_ChangeOwnerPredicate(t *testing.T) {
+	pdr := tokenid.PDR()
+	typeID := tokenid.NewFungibleTokenTypeID(t)
+	changeOwnerPredicate := templates.NewP2pkh256BytesFromKeyHash(test.RandomBytes(32))
+	var token *sdktypes.FungibleToken
+	var recTxs []*types.TransactionOrder
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getFungibleTokens: func(ctx context.Context, ownerID []byte) ([]*sdktypes.FungibleToken, error) {
+			return []*sdktypes.FungibleToken{token}, nil
+		},
+		getFungibleToken: func(ctx context.Context, id sdktypes.TokenID) (*sdktypes.FungibleToken, error) {
+			// state of the token after the split
+			return &sdktypes.FungibleToken{ID: token.ID, TypeID: typeID, Amount: token.Amount - 4, Counter: token.Counter + 1}, nil
+		},
+		sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+			recTxs = append(recTxs, tx)
+			return tx.Hash(crypto.SHA256)
+		},
+		getTransactionProof: func(ctx context.Context, txHash hex.Bytes) (*types.TxRecordProof, error) {
+			return &types.TxRecordProof{TxRecord: &types.TransactionRecord{ServerMetadata: &types.ServerMetadata{ActualFee: 1, SuccessIndicator: types.TxStatusSuccessful}}}, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+	tw.confirmTx = true
+	key, err := tw.am.GetAccountKey(0)
+	require.NoError(t, err)
+
+	t.Run("change is transferred to the change owner predicate", func(t *testing.T) {
+		recTxs = nil
+		token = newFungibleToken(t, tokenid.NewFungibleTokenID(t), typeID, "AB", 10, 0)
+		res, err := tw.SendFungible(context.Background(), 1, typeID, 4, nil, defaultProof(key), nil, WithChangeOwnerPredicate(changeOwnerPredicate))
+		require.NoError(t, err)
+		require.Len(t, res.Submissions, 2)
+		require.EqualValues(t, 2, res.FeeSum)
+		require.Len(t, recTxs, 2)
+
+		require.EqualValues(t, tokens.TransactionTypeSplitFT, recTxs[0].Type)
+		splitAttr := &tokens.SplitFungibleTokenAttributes{}
+		require.NoError(t, recTxs[0].UnmarshalAttributes(splitAttr))
+		require.EqualValues(t, 4, splitAttr.TargetValue)
+
+		require.EqualValues(t, tokens.TransactionTypeTransferFT, recTxs[1].Type)
+		require.EqualValues(t, token.ID, recTxs[1].GetUnitID())
+		transferAttr := &tokens.TransferFungibleTokenAttributes{}
+		require.NoError(t, recTxs[1].UnmarshalAttributes(transferAttr))
+		require.EqualValues(t, 6, transferAttr.Value)
+		require.EqualValues(t, token.Counter+1, transferAttr.Counter)
+		require.EqualValues(t, changeOwnerPredicate, transferAttr.NewOwnerPredicate)
+	})
+
+	t.Run("no change to move when the whole token is transferred", func(t *testing.T) {
+		recTxs = nil
+		token = newFungibleToken(t, tokenid.NewFungibleTokenID(t), typeID, "AB", 4, 0)
+		res, err := tw.SendFungible(context.Background(), 1, typeID, 4, nil, defaultProof(key), nil, WithChangeOwnerPredicate(changeOwnerPredicate))
+		require.NoError(t, err)
+		require.Len(t, res.Submissions, 1)
+		require.Len(t, recTxs, 1)
+		require.EqualValues(t, tokens.TransactionTypeTransferFT, recTxs[0].Type)
+	})
+
+	t.Run("change keeps the owner by default", func(t *testing.T) {
+		recTxs = nil
+		token = newFungibleToken(t, tokenid.NewFungibleTokenID(t), typeID, "AB", 10, 0)
+		res, err := tw.SendFungible(context.Background(), 1, typeID, 4, nil, defaultProof(key), nil)
+		require.NoError(t, err)
+		require.Len(t, res.Submissions, 1)
+		require.Len(t, recTxs, 1)
+		require.EqualValues(t, tokens.TransactionTypeSplitFT, recTxs[0].Type)
+	})
+
+	t.Run("invalid change owner predicate", func(t *testing.T) {
+		recTxs = nil
+		_, err := tw.SendFungible(context.Background(), 1, typeID, 4, nil, defaultProof(key), nil, WithChangeOwnerPredicate([]byte{}))
+		require.ErrorContains(t, err, "invalid change owner predicate")
+
+		_, err = tw.SendFungible(context.Background(), 1, typeID, 4, nil, defaultProof(key), nil, WithChangeOwnerPredicate(templates.AlwaysFalseBytes()))
+		require.ErrorContains(t, err, "invalid change owner predicate: predicate is always false")
+		require.Empty(t, recTxs)
+	})
+}
+
 func TestNewNFT_InvalidInputs(t *testing.T) {
 	accountNumber := uint64(1)
 	tests := []struct {
@@ -588,6 +1073,9 @@ func TestNewNFT(t *testing.T) {
 			require.NoError(t, err)
 			return []types.UnitID{fcrID}, nil
 		},
+		getNonFungibleTokenTypeHierarchy: func(ctx context.Context, id sdktypes.TokenTypeID) ([]*sdktypes.NonFungibleTokenType, error) {
+			return []*sdktypes.NonFungibleTokenType{{ID: id, TokenMintingPredicate: sdktypes.Predicate(templates.AlwaysTrueBytes())}}, nil
+		},
 	}
 	tw := initTestWallet(t, rpcClient)
 	_, _, err := tw.am.AddAccount()
@@ -661,6 +1149,121 @@ func TestNewNFT(t *testing.T) {
 	}
 }
 
+func TestNewNFT_TokenIDSeed(t *testing.T) {
+	pdr := tokenid.PDR()
+	recTxs := make([]*types.TransactionOrder, 0)
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+			recTxs = append(recTxs, tx)
+			return tx.Hash(crypto.SHA256)
+		},
+		getNonFungibleTokenTypeHierarchy: func(ctx context.Context, id sdktypes.TokenTypeID) ([]*sdktypes.NonFungibleTokenType, error) {
+			return []*sdktypes.NonFungibleTokenType{{ID: id, TokenMintingPredicate: sdktypes.Predicate(templates.AlwaysTrueBytes())}}, nil
+		},
+		getFungibleTokenTypeHierarchy: func(ctx context.Context, id sdktypes.TokenTypeID) ([]*sdktypes.FungibleTokenType, error) {
+			return []*sdktypes.FungibleTokenType{{ID: id, TokenMintingPredicate: sdktypes.Predicate(templates.AlwaysTrueBytes())}}, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+
+	mint := func(seed []byte) types.UnitID {
+		nft := &sdktypes.NonFungibleToken{TypeID: tokenid.NewNonFungibleTokenTypeID(t), URI: "https://alphabill.org"}
+		_, err := tw.NewNFT(context.Background(), 1, nft, nil, WithTokenIDSeed(seed))
+		require.NoError(t, err)
+		tx := recTxs[len(recTxs)-1]
+		require.EqualValues(t, tx.GetUnitID(), nft.ID)
+		require.NoError(t, nft.ID.TypeMustBe(tokens.NonFungibleTokenUnitType, tw.pdr))
+		return nft.ID
+	}
+	// same seed results in the same ID, different seed in a different one
+	id := mint([]byte("fixture 1"))
+	require.Equal(t, id, mint([]byte("fixture 1")))
+	require.NotEqual(t, id, mint([]byte("fixture 2")))
+
+	// ID of the fungible token derived from the same seed has the fungible token unit type
+	ft := &sdktypes.FungibleToken{TypeID: tokenid.NewFungibleTokenTypeID(t), Amount: 1}
+	_, err := tw.NewFungibleToken(context.Background(), 1, ft, nil, WithTokenIDSeed([]byte("fixture 1")))
+	require.NoError(t, err)
+	require.NoError(t, ft.ID.TypeMustBe(tokens.FungibleTokenUnitType, tw.pdr))
+	require.NotEqual(t, id, ft.ID)
+}
+
+func TestVerifyMintPredicateInput(t *testing.T) {
+	pdr := tokenid.PDR()
+	ftTypeID := tokenid.NewFungibleTokenTypeID(t)
+	nftTypeID := tokenid.NewNonFungibleTokenTypeID(t)
+	var mintPredicate sdktypes.Predicate
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getFungibleTokenTypeHierarchy: func(ctx context.Context, id sdktypes.TokenTypeID) ([]*sdktypes.FungibleTokenType, error) {
+			if !id.Eq(ftTypeID) {
+				return nil, nil
+			}
+			return []*sdktypes.FungibleTokenType{{ID: id, TokenMintingPredicate: mintPredicate}}, nil
+		},
+		getNonFungibleTokenTypeHierarchy: func(ctx context.Context, id sdktypes.TokenTypeID) ([]*sdktypes.NonFungibleTokenType, error) {
+			return []*sdktypes.NonFungibleTokenType{{ID: id, TokenMintingPredicate: mintPredicate}}, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+	_, _, err := tw.am.AddAccount()
+	require.NoError(t, err)
+	key1, err := tw.am.GetAccountKey(0)
+	require.NoError(t, err)
+	key2, err := tw.am.GetAccountKey(1)
+	require.NoError(t, err)
+
+	mintPredicate = sdktypes.Predicate(templates.AlwaysTrueBytes())
+	require.NoError(t, tw.VerifyMintPredicateInput(context.Background(), ftTypeID, nil))
+
+	mintPredicate = sdktypes.Predicate(templates.AlwaysFalseBytes())
+	err = tw.VerifyMintPredicateInput(context.Background(), nftTypeID, defaultProof(key1))
+	require.ErrorIs(t, err, ErrMintNotSatisfied)
+	require.ErrorContains(t, err, "minting clause is always false")
+
+	mintPredicate = sdktypes.Predicate(templates.NewP2pkh256BytesFromKeyHash(key1.PubKeyHash.Sha256))
+	require.NoError(t, tw.VerifyMintPredicateInput(context.Background(), ftTypeID, defaultProof(key1)))
+
+	err = tw.VerifyMintPredicateInput(context.Background(), ftTypeID, defaultProof(key2))
+	require.ErrorIs(t, err, ErrMintNotSatisfied)
+	require.ErrorContains(t, err, fmt.Sprintf("minting clause p2pkh(0x%X) does not match the key hash 0x%X", key1.PubKeyHash.Sha256, key2.PubKeyHash.Sha256))
+
+	err = tw.VerifyMintPredicateInput(context.Background(), nftTypeID, &PredicateInput{Argument: []byte{1}})
+	require.ErrorIs(t, err, ErrMintNotSatisfied)
+	require.ErrorContains(t, err, "requires a signature of the key")
+
+	require.ErrorIs(t, tw.VerifyMintPredicateInput(context.Background(), tokenid.NewFungibleTokenTypeID(t), defaultProof(key1)), errTokenTypeNotFound)
+
+	// minting is not attempted when the clause can not be satisfied
+	rpcClient.sendTransaction = func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+		t.Fatal("unexpected transaction")
+		return nil, nil
+	}
+	_, err = tw.NewFungibleToken(context.Background(), 2, &sdktypes.FungibleToken{TypeID: ftTypeID, Amount: 1}, defaultProof(key2))
+	require.ErrorIs(t, err, ErrMintNotSatisfied)
+}
+
+func TestEnsureTokenOwnership(t *testing.T) {
+	am := initAccountManager(t)
+	key, err := am.GetAccountKey(0)
+	require.NoError(t, err)
+	acc := &accountKey{AccountKey: key}
+	wasmPredicate, err := types.Cbor.Marshal(predicates.Predicate{Tag: wasm.PredicateEngineID, Code: []byte{1}})
+	require.NoError(t, err)
+
+	token := newNonFungibleToken(t, "NFT", templates.NewP2pkh256BytesFromKey(key.PubKey), 0, 0)
+	require.NoError(t, ensureTokenOwnership(acc, token, defaultProof(key)))
+
+	token.OwnerPredicate = templates.AlwaysFalseBytes()
+	require.ErrorContains(t, ensureTokenOwnership(acc, token, defaultProof(key)), "does not belong to account #1")
+
+	// custom predicate requires explicit owner proof
+	token.OwnerPredicate = wasmPredicate
+	require.ErrorIs(t, ensureTokenOwnership(acc, token, defaultProof(key)), ErrOwnerProofRequired)
+	require.NoError(t, ensureTokenOwnership(acc, token, &PredicateInput{Argument: []byte{1}}))
+}
+
 func TestTransferNFT(t *testing.T) {
 	pdr := tokenid.PDR()
 	tokenz := make(map[string]*sdktypes.NonFungibleToken)
@@ -734,184 +1337,1416 @@ func TestTransferNFT(t *testing.T) {
 	}
 }
 
-func TestUpdateNFTData(t *testing.T) {
+func TestSendFungible_ConcurrentCalls(t *testing.T) {
 	pdr := tokenid.PDR()
-	tokenz := make(map[string]*sdktypes.NonFungibleToken)
-	recTxs := make(map[string]*types.TransactionOrder)
+	typeID := test.RandomBytes(32)
+	var mu sync.Mutex
+	var active, maxActive int
 	rpcClient := &mockTokensPartitionClient{
 		pdr: &pdr,
-		getNonFungibleToken: func(ctx context.Context, id sdktypes.TokenID) (*sdktypes.NonFungibleToken, error) {
+		getFungibleTokens: func(ctx context.Context, ownerID []byte) ([]*sdktypes.FungibleToken, error) {
+			mu.Lock()
+			active++
+			maxActive = max(maxActive, active)
+			mu.Unlock()
+			// give the other calls a chance to interleave if the account is not locked
+			time.Sleep(10 * time.Millisecond)
+			return []*sdktypes.FungibleToken{newFungibleToken(t, test.RandomBytes(32), typeID, "AB", 5, 0)}, nil
+		},
+		sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+			mu.Lock()
+			active--
+			mu.Unlock()
+			return tx.Hash(crypto.SHA256)
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+	ak, err := tw.am.GetAccountKey(0)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := tw.SendFungible(context.Background(), 1, typeID, 5, nil, defaultProof(ak), nil)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	// the calls on the same account are serialized
+	require.Equal(t, 1, maxActive)
+}
+
+func TestLockAccount(t *testing.T) {
+	tw := initTestWallet(t, &mockTokensPartitionClient{})
+	unlock, err := tw.lockAccount(context.Background(), 1)
+	require.NoError(t, err)
+
+	// other accounts are not blocked
+	unlock2, err := tw.lockAccount(context.Background(), 2)
+	require.NoError(t, err)
+	unlock2()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = tw.lockAccount(ctx, 1)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorContains(t, err, "waiting for the lock of account #1")
+
+	unlock()
+	unlock, err = tw.lockAccount(context.Background(), 1)
+	require.NoError(t, err)
+	unlock()
+}
+
+func TestTransferNFTToHash(t *testing.T) {
+	pdr := tokenid.PDR()
+	var token *sdktypes.NonFungibleToken
+	var recTx *types.TransactionOrder
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getNonFungibleToken: func(ctx context.Context, id sdktypes.TokenID) (*sdktypes.NonFungibleToken, error) {
+			return token, nil
+		},
+		sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+			recTx = tx
+			return tx.Hash(crypto.SHA256)
+		},
+		getUnitsByOwnerID: func(ctx context.Context, ownerID hex.Bytes) ([]types.UnitID, error) {
+			fcrID, err := tokens.NewFeeCreditRecordIDFromPublicKeyHash(&pdr, types.ShardID{}, ownerID, fcrTimeout)
+			require.NoError(t, err)
+			return []types.UnitID{fcrID}, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+	ak, err := tw.am.GetAccountKey(0)
+	require.NoError(t, err)
+	token = newNonFungibleToken(t, "AB", templates.NewP2pkh256BytesFromKey(ak.PubKey), 0, 0)
+
+	pubKeyHash := test.RandomBytes(32)
+	result, err := tw.TransferNFTToHash(context.Background(), 1, token.ID, pubKeyHash, nil, defaultProof(ak))
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.NotNil(t, recTx)
+	attr := &tokens.TransferNonFungibleTokenAttributes{}
+	require.NoError(t, recTx.UnmarshalAttributes(attr))
+	require.EqualValues(t, templates.NewP2pkh256BytesFromKeyHash(pubKeyHash), attr.NewOwnerPredicate)
+
+	recTx = nil
+	_, err = tw.TransferNFTToHash(context.Background(), 1, token.ID, test.RandomBytes(20), nil, defaultProof(ak))
+	require.EqualError(t, err, "invalid public key hash length 20, expected 32 bytes")
+	_, err = tw.TransferNFTToHash(context.Background(), 1, token.ID, nil, nil, defaultProof(ak))
+	require.EqualError(t, err, "invalid public key hash length 0, expected 32 bytes")
+	require.Nil(t, recTx)
+}
+
+func TestConfirmTimeout(t *testing.T) {
+	pdr := tokenid.PDR()
+	var token *sdktypes.NonFungibleToken
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getNonFungibleToken: func(ctx context.Context, id sdktypes.TokenID) (*sdktypes.NonFungibleToken, error) {
+			return token, nil
+		},
+		sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+			return tx.Hash(crypto.SHA256)
+		},
+		getTransactionProof: func(ctx context.Context, txHash hex.Bytes) (*types.TxRecordProof, error) {
+			// transaction never gets confirmed
+			return nil, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+	tw.confirmTx = true
+	tw.confirmTimeout = 50 * time.Millisecond
+	ak, err := tw.am.GetAccountKey(0)
+	require.NoError(t, err)
+	token = newNonFungibleToken(t, "AB", templates.NewP2pkh256BytesFromKey(ak.PubKey), 0, 0)
+
+	start := time.Now()
+	_, err = tw.TransferNFT(context.Background(), 1, token.ID, nil, nil, defaultProof(ak))
+	require.ErrorIs(t, err, ErrConfirmTimeout)
+	require.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestTransferNFTs(t *testing.T) {
+	pdr := tokenid.PDR()
+	tokenz := make(map[string]*sdktypes.NonFungibleToken)
+	var recTxs []*types.TransactionOrder
+	roundInfoCalls := 0
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getNonFungibleToken: func(ctx context.Context, id sdktypes.TokenID) (*sdktypes.NonFungibleToken, error) {
+			return tokenz[string(id)], nil
+		},
+		sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+			recTxs = append(recTxs, tx)
+			return tx.Hash(crypto.SHA256)
+		},
+		getRoundInfo: func(ctx context.Context) (*sdktypes.RoundInfo, error) {
+			roundInfoCalls++
+			return &sdktypes.RoundInfo{RoundNumber: 1}, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+	ak, err := tw.am.GetAccountKey(0)
+	require.NoError(t, err)
+	receiver, err := hexutil.Decode("0x0290a43bc454babf1ea8b0b76fcbb01a8f27a989047cf6d6d76397cc4756321e64")
+	require.NoError(t, err)
+
+	ownerPredicate := templates.NewP2pkh256BytesFromKey(ak.PubKey)
+	nft1 := newNonFungibleToken(t, "AB", ownerPredicate, 0, 0)
+	nft2 := newNonFungibleToken(t, "AB", ownerPredicate, 0, 0)
+	locked := newNonFungibleToken(t, "AB", ownerPredicate, 1, 0)
+	foreign := newNonFungibleToken(t, "AB", templates.NewP2pkh256BytesFromKeyHash(test.RandomBytes(32)), 0, 0)
+	for _, nft := range []*sdktypes.NonFungibleToken{nft1, nft2, locked, foreign} {
+		tokenz[string(nft.ID)] = nft
+	}
+
+	t.Run("tokens are transferred in a batch, locked token is skipped", func(t *testing.T) {
+		recTxs, roundInfoCalls = nil, 0
+		results, err := tw.TransferNFTs(context.Background(), 1, []NFTTransfer{
+			{TokenID: nft1.ID, ReceiverPubKey: receiver},
+			{TokenID: locked.ID, ReceiverPubKey: receiver},
+			{TokenID: nft2.ID},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		require.Len(t, recTxs, 2)
+		require.Equal(t, 1, roundInfoCalls)
+
+		require.NoError(t, results[0].SkipReason)
+		require.Len(t, results[0].Submissions, 1)
+		require.EqualValues(t, nft1.ID, results[0].Submissions[0].UnitID)
+		attrs := &tokens.TransferNonFungibleTokenAttributes{}
+		require.NoError(t, recTxs[0].UnmarshalAttributes(attrs))
+		require.EqualValues(t, templates.NewP2pkh256BytesFromKeyHash(hash.Sum256(receiver)), attrs.NewOwnerPredicate)
+
+		require.ErrorContains(t, results[1].SkipReason, "is locked")
+		require.Empty(t, results[1].Submissions)
+
+		require.NoError(t, results[2].SkipReason)
+		require.EqualValues(t, nft2.ID, results[2].Submissions[0].UnitID)
+		require.NoError(t, recTxs[1].UnmarshalAttributes(attrs))
+		require.EqualValues(t, templates.AlwaysTrueBytes(), attrs.NewOwnerPredicate)
+	})
+
+	t.Run("nothing is sent if a token does not belong to the account", func(t *testing.T) {
+		recTxs = nil
+		_, err := tw.TransferNFTs(context.Background(), 1, []NFTTransfer{
+			{TokenID: nft1.ID, ReceiverPubKey: receiver},
+			{TokenID: foreign.ID, ReceiverPubKey: receiver},
+		})
+		require.ErrorContains(t, err, "does not belong to account #1")
+		require.Empty(t, recTxs)
+	})
+
+	t.Run("duplicate token", func(t *testing.T) {
+		recTxs = nil
+		_, err := tw.TransferNFTs(context.Background(), 1, []NFTTransfer{{TokenID: nft1.ID}, {TokenID: nft1.ID}})
+		require.ErrorContains(t, err, "duplicate transfer of token")
+		require.Empty(t, recTxs)
+	})
+
+	t.Run("all tokens locked", func(t *testing.T) {
+		recTxs = nil
+		results, err := tw.TransferNFTs(context.Background(), 1, []NFTTransfer{{TokenID: locked.ID}})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Error(t, results[0].SkipReason)
+		require.Empty(t, recTxs)
+	})
+
+	t.Run("no transfers", func(t *testing.T) {
+		_, err := tw.TransferNFTs(context.Background(), 1, nil)
+		require.ErrorContains(t, err, "no transfers")
+	})
+}
+
+func TestUpdateNFTData(t *testing.T) {
+	pdr := tokenid.PDR()
+	tokenz := make(map[string]*sdktypes.NonFungibleToken)
+	recTxs := make(map[string]*types.TransactionOrder)
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getNonFungibleToken: func(ctx context.Context, id sdktypes.TokenID) (*sdktypes.NonFungibleToken, error) {
 			return tokenz[string(id)], nil
 		},
 		sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
-			recTxs[string(tx.GetUnitID())] = tx
-			return tx.Hash(crypto.SHA256)
+			recTxs[string(tx.GetUnitID())] = tx
+			return tx.Hash(crypto.SHA256)
+		},
+		getUnitsByOwnerID: func(ctx context.Context, ownerID hex.Bytes) ([]types.UnitID, error) {
+			// by default returns only the fee credit record id
+			fcrID, err := tokens.NewFeeCreditRecordIDFromPublicKeyHash(&pdr, types.ShardID{}, ownerID, fcrTimeout)
+			require.NoError(t, err)
+			return []types.UnitID{fcrID}, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+	tok := newNonFungibleToken(t, "AB", nil, 0, 0)
+	tokenz[string(tok.ID)] = tok
+
+	ak, err := tw.am.GetAccountKey(0)
+	require.NoError(t, err)
+
+	// test data, counter and predicate inputs are submitted correctly
+	data := test.RandomBytes(64)
+	result, err := tw.UpdateNFTData(context.Background(), 1, tok.ID, data, &PredicateInput{Argument: nil}, []*PredicateInput{{AccountKey: ak}}, nil)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	tx, found := recTxs[string(tok.ID)]
+	require.True(t, found)
+	require.EqualValues(t, tok.ID, tx.GetUnitID())
+	require.Equal(t, tokens.TransactionTypeUpdateNFT, tx.Type)
+
+	// test that locked token tx is not sent
+	lockedToken := newNonFungibleToken(t, "AB", nil, 1, 0)
+	tokenz[string(tok.ID)] = lockedToken
+	result, err = tw.UpdateNFTData(context.Background(), 1, tok.ID, data, &PredicateInput{Argument: nil}, []*PredicateInput{{AccountKey: ak}}, nil)
+	require.ErrorContains(t, err, "token is locked")
+	require.Nil(t, result)
+
+	// the update is not sent when the counter of the token differs from the expected one
+	tok = newNonFungibleToken(t, "AB", nil, 0, 3)
+	tokenz[string(tok.ID)] = tok
+	delete(recTxs, string(tok.ID))
+	expectedCounter := uint64(2)
+	result, err = tw.UpdateNFTData(context.Background(), 1, tok.ID, data, &PredicateInput{Argument: nil}, []*PredicateInput{{AccountKey: ak}}, &expectedCounter)
+	require.EqualError(t, err, "token counter is 3, expected 2")
+	require.Nil(t, result)
+	require.NotContains(t, recTxs, string(tok.ID))
+
+	expectedCounter = 3
+	result, err = tw.UpdateNFTData(context.Background(), 1, tok.ID, data, &PredicateInput{Argument: nil}, []*PredicateInput{{AccountKey: ak}}, &expectedCounter)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.Contains(t, recTxs, string(tok.ID))
+
+	// custom data-update predicate requires the argument to be provided
+	customPredicate, err := types.Cbor.Marshal(predicates.Predicate{Tag: wasm.PredicateEngineID, Code: []byte{1, 2, 3}})
+	require.NoError(t, err)
+	tok = newNonFungibleToken(t, "AB", nil, 0, 0)
+	tok.DataUpdatePredicate = customPredicate
+	tokenz[string(tok.ID)] = tok
+	result, err = tw.UpdateNFTData(context.Background(), 1, tok.ID, data, &PredicateInput{Argument: nil}, nil, nil)
+	require.ErrorIs(t, err, ErrDataUpdateProofRequired)
+	require.Nil(t, result)
+
+	argument := []byte{4, 5, 6}
+	result, err = tw.UpdateNFTData(context.Background(), 1, tok.ID, data, &PredicateInput{Argument: argument}, nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	var authProof tokens.UpdateNonFungibleTokenAuthProof
+	require.NoError(t, recTxs[string(tok.ID)].UnmarshalAuthProof(&authProof))
+	require.EqualValues(t, argument, authProof.TokenDataUpdateProof)
+}
+
+func TestTransferAndUpdateNFT(t *testing.T) {
+	pdr := tokenid.PDR()
+	var token *sdktypes.NonFungibleToken
+	var recTxs []*types.TransactionOrder
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getNonFungibleToken: func(ctx context.Context, id sdktypes.TokenID) (*sdktypes.NonFungibleToken, error) {
+			return token, nil
+		},
+		sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+			recTxs = append(recTxs, tx)
+			return tx.Hash(crypto.SHA256)
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+	ak, err := tw.am.GetAccountKey(0)
+	require.NoError(t, err)
+	receiver, err := hexutil.Decode("0x0290a43bc454babf1ea8b0b76fcbb01a8f27a989047cf6d6d76397cc4756321e64")
+	require.NoError(t, err)
+	data := []byte{1, 2, 3}
+
+	// transfer and update are sent in order, the update is created for the counter after the transfer
+	token = newNonFungibleToken(t, "AB", templates.NewP2pkh256BytesFromKey(ak.PubKey), 0, 5)
+	result, err := tw.TransferAndUpdateNFT(context.Background(), 1, token.ID, receiver, data, nil, defaultProof(ak), &PredicateInput{Argument: nil}, nil)
+	require.NoError(t, err)
+	require.Len(t, result.Submissions, 2)
+	require.Len(t, recTxs, 2)
+	require.Equal(t, tokens.TransactionTypeTransferNFT, recTxs[0].Type)
+	transferAttr := &tokens.TransferNonFungibleTokenAttributes{}
+	require.NoError(t, recTxs[0].UnmarshalAttributes(transferAttr))
+	require.EqualValues(t, 5, transferAttr.Counter)
+	require.EqualValues(t, templates.NewP2pkh256BytesFromKeyHash(hash.Sum256(receiver)), transferAttr.NewOwnerPredicate)
+	require.Equal(t, tokens.TransactionTypeUpdateNFT, recTxs[1].Type)
+	updateAttr := &tokens.UpdateNonFungibleTokenAttributes{}
+	require.NoError(t, recTxs[1].UnmarshalAttributes(updateAttr))
+	require.EqualValues(t, 6, updateAttr.Counter)
+	require.EqualValues(t, data, updateAttr.Data)
+
+	// locked token is not sent
+	recTxs = nil
+	token = newNonFungibleToken(t, "AB", templates.NewP2pkh256BytesFromKey(ak.PubKey), 1, 0)
+	result, err = tw.TransferAndUpdateNFT(context.Background(), 1, token.ID, receiver, data, nil, defaultProof(ak), &PredicateInput{Argument: nil}, nil)
+	require.ErrorContains(t, err, "token is locked")
+	require.Nil(t, result)
+	require.Empty(t, recTxs)
+
+	// fee credit must cover both transactions
+	tw.maxFee = 60000
+	token = newNonFungibleToken(t, "AB", templates.NewP2pkh256BytesFromKey(ak.PubKey), 0, 0)
+	_, err = tw.TransferAndUpdateNFT(context.Background(), 1, token.ID, receiver, data, nil, defaultProof(ak), &PredicateInput{Argument: nil}, nil)
+	require.ErrorIs(t, err, ErrInsufficientFeeCredit)
+	require.Empty(t, recTxs)
+}
+
+func TestOwnerPredicateForAccount(t *testing.T) {
+	tw := initTestWallet(t, &mockTokensPartitionClient{})
+	_, _, err := tw.am.AddAccount()
+	require.NoError(t, err)
+
+	for _, accountNumber := range []uint64{1, 2} {
+		ak, err := tw.am.GetAccountKey(accountNumber - 1)
+		require.NoError(t, err)
+		predicate, err := tw.OwnerPredicateForAccount(accountNumber)
+		require.NoError(t, err)
+		require.EqualValues(t, templates.NewP2pkh256BytesFromKey(ak.PubKey), predicate)
+	}
+
+	_, err = tw.OwnerPredicateForAccount(0)
+	require.EqualError(t, err, "invalid account number: 0")
+
+	_, err = tw.OwnerPredicateForAccount(3)
+	require.Error(t, err)
+}
+
+func TestIsTokenOwnedByAccount(t *testing.T) {
+	pdr := tokenid.PDR()
+	var nft *sdktypes.NonFungibleToken
+	var ft *sdktypes.FungibleToken
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getNonFungibleToken: func(ctx context.Context, id sdktypes.TokenID) (*sdktypes.NonFungibleToken, error) {
+			return nft, nil
+		},
+		getFungibleToken: func(ctx context.Context, id sdktypes.TokenID) (*sdktypes.FungibleToken, error) {
+			return ft, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+	ak, err := tw.am.GetAccountKey(0)
+	require.NoError(t, err)
+	ownerPredicate := templates.NewP2pkh256BytesFromKey(ak.PubKey)
+
+	nft = newNonFungibleToken(t, "AB", ownerPredicate, 0, 0)
+	owned, err := tw.IsTokenOwnedByAccount(context.Background(), 1, nft.ID)
+	require.NoError(t, err)
+	require.True(t, owned)
+
+	nft = newNonFungibleToken(t, "AB", templates.AlwaysTrueBytes(), 0, 0)
+	owned, err = tw.IsTokenOwnedByAccount(context.Background(), 1, nft.ID)
+	require.NoError(t, err)
+	require.False(t, owned)
+
+	ft = &sdktypes.FungibleToken{ID: tokenid.NewFungibleTokenID(t), OwnerPredicate: ownerPredicate}
+	owned, err = tw.IsTokenOwnedByAccount(context.Background(), 1, ft.ID)
+	require.NoError(t, err)
+	require.True(t, owned)
+
+	nft = nil
+	_, err = tw.IsTokenOwnedByAccount(context.Background(), 1, tokenid.NewNonFungibleTokenID(t))
+	require.ErrorContains(t, err, "token not found")
+
+	_, err = tw.IsTokenOwnedByAccount(context.Background(), 1, tokenid.NewFungibleTokenTypeID(t))
+	require.ErrorContains(t, err, "invalid token ID")
+}
+
+func TestLockToken(t *testing.T) {
+	pdr := tokenid.PDR()
+	var token *sdktypes.NonFungibleToken
+	recTxs := make(map[string]*types.TransactionOrder)
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getNonFungibleToken: func(ctx context.Context, id sdktypes.TokenID) (*sdktypes.NonFungibleToken, error) {
+			return token, nil
+		},
+		sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+			recTxs[string(tx.GetUnitID())] = tx
+			return tx.Hash(crypto.SHA256)
+		},
+		getUnitsByOwnerID: func(ctx context.Context, ownerID hex.Bytes) ([]types.UnitID, error) {
+			// by default returns only the fee credit record id
+			fcrID, err := tokens.NewFeeCreditRecordIDFromPublicKeyHash(&pdr, types.ShardID{}, ownerID, fcrTimeout)
+			require.NoError(t, err)
+			return []types.UnitID{fcrID}, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+	ak, err := tw.am.GetAccountKey(0)
+	require.NoError(t, err)
+
+	// test token is already locked
+	token = newNonFungibleToken(t, "AB", templates.NewP2pkh256BytesFromKey(ak.PubKey), wallet.LockReasonManual, 0)
+	result, err := tw.LockToken(context.Background(), 1, token.ID, &PredicateInput{Argument: nil})
+	require.ErrorContains(t, err, "token is already locked")
+	require.Nil(t, result)
+
+	// test lock token ok
+	token = newNonFungibleToken(t, "AB", templates.NewP2pkh256BytesFromKey(ak.PubKey), 0, 0)
+	result, err = tw.LockToken(context.Background(), 1, token.ID, &PredicateInput{Argument: nil})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	tx, found := recTxs[string(token.ID)]
+	require.True(t, found)
+	require.EqualValues(t, token.ID, tx.GetUnitID())
+	require.Equal(t, tokens.TransactionTypeLockToken, tx.Type)
+	require.Nil(t, tx.StateLock)
+
+	// test lock token with state lock predicate
+	_, err = tw.LockTokenWithPredicate(context.Background(), 1, token.ID, &types.StateLock{}, &PredicateInput{Argument: nil})
+	require.ErrorContains(t, err, "state lock execution predicate must not be empty")
+	stateLock := wallet.NewP2PKHStateLock(ak.PubKeyHash.Sha256)
+	result, err = tw.LockTokenWithPredicate(context.Background(), 1, token.ID, stateLock, &PredicateInput{Argument: nil})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	tx = recTxs[string(token.ID)]
+	require.Equal(t, tokens.TransactionTypeLockToken, tx.Type)
+	require.Equal(t, stateLock, tx.StateLock)
+	require.EqualValues(t, templates.NewP2pkh256BytesFromKey(ak.PubKey), tx.StateLock.ExecutionPredicate)
+}
+
+func TestUnlockToken(t *testing.T) {
+	pdr := tokenid.PDR()
+	var token *sdktypes.NonFungibleToken
+	recTxs := make(map[string]*types.TransactionOrder)
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getNonFungibleToken: func(ctx context.Context, id sdktypes.TokenID) (*sdktypes.NonFungibleToken, error) {
+			return token, nil
+		},
+		sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+			recTxs[string(tx.GetUnitID())] = tx
+			return tx.Hash(crypto.SHA256)
+		},
+		getUnitsByOwnerID: func(ctx context.Context, ownerID hex.Bytes) ([]types.UnitID, error) {
+			// by default returns only the fee credit record id
+			fcrID, err := tokens.NewFeeCreditRecordIDFromPublicKeyHash(&pdr, types.ShardID{}, ownerID, fcrTimeout)
+			require.NoError(t, err)
+			return []types.UnitID{fcrID}, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+	ak, err := tw.am.GetAccountKey(0)
+	require.NoError(t, err)
+
+	// test token is already unlocked
+	token = newNonFungibleToken(t, "AB", templates.NewP2pkh256BytesFromKey(ak.PubKey), 0, 0)
+	result, err := tw.UnlockToken(context.Background(), 1, token.ID, &PredicateInput{Argument: nil})
+	require.ErrorContains(t, err, "token is already unlocked")
+	require.Nil(t, result)
+
+	// test unlock predicate can not be satisfied without the owner key
+	token = newNonFungibleToken(t, "AB", templates.NewP2pkh256BytesFromKey(ak.PubKey), wallet.LockReasonManual, 0)
+	result, err = tw.UnlockToken(context.Background(), 1, token.ID, &PredicateInput{Argument: nil})
+	require.ErrorContains(t, err, "unlock requires a signature of the owner key")
+	require.Nil(t, result)
+
+	// test unlock predicate can not be satisfied by a different key
+	_, _, err = tw.am.AddAccount()
+	require.NoError(t, err)
+	ak2, err := tw.am.GetAccountKey(1)
+	require.NoError(t, err)
+	result, err = tw.UnlockToken(context.Background(), 1, token.ID, defaultProof(ak2))
+	require.ErrorContains(t, err, "unlock predicate can not be satisfied by the given key")
+	require.Nil(t, result)
+
+	// test token with always false owner predicate can not be unlocked
+	token = newNonFungibleToken(t, "AB", templates.AlwaysFalseBytes(), wallet.LockReasonManual, 0)
+	result, err = tw.UnlockToken(context.Background(), 1, token.ID, &PredicateInput{Argument: []byte{1}})
+	require.ErrorContains(t, err, "unlock predicate is always false")
+	require.Nil(t, result)
+
+	// test unlock token ok
+	token = newNonFungibleToken(t, "AB", templates.NewP2pkh256BytesFromKey(ak.PubKey), wallet.LockReasonManual, 0)
+	result, err = tw.UnlockToken(context.Background(), 1, token.ID, defaultProof(ak))
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	tx, found := recTxs[string(token.ID)]
+	require.True(t, found)
+	require.EqualValues(t, token.ID, tx.GetUnitID())
+	require.Equal(t, tokens.TransactionTypeUnlockToken, tx.Type)
+}
+
+func TestSubmissionResult_FailedSubmissions(t *testing.T) {
+	newProof := func(status types.TxStatus) *types.TxRecordProof {
+		return &types.TxRecordProof{TxRecord: &types.TransactionRecord{ServerMetadata: &types.ServerMetadata{SuccessIndicator: status, ActualFee: 1}}}
+	}
+	unconfirmed := &txsubmitter.TxSubmission{UnitID: []byte{1}}
+	succeeded := &txsubmitter.TxSubmission{UnitID: []byte{2}, Proof: newProof(types.TxStatusSuccessful)}
+	failed := &txsubmitter.TxSubmission{UnitID: []byte{3}, Proof: newProof(types.TxStatusFailed)}
+	outOfGas := &txsubmitter.TxSubmission{UnitID: []byte{4}, Proof: newProof(types.TxErrOutOfGas)}
+
+	require.True(t, succeeded.Success())
+	require.False(t, unconfirmed.Success())
+	require.False(t, failed.Success())
+
+	res := &SubmissionResult{Submissions: []*txsubmitter.TxSubmission{unconfirmed, succeeded, failed, outOfGas}}
+	require.Equal(t, []*txsubmitter.TxSubmission{failed, outOfGas}, res.FailedSubmissions())
+	res = &SubmissionResult{Submissions: []*txsubmitter.TxSubmission{unconfirmed, succeeded}}
+	require.Empty(t, res.FailedSubmissions())
+}
+
+func TestUnlockTokens(t *testing.T) {
+	pdr := tokenid.PDR()
+	nfts := make(map[string]*sdktypes.NonFungibleToken)
+	recTxs := make(map[string]*types.TransactionOrder)
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getNonFungibleToken: func(ctx context.Context, id sdktypes.TokenID) (*sdktypes.NonFungibleToken, error) {
+			return nfts[string(id)], nil
+		},
+		sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+			recTxs[string(tx.GetUnitID())] = tx
+			return tx.Hash(crypto.SHA256)
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+	ak, err := tw.am.GetAccountKey(0)
+	require.NoError(t, err)
+	ownerPredicate := templates.NewP2pkh256BytesFromKey(ak.PubKey)
+
+	locked1 := newNonFungibleToken(t, "AB", ownerPredicate, wallet.LockReasonManual, 0)
+	locked2 := newNonFungibleToken(t, "AB", ownerPredicate, wallet.LockReasonManual, 0)
+	unlocked := newNonFungibleToken(t, "AB", ownerPredicate, 0, 0)
+	for _, token := range []*sdktypes.NonFungibleToken{locked1, locked2, unlocked} {
+		nfts[string(token.ID)] = token
+	}
+
+	// no tokens
+	_, err = tw.UnlockTokens(context.Background(), 1, nil, defaultProof(ak))
+	require.ErrorContains(t, err, "no tokens to unlock")
+
+	// all the tokens are already unlocked
+	_, err = tw.UnlockTokens(context.Background(), 1, []types.UnitID{unlocked.ID}, defaultProof(ak))
+	require.ErrorContains(t, err, "all the tokens are already unlocked")
+	require.Empty(t, recTxs)
+
+	// unlocked token is skipped, locked tokens are unlocked in one batch
+	result, err := tw.UnlockTokens(context.Background(), 1, []types.UnitID{locked1.ID, unlocked.ID, locked2.ID}, defaultProof(ak))
+	require.NoError(t, err)
+	require.Len(t, result.Submissions, 2)
+	require.Len(t, recTxs, 2)
+	for _, token := range []*sdktypes.NonFungibleToken{locked1, locked2} {
+		tx, found := recTxs[string(token.ID)]
+		require.True(t, found)
+		require.Equal(t, tokens.TransactionTypeUnlockToken, tx.Type)
+	}
+	require.NotContains(t, recTxs, string(unlocked.ID))
+}
+
+func TestEnsureFeeCreditForTxCount(t *testing.T) {
+	pdr := tokenid.PDR()
+	var fcr *sdktypes.FeeCreditRecord
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getFeeCreditRecordByOwnerID: func(ctx context.Context, ownerID []byte) (*sdktypes.FeeCreditRecord, error) {
+			return fcr, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+	tw.maxFee = 10
+	fcr = &sdktypes.FeeCreditRecord{ID: test.RandomBytes(33), Balance: 25}
+
+	require.NoError(t, tw.EnsureFeeCreditForTxCount(context.Background(), 1, 2))
+
+	err := tw.EnsureFeeCreditForTxCount(context.Background(), 1, 3)
+	require.ErrorIs(t, err, ErrInsufficientFeeCredit)
+	require.ErrorContains(t, err, "balance 25, required 30, shortfall 5")
+
+	err = tw.EnsureFeeCreditForTxCount(context.Background(), 1, math.MaxUint64)
+	require.ErrorIs(t, err, ErrInsufficientFeeCredit)
+	require.ErrorContains(t, err, "overflows")
+
+	require.ErrorContains(t, tw.EnsureFeeCreditForTxCount(context.Background(), 1, 0), "invalid transaction count: 0")
+	require.ErrorContains(t, tw.EnsureFeeCreditForTxCount(context.Background(), 0, 1), "invalid account number: 0")
+
+	fcr = nil
+	require.ErrorIs(t, tw.EnsureFeeCreditForTxCount(context.Background(), 1, 1), ErrNoFeeCredit)
+}
+
+func TestGetFeeCreditRecord(t *testing.T) {
+	pdr := tokenid.PDR()
+	var ownerIDs [][]byte
+	rpcClient := &mockTokensPartitionClient{pdr: &pdr}
+	tw := initTestWallet(t, rpcClient)
+
+	fcr, err := tw.GetFeeCreditRecord(context.Background(), 1)
+	require.NoError(t, err)
+	require.EqualValues(t, 100000, fcr.Balance)
+	require.EqualValues(t, 2, *fcr.Counter)
+
+	// deprecated GetFeeCredit delegates to GetFeeCreditRecord
+	rpcClient.getFeeCreditRecordByOwnerID = func(ctx context.Context, ownerID []byte) (*sdktypes.FeeCreditRecord, error) {
+		ownerIDs = append(ownerIDs, ownerID)
+		return nil, nil
+	}
+	fcr, err = tw.GetFeeCredit(context.Background(), fees.GetFeeCreditCmd{AccountIndex: 0})
+	require.NoError(t, err)
+	require.Nil(t, fcr)
+	acc, err := tw.am.GetAccountKey(0)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{acc.PubKeyHash.Sha256}, ownerIDs)
+
+	_, err = tw.GetFeeCreditRecord(context.Background(), 0)
+	require.ErrorContains(t, err, "invalid account number: 0")
+}
+
+func TestUnlockAllTokens(t *testing.T) {
+	pdr := tokenid.PDR()
+	var fts []*sdktypes.FungibleToken
+	var nfts []*sdktypes.NonFungibleToken
+	recTxs := make(map[string]*types.TransactionOrder)
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getFungibleTokens: func(ctx context.Context, ownerID []byte) ([]*sdktypes.FungibleToken, error) {
+			return fts, nil
+		},
+		getNonFungibleTokens: func(ctx context.Context, ownerID []byte) ([]*sdktypes.NonFungibleToken, error) {
+			return nfts, nil
+		},
+		sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+			recTxs[string(tx.GetUnitID())] = tx
+			return tx.Hash(crypto.SHA256)
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+	ak, err := tw.am.GetAccountKey(0)
+	require.NoError(t, err)
+	ownerPredicate := templates.NewP2pkh256BytesFromKey(ak.PubKey)
+
+	// no locked tokens
+	results, err := tw.UnlockAllTokens(context.Background(), 1, defaultProof(ak))
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	lockedFT := newFungibleToken(t, tokenid.NewFungibleTokenID(t), tokenid.NewFungibleTokenTypeID(t), "AB", 5, wallet.LockReasonManual)
+	lockedFT.OwnerPredicate = ownerPredicate
+	unlockedFT := newFungibleToken(t, tokenid.NewFungibleTokenID(t), tokenid.NewFungibleTokenTypeID(t), "AB", 5, 0)
+	unlockedFT.OwnerPredicate = ownerPredicate
+	fts = []*sdktypes.FungibleToken{lockedFT, unlockedFT}
+	lockedNFT := newNonFungibleToken(t, "NFT", ownerPredicate, wallet.LockReasonManual, 0)
+	neverUnlockableNFT := newNonFungibleToken(t, "NFT", templates.AlwaysFalseBytes(), wallet.LockReasonManual, 0)
+	nfts = []*sdktypes.NonFungibleToken{lockedNFT, neverUnlockableNFT}
+
+	locked, err := tw.ListLockedTokens(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, locked, 3)
+
+	results, err = tw.UnlockAllTokens(context.Background(), 1, defaultProof(ak))
+	require.ErrorContains(t, err, "1 token(s) could not be unlocked")
+	require.ErrorContains(t, err, fmt.Sprintf("token '%s' does not belong to account #1", neverUnlockableNFT.ID))
+	require.Len(t, results, 2)
+	require.Len(t, recTxs, 2)
+	for _, id := range []types.UnitID{lockedFT.ID, lockedNFT.ID} {
+		tx, found := recTxs[string(id)]
+		require.True(t, found)
+		require.Equal(t, tokens.TransactionTypeUnlockToken, tx.Type)
+	}
+}
+
+func TestSendFungibleByID(t *testing.T) {
+	t.Parallel()
+
+	pdr := tokenid.PDR()
+	token := newFungibleToken(t, test.RandomBytes(32), test.RandomBytes(32), "AB", 100, 0)
+	token.DecimalPlaces = 2
+
+	be := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getFungibleToken: func(ctx context.Context, id sdktypes.TokenID) (*sdktypes.FungibleToken, error) {
+			if bytes.Equal(id, token.ID) {
+				return token, nil
+			}
+			return nil, fmt.Errorf("not found")
+		},
+		getUnitsByOwnerID: func(ctx context.Context, ownerID hex.Bytes) ([]types.UnitID, error) {
+			// by default returns only the fee credit record id
+			fcrID, err := tokens.NewFeeCreditRecordIDFromPublicKeyHash(&pdr, types.ShardID{}, ownerID, fcrTimeout)
+			require.NoError(t, err)
+			return []types.UnitID{fcrID}, nil
+		},
+		sendTransaction: func(ctx context.Context, txs *types.TransactionOrder) ([]byte, error) {
+			return nil, nil
+		},
+	}
+
+	// Initialize the wallet
+	w := initTestWallet(t, be)
+	pk, err := w.am.GetPublicKey(0)
+	require.NoError(t, err)
+	token.OwnerPredicate = templates.NewP2pkh256BytesFromKey(pk)
+
+	// Test sending fungible token by ID
+	sub, err := w.SendFungibleByID(context.Background(), 1, token.ID, 50, nil, nil)
+	require.NoError(t, err)
+	// ensure it's a split
+	require.Equal(t, tokens.TransactionTypeSplitFT, sub.Submissions[0].Transaction.Type)
+	// the receiver gets a new token
+	created := sub.GetCreatedUnits()
+	require.Len(t, created, 1)
+	unitType, err := pdr.ExtractUnitType(created[0])
+	require.NoError(t, err)
+	require.EqualValues(t, tokens.FungibleTokenUnitType, unitType)
+	newTokenID, err := pdr.ComposeUnitID(types.ShardID{}, tokens.FungibleTokenUnitType, tokens.PrndSh(sub.Submissions[0].Transaction))
+	require.NoError(t, err)
+	require.Equal(t, newTokenID, created[0])
+	// the amount of the result is formatted with the decimals of the token type
+	require.EqualValues(t, 2, sub.DecimalPlaces)
+	require.Equal(t, "0.50", sub.FormatAmount(50))
+
+	sub, err = w.SendFungibleByID(context.Background(), 1, token.ID, 100, nil, nil)
+	require.NoError(t, err)
+	// ensure it's a transfer
+	require.Equal(t, tokens.TransactionTypeTransferFT, sub.Submissions[0].Transaction.Type)
+	require.Empty(t, sub.GetCreatedUnits())
+
+	// Test sending fungible token by ID with insufficient balance
+	_, err = w.SendFungibleByID(context.Background(), 1, token.ID, 200, nil, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "insufficient FT value")
+
+	// Test sending fungible token by ID with invalid account number
+	_, err = w.SendFungibleByID(context.Background(), 0, token.ID, 50, nil, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid account number")
+}
+
+func TestBurnFungibleToken(t *testing.T) {
+	t.Parallel()
+
+	pdr := tokenid.PDR()
+	token := newFungibleToken(t, test.RandomBytes(32), test.RandomBytes(32), "AB", 100, 0)
+	var recTxs []*types.TransactionOrder
+	be := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getFungibleToken: func(ctx context.Context, id sdktypes.TokenID) (*sdktypes.FungibleToken, error) {
+			if bytes.Equal(id, token.ID) {
+				return token, nil
+			}
+			return nil, fmt.Errorf("not found")
+		},
+		sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+			recTxs = append(recTxs, tx)
+			return tx.Hash(crypto.SHA256)
+		},
+	}
+	w := initTestWallet(t, be)
+	pk, err := w.am.GetPublicKey(0)
+	require.NoError(t, err)
+
+	// token is not owned by the account
+	token.OwnerPredicate = templates.NewP2pkh256BytesFromKey(test.RandomBytes(33))
+	_, err = w.BurnFungibleToken(context.Background(), 1, token.ID, nil, nil)
+	require.ErrorContains(t, err, "does not belong to account #1")
+
+	// token is locked
+	token.OwnerPredicate = templates.NewP2pkh256BytesFromKey(pk)
+	token.LockStatus = 1
+	_, err = w.BurnFungibleToken(context.Background(), 1, token.ID, nil, nil)
+	require.EqualError(t, err, "token is locked")
+	require.Empty(t, recTxs)
+
+	token.LockStatus = 0
+	result, err := w.BurnFungibleToken(context.Background(), 1, token.ID, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, result.Submissions, 1)
+	require.Len(t, recTxs, 1)
+	tx := recTxs[0]
+	require.Equal(t, tokens.TransactionTypeBurnFT, tx.Type)
+	require.EqualValues(t, token.ID, tx.GetUnitID())
+	require.NotEmpty(t, tx.FeeProof)
+	attr := &tokens.BurnFungibleTokenAttributes{}
+	require.NoError(t, tx.UnmarshalAttributes(attr))
+	require.EqualValues(t, token.TypeID, attr.TypeID)
+	require.EqualValues(t, 100, attr.Value)
+	require.EqualValues(t, token.Counter, attr.Counter)
+	require.Empty(t, attr.TargetTokenID)
+}
+
+func TestJoinFungibleTokens(t *testing.T) {
+	t.Parallel()
+
+	pdr := tokenid.PDR()
+	typeID := tokenid.NewFungibleTokenTypeID(t)
+	tokenz := make(map[string]*sdktypes.FungibleToken)
+	executed := make(map[string]*types.TransactionOrder)
+	var recTxs []*types.TransactionOrder
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getFungibleToken: func(ctx context.Context, id sdktypes.TokenID) (*sdktypes.FungibleToken, error) {
+			return tokenz[string(id)], nil
+		},
+		sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+			recTxs = append(recTxs, tx)
+			txHash, err := tx.Hash(crypto.SHA256)
+			executed[string(txHash)] = tx
+			return txHash, err
+		},
+		getTransactionProof: func(ctx context.Context, txHash hex.Bytes) (*types.TxRecordProof, error) {
+			tx, ok := executed[string(txHash)]
+			if !ok {
+				return nil, nil
+			}
+			txBytes, err := tx.MarshalCBOR()
+			if err != nil {
+				return nil, err
+			}
+			return &types.TxRecordProof{TxRecord: &types.TransactionRecord{Version: 1, TransactionOrder: txBytes, ServerMetadata: &types.ServerMetadata{ActualFee: 1, SuccessIndicator: types.TxStatusSuccessful}}}, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+	tw.maxFee = 10
+	ak, err := tw.am.GetAccountKey(0)
+	require.NoError(t, err)
+	ownerPredicate := templates.NewP2pkh256BytesFromKey(ak.PubKey)
+
+	newToken := func(typeID sdktypes.TokenTypeID, amount uint64, owner []byte, lockStatus uint64) *sdktypes.FungibleToken {
+		token := newFungibleToken(t, tokenid.NewFungibleTokenID(t), typeID, "AB", amount, lockStatus)
+		token.OwnerPredicate = owner
+		tokenz[string(token.ID)] = token
+		return token
+	}
+	ft1 := newToken(typeID, 10, ownerPredicate, 0)
+	ft2 := newToken(typeID, 20, ownerPredicate, 0)
+	ft3 := newToken(typeID, 30, ownerPredicate, 0)
+
+	t.Run("invalid input", func(t *testing.T) {
+		otherType := newToken(tokenid.NewFungibleTokenTypeID(t), 5, ownerPredicate, 0)
+		notOwned := newToken(typeID, 5, templates.NewP2pkh256BytesFromKey(test.RandomBytes(33)), 0)
+		locked := newToken(typeID, 5, ownerPredicate, 1)
+		tests := []struct {
+			name     string
+			tokenIDs []sdktypes.TokenID
+			wantErr  string
+		}{
+			{name: "single token", tokenIDs: []sdktypes.TokenID{ft1.ID}, wantErr: "at least two tokens are required to join, got 1"},
+			{name: "duplicate token", tokenIDs: []sdktypes.TokenID{ft1.ID, ft2.ID, ft1.ID}, wantErr: "duplicate token"},
+			{name: "unknown token", tokenIDs: []sdktypes.TokenID{ft1.ID, tokenid.NewFungibleTokenID(t)}, wantErr: "token not found"},
+			{name: "different type", tokenIDs: []sdktypes.TokenID{ft1.ID, otherType.ID}, wantErr: "expected type"},
+			{name: "not owned", tokenIDs: []sdktypes.TokenID{ft1.ID, notOwned.ID}, wantErr: "does not belong to account #1"},
+			{name: "locked", tokenIDs: []sdktypes.TokenID{ft1.ID, locked.ID}, wantErr: "is locked"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := tw.JoinFungibleTokens(context.Background(), 1, typeID, tt.tokenIDs)
+				require.ErrorContains(t, err, tt.wantErr)
+				require.Empty(t, recTxs)
+			})
+		}
+	})
+
+	t.Run("tokens are joined into the first token", func(t *testing.T) {
+		result, err := tw.JoinFungibleTokens(context.Background(), 1, typeID, []sdktypes.TokenID{ft2.ID, ft1.ID, ft3.ID})
+		require.NoError(t, err)
+		require.EqualValues(t, ft2.ID, result.GetUnit())
+		require.EqualValues(t, 1, result.AccountNumber)
+		// lock, 2 burns and join
+		require.Len(t, recTxs, 4)
+		require.EqualValues(t, 4, result.FeeSum)
+		require.Equal(t, tokens.TransactionTypeLockToken, recTxs[0].Type)
+		require.Equal(t, tokens.TransactionTypeBurnFT, recTxs[1].Type)
+		require.EqualValues(t, ft1.ID, recTxs[1].GetUnitID())
+		require.Equal(t, tokens.TransactionTypeBurnFT, recTxs[2].Type)
+		require.EqualValues(t, ft3.ID, recTxs[2].GetUnitID())
+		require.Equal(t, tokens.TransactionTypeJoinFT, recTxs[3].Type)
+		require.EqualValues(t, ft2.ID, recTxs[3].GetUnitID())
+	})
+}
+
+func TestCollectDust_MultipleTypes(t *testing.T) {
+	t.Parallel()
+
+	pdr := tokenid.PDR()
+	typeID1 := tokenid.NewFungibleTokenTypeID(t)
+	typeID2 := tokenid.NewFungibleTokenTypeID(t)
+	executed := make(map[string]*types.TransactionOrder)
+	var recTxs []*types.TransactionOrder
+	fcrCalls := 0
+	ucRound := uint64(100)
+	var allTokens []*sdktypes.FungibleToken
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getFungibleTokens: func(ctx context.Context, ownerID []byte) ([]*sdktypes.FungibleToken, error) {
+			return allTokens, nil
+		},
+		getFeeCreditRecordByOwnerID: func(ctx context.Context, ownerID []byte) (*sdktypes.FeeCreditRecord, error) {
+			fcrCalls++
+			return &sdktypes.FeeCreditRecord{ID: tokenid.NewFungibleTokenID(t), Balance: 100000}, nil
+		},
+		getRoundInfo: func(ctx context.Context) (*sdktypes.RoundInfo, error) {
+			return &sdktypes.RoundInfo{RoundNumber: 5}, nil
+		},
+		sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+			recTxs = append(recTxs, tx)
+			txHash, err := tx.Hash(crypto.SHA256)
+			executed[string(txHash)] = tx
+			return txHash, err
+		},
+		getTransactionProof: func(ctx context.Context, txHash hex.Bytes) (*types.TxRecordProof, error) {
+			tx, ok := executed[string(txHash)]
+			if !ok {
+				return nil, nil
+			}
+			txBytes, err := tx.MarshalCBOR()
+			if err != nil {
+				return nil, err
+			}
+			// every confirmed transaction advances the round of the unicity certificate
+			ucRound++
+			ucBytes, err := types.Cbor.Marshal(&types.UnicityCertificate{Version: 1, InputRecord: &types.InputRecord{Version: 1, RoundNumber: ucRound}})
+			if err != nil {
+				return nil, err
+			}
+			return &types.TxRecordProof{
+				TxRecord: &types.TransactionRecord{Version: 1, TransactionOrder: txBytes, ServerMetadata: &types.ServerMetadata{ActualFee: 1, SuccessIndicator: types.TxStatusSuccessful}},
+				TxProof:  &types.TxProof{Version: 1, UnicityCertificate: ucBytes},
+			}, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+	tw.maxFee = 10
+	ak, err := tw.am.GetAccountKey(0)
+	require.NoError(t, err)
+	for _, typeID := range []sdktypes.TokenTypeID{typeID1, typeID1, typeID2, typeID2} {
+		token := newFungibleToken(t, tokenid.NewFungibleTokenID(t), typeID, "AB", 10, 0)
+		token.OwnerPredicate = templates.NewP2pkh256BytesFromKey(ak.PubKey)
+		allTokens = append(allTokens, token)
+	}
+
+	results, err := tw.CollectDust(context.Background(), 1, nil, defaultProof(ak), nil)
+	require.NoError(t, err)
+	require.Len(t, results[0], 2)
+	for _, res := range results[0] {
+		require.EqualValues(t, 3, res.FeeSum)
+		require.NotNil(t, res.GetUnit())
+	}
+	// single fee credit check for both types
+	require.Equal(t, 1, fcrCalls)
+	// lock, burn and join for both types
+	require.Len(t, recTxs, 6)
+	// first transaction uses the round number fetched from the node, the following ones
+	// the round of the unicity certificate of the previous confirmed transaction
+	require.EqualValues(t, 5+txTimeoutRoundCount, recTxs[0].Timeout())
+	for i := 1; i < len(recTxs); i++ {
+		require.EqualValues(t, 100+uint64(i)+txTimeoutRoundCount, recTxs[i].Timeout())
+	}
+}
+
+func TestCollectDust_MaxTokensPerSwap(t *testing.T) {
+	t.Parallel()
+
+	pdr := tokenid.PDR()
+	typeID := tokenid.NewFungibleTokenTypeID(t)
+	executed := make(map[string]*types.TransactionOrder)
+	var recTxs []*types.TransactionOrder
+	var allTokens []*sdktypes.FungibleToken
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getFungibleTokens: func(ctx context.Context, ownerID []byte) ([]*sdktypes.FungibleToken, error) {
+			return allTokens, nil
+		},
+		sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+			recTxs = append(recTxs, tx)
+			txHash, err := tx.Hash(crypto.SHA256)
+			executed[string(txHash)] = tx
+			return txHash, err
+		},
+		getTransactionProof: func(ctx context.Context, txHash hex.Bytes) (*types.TxRecordProof, error) {
+			tx, ok := executed[string(txHash)]
+			if !ok {
+				return nil, nil
+			}
+			txBytes, err := tx.MarshalCBOR()
+			if err != nil {
+				return nil, err
+			}
+			return &types.TxRecordProof{
+				TxRecord: &types.TransactionRecord{Version: 1, TransactionOrder: txBytes, ServerMetadata: &types.ServerMetadata{ActualFee: 1, SuccessIndicator: types.TxStatusSuccessful}},
+				TxProof:  &types.TxProof{Version: 1},
+			}, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+	tw.maxFee = 10
+	ak, err := tw.am.GetAccountKey(0)
+	require.NoError(t, err)
+	for i := 0; i < 4; i++ {
+		token := newFungibleToken(t, tokenid.NewFungibleTokenID(t), typeID, "AB", 10, 0)
+		token.OwnerPredicate = templates.NewP2pkh256BytesFromKey(ak.PubKey)
+		allTokens = append(allTokens, token)
+	}
+
+	// three tokens are joined into the first one, a token per swap
+	var progress [][3]int
+	results, err := tw.CollectDust(context.Background(), 1, nil, defaultProof(ak), nil, WithMaxTokensPerSwap(1), WithProgress(func(accountNumber uint64, swapsDone, swapsTotal int) {
+		progress = append(progress, [3]int{int(accountNumber), swapsDone, swapsTotal})
+	}))
+	require.NoError(t, err)
+	require.Len(t, results[0], 3)
+	require.Equal(t, [][3]int{{1, 1, 3}, {1, 2, 3}, {1, 3, 3}}, progress)
+	for _, res := range results[0] {
+		require.EqualValues(t, 1, res.AccountNumber)
+		// lock, burn and join
+		require.EqualValues(t, 3, res.FeeSum)
+		require.EqualValues(t, allTokens[0].ID, res.GetUnit())
+	}
+	require.Len(t, recTxs, 9)
+	for i, tx := range recTxs {
+		switch i % 3 {
+		case 0:
+			require.Equal(t, tokens.TransactionTypeLockToken, tx.Type)
+		case 1:
+			require.Equal(t, tokens.TransactionTypeBurnFT, tx.Type)
+		case 2:
+			require.Equal(t, tokens.TransactionTypeJoinFT, tx.Type)
+		}
+	}
+}
+
+func TestSendFungibleFromTokens(t *testing.T) {
+	t.Parallel()
+
+	pdr := tokenid.PDR()
+	typeID := test.RandomBytes(32)
+	tokenz := make(map[string]*sdktypes.FungibleToken)
+	var recTxs []*types.TransactionOrder
+	be := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getFungibleToken: func(ctx context.Context, id sdktypes.TokenID) (*sdktypes.FungibleToken, error) {
+			return tokenz[string(id)], nil
 		},
 		getUnitsByOwnerID: func(ctx context.Context, ownerID hex.Bytes) ([]types.UnitID, error) {
-			// by default returns only the fee credit record id
 			fcrID, err := tokens.NewFeeCreditRecordIDFromPublicKeyHash(&pdr, types.ShardID{}, ownerID, fcrTimeout)
 			require.NoError(t, err)
 			return []types.UnitID{fcrID}, nil
 		},
+		sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+			recTxs = append(recTxs, tx)
+			return nil, nil
+		},
+	}
+	w := initTestWallet(t, be)
+	pk, err := w.am.GetPublicKey(0)
+	require.NoError(t, err)
+	ownerPredicate := templates.NewP2pkh256BytesFromKey(pk)
+
+	t1 := newFungibleToken(t, test.RandomBytes(32), typeID, "AB", 30, 0)
+	t2 := newFungibleToken(t, test.RandomBytes(32), typeID, "AB", 50, 0)
+	t3 := newFungibleToken(t, test.RandomBytes(32), typeID, "AB", 40, 0)
+	locked := newFungibleToken(t, test.RandomBytes(32), typeID, "AB", 40, 1)
+	otherType := newFungibleToken(t, test.RandomBytes(32), test.RandomBytes(32), "CD", 40, 0)
+	foreign := newFungibleToken(t, test.RandomBytes(32), typeID, "AB", 40, 0)
+	for _, token := range []*sdktypes.FungibleToken{t1, t2, t3, locked, otherType, foreign} {
+		token.OwnerPredicate = ownerPredicate
+		tokenz[string(token.ID)] = token
+	}
+	foreign.OwnerPredicate = templates.NewP2pkh256BytesFromKeyHash(test.RandomBytes(32))
+
+	t.Run("tokens are spent in the given order, last one is split", func(t *testing.T) {
+		recTxs = nil
+		res, err := w.SendFungibleFromTokens(context.Background(), 1, []sdktypes.TokenID{t1.ID, t2.ID, t3.ID}, 60, nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, res.Submissions, 2)
+		require.Len(t, recTxs, 2)
+		require.Equal(t, tokens.TransactionTypeTransferFT, recTxs[0].Type)
+		require.EqualValues(t, t1.ID, recTxs[0].GetUnitID())
+		require.Equal(t, tokens.TransactionTypeSplitFT, recTxs[1].Type)
+		require.EqualValues(t, t2.ID, recTxs[1].GetUnitID())
+		attr := &tokens.SplitFungibleTokenAttributes{}
+		require.NoError(t, recTxs[1].UnmarshalAttributes(attr))
+		require.EqualValues(t, 30, attr.TargetValue)
+	})
+
+	t.Run("invalid inputs", func(t *testing.T) {
+		recTxs = nil
+		_, err := w.SendFungibleFromTokens(context.Background(), 1, []sdktypes.TokenID{t1.ID, t2.ID}, 81, nil, nil, nil)
+		require.ErrorContains(t, err, "insufficient value of input tokens: got 80, need 81")
+
+		_, err = w.SendFungibleFromTokens(context.Background(), 1, []sdktypes.TokenID{t1.ID, locked.ID}, 10, nil, nil, nil)
+		require.ErrorContains(t, err, "is locked")
+
+		_, err = w.SendFungibleFromTokens(context.Background(), 1, []sdktypes.TokenID{t1.ID, otherType.ID}, 10, nil, nil, nil)
+		require.ErrorContains(t, err, "expected type")
+
+		_, err = w.SendFungibleFromTokens(context.Background(), 1, []sdktypes.TokenID{t1.ID, t1.ID}, 10, nil, nil, nil)
+		require.ErrorContains(t, err, "duplicate input token")
+
+		_, err = w.SendFungibleFromTokens(context.Background(), 1, []sdktypes.TokenID{foreign.ID}, 10, nil, nil, nil)
+		require.ErrorContains(t, err, "does not belong to account #1")
+
+		_, err = w.SendFungibleFromTokens(context.Background(), 1, nil, 10, nil, nil, nil)
+		require.ErrorContains(t, err, "no input tokens")
+
+		_, err = w.SendFungibleFromTokens(context.Background(), 1, []sdktypes.TokenID{t1.ID}, 0, nil, nil, nil)
+		require.ErrorContains(t, err, "invalid amount")
+		require.Empty(t, recTxs)
+	})
+}
+
+func TestGetTokenHistory(t *testing.T) {
+	pdr := tokenid.PDR()
+	tokenID := tokenid.NewNonFungibleTokenID(t)
+	otherID := tokenid.NewNonFungibleTokenID(t)
+	// unit ID of the transaction executed in the round
+	txs := map[uint64]types.UnitID{3: tokenID, 4: otherID, 7: tokenID, 12000: tokenID}
+	var scanned [][2]uint64
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getRoundInfo: func(ctx context.Context) (*sdktypes.RoundInfo, error) {
+			return &sdktypes.RoundInfo{RoundNumber: 15000}, nil
+		},
+		scanBlocks: func(ctx context.Context, fromRound, toRound uint64, filter func(*types.TransactionOrder) bool) ([]*types.TxRecordProof, error) {
+			scanned = append(scanned, [2]uint64{fromRound, toRound})
+			var proofs []*types.TxRecordProof
+			for round := fromRound; round <= toRound; round++ {
+				unitID, ok := txs[round]
+				if !ok || !filter(&types.TransactionOrder{Payload: types.Payload{UnitID: unitID}}) {
+					continue
+				}
+				// actual fee is used to identify the round of the transaction
+				proofs = append(proofs, &types.TxRecordProof{TxRecord: &types.TransactionRecord{ServerMetadata: &types.ServerMetadata{ActualFee: round}}})
+			}
+			return proofs, nil
+		},
 	}
 	tw := initTestWallet(t, rpcClient)
-	tok := newNonFungibleToken(t, "AB", nil, 0, 0)
-	tokenz[string(tok.ID)] = tok
 
-	ak, err := tw.am.GetAccountKey(0)
+	rounds := func(proofs []*types.TxRecordProof) []uint64 {
+		var res []uint64
+		for _, p := range proofs {
+			res = append(res, p.ActualFee())
+		}
+		return res
+	}
+
+	proofs, err := tw.GetTokenHistory(context.Background(), tokenID, 1)
 	require.NoError(t, err)
+	require.Equal(t, []uint64{3, 7, 12000}, rounds(proofs))
+	require.Equal(t, [][2]uint64{{1, 10000}, {10001, 15000}}, scanned)
 
-	// test data, counter and predicate inputs are submitted correctly
-	data := test.RandomBytes(64)
-	result, err := tw.UpdateNFTData(context.Background(), 1, tok.ID, data, &PredicateInput{Argument: nil}, []*PredicateInput{{AccountKey: ak}})
+	scanned = nil
+	proofs, err = tw.GetTokenHistory(context.Background(), tokenID, 5)
 	require.NoError(t, err)
-	require.NotNil(t, result)
-	tx, found := recTxs[string(tok.ID)]
-	require.True(t, found)
-	require.EqualValues(t, tok.ID, tx.GetUnitID())
-	require.Equal(t, tokens.TransactionTypeUpdateNFT, tx.Type)
+	require.Equal(t, []uint64{7, 12000}, rounds(proofs))
 
-	// test that locked token tx is not sent
-	lockedToken := newNonFungibleToken(t, "AB", nil, 1, 0)
-	tokenz[string(tok.ID)] = lockedToken
-	result, err = tw.UpdateNFTData(context.Background(), 1, tok.ID, data, &PredicateInput{Argument: nil}, []*PredicateInput{{AccountKey: ak}})
-	require.ErrorContains(t, err, "token is locked")
-	require.Nil(t, result)
+	// since is after the latest round
+	scanned = nil
+	proofs, err = tw.GetTokenHistory(context.Background(), tokenID, 20000)
+	require.NoError(t, err)
+	require.Empty(t, proofs)
+	require.Empty(t, scanned)
 }
 
-func TestLockToken(t *testing.T) {
+func TestGetAccountHistory(t *testing.T) {
 	pdr := tokenid.PDR()
-	var token *sdktypes.NonFungibleToken
-	recTxs := make(map[string]*types.TransactionOrder)
+	ftID := tokenid.NewFungibleTokenID(t)
+	nftID := tokenid.NewNonFungibleTokenID(t)
+	typeID := tokenid.NewFungibleTokenTypeID(t)
+	nftTypeID := tokenid.NewNonFungibleTokenTypeID(t)
+	otherID := tokenid.NewNonFungibleTokenID(t)
+	var ownedUnits []types.UnitID
+	// unit ID of the transaction executed in the round
+	txs := map[uint64]types.UnitID{1: typeID, 2: nftTypeID, 3: ftID, 4: otherID, 5: nftID, 6: ftID}
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getRoundInfo: func(ctx context.Context) (*sdktypes.RoundInfo, error) {
+			return &sdktypes.RoundInfo{RoundNumber: 10}, nil
+		},
+		getUnitsByOwnerID: func(ctx context.Context, ownerID hex.Bytes) ([]types.UnitID, error) {
+			return ownedUnits, nil
+		},
+		getFungibleTokenTypes: func(ctx context.Context, pubKey sdktypes.PubKey) ([]*sdktypes.FungibleTokenType, error) {
+			return []*sdktypes.FungibleTokenType{{ID: typeID}}, nil
+		},
+		getNonFungibleTokenTypes: func(ctx context.Context, pubKey sdktypes.PubKey) ([]*sdktypes.NonFungibleTokenType, error) {
+			return []*sdktypes.NonFungibleTokenType{{ID: nftTypeID}}, nil
+		},
+		scanBlocks: func(ctx context.Context, fromRound, toRound uint64, filter func(*types.TransactionOrder) bool) ([]*types.TxRecordProof, error) {
+			var proofs []*types.TxRecordProof
+			for round := fromRound; round <= toRound; round++ {
+				unitID, ok := txs[round]
+				if !ok || !filter(&types.TransactionOrder{Payload: types.Payload{UnitID: unitID}}) {
+					continue
+				}
+				// actual fee is used to identify the round of the transaction
+				proofs = append(proofs, &types.TxRecordProof{TxRecord: &types.TransactionRecord{ServerMetadata: &types.ServerMetadata{ActualFee: round}}})
+			}
+			return proofs, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+	rounds := func(proofs []*types.TxRecordProof) []uint64 {
+		var res []uint64
+		for _, p := range proofs {
+			res = append(res, p.ActualFee())
+		}
+		return res
+	}
+
+	// the fee credit record owned by the account is not part of the token history
+	fcrID, err := tokens.NewFeeCreditRecordIDFromPublicKeyHash(&pdr, types.ShardID{}, test.RandomBytes(32), fcrTimeout)
+	require.NoError(t, err)
+	ownedUnits = []types.UnitID{ftID, nftID, fcrID}
+	proofs, err := tw.GetAccountHistory(context.Background(), 1, 1)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1, 2, 3, 5, 6}, rounds(proofs))
+
+	proofs, err = tw.GetAccountHistory(context.Background(), 1, 4)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{5, 6}, rounds(proofs))
+
+	_, err = tw.GetAccountHistory(context.Background(), 0, 1)
+	require.ErrorContains(t, err, "invalid account number: 0")
+}
+
+func TestCheckMaxFee(t *testing.T) {
+	pdr := tokenid.PDR()
+	tokenz := make(map[string]*sdktypes.NonFungibleToken)
+	actualFee := uint64(1)
 	rpcClient := &mockTokensPartitionClient{
 		pdr: &pdr,
 		getNonFungibleToken: func(ctx context.Context, id sdktypes.TokenID) (*sdktypes.NonFungibleToken, error) {
-			return token, nil
+			return tokenz[string(id)], nil
 		},
 		sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
-			recTxs[string(tx.GetUnitID())] = tx
 			return tx.Hash(crypto.SHA256)
 		},
-		getUnitsByOwnerID: func(ctx context.Context, ownerID hex.Bytes) ([]types.UnitID, error) {
-			// by default returns only the fee credit record id
-			fcrID, err := tokens.NewFeeCreditRecordIDFromPublicKeyHash(&pdr, types.ShardID{}, ownerID, fcrTimeout)
-			require.NoError(t, err)
-			return []types.UnitID{fcrID}, nil
+		getTransactionProof: func(ctx context.Context, txHash hex.Bytes) (*types.TxRecordProof, error) {
+			return &types.TxRecordProof{TxRecord: &types.TransactionRecord{ServerMetadata: &types.ServerMetadata{ActualFee: actualFee, SuccessIndicator: types.TxStatusSuccessful}}}, nil
 		},
 	}
 	tw := initTestWallet(t, rpcClient)
+	tw.maxFee = 10
+	tw.confirmTx = true
 	ak, err := tw.am.GetAccountKey(0)
 	require.NoError(t, err)
+	nft := newNonFungibleToken(t, "AB", templates.NewP2pkh256BytesFromKey(ak.PubKey), 0, 0)
+	tokenz[string(nft.ID)] = nft
 
-	// test token is already locked
-	token = newNonFungibleToken(t, "AB", templates.NewP2pkh256BytesFromKey(ak.PubKey), wallet.LockReasonManual, 0)
-	result, err := tw.LockToken(context.Background(), 1, token.ID, &PredicateInput{Argument: nil})
-	require.ErrorContains(t, err, "token is already locked")
-	require.Nil(t, result)
+	// nothing observed yet
+	ok, observedMax := tw.CheckMaxFee()
+	require.True(t, ok)
+	require.Zero(t, observedMax)
 
-	// test lock token ok
-	token = newNonFungibleToken(t, "AB", templates.NewP2pkh256BytesFromKey(ak.PubKey), 0, 0)
-	result, err = tw.LockToken(context.Background(), 1, token.ID, &PredicateInput{Argument: nil})
+	_, err = tw.TransferNFT(context.Background(), 1, nft.ID, nil, nil, nil)
 	require.NoError(t, err)
-	require.NotNil(t, result)
-	tx, found := recTxs[string(token.ID)]
-	require.True(t, found)
-	require.EqualValues(t, token.ID, tx.GetUnitID())
-	require.Equal(t, tokens.TransactionTypeLockToken, tx.Type)
+	ok, observedMax = tw.CheckMaxFee()
+	require.True(t, ok)
+	require.EqualValues(t, 1, observedMax)
+
+	// transaction used up the whole max fee
+	actualFee = 10
+	_, err = tw.TransferNFT(context.Background(), 1, nft.ID, nil, nil, nil)
+	require.NoError(t, err)
+	ok, observedMax = tw.CheckMaxFee()
+	require.False(t, ok)
+	require.EqualValues(t, 10, observedMax)
 }
 
-func TestUnlockToken(t *testing.T) {
+func TestDryRun(t *testing.T) {
 	pdr := tokenid.PDR()
-	var token *sdktypes.NonFungibleToken
-	recTxs := make(map[string]*types.TransactionOrder)
+	tokenz := make(map[string]*sdktypes.NonFungibleToken)
+	var recTxs []*types.TransactionOrder
 	rpcClient := &mockTokensPartitionClient{
 		pdr: &pdr,
 		getNonFungibleToken: func(ctx context.Context, id sdktypes.TokenID) (*sdktypes.NonFungibleToken, error) {
-			return token, nil
+			return tokenz[string(id)], nil
 		},
 		sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
-			recTxs[string(tx.GetUnitID())] = tx
+			recTxs = append(recTxs, tx)
 			return tx.Hash(crypto.SHA256)
 		},
-		getUnitsByOwnerID: func(ctx context.Context, ownerID hex.Bytes) ([]types.UnitID, error) {
-			// by default returns only the fee credit record id
-			fcrID, err := tokens.NewFeeCreditRecordIDFromPublicKeyHash(&pdr, types.ShardID{}, ownerID, fcrTimeout)
-			require.NoError(t, err)
-			return []types.UnitID{fcrID}, nil
-		},
 	}
 	tw := initTestWallet(t, rpcClient)
+	tw.maxFee = 10
+	tw.confirmTx = true
+	WithDryRun()(tw)
 	ak, err := tw.am.GetAccountKey(0)
 	require.NoError(t, err)
 
-	// test token is already unlocked
-	token = newNonFungibleToken(t, "AB", templates.NewP2pkh256BytesFromKey(ak.PubKey), 0, 0)
-	result, err := tw.UnlockToken(context.Background(), 1, token.ID, &PredicateInput{Argument: nil})
-	require.ErrorContains(t, err, "token is already unlocked")
-	require.Nil(t, result)
+	nft1 := newNonFungibleToken(t, "AB", templates.NewP2pkh256BytesFromKey(ak.PubKey), 0, 0)
+	nft2 := newNonFungibleToken(t, "AB", templates.NewP2pkh256BytesFromKey(ak.PubKey), 0, 0)
+	tokenz[string(nft1.ID)] = nft1
+	tokenz[string(nft2.ID)] = nft2
 
-	// test unlock token ok
-	token = newNonFungibleToken(t, "AB", templates.NewP2pkh256BytesFromKey(ak.PubKey), wallet.LockReasonManual, 0)
-	result, err = tw.UnlockToken(context.Background(), 1, token.ID, &PredicateInput{Argument: nil})
-	require.NoError(t, err)
-	require.NotNil(t, result)
-	tx, found := recTxs[string(token.ID)]
-	require.True(t, found)
-	require.EqualValues(t, token.ID, tx.GetUnitID())
-	require.Equal(t, tokens.TransactionTypeUnlockToken, tx.Type)
-}
+	t.Run("single transaction is built but not sent", func(t *testing.T) {
+		result, err := tw.TransferNFT(context.Background(), 1, nft1.ID, nil, nil, nil)
+		require.NoError(t, err)
+		require.True(t, result.DryRun)
+		require.Len(t, result.Submissions, 1)
+		require.NotNil(t, result.Submissions[0].Transaction.AuthProof)
+		require.False(t, result.Submissions[0].Confirmed())
+		require.Zero(t, result.FeeSum)
+		require.EqualValues(t, 10, result.MaxFeeSum())
+		require.Empty(t, recTxs)
+	})
 
-func TestSendFungibleByID(t *testing.T) {
-	t.Parallel()
+	t.Run("batch is built but not sent", func(t *testing.T) {
+		results, err := tw.TransferNFTs(context.Background(), 1, []NFTTransfer{{TokenID: nft1.ID}, {TokenID: nft2.ID}})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		for _, res := range results {
+			require.True(t, res.DryRun)
+			require.EqualValues(t, 10, res.MaxFeeSum())
+		}
+		require.Empty(t, recTxs)
+	})
 
-	pdr := tokenid.PDR()
-	token := newFungibleToken(t, test.RandomBytes(32), test.RandomBytes(32), "AB", 100, 0)
+	t.Run("dust collection and resume are not supported", func(t *testing.T) {
+		_, err := tw.CollectDust(context.Background(), 1, nil, nil, nil)
+		require.ErrorIs(t, err, ErrDryRunNotSupported)
+		_, err = tw.ResumeTokenOperations(context.Background())
+		require.ErrorIs(t, err, ErrDryRunNotSupported)
+	})
+}
 
-	be := &mockTokensPartitionClient{
+func TestWithTimeoutRounds(t *testing.T) {
+	pdr := tokenid.PDR()
+	tokenz := make(map[string]*sdktypes.NonFungibleToken)
+	var recTxs []*types.TransactionOrder
+	rpcClient := &mockTokensPartitionClient{
 		pdr: &pdr,
-		getFungibleToken: func(ctx context.Context, id sdktypes.TokenID) (*sdktypes.FungibleToken, error) {
-			if bytes.Equal(id, token.ID) {
-				return token, nil
-			}
-			return nil, fmt.Errorf("not found")
+		getNonFungibleToken: func(ctx context.Context, id sdktypes.TokenID) (*sdktypes.NonFungibleToken, error) {
+			return tokenz[string(id)], nil
 		},
-		getUnitsByOwnerID: func(ctx context.Context, ownerID hex.Bytes) ([]types.UnitID, error) {
-			// by default returns only the fee credit record id
-			fcrID, err := tokens.NewFeeCreditRecordIDFromPublicKeyHash(&pdr, types.ShardID{}, ownerID, fcrTimeout)
-			require.NoError(t, err)
-			return []types.UnitID{fcrID}, nil
+		sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+			recTxs = append(recTxs, tx)
+			return tx.Hash(crypto.SHA256)
 		},
-		sendTransaction: func(ctx context.Context, txs *types.TransactionOrder) ([]byte, error) {
-			return nil, nil
+		getRoundInfo: func(ctx context.Context) (*sdktypes.RoundInfo, error) {
+			return &sdktypes.RoundInfo{RoundNumber: 5}, nil
 		},
 	}
-
-	// Initialize the wallet
-	w := initTestWallet(t, be)
-	pk, err := w.am.GetPublicKey(0)
+	tw := initTestWallet(t, rpcClient)
+	ak, err := tw.am.GetAccountKey(0)
 	require.NoError(t, err)
-	token.OwnerPredicate = templates.NewP2pkh256BytesFromKey(pk)
+	nft := newNonFungibleToken(t, "AB", templates.NewP2pkh256BytesFromKey(ak.PubKey), 0, 0)
+	tokenz[string(nft.ID)] = nft
 
-	// Test sending fungible token by ID
-	sub, err := w.SendFungibleByID(context.Background(), 1, token.ID, 50, nil, nil)
+	// default timeout
+	_, err = tw.TransferNFT(context.Background(), 1, nft.ID, nil, nil, nil)
 	require.NoError(t, err)
-	// ensure it's a split
-	require.Equal(t, tokens.TransactionTypeSplitFT, sub.Submissions[0].Transaction.Type)
+	require.Len(t, recTxs, 1)
+	require.EqualValues(t, 5+txTimeoutRoundCount, recTxs[0].Timeout())
 
-	sub, err = w.SendFungibleByID(context.Background(), 1, token.ID, 100, nil, nil)
+	// overridden timeout
+	WithTimeoutRounds(25)(tw)
+	_, err = tw.TransferNFT(context.Background(), 1, nft.ID, nil, nil, nil)
 	require.NoError(t, err)
-	// ensure it's a transfer
-	require.Equal(t, tokens.TransactionTypeTransferFT, sub.Submissions[0].Transaction.Type)
-
-	// Test sending fungible token by ID with insufficient balance
-	_, err = w.SendFungibleByID(context.Background(), 1, token.ID, 200, nil, nil)
-	require.Error(t, err)
-	require.Contains(t, err.Error(), "insufficient FT value")
-
-	// Test sending fungible token by ID with invalid account number
-	_, err = w.SendFungibleByID(context.Background(), 0, token.ID, 50, nil, nil)
-	require.Error(t, err)
-	require.Contains(t, err.Error(), "invalid account number")
+	require.Len(t, recTxs, 2)
+	require.EqualValues(t, 30, recTxs[1].Timeout())
 }
 
 func initTestWallet(t *testing.T, tokensClient sdktypes.TokensPartitionClient) *Wallet {
@@ -954,6 +2789,7 @@ type mockTokensPartitionClient struct {
 	getTransactionProof         func(ctx context.Context, txHash hex.Bytes) (*types.TxRecordProof, error)
 	getFeeCreditRecordByOwnerID func(ctx context.Context, ownerID []byte) (*sdktypes.FeeCreditRecord, error)
 	getBlock                    func(ctx context.Context, roundNumber uint64) (*types.Block, error)
+	scanBlocks                  func(ctx context.Context, fromRound, toRound uint64, filter func(*types.TransactionOrder) bool) ([]*types.TxRecordProof, error)
 	getUnitsByOwnerID           func(ctx context.Context, ownerID hex.Bytes) ([]types.UnitID, error)
 }
 
@@ -1073,6 +2909,13 @@ func (m *mockTokensPartitionClient) GetBlock(ctx context.Context, roundNumber ui
 	return nil, fmt.Errorf("GetBlock not implemented")
 }
 
+func (m *mockTokensPartitionClient) ScanBlocks(ctx context.Context, fromRound, toRound uint64, filter func(*types.TransactionOrder) bool) ([]*types.TxRecordProof, error) {
+	if m.scanBlocks != nil {
+		return m.scanBlocks(ctx, fromRound, toRound, filter)
+	}
+	return nil, fmt.Errorf("ScanBlocks not implemented")
+}
+
 func (m *mockTokensPartitionClient) GetUnitsByOwnerID(ctx context.Context, ownerID hex.Bytes) ([]types.UnitID, error) {
 	if m.getUnitsByOwnerID != nil {
 		return m.getUnitsByOwnerID(ctx, ownerID)
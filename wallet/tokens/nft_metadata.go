@@ -0,0 +1,154 @@
+package tokens
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+)
+
+const (
+	DefaultIPFSGateway         = "https://ipfs.io/ipfs/"
+	defaultMaxMetadataSize     = 64 * 1024
+	defaultMetadataHTTPTimeout = 10 * time.Second
+)
+
+type (
+	// NFTMetadata contains the standard fields of the JSON metadata the URI of a non-fungible token points to.
+	NFTMetadata struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Image       string `json:"image"`
+	}
+
+	// MetadataResolver fetches and parses the metadata of non-fungible tokens. Network access happens only
+	// when ResolveMetadata is called, so the resolver must be created explicitly by the caller.
+	MetadataResolver struct {
+		hc          *http.Client
+		ipfsGateway string
+		maxSize     int64
+	}
+
+	MetadataResolverOption func(*MetadataResolver)
+)
+
+// WithIPFSGateway sets the gateway used to resolve "ipfs://" URIs, the CID and path of the URI are
+// appended to the gateway URL.
+func WithIPFSGateway(gateway string) MetadataResolverOption {
+	return func(r *MetadataResolver) {
+		r.ipfsGateway = gateway
+	}
+}
+
+// WithMaxMetadataSize sets the maximum size of the metadata document in bytes.
+func WithMaxMetadataSize(size int64) MetadataResolverOption {
+	return func(r *MetadataResolver) {
+		r.maxSize = size
+	}
+}
+
+// WithMetadataTimeout sets the timeout of the metadata request.
+func WithMetadataTimeout(timeout time.Duration) MetadataResolverOption {
+	return func(r *MetadataResolver) {
+		r.hc.Timeout = timeout
+	}
+}
+
+func NewMetadataResolver(opts ...MetadataResolverOption) *MetadataResolver {
+	r := &MetadataResolver{
+		hc:          &http.Client{Timeout: defaultMetadataHTTPTimeout},
+		ipfsGateway: DefaultIPFSGateway,
+		maxSize:     defaultMaxMetadataSize,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// ResolveMetadata fetches the JSON metadata the URI of the token points to. Supported URI schemes are
+// "http", "https" and "ipfs", the latter is resolved using the configured IPFS gateway.
+func (r *MetadataResolver) ResolveMetadata(ctx context.Context, nft *sdktypes.NonFungibleToken) (*NFTMetadata, error) {
+	if nft == nil {
+		return nil, errors.New("token is nil")
+	}
+	if nft.URI == "" {
+		return nil, errors.New("token has no URI")
+	}
+	addr, err := r.metadataURL(nft.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	rsp, err := r.hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch metadata: %w", err)
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch metadata: unexpected status %s", rsp.Status)
+	}
+	if err := verifyMetadataContentType(rsp.Header.Get("Content-Type")); err != nil {
+		return nil, err
+	}
+	if rsp.ContentLength > r.maxSize {
+		return nil, fmt.Errorf("metadata size %d bytes exceeds the limit of %d bytes", rsp.ContentLength, r.maxSize)
+	}
+	buf, err := io.ReadAll(io.LimitReader(rsp.Body, r.maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata: %w", err)
+	}
+	if int64(len(buf)) > r.maxSize {
+		return nil, fmt.Errorf("metadata exceeds the limit of %d bytes", r.maxSize)
+	}
+
+	md := &NFTMetadata{}
+	if err := json.Unmarshal(buf, md); err != nil {
+		return nil, fmt.Errorf("failed to decode metadata: %w", err)
+	}
+	return md, nil
+}
+
+func (r *MetadataResolver) metadataURL(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("invalid URI: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https":
+		return u.String(), nil
+	case "ipfs":
+		if r.ipfsGateway == "" {
+			return "", errors.New("IPFS gateway is not configured")
+		}
+		// ipfs://<cid>/<path>
+		return strings.TrimSuffix(r.ipfsGateway, "/") + "/" + strings.TrimPrefix(u.Host+u.Path, "/"), nil
+	default:
+		return "", fmt.Errorf("unsupported URI scheme %q", u.Scheme)
+	}
+}
+
+func verifyMetadataContentType(contentType string) error {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("invalid metadata content type %q: %w", contentType, err)
+	}
+	if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		return fmt.Errorf("unsupported metadata content type %q", mediaType)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package tokens
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+)
+
+func TestResolveMetadata(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok.json", "/ipfs/QmCID/meta.json":
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			_, _ = w.Write([]byte(`{"name":"Cat","description":"A cat","image":"ipfs://QmImage","extra":1}`))
+		case "/html":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<html></html>`))
+		case "/large.json":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"name":"` + strings.Repeat("x", 100) + `"}`))
+		case "/invalid.json":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"name":`))
+		case "/slow.json":
+			time.Sleep(200 * time.Millisecond)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	resolver := NewMetadataResolver(WithIPFSGateway(srv.URL+"/ipfs/"), WithMaxMetadataSize(80))
+	resolve := func(uri string) (*NFTMetadata, error) {
+		return resolver.ResolveMetadata(context.Background(), &sdktypes.NonFungibleToken{URI: uri})
+	}
+
+	t.Run("http", func(t *testing.T) {
+		md, err := resolve(srv.URL + "/ok.json")
+		require.NoError(t, err)
+		require.Equal(t, &NFTMetadata{Name: "Cat", Description: "A cat", Image: "ipfs://QmImage"}, md)
+	})
+
+	t.Run("ipfs gateway", func(t *testing.T) {
+		md, err := resolve("ipfs://QmCID/meta.json")
+		require.NoError(t, err)
+		require.Equal(t, "Cat", md.Name)
+	})
+
+	t.Run("invalid content type", func(t *testing.T) {
+		_, err := resolve(srv.URL + "/html")
+		require.ErrorContains(t, err, `unsupported metadata content type "text/html"`)
+	})
+
+	t.Run("size limit", func(t *testing.T) {
+		_, err := resolve(srv.URL + "/large.json")
+		require.ErrorContains(t, err, "exceeds the limit of 80 bytes")
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		_, err := resolve(srv.URL + "/invalid.json")
+		require.ErrorContains(t, err, "failed to decode metadata")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := resolve(srv.URL + "/missing.json")
+		require.ErrorContains(t, err, "unexpected status 404 Not Found")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		resolver := NewMetadataResolver(WithMetadataTimeout(50 * time.Millisecond))
+		_, err := resolver.ResolveMetadata(context.Background(), &sdktypes.NonFungibleToken{URI: srv.URL + "/slow.json"})
+		require.ErrorContains(t, err, "failed to fetch metadata")
+	})
+
+	t.Run("unsupported URI", func(t *testing.T) {
+		_, err := resolve("file:///etc/passwd")
+		require.ErrorContains(t, err, `unsupported URI scheme "file"`)
+
+		_, err = resolve("")
+		require.ErrorContains(t, err, "token has no URI")
+	})
+}
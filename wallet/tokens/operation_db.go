@@ -0,0 +1,82 @@
+package tokens
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	OperationDBFileName = "tokenoperations.db"
+)
+
+var (
+	bucketOperations = []byte("operations")
+)
+
+type (
+	OperationBoltStore struct {
+		db *bolt.DB
+	}
+)
+
+func NewOperationDB(dir string) (*OperationBoltStore, error) {
+	dbFile := filepath.Join(dir, OperationDBFileName)
+	if err := os.MkdirAll(filepath.Dir(dbFile), 0700); err != nil { // ensure dirs exist
+		return nil, err
+	}
+	db, err := bolt.Open(dbFile, 0600, &bolt.Options{Timeout: 3 * time.Second}) // -rw-------
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bolt DB %s: %w", dbFile, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketOperations)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create db buckets: %w", err)
+	}
+	return &OperationBoltStore{db: db}, nil
+}
+
+func (s *OperationBoltStore) GetOperations() ([]*Operation, error) {
+	var ops []*Operation
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketOperations).ForEach(func(k, v []byte) error {
+			var op *Operation
+			if err := json.Unmarshal(v, &op); err != nil {
+				return fmt.Errorf("failed to deserialize token operation json: %w", err)
+			}
+			ops = append(ops, op)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ops, nil
+}
+
+func (s *OperationBoltStore) SetOperation(op *Operation) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		opBytes, err := json.Marshal(op)
+		if err != nil {
+			return fmt.Errorf("failed to serialize token operation to json: %w", err)
+		}
+		return tx.Bucket(bucketOperations).Put([]byte(op.ID), opBytes)
+	})
+}
+
+func (s *OperationBoltStore) DeleteOperation(id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketOperations).Delete([]byte(id))
+	})
+}
+
+func (s *OperationBoltStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,370 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
+	"github.com/alphabill-org/alphabill-go-base/types"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"github.com/alphabill-org/alphabill-wallet/wallet"
+	"github.com/alphabill-org/alphabill-wallet/wallet/txsubmitter"
+)
+
+const (
+	OperationSend         = "send"
+	OperationTransferNFTs = "transfer-nfts"
+
+	// interval of polling the proofs of the resumed transactions
+	confirmationPollInterval = 500 * time.Millisecond
+)
+
+var errCanNotResign = errors.New("transaction can not be re-signed with the account key")
+
+type (
+	// Operation is the write-ahead log entry of a multi-transaction token operation, it holds
+	// the transactions of the operation until all of them have been sent (and confirmed if the
+	// wallet waits for confirmations).
+	Operation struct {
+		ID            string                    `json:"id"` // ID of the first transaction of the operation
+		Kind          string                    `json:"kind"`
+		AccountNumber uint64                    `json:"accountNumber"`
+		Txs           []*types.TransactionOrder `json:"txs"`
+	}
+
+	OperationStore interface {
+		GetOperations() ([]*Operation, error)
+		SetOperation(op *Operation) error
+		DeleteOperation(id string) error
+		Close() error
+	}
+
+	// ResumeResult is the result of resuming single interrupted operation.
+	ResumeResult struct {
+		Operation *Operation
+		// Submissions are the confirmed transactions of the operation, including the ones re-sent on resume.
+		Submissions []*txsubmitter.TxSubmission
+		// Skipped are the units whose transaction was not re-sent because the unit has changed
+		// since the transaction was created, ie the transaction was executed or the unit was spent
+		// by another transaction.
+		Skipped []types.UnitID
+		// Err is set when the operation could not be resumed, the operation is kept in the
+		// store so that it can be resumed again.
+		Err error
+	}
+
+	Option func(*Wallet)
+
+	// tokenOwnerAuthProof has the layout shared by the auth proofs of the transactions that
+	// spend a token, ie TransferFungibleTokenAuthProof and TransferNonFungibleTokenAuthProof.
+	tokenOwnerAuthProof struct {
+		_                    struct{} `cbor:",toarray"`
+		OwnerProof           []byte
+		TokenTypeOwnerProofs [][]byte
+	}
+)
+
+// WithOperationStore makes the wallet log the transactions of multi-transaction operations
+// (fungible token send and NFT batch transfer) to the store so that the operations can be
+// resumed with ResumeTokenOperations after the wallet was interrupted.
+func WithOperationStore(store OperationStore) Option {
+	return func(w *Wallet) {
+		w.opStore = store
+	}
+}
+
+// WithDryRun makes the wallet build and sign the transactions without sending them, the
+// submission results hold the transactions so that their fees can be estimated before
+// submitting them for real.
+func WithDryRun() Option {
+	return func(w *Wallet) {
+		w.dryRun = true
+	}
+}
+
+// WithTimeoutRounds sets the number of rounds the transactions of the wallet are valid for, ie
+// the timeout of a transaction is the current round number plus n. When n is zero the default
+// of 10 rounds is used.
+func WithTimeoutRounds(n uint64) Option {
+	return func(w *Wallet) {
+		w.timeoutRounds = n
+	}
+}
+
+// WithSendRetries makes the wallet retry sending a transaction up to maxRetries times, with
+// exponential backoff starting from backoff, when the node returns a transient error. See
+// txsubmitter.WithMaxRetries and txsubmitter.WithRetryBackoff.
+func WithSendRetries(maxRetries int, backoff time.Duration) Option {
+	return func(w *Wallet) {
+		w.batchOpts = append(w.batchOpts, txsubmitter.WithMaxRetries(maxRetries), txsubmitter.WithRetryBackoff(backoff))
+	}
+}
+
+// WithConfirmTimeout limits the time the wallet waits for the transactions to be confirmed, when
+// the timeout is reached before the transactions are confirmed ErrConfirmTimeout is returned. When
+// d is zero the wallet waits until the transactions time out on the blockchain.
+func WithConfirmTimeout(d time.Duration) Option {
+	return func(w *Wallet) {
+		w.confirmTimeout = d
+	}
+}
+
+// WithTypeCache makes GetFungibleTokenType and GetNonFungibleTokenType cache the fetched type
+// hierarchies for ttl, so that repeated operations with the same token type don't fetch the type
+// again. Types that are not found are not cached. Caching is disabled by default.
+func WithTypeCache(ttl time.Duration) Option {
+	return func(w *Wallet) {
+		if ttl > 0 {
+			w.ftTypeCache = newTTLCache[[]*sdktypes.FungibleTokenType](ttl)
+			w.nftTypeCache = newTTLCache[[]*sdktypes.NonFungibleTokenType](ttl)
+		}
+	}
+}
+
+// WithRoundNumberCache makes GetRoundNumber reuse the fetched round number for ttl (ie one second),
+// so that the transactions of a batch operation don't fetch the round number one by one. The
+// timeouts of the transactions are then based on a round number up to ttl old. Caching is
+// disabled by default.
+func WithRoundNumberCache(ttl time.Duration) Option {
+	return func(w *Wallet) {
+		if ttl > 0 {
+			w.roundCache = newTTLCache[uint64](ttl)
+		}
+	}
+}
+
+// ListTokenOperations returns the operations that have been interrupted before all of their
+// transactions were sent or confirmed.
+func (w *Wallet) ListTokenOperations() ([]*Operation, error) {
+	if w.opStore == nil {
+		return nil, nil
+	}
+	return w.opStore.GetOperations()
+}
+
+// ResumeTokenOperations re-checks the transactions of all the interrupted operations:
+//   - confirmed transactions are reported as such;
+//   - transactions that have not timed out yet are sent again as is;
+//   - timed out transactions are re-signed with a fresh timeout and sent again, but only if the
+//     unit is still in the state the transaction was created for, so that nothing is submitted twice.
+//
+// The operations that were resumed successfully are deleted from the store.
+func (w *Wallet) ResumeTokenOperations(ctx context.Context) ([]*ResumeResult, error) {
+	if w.dryRun {
+		return nil, ErrDryRunNotSupported
+	}
+	ops, err := w.ListTokenOperations()
+	if err != nil {
+		return nil, fmt.Errorf("loading token operations: %w", err)
+	}
+	if len(ops) == 0 {
+		return nil, nil
+	}
+	roundNumber, err := w.GetRoundNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*ResumeResult, 0, len(ops))
+	for _, op := range ops {
+		res := w.resumeOperation(ctx, op, roundNumber)
+		if res.Err == nil {
+			if err := w.opStore.DeleteOperation(op.ID); err != nil {
+				return results, fmt.Errorf("deleting token operation %s: %w", op.ID, err)
+			}
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+func (w *Wallet) resumeOperation(ctx context.Context, op *Operation, roundNumber uint64) *ResumeResult {
+	res := &ResumeResult{Operation: op}
+	unlock, err := w.lockAccount(ctx, op.AccountNumber)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	defer unlock()
+	acc, err := w.getAccount(op.AccountNumber)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	batch := txsubmitter.NewBatch(w.tokensClient, w.log, w.batchOpts...)
+	for _, tx := range op.Txs {
+		sub, err := txsubmitter.New(tx)
+		if err != nil {
+			res.Err = err
+			return res
+		}
+		if roundNumber <= tx.Timeout() {
+			proof, err := w.tokensClient.GetTransactionProof(ctx, sub.TxHash)
+			if err != nil {
+				res.Err = fmt.Errorf("fetching transaction proof: %w", err)
+				return res
+			}
+			if proof != nil {
+				sub.Proof = proof
+				res.Submissions = append(res.Submissions, sub)
+			} else {
+				batch.Add(sub)
+			}
+			continue
+		}
+		// the transaction has timed out, so the proof is final and returned without waiting
+		proof, err := wallet.WaitForConfirmation(ctx, w.tokensClient, tx, confirmationPollInterval)
+		if err != nil {
+			res.Err = fmt.Errorf("fetching transaction proof: %w", err)
+			return res
+		}
+		if proof != nil {
+			sub.Proof = proof
+			res.Submissions = append(res.Submissions, sub)
+			continue
+		}
+
+		usable, err := w.isUnitUnchanged(ctx, acc, tx)
+		if err != nil {
+			res.Err = err
+			return res
+		}
+		if !usable {
+			w.log.InfoContext(ctx, "unit has changed, not re-sending transaction", slog.String("unitID", tx.GetUnitID().String()), slog.String("txHash", sub.TxID()))
+			res.Skipped = append(res.Skipped, tx.GetUnitID())
+			continue
+		}
+		newTx, err := w.resignTx(acc, tx, w.txTimeout(roundNumber))
+		if err != nil {
+			res.Err = fmt.Errorf("re-signing transaction %s: %w", sub.TxID(), err)
+			return res
+		}
+		if sub, err = txsubmitter.New(newTx); err != nil {
+			res.Err = err
+			return res
+		}
+		batch.Add(sub)
+	}
+	if len(batch.Submissions()) > 0 {
+		res.Err = w.sendTx(ctx, batch, true)
+		for _, sub := range batch.Submissions() {
+			if sub.Confirmed() {
+				res.Submissions = append(res.Submissions, sub)
+			}
+		}
+	}
+	return res
+}
+
+// isUnitUnchanged returns true if the unit of the transaction is still owned by the account
+// and has the counter the transaction was created for, ie the transaction has not been executed.
+func (w *Wallet) isUnitUnchanged(ctx context.Context, acc *accountKey, tx *types.TransactionOrder) (bool, error) {
+	var token Token
+	var counter, txCounter uint64
+	switch tx.Type {
+	case tokens.TransactionTypeTransferFT, tokens.TransactionTypeSplitFT:
+		ft, err := w.tokensClient.GetFungibleToken(ctx, tx.GetUnitID())
+		if err != nil {
+			return false, fmt.Errorf("fetching token %s: %w", tx.GetUnitID(), err)
+		}
+		if ft == nil {
+			return false, nil
+		}
+		token, counter = ft, ft.Counter
+		if tx.Type == tokens.TransactionTypeTransferFT {
+			attr := &tokens.TransferFungibleTokenAttributes{}
+			if err := tx.UnmarshalAttributes(attr); err != nil {
+				return false, fmt.Errorf("decoding transaction attributes: %w", err)
+			}
+			txCounter = attr.Counter
+		} else {
+			attr := &tokens.SplitFungibleTokenAttributes{}
+			if err := tx.UnmarshalAttributes(attr); err != nil {
+				return false, fmt.Errorf("decoding transaction attributes: %w", err)
+			}
+			txCounter = attr.Counter
+		}
+	case tokens.TransactionTypeTransferNFT:
+		nft, err := w.tokensClient.GetNonFungibleToken(ctx, tx.GetUnitID())
+		if err != nil {
+			return false, fmt.Errorf("fetching token %s: %w", tx.GetUnitID(), err)
+		}
+		if nft == nil {
+			return false, nil
+		}
+		attr := &tokens.TransferNonFungibleTokenAttributes{}
+		if err := tx.UnmarshalAttributes(attr); err != nil {
+			return false, fmt.Errorf("decoding transaction attributes: %w", err)
+		}
+		token, counter, txCounter = nft, nft.Counter, attr.Counter
+	default:
+		return false, fmt.Errorf("unsupported transaction type %d", tx.Type)
+	}
+	return counter == txCounter && token.GetLockStatus() == 0 && ensureTokenOwnership(acc, token, nil) == nil, nil
+}
+
+// resignTx creates a copy of the transaction with the given timeout and signs it with the account key.
+// Only transactions whose owner proof is the account key signature and which have no token type
+// owner proofs can be re-signed.
+func (w *Wallet) resignTx(acc *accountKey, tx *types.TransactionOrder, timeout uint64) (*types.TransactionOrder, error) {
+	var authProof tokenOwnerAuthProof
+	if err := tx.UnmarshalAuthProof(&authProof); err != nil {
+		return nil, fmt.Errorf("decoding auth proof: %w", err)
+	}
+	for _, p := range authProof.TokenTypeOwnerProofs {
+		if len(p) > 0 {
+			return nil, errCanNotResign
+		}
+	}
+	clientMetadata := *tx.ClientMetadata
+	clientMetadata.Timeout = timeout
+	newTx := &types.TransactionOrder{
+		Version:     tx.Version,
+		Payload:     tx.Payload,
+		StateUnlock: tx.StateUnlock,
+	}
+	newTx.ClientMetadata = &clientMetadata
+
+	sigBytes, err := newTx.AuthProofSigBytes()
+	if err != nil {
+		return nil, err
+	}
+	if authProof.OwnerProof, err = defaultProof(acc.AccountKey).Proof(sigBytes); err != nil {
+		return nil, err
+	}
+	if err = newTx.SetAuthProof(authProof); err != nil {
+		return nil, fmt.Errorf("failed to set auth proof: %w", err)
+	}
+	newTx.FeeProof, err = sdktypes.NewP2pkhFeeSignatureFromKey(newTx, acc.PrivKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign tx fee proof: %w", err)
+	}
+	return newTx, nil
+}
+
+// sendBatch sends the transactions of the batch, if the wallet has an operation store the
+// transactions are logged to it until the batch has been sent so that the operation can
+// be resumed when it's interrupted.
+func (w *Wallet) sendBatch(ctx context.Context, kind string, accountNumber uint64, batch *txsubmitter.TxSubmissionBatch, confirmTx bool) error {
+	subs := batch.Submissions()
+	if w.opStore == nil || w.dryRun || len(subs) == 0 {
+		return w.sendTx(ctx, batch, confirmTx)
+	}
+	op := &Operation{ID: subs[0].TxID(), Kind: kind, AccountNumber: accountNumber}
+	for _, sub := range subs {
+		op.Txs = append(op.Txs, sub.Transaction)
+	}
+	if err := w.opStore.SetOperation(op); err != nil {
+		return fmt.Errorf("storing token operation: %w", err)
+	}
+	if err := w.sendTx(ctx, batch, confirmTx); err != nil {
+		return err
+	}
+	if err := w.opStore.DeleteOperation(op.ID); err != nil {
+		return fmt.Errorf("deleting token operation: %w", err)
+	}
+	return nil
+}
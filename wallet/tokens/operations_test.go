@@ -0,0 +1,172 @@
+package tokens
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-go-base/predicates/templates"
+	tokenid "github.com/alphabill-org/alphabill-go-base/testutils/tokens"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
+	"github.com/stretchr/testify/require"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+)
+
+func TestOperationDB_GetSetDelete(t *testing.T) {
+	s, err := NewOperationDB(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, s.Close()) })
+
+	// verify empty store returns no operations
+	ops, err := s.GetOperations()
+	require.NoError(t, err)
+	require.Empty(t, ops)
+
+	// store operation
+	tx := &types.TransactionOrder{Version: 1, Payload: types.Payload{Type: 6, UnitID: []byte{1}, ClientMetadata: &types.ClientMetadata{Timeout: 11}}}
+	op := &Operation{ID: "0x01", Kind: OperationTransferNFTs, AccountNumber: 2, Txs: []*types.TransactionOrder{tx}}
+	require.NoError(t, s.SetOperation(op))
+
+	// verify stored equals actual
+	ops, err = s.GetOperations()
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+	require.Equal(t, op.ID, ops[0].ID)
+	require.Equal(t, op.Kind, ops[0].Kind)
+	require.Equal(t, op.AccountNumber, ops[0].AccountNumber)
+	require.Len(t, ops[0].Txs, 1)
+	require.Equal(t, tx.Timeout(), ops[0].Txs[0].Timeout())
+	require.EqualValues(t, tx.GetUnitID(), ops[0].Txs[0].GetUnitID())
+
+	// delete operation
+	require.NoError(t, s.DeleteOperation(op.ID))
+	ops, err = s.GetOperations()
+	require.NoError(t, err)
+	require.Empty(t, ops)
+}
+
+func TestResumeTokenOperations(t *testing.T) {
+	pdr := tokenid.PDR()
+	tokenz := make(map[string]*sdktypes.NonFungibleToken)
+	executed := make(map[string]bool)
+	var recTxs []*types.TransactionOrder
+	var sendErr error
+	roundNumber := uint64(1)
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getNonFungibleToken: func(ctx context.Context, id sdktypes.TokenID) (*sdktypes.NonFungibleToken, error) {
+			return tokenz[string(id)], nil
+		},
+		sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+			if sendErr != nil {
+				return nil, sendErr
+			}
+			recTxs = append(recTxs, tx)
+			txHash, err := tx.Hash(crypto.SHA256)
+			executed[string(txHash)] = true
+			return txHash, err
+		},
+		getTransactionProof: func(ctx context.Context, txHash hex.Bytes) (*types.TxRecordProof, error) {
+			if !executed[string(txHash)] {
+				return nil, nil
+			}
+			return &types.TxRecordProof{TxRecord: &types.TransactionRecord{ServerMetadata: &types.ServerMetadata{SuccessIndicator: types.TxStatusSuccessful}}}, nil
+		},
+		getRoundInfo: func(ctx context.Context) (*sdktypes.RoundInfo, error) {
+			return &sdktypes.RoundInfo{RoundNumber: roundNumber}, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+	store, err := NewOperationDB(t.TempDir())
+	require.NoError(t, err)
+	tw.opStore = store
+	t.Cleanup(tw.Close)
+	ak, err := tw.am.GetAccountKey(0)
+	require.NoError(t, err)
+
+	ownerPredicate := templates.NewP2pkh256BytesFromKey(ak.PubKey)
+	nft1 := newNonFungibleToken(t, "AB", ownerPredicate, 0, 0)
+	nft2 := newNonFungibleToken(t, "AB", ownerPredicate, 0, 0)
+	nft3 := newNonFungibleToken(t, "AB", ownerPredicate, 0, 0)
+	for _, nft := range []*sdktypes.NonFungibleToken{nft1, nft2, nft3} {
+		tokenz[string(nft.ID)] = nft
+	}
+	transfers := []NFTTransfer{{TokenID: nft1.ID}, {TokenID: nft2.ID}, {TokenID: nft3.ID}}
+
+	// interruptTransfer starts the batch transfer which fails to send, leaving the operation in the store
+	interruptTransfer := func(t *testing.T) *Operation {
+		sendErr = errors.New("connection refused")
+		_, err := tw.TransferNFTs(context.Background(), 1, transfers)
+		require.ErrorIs(t, err, sendErr)
+		sendErr = nil
+
+		ops, err := tw.ListTokenOperations()
+		require.NoError(t, err)
+		require.Len(t, ops, 1)
+		require.Equal(t, OperationTransferNFTs, ops[0].Kind)
+		require.EqualValues(t, 1, ops[0].AccountNumber)
+		require.Len(t, ops[0].Txs, 3)
+		return ops[0]
+	}
+
+	t.Run("nothing to resume", func(t *testing.T) {
+		results, err := tw.ResumeTokenOperations(context.Background())
+		require.NoError(t, err)
+		require.Empty(t, results)
+	})
+
+	t.Run("transactions that have not timed out are re-sent as is", func(t *testing.T) {
+		recTxs, roundNumber = nil, 1
+		op := interruptTransfer(t)
+
+		results, err := tw.ResumeTokenOperations(context.Background())
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.NoError(t, results[0].Err)
+		require.Empty(t, results[0].Skipped)
+		require.Len(t, results[0].Submissions, 3)
+		require.Len(t, recTxs, 3)
+		for i, tx := range recTxs {
+			require.Equal(t, op.Txs[i].Timeout(), tx.Timeout())
+			require.Equal(t, op.Txs[i].AuthProof, tx.AuthProof)
+		}
+
+		ops, err := tw.ListTokenOperations()
+		require.NoError(t, err)
+		require.Empty(t, ops)
+	})
+
+	t.Run("timed out transactions are re-signed unless the unit has changed", func(t *testing.T) {
+		recTxs, roundNumber = nil, 2
+		op := interruptTransfer(t)
+
+		// transaction of nft3 got executed before the interruption, nft2 was spent by another transaction
+		txHash, err := op.Txs[2].Hash(crypto.SHA256)
+		require.NoError(t, err)
+		executed[string(txHash)] = true
+		spent := *nft2
+		spent.Counter = 1
+		tokenz[string(nft2.ID)] = &spent
+		roundNumber = op.Txs[0].Timeout() + 1
+
+		results, err := tw.ResumeTokenOperations(context.Background())
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.NoError(t, results[0].Err)
+		require.Equal(t, []types.UnitID{nft2.ID}, results[0].Skipped)
+		require.Len(t, results[0].Submissions, 2)
+
+		require.Len(t, recTxs, 1)
+		require.EqualValues(t, nft1.ID, recTxs[0].GetUnitID())
+		require.Equal(t, roundNumber+txTimeoutRoundCount, recTxs[0].Timeout())
+		require.NotEqual(t, op.Txs[0].AuthProof, recTxs[0].AuthProof)
+		require.NotEmpty(t, recTxs[0].FeeProof)
+
+		ops, err := tw.ListTokenOperations()
+		require.NoError(t, err)
+		require.Empty(t, ops)
+	})
+}
@@ -0,0 +1,118 @@
+package tokens
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/alphabill-org/alphabill-go-base/types"
+)
+
+const ProofArchiveVersion = 1
+
+type (
+	// ProofArchive is a self-describing envelope of the transaction proofs of a single operation,
+	// it holds the metadata of the operation alongside the proofs.
+	ProofArchive struct {
+		Version       uint32                 `cbor:"version" json:"version"`
+		Timestamp     int64                  `cbor:"timestamp" json:"timestamp"` // unix time (seconds) of the operation
+		Operation     string                 `cbor:"operation" json:"operation"`
+		AccountNumber uint64                 `cbor:"accountNumber,omitempty" json:"accountNumber,omitempty"`
+		UnitIDs       []types.UnitID         `cbor:"unitIds,omitempty" json:"unitIds,omitempty"` // units of the transactions, ie token or token type IDs
+		TypeID        types.UnitID           `cbor:"typeId,omitempty" json:"typeId,omitempty"`
+		Amount        string                 `cbor:"amount,omitempty" json:"amount,omitempty"` // amount of the operation as entered by the user
+		Proofs        []*types.TxRecordProof `cbor:"proofs" json:"proofs"`
+	}
+)
+
+// NewProofArchive returns archive of the proofs, the unit IDs of the archive are collected from
+// the transactions of the proofs.
+func NewProofArchive(operation string, timestamp int64, proofs []*types.TxRecordProof) (*ProofArchive, error) {
+	archive := &ProofArchive{
+		Version:   ProofArchiveVersion,
+		Timestamp: timestamp,
+		Operation: operation,
+		Proofs:    proofs,
+	}
+	for _, proof := range proofs {
+		tx, err := proof.GetTransactionOrderV1()
+		if err != nil {
+			return nil, fmt.Errorf("decoding transaction of the proof: %w", err)
+		}
+		archive.UnitIDs = append(archive.UnitIDs, tx.GetUnitID())
+	}
+	return archive, nil
+}
+
+// EncodeProofArchive writes the archive to w as CBOR.
+func EncodeProofArchive(w io.Writer, archive *ProofArchive) error {
+	if err := types.Cbor.Encode(w, archive); err != nil {
+		return fmt.Errorf("encoding proof archive as CBOR: %w", err)
+	}
+	return nil
+}
+
+// EncodeProofArchiveJSON writes the archive to w as indented JSON.
+func EncodeProofArchiveJSON(w io.Writer, archive *ProofArchive) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(archive); err != nil {
+		return fmt.Errorf("encoding proof archive as JSON: %w", err)
+	}
+	return nil
+}
+
+// DecodeProofArchive reads the proof archive from r, the format of the data is detected by
+// attempting to decode it as CBOR first and as JSON then. The bare list of proofs saved without
+// the metadata is decoded too, it's returned as an archive of version 0 with only the proofs set.
+func DecodeProofArchive(r io.Reader) (*ProofArchive, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading proof archive: %w", err)
+	}
+	archive, bare, cborErr := decodeProofArchiveCBOR(data)
+	if cborErr != nil {
+		var jsonErr error
+		if archive, bare, jsonErr = decodeProofArchiveJSON(data); jsonErr != nil {
+			return nil, fmt.Errorf("proof archive is neither CBOR nor JSON: %w", errors.Join(cborErr, jsonErr))
+		}
+	}
+	if !bare && archive.Version != ProofArchiveVersion {
+		return nil, fmt.Errorf("unsupported proof archive version %d", archive.Version)
+	}
+	return archive, nil
+}
+
+func decodeProofArchiveCBOR(data []byte) (archive *ProofArchive, bare bool, err error) {
+	// CBOR major type 4 (array) or null is the bare list of proofs
+	if len(data) > 0 && (data[0]>>5 == 4 || data[0] == 0xf6) {
+		var proofs []*types.TxRecordProof
+		if err = types.Cbor.Unmarshal(data, &proofs); err != nil {
+			return nil, true, fmt.Errorf("decoding transaction proofs as CBOR: %w", err)
+		}
+		return &ProofArchive{Proofs: proofs}, true, nil
+	}
+	archive = &ProofArchive{}
+	if err = types.Cbor.Unmarshal(data, archive); err != nil {
+		return nil, false, fmt.Errorf("decoding proof archive as CBOR: %w", err)
+	}
+	return archive, false, nil
+}
+
+func decodeProofArchiveJSON(data []byte) (archive *ProofArchive, bare bool, err error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && (data[0] == '[' || bytes.Equal(data, []byte("null"))) {
+		var proofs []*types.TxRecordProof
+		if err = json.Unmarshal(data, &proofs); err != nil {
+			return nil, true, fmt.Errorf("decoding transaction proofs as JSON: %w", err)
+		}
+		return &ProofArchive{Proofs: proofs}, true, nil
+	}
+	archive = &ProofArchive{}
+	if err = json.Unmarshal(data, archive); err != nil {
+		return nil, false, fmt.Errorf("decoding proof archive as JSON: %w", err)
+	}
+	return archive, false, nil
+}
@@ -0,0 +1,105 @@
+package tokens
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	tokenid "github.com/alphabill-org/alphabill-go-base/testutils/tokens"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/stretchr/testify/require"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+)
+
+func TestProofArchive_EncodeDecode(t *testing.T) {
+	tokenID := tokenid.NewFungibleTokenID(t)
+	attr := &tokens.TransferFungibleTokenAttributes{Value: 5, Counter: 1}
+	tx, err := sdktypes.NewTransactionOrder(types.NetworkLocal, tokens.DefaultPartitionID, tokenID, tokens.TransactionTypeTransferFT, attr, sdktypes.WithTimeout(10))
+	require.NoError(t, err)
+	txBytes, err := tx.MarshalCBOR()
+	require.NoError(t, err)
+	proofs := []*types.TxRecordProof{{
+		TxRecord: &types.TransactionRecord{Version: 1, TransactionOrder: txBytes, ServerMetadata: &types.ServerMetadata{ActualFee: 1}},
+		TxProof:  &types.TxProof{Version: 1},
+	}}
+
+	t.Run("archive with metadata", func(t *testing.T) {
+		archive, err := NewProofArchive("wallet token send fungible", 1700000000, proofs)
+		require.NoError(t, err)
+		require.Equal(t, []types.UnitID{tokenID}, archive.UnitIDs)
+		archive.AccountNumber = 2
+		archive.TypeID = tokenid.NewFungibleTokenTypeID(t)
+		archive.Amount = "0.05"
+
+		buf := &bytes.Buffer{}
+		require.NoError(t, EncodeProofArchive(buf, archive))
+		decoded, err := DecodeProofArchive(buf)
+		require.NoError(t, err)
+		require.EqualValues(t, ProofArchiveVersion, decoded.Version)
+		require.EqualValues(t, 1700000000, decoded.Timestamp)
+		require.Equal(t, archive.Operation, decoded.Operation)
+		require.EqualValues(t, 2, decoded.AccountNumber)
+		require.Equal(t, archive.UnitIDs, decoded.UnitIDs)
+		require.Equal(t, archive.TypeID, decoded.TypeID)
+		require.Equal(t, "0.05", decoded.Amount)
+		require.Len(t, decoded.Proofs, 1)
+		require.EqualValues(t, 1, decoded.Proofs[0].ActualFee())
+		decodedTx, err := decoded.Proofs[0].GetTransactionOrderV1()
+		require.NoError(t, err)
+		require.EqualValues(t, tokenID, decodedTx.GetUnitID())
+	})
+
+	t.Run("bare list of proofs", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, types.Cbor.Encode(buf, proofs))
+		decoded, err := DecodeProofArchive(buf)
+		require.NoError(t, err)
+		require.Zero(t, decoded.Version)
+		require.Empty(t, decoded.Operation)
+		require.Len(t, decoded.Proofs, 1)
+	})
+
+	t.Run("archive as JSON", func(t *testing.T) {
+		archive, err := NewProofArchive("wallet token send fungible", 1700000000, proofs)
+		require.NoError(t, err)
+		archive.Amount = "0.05"
+
+		buf := &bytes.Buffer{}
+		require.NoError(t, EncodeProofArchiveJSON(buf, archive))
+		require.Contains(t, buf.String(), `"operation": "wallet token send fungible"`)
+		decoded, err := DecodeProofArchive(buf)
+		require.NoError(t, err)
+		require.EqualValues(t, ProofArchiveVersion, decoded.Version)
+		require.Equal(t, archive.Operation, decoded.Operation)
+		require.Equal(t, archive.UnitIDs, decoded.UnitIDs)
+		require.Equal(t, "0.05", decoded.Amount)
+		require.Len(t, decoded.Proofs, 1)
+		decodedTx, err := decoded.Proofs[0].GetTransactionOrderV1()
+		require.NoError(t, err)
+		require.EqualValues(t, tokenID, decodedTx.GetUnitID())
+	})
+
+	t.Run("bare list of proofs as JSON", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, json.NewEncoder(buf).Encode(proofs))
+		decoded, err := DecodeProofArchive(buf)
+		require.NoError(t, err)
+		require.Zero(t, decoded.Version)
+		require.Len(t, decoded.Proofs, 1)
+		require.EqualValues(t, 1, decoded.Proofs[0].ActualFee())
+	})
+
+	t.Run("neither CBOR nor JSON", func(t *testing.T) {
+		_, err := DecodeProofArchive(bytes.NewBufferString("not a proof"))
+		require.ErrorContains(t, err, "proof archive is neither CBOR nor JSON")
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, EncodeProofArchive(buf, &ProofArchive{Version: 2}))
+		_, err := DecodeProofArchive(buf)
+		require.ErrorContains(t, err, "unsupported proof archive version 2")
+	})
+}
@@ -14,9 +14,105 @@ import (
 	"github.com/alphabill-org/alphabill-wallet/wallet/txsubmitter"
 )
 
+// maxBurnBatchSize is the default number of tokens burned in a single dust collection swap
 const maxBurnBatchSize = 100
 
-func (w *Wallet) CollectDust(ctx context.Context, accountNumber uint64, allowedTokenTypes []sdktypes.TokenTypeID, ownerPredicateInput *PredicateInput, typeOwnerPredicateInputs []*PredicateInput) (map[uint64][]*SubmissionResult, error) {
+type (
+	// CollectDustOptions are the optional parameters of CollectDust.
+	CollectDustOptions struct {
+		// MaxTokensPerSwap is the maximum number of tokens burned and joined into the target token
+		// in a single swap, the default is used when zero.
+		MaxTokensPerSwap int
+		// Progress if set is called after each completed swap with the number of swaps done and the
+		// total number of swaps of the account. The total is the upper bound, the swaps of a token type
+		// are skipped when the joined value would overflow.
+		Progress func(accountNumber uint64, swapsDone, swapsTotal int)
+	}
+
+	CollectDustOption func(*CollectDustOptions)
+)
+
+// WithMaxTokensPerSwap sets the maximum number of tokens joined in a single dust collection swap.
+func WithMaxTokensPerSwap(n int) CollectDustOption {
+	return func(o *CollectDustOptions) {
+		o.MaxTokensPerSwap = n
+	}
+}
+
+// WithProgress sets the callback that is called after each completed dust collection swap.
+func WithProgress(fn func(accountNumber uint64, swapsDone, swapsTotal int)) CollectDustOption {
+	return func(o *CollectDustOptions) {
+		o.Progress = fn
+	}
+}
+
+// dcState is shared by the transactions of a dust collection pass, the fee credit is checked and
+// the round number is fetched only once per pass. The round number is advanced by the rounds of
+// the unicity certificates of the confirmed transactions, so that the timeouts of the following
+// transactions don't fall behind.
+type dcState struct {
+	fcrID       types.UnitID
+	roundNumber uint64
+	// swapDone if set is called after each completed swap
+	swapDone func()
+}
+
+func (w *Wallet) newDCState(ctx context.Context, acc *accountKey, txCount uint64) (*dcState, error) {
+	fcrID, err := w.ensureFeeCredit(ctx, acc.AccountKey, txCount)
+	if err != nil {
+		return nil, err
+	}
+	roundNumber, err := w.GetRoundNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get round number: %w", err)
+	}
+	return &dcState{fcrID: fcrID, roundNumber: roundNumber}, nil
+}
+
+func (s *dcState) observe(proofs ...*types.TxRecordProof) {
+	for _, proof := range proofs {
+		if proof == nil || proof.TxProof == nil || proof.TxProof.UnicityCertificate == nil {
+			continue
+		}
+		uc := &types.UnicityCertificate{}
+		if err := types.Cbor.Unmarshal(proof.TxProof.UnicityCertificate, uc); err != nil {
+			continue
+		}
+		s.roundNumber = max(s.roundNumber, uc.GetRoundNumber())
+	}
+}
+
+// dcTxCount returns the number of transactions needed to join the tokens in swaps of batchSize burned tokens.
+func dcTxCount(tokenCount, batchSize int) uint64 {
+	batchCount := ((tokenCount - 1) / batchSize) + 1
+	return uint64(tokenCount + batchCount*2) // +lock fee and join fee for every batch
+}
+
+// dcSwapCount returns the number of swaps needed to join the tokens, the first token is the target
+// of the swaps and the rest are burned in batches of batchSize.
+func dcSwapCount(tokenCount, batchSize int) int {
+	if tokenCount < 2 {
+		return 0
+	}
+	return ((tokenCount - 2) / batchSize) + 1
+}
+
+// CollectDust joins the fungible tokens of the account (all accounts if accountNumber is AllAccounts)
+// per token type. The tokens of a type are joined in swaps of at most MaxTokensPerSwap burned tokens,
+// the result of an account has a SubmissionResult per swap. On error the results of the swaps completed
+// so far are returned with the error.
+func (w *Wallet) CollectDust(ctx context.Context, accountNumber uint64, allowedTokenTypes []sdktypes.TokenTypeID, ownerPredicateInput *PredicateInput, typeOwnerPredicateInputs []*PredicateInput, opts ...CollectDustOption) (map[uint64][]*SubmissionResult, error) {
+	// dust collection joins the burned tokens, so the burn transactions must be executed
+	if w.dryRun {
+		return nil, ErrDryRunNotSupported
+	}
+	o := &CollectDustOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.MaxTokensPerSwap <= 0 {
+		o.MaxTokensPerSwap = maxBurnBatchSize
+	}
 	keys, err := w.getAccounts(accountNumber)
 	if err != nil {
 		return nil, err
@@ -24,40 +120,141 @@ func (w *Wallet) CollectDust(ctx context.Context, accountNumber uint64, allowedT
 	results := make(map[uint64][]*SubmissionResult, len(keys))
 
 	for _, key := range keys {
-		tokensByTypes, err := w.getTokensForDC(ctx, key.PubKey, allowedTokenTypes)
+		subResults, err := w.collectAccountDust(ctx, key, allowedTokenTypes, o, ownerPredicateInput, typeOwnerPredicateInputs)
+		results[key.idx] = subResults
 		if err != nil {
-			return nil, err
+			return results, err
 		}
-		var subResults []*SubmissionResult
-		for _, tokenz := range tokensByTypes {
-			subResult, err := w.collectDust(ctx, key, tokenz, ownerPredicateInput, typeOwnerPredicateInputs)
-			if err != nil {
-				return results, err
-			}
-			if subResult != nil {
-				subResults = append(subResults, subResult)
-			}
-		}
-		results[key.idx] = subResults
 	}
 	return results, nil
 }
 
-func (w *Wallet) collectDust(ctx context.Context, acc *accountKey, tokens []*sdktypes.FungibleToken, ownerPredicateInput *PredicateInput, typeOwnerPredicateInputs []*PredicateInput) (*SubmissionResult, error) {
-	batchCount := ((len(tokens) - 1) / maxBurnBatchSize) + 1
-	txCount := len(tokens) + batchCount*2 // +lock fee and join fee for every batch
-	fcrID, err := w.ensureFeeCredit(ctx, acc.AccountKey, txCount)
+// collectAccountDust joins the fungible tokens of the account per token type, the results of the
+// completed swaps are returned also on error.
+func (w *Wallet) collectAccountDust(ctx context.Context, key *accountKey, allowedTokenTypes []sdktypes.TokenTypeID, o *CollectDustOptions, ownerPredicateInput *PredicateInput, typeOwnerPredicateInputs []*PredicateInput) ([]*SubmissionResult, error) {
+	unlock, err := w.lockAccount(ctx, key.AccountNumber())
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	tokensByTypes, err := w.getTokensForDC(ctx, key.PubKey, allowedTokenTypes)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokensByTypes) == 0 {
+		return nil, nil
+	}
+	// single fee credit check and round number fetch for all the types
+	var txCount uint64
+	var swapsTotal int
+	for _, tokenz := range tokensByTypes {
+		txCount += dcTxCount(len(tokenz), o.MaxTokensPerSwap)
+		swapsTotal += dcSwapCount(len(tokenz), o.MaxTokensPerSwap)
+	}
+	st, err := w.newDCState(ctx, key, txCount)
 	if err != nil {
 		return nil, err
 	}
+	if o.Progress != nil {
+		swapsDone := 0
+		accNr := key.AccountNumber()
+		st.swapDone = func() {
+			swapsDone++
+			o.Progress(accNr, swapsDone, swapsTotal)
+		}
+	}
+	var subResults []*SubmissionResult
+	for _, tokenz := range tokensByTypes {
+		swapResults, err := w.collectDust(ctx, key, st, tokenz, o.MaxTokensPerSwap, ownerPredicateInput, typeOwnerPredicateInputs)
+		subResults = append(subResults, swapResults...)
+		if err != nil {
+			return subResults, err
+		}
+	}
+	return subResults, nil
+}
+
+// JoinFungibleTokens joins the given fungible tokens into the first token of the list, ie the first
+// token is locked and the rest of the tokens are burned and joined into it. All the tokens must be
+// unlocked tokens of the given type owned by the account, otherwise an error is returned before any
+// transaction is submitted. GetUnit of the result returns the ID of the joined token.
+func (w *Wallet) JoinFungibleTokens(ctx context.Context, accountNumber uint64, typeID sdktypes.TokenTypeID, tokenIDs []sdktypes.TokenID) (*SubmissionResult, error) {
+	// the burned tokens are joined, so the burn transactions must be executed
+	if w.dryRun {
+		return nil, ErrDryRunNotSupported
+	}
+	if len(tokenIDs) < 2 {
+		return nil, fmt.Errorf("at least two tokens are required to join, got %d", len(tokenIDs))
+	}
+	unlock, err := w.lockAccount(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	acc, err := w.getAccount(accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	ownerPredicateInput := defaultProof(acc.AccountKey)
+
+	tokenz := make([]*sdktypes.FungibleToken, 0, len(tokenIDs))
+	seen := make(map[string]struct{}, len(tokenIDs))
+	var sum uint64
+	for _, tokenID := range tokenIDs {
+		if _, ok := seen[string(tokenID)]; ok {
+			return nil, fmt.Errorf("duplicate token %s", tokenID)
+		}
+		seen[string(tokenID)] = struct{}{}
+
+		token, err := w.GetFungibleToken(ctx, tokenID)
+		if err != nil {
+			return nil, err
+		}
+		if !typeID.Eq(token.TypeID) {
+			return nil, fmt.Errorf("token %s is of type %s, expected type %s", token.ID, token.TypeID, typeID)
+		}
+		if err = ensureTokenOwnership(acc, token, ownerPredicateInput); err != nil {
+			return nil, err
+		}
+		if token.LockStatus != 0 {
+			return nil, fmt.Errorf("token %s is locked", token.ID)
+		}
+		if sum, _, err = util.AddUint64(sum, token.Amount); err != nil {
+			return nil, fmt.Errorf("joined value of the tokens overflows: %w", err)
+		}
+		tokenz = append(tokenz, token)
+	}
+
+	st, err := w.newDCState(ctx, acc, dcTxCount(len(tokenz), maxBurnBatchSize))
+	if err != nil {
+		return nil, err
+	}
+	swapResults, err := w.collectDust(ctx, acc, st, tokenz, maxBurnBatchSize, ownerPredicateInput, nil)
+	if err != nil {
+		return nil, err
+	}
+	// combine the swaps, the last join transaction is the one of the joined token
+	res := &SubmissionResult{AccountNumber: accountNumber}
+	for _, swap := range swapResults {
+		res.FeeSum += swap.FeeSum
+		res.Submissions = swap.Submissions
+	}
+	return res, nil
+}
+
+// collectDust joins the tokens into the first token in swaps of at most batchSize burned tokens, a
+// swap locks the target token, burns the batch of tokens and joins them into the target token.
+// Returns the results of the completed swaps, a result has the join transaction of the swap.
+func (w *Wallet) collectDust(ctx context.Context, acc *accountKey, st *dcState, tokens []*sdktypes.FungibleToken, batchSize int, ownerPredicateInput *PredicateInput, typeOwnerPredicateInputs []*PredicateInput) ([]*SubmissionResult, error) {
 	// first token to be joined into
 	targetToken := tokens[0]
 	totalAmountJoined := targetToken.Amount
 	burnTokens := tokens[1:]
-	totalFees := uint64(0)
+	var results []*SubmissionResult
 
-	for startIdx := 0; startIdx < len(burnTokens); startIdx += maxBurnBatchSize {
-		endIdx := startIdx + maxBurnBatchSize
+	for startIdx := 0; startIdx < len(burnTokens); startIdx += batchSize {
+		endIdx := startIdx + batchSize
 		if endIdx > len(burnTokens) {
 			endIdx = len(burnTokens)
 		}
@@ -71,40 +268,43 @@ func (w *Wallet) collectDust(ctx context.Context, acc *accountKey, tokens []*sdk
 			if err != nil {
 				w.log.WarnContext(ctx, fmt.Sprintf("unable to join tokens of type '%X', account key '0x%X': %v", token.TypeID, acc.PubKey, err))
 				// just stop without returning error, so that we can continue with other token types
-				if totalFees > 0 {
-					return &SubmissionResult{FeeSum: totalFees}, nil
-				}
-				return nil, nil
+				return results, nil
 			}
 		}
 
 		var lockFee uint64
-		lockFee, err = w.lockTokenForDC(ctx, acc, fcrID, targetToken, ownerPredicateInput)
+		lockFee, err = w.lockTokenForDC(ctx, acc, st, targetToken, ownerPredicateInput)
 		if err != nil {
-			return nil, fmt.Errorf("failed to lock target token: %w", err)
+			return results, fmt.Errorf("failed to lock target token: %w", err)
 		}
 
 		targetToken.Counter += 1
-		burnBatchAmount, burnFee, proofs, err := w.burnTokensForDC(ctx, acc, burnBatch, targetToken, fcrID, ownerPredicateInput, typeOwnerPredicateInputs)
+		burnBatchAmount, burnFee, proofs, err := w.burnTokensForDC(ctx, acc, st, burnBatch, targetToken, ownerPredicateInput, typeOwnerPredicateInputs)
 		if err != nil {
-			return nil, err
+			return results, err
 		}
 
 		// if there's more to burn, update counter to continue
-		var joinFee uint64
-		joinFee, err = w.joinTokenForDC(ctx, acc, proofs, targetToken, fcrID, ownerPredicateInput, typeOwnerPredicateInputs)
+		joinSub, err := w.joinTokenForDC(ctx, acc, st, proofs, targetToken, ownerPredicateInput, typeOwnerPredicateInputs)
 		if err != nil {
-			return nil, err
+			return results, err
 		}
 		targetToken.Counter += 1
 
 		totalAmountJoined += burnBatchAmount
-		totalFees += lockFee + burnFee + joinFee
+		results = append(results, &SubmissionResult{
+			Submissions:   []*txsubmitter.TxSubmission{joinSub},
+			AccountNumber: acc.AccountNumber(),
+			FeeSum:        lockFee + burnFee + joinSub.Proof.ActualFee(),
+		})
+		if st.swapDone != nil {
+			st.swapDone()
+		}
 	}
-	return &SubmissionResult{FeeSum: totalFees}, nil
+	return results, nil
 }
 
-func (w *Wallet) joinTokenForDC(ctx context.Context, acc *accountKey, burnProofs []*types.TxRecordProof, targetToken *sdktypes.FungibleToken, fcrID types.UnitID, ownerPredicateInput *PredicateInput, typeOwnerPredicateInputs []*PredicateInput) (uint64, error) {
+func (w *Wallet) joinTokenForDC(ctx context.Context, acc *accountKey, st *dcState, burnProofs []*types.TxRecordProof, targetToken *sdktypes.FungibleToken, ownerPredicateInput *PredicateInput, typeOwnerPredicateInputs []*PredicateInput) (*txsubmitter.TxSubmission, error) {
 	var extErr error
 	// explicitly sort proofs by unit ids in increasing order
 	sort.Slice(burnProofs, func(i, j int) bool {
@@ -123,69 +323,62 @@ func (w *Wallet) joinTokenForDC(ctx context.Context, acc *accountKey, burnProofs
 		return a.Compare(b) < 0
 	})
 	if extErr != nil {
-		return 0, extErr
-	}
-	roundNumber, err := w.GetRoundNumber(ctx)
-	if err != nil {
-		return 0, err
+		return nil, extErr
 	}
 
 	tx, err := targetToken.Join(burnProofs,
-		sdktypes.WithTimeout(roundNumber+txTimeoutRoundCount),
-		sdktypes.WithFeeCreditRecordID(fcrID),
+		sdktypes.WithTimeout(w.txTimeout(st.roundNumber)),
+		sdktypes.WithFeeCreditRecordID(st.fcrID),
 		sdktypes.WithMaxFee(w.maxFee),
 	)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	sigBytes, err := tx.AuthProofSigBytes()
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	typeOwnerProofs, err := newProofs(sigBytes, typeOwnerPredicateInputs)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	ownerProof, err := ownerPredicateInput.Proof(sigBytes)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	err = tx.SetAuthProof(tokens.JoinFungibleTokenAuthProof{
 		OwnerProof:           ownerProof,
 		TokenTypeOwnerProofs: typeOwnerProofs,
 	})
 	if err != nil {
-		return 0, fmt.Errorf("failed to set auth proof: %w", err)
+		return nil, fmt.Errorf("failed to set auth proof: %w", err)
 	}
 	tx.FeeProof, err = sdktypes.NewP2pkhFeeSignatureFromKey(tx, acc.PrivKey)
 	if err != nil {
-		return 0, fmt.Errorf("failed to sign tx fee proof: %w", err)
+		return nil, fmt.Errorf("failed to sign tx fee proof: %w", err)
 	}
 
 	sub, err := txsubmitter.New(tx)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	if err = sub.ToBatch(w.tokensClient, w.log).SendTx(ctx, true); err != nil {
-		return 0, err
+	if err = w.sendTx(ctx, sub.ToBatch(w.tokensClient, w.log, w.batchOpts...), true); err != nil {
+		return nil, err
 	}
-	return sub.Proof.TxRecord.ServerMetadata.ActualFee, nil
+	st.observe(sub.Proof)
+	return sub, nil
 }
 
-func (w *Wallet) burnTokensForDC(ctx context.Context, acc *accountKey, tokensToBurn []*sdktypes.FungibleToken, targetToken *sdktypes.FungibleToken, fcrID types.UnitID, ownerPredicateInput *PredicateInput, typeOwnerPredicateInputs []*PredicateInput) (uint64, uint64, []*types.TxRecordProof, error) {
-	burnBatch := txsubmitter.NewBatch(w.tokensClient, w.log)
+func (w *Wallet) burnTokensForDC(ctx context.Context, acc *accountKey, st *dcState, tokensToBurn []*sdktypes.FungibleToken, targetToken *sdktypes.FungibleToken, ownerPredicateInput *PredicateInput, typeOwnerPredicateInputs []*PredicateInput) (uint64, uint64, []*types.TxRecordProof, error) {
+	burnBatch := txsubmitter.NewBatch(w.tokensClient, w.log, w.batchOpts...)
 	burnBatchAmount := uint64(0)
 
-	roundNumber, err := w.GetRoundNumber(ctx)
-	if err != nil {
-		return 0, 0, nil, fmt.Errorf("failed to get round number: %w", err)
-	}
 	for _, token := range tokensToBurn {
 		burnBatchAmount += token.Amount
 		tx, err := token.Burn(targetToken.ID, targetToken.Counter,
-			sdktypes.WithTimeout(roundNumber+txTimeoutRoundCount),
-			sdktypes.WithFeeCreditRecordID(fcrID),
+			sdktypes.WithTimeout(w.txTimeout(st.roundNumber)),
+			sdktypes.WithFeeCreditRecordID(st.fcrID),
 			sdktypes.WithMaxFee(w.maxFee),
 		)
 		if err != nil {
@@ -223,7 +416,7 @@ func (w *Wallet) burnTokensForDC(ctx context.Context, acc *accountKey, tokensToB
 		burnBatch.Add(sub)
 	}
 
-	if err := burnBatch.SendTx(ctx, true); err != nil {
+	if err := w.sendTx(ctx, burnBatch, true); err != nil {
 		return 0, 0, nil, fmt.Errorf("failed to send burn tx: %w", err)
 	}
 
@@ -233,6 +426,7 @@ func (w *Wallet) burnTokensForDC(ctx context.Context, acc *accountKey, tokensToB
 		proofs = append(proofs, sub.Proof)
 		feeSum += sub.Proof.TxRecord.ServerMetadata.ActualFee
 	}
+	st.observe(proofs...)
 	return burnBatchAmount, feeSum, proofs, nil
 }
 
@@ -267,14 +461,10 @@ func (w *Wallet) getTokensForDC(ctx context.Context, key sdktypes.PubKey, allowe
 	return tokensByTypes, nil
 }
 
-func (w *Wallet) lockTokenForDC(ctx context.Context, acc *accountKey, fcrID types.UnitID, targetToken Token, ownerPredicateInput *PredicateInput) (uint64, error) {
-	roundNumber, err := w.GetRoundNumber(ctx)
-	if err != nil {
-		return 0, err
-	}
+func (w *Wallet) lockTokenForDC(ctx context.Context, acc *accountKey, st *dcState, targetToken Token, ownerPredicateInput *PredicateInput) (uint64, error) {
 	tx, err := targetToken.Lock(wallet.LockReasonCollectDust,
-		sdktypes.WithTimeout(roundNumber+txTimeoutRoundCount),
-		sdktypes.WithFeeCreditRecordID(fcrID),
+		sdktypes.WithTimeout(w.txTimeout(st.roundNumber)),
+		sdktypes.WithFeeCreditRecordID(st.fcrID),
 		sdktypes.WithMaxFee(w.maxFee),
 	)
 	if err != nil {
@@ -304,8 +494,9 @@ func (w *Wallet) lockTokenForDC(ctx context.Context, acc *accountKey, fcrID type
 	if err != nil {
 		return 0, err
 	}
-	if err = sub.ToBatch(w.tokensClient, w.log).SendTx(ctx, true); err != nil {
+	if err = w.sendTx(ctx, sub.ToBatch(w.tokensClient, w.log, w.batchOpts...), true); err != nil {
 		return 0, err
 	}
+	st.observe(sub.Proof)
 	return sub.Proof.TxRecord.ServerMetadata.ActualFee, nil
 }
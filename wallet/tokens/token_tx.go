@@ -1,13 +1,14 @@
 package tokens
 
 import (
+	"bytes"
 	"context"
 	"fmt"
-	"sort"
 
 	"github.com/alphabill-org/alphabill-go-base/hash"
 	"github.com/alphabill-org/alphabill-go-base/predicates/templates"
 	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
+	"github.com/alphabill-org/alphabill-go-base/types"
 
 	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
 	"github.com/alphabill-org/alphabill-wallet/wallet/txsubmitter"
@@ -15,8 +16,18 @@ import (
 
 const (
 	txTimeoutRoundCount = 10
+	// historyScanRange is the number of rounds scanned with a single ScanBlocks call
+	historyScanRange = 10000
 )
 
+// txTimeout returns the timeout of a transaction created in the given round.
+func (w *Wallet) txTimeout(roundNumber uint64) uint64 {
+	if w.timeoutRounds == 0 {
+		return roundNumber + txTimeoutRoundCount
+	}
+	return roundNumber + w.timeoutRounds
+}
+
 func ownerPredicateFromHash(receiverPubKeyHash []byte) sdktypes.Predicate {
 	var bytes []byte
 	if receiverPubKeyHash != nil {
@@ -35,108 +46,217 @@ func OwnerPredicateFromPubKey(receiverPubKey sdktypes.PubKey) sdktypes.Predicate
 	return ownerPredicateFromHash(h)
 }
 
-// assumes there's sufficient balance for the given amount, sends transactions immediately
-func (w *Wallet) doSendMultiple(ctx context.Context, amount uint64, tokens []*sdktypes.FungibleToken, acc *accountKey, fcrID, receiverPubKey []byte, ownerProof *PredicateInput, typeOwnerPredicateInputs []*PredicateInput) (*SubmissionResult, error) {
+// assumes there's sufficient balance for the given amount, spends the tokens in the given order
+// and sends transactions immediately
+func (w *Wallet) doSendMultiple(ctx context.Context, amount uint64, tokens []*sdktypes.FungibleToken, acc *accountKey, fcrID, receiverPubKey, changeOwnerPredicate []byte, ownerProof *PredicateInput, typeOwnerPredicateInputs []*PredicateInput) (*SubmissionResult, error) {
 	var accumulatedSum uint64
-	sort.Slice(tokens, func(i, j int) bool {
-		return tokens[i].Amount > tokens[j].Amount
-	})
-
-	batch := txsubmitter.NewBatch(w.tokensClient, w.log)
+	batch := txsubmitter.NewBatch(w.tokensClient, w.log, w.batchOpts...)
 	roundNumber, err := w.GetRoundNumber(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	// the token that is split, if any, its remaining value is the change
+	var splitToken *sdktypes.FungibleToken
 	for _, t := range tokens {
 		remainingAmount := amount - accumulatedSum
-		sub, err := w.prepareSplitOrTransferTx(acc, remainingAmount, t, fcrID, receiverPubKey, roundNumber+txTimeoutRoundCount, ownerProof, typeOwnerPredicateInputs)
+		sub, err := w.prepareSplitOrTransferTx(acc, remainingAmount, t, fcrID, receiverPubKey, w.txTimeout(roundNumber), ownerProof, typeOwnerPredicateInputs)
 		if err != nil {
 			return nil, err
 		}
 		batch.Add(sub)
+		if remainingAmount < t.Amount {
+			splitToken = t
+		}
 		accumulatedSum += t.Amount
 		if accumulatedSum >= amount {
 			break
 		}
 	}
-	err = batch.SendTx(ctx, w.confirmTx)
+	moveChange := splitToken != nil && changeOwnerPredicate != nil && !bytes.Equal(splitToken.OwnerPredicate, changeOwnerPredicate)
+	err = w.sendBatch(ctx, OperationSend, acc.AccountNumber(), batch, w.confirmTx || moveChange)
+	submissions := batch.Submissions()
+	if err == nil && moveChange && !w.dryRun {
+		var sub *txsubmitter.TxSubmission
+		sub, err = w.sendSplitChange(ctx, acc, splitToken.ID, fcrID, changeOwnerPredicate, ownerProof, typeOwnerPredicateInputs)
+		if sub != nil {
+			submissions = append(submissions, sub)
+		}
+	}
 	feeSum := uint64(0)
-	for _, sub := range batch.Submissions() {
+	for _, sub := range submissions {
 		if sub.Confirmed() {
 			feeSum += sub.Proof.TxRecord.ServerMetadata.ActualFee
 		}
 	}
-	return &SubmissionResult{Submissions: batch.Submissions(), FeeSum: feeSum, AccountNumber: acc.AccountNumber()}, err
+	return &SubmissionResult{Submissions: submissions, FeeSum: feeSum, AccountNumber: acc.AccountNumber(), DryRun: w.dryRun, pdr: w.pdr}, err
 }
 
 func (w *Wallet) prepareSplitOrTransferTx(acc *accountKey, amount uint64, ft *sdktypes.FungibleToken, fcrID, receiverPubKey []byte, timeout uint64, ownerPredicateInput *PredicateInput, typeOwnerPredicateInputs []*PredicateInput) (*txsubmitter.TxSubmission, error) {
 	if amount >= ft.Amount {
-		tx, err := ft.Transfer(OwnerPredicateFromPubKey(receiverPubKey),
-			sdktypes.WithTimeout(timeout),
-			sdktypes.WithFeeCreditRecordID(fcrID),
-			sdktypes.WithMaxFee(w.maxFee),
-		)
-		if err != nil {
-			return nil, err
-		}
+		return w.prepareTransferTx(acc, ft, fcrID, OwnerPredicateFromPubKey(receiverPubKey), timeout, ownerPredicateInput, typeOwnerPredicateInputs)
+	}
+	tx, err := ft.Split(amount, OwnerPredicateFromPubKey(receiverPubKey),
+		sdktypes.WithTimeout(timeout),
+		sdktypes.WithFeeCreditRecordID(fcrID),
+		sdktypes.WithMaxFee(w.maxFee),
+	)
+	if err != nil {
+		return nil, err
+	}
+	payloadBytes, err := tx.AuthProofSigBytes()
+	if err != nil {
+		return nil, err
+	}
+	typeOwnerProofs, err := newProofs(payloadBytes, typeOwnerPredicateInputs)
+	if err != nil {
+		return nil, err
+	}
+	ownerProof, err := ownerPredicateInput.Proof(payloadBytes)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.SetAuthProof(tokens.SplitFungibleTokenAuthProof{
+		OwnerProof:           ownerProof,
+		TokenTypeOwnerProofs: typeOwnerProofs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set auth proof: %w", err)
+	}
+	tx.FeeProof, err = sdktypes.NewP2pkhFeeSignatureFromKey(tx, acc.PrivKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign tx fee proof: %w", err)
+	}
+	return txsubmitter.New(tx)
+}
 
-		payloadBytes, err := tx.AuthProofSigBytes()
-		if err != nil {
-			return nil, err
-		}
-		typeOwnerProofs, err := newProofs(payloadBytes, typeOwnerPredicateInputs)
-		if err != nil {
-			return nil, err
-		}
-		ownerProof, err := ownerPredicateInput.Proof(payloadBytes)
-		if err != nil {
-			return nil, err
-		}
-		err = tx.SetAuthProof(tokens.TransferFungibleTokenAuthProof{
-			OwnerProof:           ownerProof,
-			TokenTypeOwnerProofs: typeOwnerProofs,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to set auth proof: %w", err)
-		}
-		tx.FeeProof, err = sdktypes.NewP2pkhFeeSignatureFromKey(tx, acc.PrivKey)
-		if err != nil {
-			return nil, fmt.Errorf("failed to sign tx fee proof: %w", err)
-		}
-		return txsubmitter.New(tx)
-	} else {
-		tx, err := ft.Split(amount, OwnerPredicateFromPubKey(receiverPubKey),
-			sdktypes.WithTimeout(timeout),
-			sdktypes.WithFeeCreditRecordID(fcrID),
-			sdktypes.WithMaxFee(w.maxFee),
-		)
-		if err != nil {
-			return nil, err
-		}
-		payloadBytes, err := tx.AuthProofSigBytes()
-		if err != nil {
-			return nil, err
-		}
-		typeOwnerProofs, err := newProofs(payloadBytes, typeOwnerPredicateInputs)
-		if err != nil {
-			return nil, err
-		}
-		ownerProof, err := ownerPredicateInput.Proof(payloadBytes)
-		if err != nil {
-			return nil, err
-		}
-		err = tx.SetAuthProof(tokens.SplitFungibleTokenAuthProof{
-			OwnerProof:           ownerProof,
-			TokenTypeOwnerProofs: typeOwnerProofs,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to set auth proof: %w", err)
-		}
-		tx.FeeProof, err = sdktypes.NewP2pkhFeeSignatureFromKey(tx, acc.PrivKey)
-		if err != nil {
-			return nil, fmt.Errorf("failed to sign tx fee proof: %w", err)
-		}
-		return txsubmitter.New(tx)
+func (w *Wallet) prepareTransferTx(acc *accountKey, ft *sdktypes.FungibleToken, fcrID, newOwnerPredicate []byte, timeout uint64, ownerPredicateInput *PredicateInput, typeOwnerPredicateInputs []*PredicateInput) (*txsubmitter.TxSubmission, error) {
+	tx, err := ft.Transfer(newOwnerPredicate,
+		sdktypes.WithTimeout(timeout),
+		sdktypes.WithFeeCreditRecordID(fcrID),
+		sdktypes.WithMaxFee(w.maxFee),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	payloadBytes, err := tx.AuthProofSigBytes()
+	if err != nil {
+		return nil, err
+	}
+	typeOwnerProofs, err := newProofs(payloadBytes, typeOwnerPredicateInputs)
+	if err != nil {
+		return nil, err
+	}
+	ownerProof, err := ownerPredicateInput.Proof(payloadBytes)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.SetAuthProof(tokens.TransferFungibleTokenAuthProof{
+		OwnerProof:           ownerProof,
+		TokenTypeOwnerProofs: typeOwnerProofs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set auth proof: %w", err)
+	}
+	tx.FeeProof, err = sdktypes.NewP2pkhFeeSignatureFromKey(tx, acc.PrivKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign tx fee proof: %w", err)
+	}
+	return txsubmitter.New(tx)
+}
+
+func (w *Wallet) prepareNFTTransferTx(acc *accountKey, nft *sdktypes.NonFungibleToken, newOwnerPredicate sdktypes.Predicate, fcrID []byte, timeout uint64, ownerPredicateInput *PredicateInput, typeOwnerPredicateInputs []*PredicateInput) (*types.TransactionOrder, error) {
+	tx, err := nft.Transfer(newOwnerPredicate,
+		sdktypes.WithTimeout(timeout),
+		sdktypes.WithFeeCreditRecordID(fcrID),
+		sdktypes.WithMaxFee(w.maxFee),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	sigBytes, err := tx.AuthProofSigBytes()
+	if err != nil {
+		return nil, err
+	}
+	typeOwnerProofs, err := newProofs(sigBytes, typeOwnerPredicateInputs)
+	if err != nil {
+		return nil, err
+	}
+	ownerProof, err := ownerPredicateInput.Proof(sigBytes)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.SetAuthProof(tokens.TransferNonFungibleTokenAuthProof{
+		OwnerProof:           ownerProof,
+		TokenTypeOwnerProofs: typeOwnerProofs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set auth proof: %w", err)
+	}
+	tx.FeeProof, err = sdktypes.NewP2pkhFeeSignatureFromKey(tx, acc.PrivKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign tx fee proof: %w", err)
+	}
+	return tx, nil
+}
+
+// prepareNFTUpdateTx returns signed transaction which replaces the data of the NFT.
+func (w *Wallet) prepareNFTUpdateTx(acc *accountKey, nft *sdktypes.NonFungibleToken, data []byte, fcrID []byte, timeout uint64, tokenDataUpdatePredicateInput *PredicateInput, tokenTypeDataUpdatePredicateInputs []*PredicateInput) (*types.TransactionOrder, error) {
+	tx, err := nft.Update(data,
+		sdktypes.WithTimeout(timeout),
+		sdktypes.WithFeeCreditRecordID(fcrID),
+		sdktypes.WithMaxFee(w.maxFee),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	sigBytes, err := tx.AuthProofSigBytes()
+	if err != nil {
+		return nil, err
+	}
+	tokenDataUpdateProof, err := tokenDataUpdatePredicateInput.Proof(sigBytes)
+	if err != nil {
+		return nil, err
+	}
+	tokenTypeDataUpdateProofs, err := newProofs(sigBytes, tokenTypeDataUpdatePredicateInputs)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.SetAuthProof(tokens.UpdateNonFungibleTokenAuthProof{
+		TokenDataUpdateProof:      tokenDataUpdateProof,
+		TokenTypeDataUpdateProofs: tokenTypeDataUpdateProofs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set auth proof: %w", err)
+	}
+	tx.FeeProof, err = sdktypes.NewP2pkhFeeSignatureFromKey(tx, acc.PrivKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign tx fee proof: %w", err)
+	}
+	return tx, nil
+}
+
+// sendSplitChange transfers the remaining value of the split token to the change owner predicate.
+// Split leaves the remaining value in the original unit, so the split must be confirmed before
+// the change can be transferred.
+func (w *Wallet) sendSplitChange(ctx context.Context, acc *accountKey, tokenID sdktypes.TokenID, fcrID, changeOwnerPredicate []byte, ownerPredicateInput *PredicateInput, typeOwnerPredicateInputs []*PredicateInput) (*txsubmitter.TxSubmission, error) {
+	ft, err := w.GetFungibleToken(ctx, tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch split token: %w", err)
+	}
+	roundNumber, err := w.GetRoundNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := w.prepareTransferTx(acc, ft, fcrID, changeOwnerPredicate, w.txTimeout(roundNumber), ownerPredicateInput, typeOwnerPredicateInputs)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.sendTx(ctx, sub.ToBatch(w.tokensClient, w.log, w.batchOpts...), w.confirmTx); err != nil {
+		return sub, fmt.Errorf("failed to transfer split change: %w", err)
 	}
+	return sub, nil
 }
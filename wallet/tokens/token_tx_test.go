@@ -3,6 +3,7 @@ package tokens
 import (
 	"bytes"
 	"context"
+	"crypto"
 	"testing"
 
 	"github.com/alphabill-org/alphabill-go-base/types"
@@ -10,7 +11,6 @@ import (
 	"github.com/stretchr/testify/require"
 
 	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
-	"github.com/alphabill-org/alphabill-wallet/internal/testutils"
 	"github.com/alphabill-org/alphabill-wallet/internal/testutils/logger"
 	"github.com/alphabill-org/alphabill-wallet/wallet/txsubmitter"
 )
@@ -57,7 +57,9 @@ func TestConfirmUnitsTx_ok(t *testing.T) {
 func TestConfirmUnitsTx_timeout(t *testing.T) {
 	getRoundInfoCalled := 0
 	getTxProofCalled := 0
-	randomTxHash1 := testutils.RandomBytes(32)
+	tx1 := &types.TransactionOrder{Payload: types.Payload{ClientMetadata: &types.ClientMetadata{Timeout: 101}}}
+	txHash1, err := tx1.Hash(crypto.SHA256)
+	require.NoError(t, err)
 	rpcClient := &mockTokensPartitionClient{
 		sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
 			return nil, nil
@@ -71,16 +73,15 @@ func TestConfirmUnitsTx_timeout(t *testing.T) {
 		},
 		getTransactionProof: func(ctx context.Context, txHash hex.Bytes) (*types.TxRecordProof, error) {
 			getTxProofCalled++
-			if bytes.Equal(txHash, randomTxHash1) {
+			if bytes.Equal(txHash, txHash1) {
 				return &types.TxRecordProof{}, nil
 			}
 			return nil, nil
 		},
 	}
 	batch := txsubmitter.NewBatch(rpcClient, logger.New(t))
-	sub1, err := txsubmitter.New(&types.TransactionOrder{Payload: types.Payload{ClientMetadata: &types.ClientMetadata{Timeout: 101}}})
+	sub1, err := txsubmitter.New(tx1)
 	require.NoError(t, err)
-	sub1.TxHash = randomTxHash1
 	batch.Add(sub1)
 	sub2, err := txsubmitter.New(&types.TransactionOrder{Payload: types.Payload{ClientMetadata: &types.ClientMetadata{Timeout: 102}}})
 	require.NoError(t, err)
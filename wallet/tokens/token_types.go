@@ -0,0 +1,340 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+)
+
+var errTokenTypeNotFound = errors.New("token type not found")
+
+// UnresolvedTypeToken is a token whose type could not be resolved or whose
+// type metadata does not match the type.
+type UnresolvedTypeToken struct {
+	ID     sdktypes.TokenID
+	TypeID sdktypes.TokenTypeID
+	Reason error
+}
+
+// FindUnresolvedTypeTokens attempts to resolve the type of each given token and returns the tokens
+// whose type can't be fetched or whose symbol (or decimal places for fungible tokens) do not match
+// the type. Each type is fetched only once. Returns error only if the context is cancelled.
+func (w *Wallet) FindUnresolvedTypeTokens(ctx context.Context, fts []*sdktypes.FungibleToken, nfts []*sdktypes.NonFungibleToken) ([]*UnresolvedTypeToken, error) {
+	var res []*UnresolvedTypeToken
+
+	ftTypes := make(map[string]*sdktypes.FungibleTokenType)
+	ftTypeErrs := make(map[string]error)
+	for _, ft := range fts {
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+		typeID := string(ft.TypeID)
+		tt, cached := ftTypes[typeID]
+		err := ftTypeErrs[typeID]
+		if !cached && err == nil {
+			tt, err = w.GetFungibleTokenType(ctx, ft.TypeID)
+			if err == nil && tt == nil {
+				err = errTokenTypeNotFound
+			}
+			ftTypes[typeID], ftTypeErrs[typeID] = tt, err
+		}
+		if err == nil {
+			err = verifyFungibleTokenType(ft, tt)
+		}
+		if err != nil {
+			res = append(res, &UnresolvedTypeToken{ID: ft.ID, TypeID: ft.TypeID, Reason: err})
+		}
+	}
+
+	nftTypes := make(map[string]*sdktypes.NonFungibleTokenType)
+	nftTypeErrs := make(map[string]error)
+	for _, nft := range nfts {
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+		typeID := string(nft.TypeID)
+		tt, cached := nftTypes[typeID]
+		err := nftTypeErrs[typeID]
+		if !cached && err == nil {
+			tt, err = w.GetNonFungibleTokenType(ctx, nft.TypeID)
+			if err == nil && tt == nil {
+				err = errTokenTypeNotFound
+			}
+			nftTypes[typeID], nftTypeErrs[typeID] = tt, err
+		}
+		if err == nil && nft.Symbol != tt.Symbol {
+			err = fmt.Errorf("symbol mismatch: token has %q, type has %q", nft.Symbol, tt.Symbol)
+		}
+		if err != nil {
+			res = append(res, &UnresolvedTypeToken{ID: nft.ID, TypeID: nft.TypeID, Reason: err})
+		}
+	}
+	return res, nil
+}
+
+func verifyFungibleTokenType(ft *sdktypes.FungibleToken, tt *sdktypes.FungibleTokenType) error {
+	if ft.Symbol != tt.Symbol {
+		return fmt.Errorf("symbol mismatch: token has %q, type has %q", ft.Symbol, tt.Symbol)
+	}
+	if ft.DecimalPlaces != tt.DecimalPlaces {
+		return fmt.Errorf("decimal places mismatch: token has %d, type has %d", ft.DecimalPlaces, tt.DecimalPlaces)
+	}
+	return nil
+}
+
+// TokenTypeDescription is a human-readable summary of a token type, predicates
+// are described using DescribePredicate.
+type TokenTypeDescription struct {
+	ID                       sdktypes.TokenTypeID
+	ParentTypeID             sdktypes.TokenTypeID
+	Fungible                 bool
+	Symbol                   string
+	Name                     string
+	DecimalPlaces            uint32
+	SubTypeCreationPredicate string
+	TokenMintingPredicate    string
+	TokenTypeOwnerPredicate  string
+	// DataUpdatePredicate is set only for non-fungible token types.
+	DataUpdatePredicate string
+}
+
+// DescribeTokenType fetches the fungible or non-fungible token type with the given ID and
+// returns its human-readable description.
+func (w *Wallet) DescribeTokenType(ctx context.Context, typeID sdktypes.TokenTypeID) (*TokenTypeDescription, error) {
+	unitType, err := w.pdr.ExtractUnitType(typeID)
+	if err != nil {
+		return nil, fmt.Errorf("extracting unit type: %w", err)
+	}
+	switch unitType {
+	case tokens.FungibleTokenTypeUnitType:
+		tt, err := w.GetFungibleTokenType(ctx, typeID)
+		if err != nil {
+			return nil, fmt.Errorf("fetching fungible token type: %w", err)
+		}
+		if tt == nil {
+			return nil, fmt.Errorf("%w: %s", errTokenTypeNotFound, typeID)
+		}
+		return describeFungibleTokenType(tt), nil
+	case tokens.NonFungibleTokenTypeUnitType:
+		tt, err := w.GetNonFungibleTokenType(ctx, typeID)
+		if err != nil {
+			return nil, fmt.Errorf("fetching non-fungible token type: %w", err)
+		}
+		if tt == nil {
+			return nil, fmt.Errorf("%w: %s", errTokenTypeNotFound, typeID)
+		}
+		return describeNonFungibleTokenType(tt), nil
+	default:
+		return nil, fmt.Errorf("invalid token type ID: unit type %d", unitType)
+	}
+}
+
+func describeFungibleTokenType(tt *sdktypes.FungibleTokenType) *TokenTypeDescription {
+	return &TokenTypeDescription{
+		ID:                       tt.ID,
+		ParentTypeID:             tt.ParentTypeID,
+		Fungible:                 true,
+		Symbol:                   tt.Symbol,
+		Name:                     tt.Name,
+		DecimalPlaces:            tt.DecimalPlaces,
+		SubTypeCreationPredicate: DescribePredicate(tt.SubTypeCreationPredicate),
+		TokenMintingPredicate:    DescribePredicate(tt.TokenMintingPredicate),
+		TokenTypeOwnerPredicate:  DescribePredicate(tt.TokenTypeOwnerPredicate),
+	}
+}
+
+func describeNonFungibleTokenType(tt *sdktypes.NonFungibleTokenType) *TokenTypeDescription {
+	return &TokenTypeDescription{
+		ID:                       tt.ID,
+		ParentTypeID:             tt.ParentTypeID,
+		Symbol:                   tt.Symbol,
+		Name:                     tt.Name,
+		SubTypeCreationPredicate: DescribePredicate(tt.SubTypeCreationPredicate),
+		TokenMintingPredicate:    DescribePredicate(tt.TokenMintingPredicate),
+		TokenTypeOwnerPredicate:  DescribePredicate(tt.TokenTypeOwnerPredicate),
+		DataUpdatePredicate:      DescribePredicate(tt.DataUpdatePredicate),
+	}
+}
+
+// TokenContext is a token together with its type hierarchy, it has everything needed to decide
+// which operations are permitted on the token. Predicates are described using DescribePredicate.
+type TokenContext struct {
+	// Token is either *sdktypes.FungibleToken or *sdktypes.NonFungibleToken.
+	Token          Token
+	Fungible       bool
+	OwnerPredicate string
+	// DataUpdatePredicate is set only for non-fungible tokens.
+	DataUpdatePredicate string
+	// Types is the type hierarchy of the token, the type of the token is the first and the root
+	// type the last element. The inherited predicates of the types must be satisfied too.
+	Types []*TokenTypeDescription
+}
+
+// GetTokenWithTypeContext fetches the fungible or non-fungible token with the given ID together
+// with its type hierarchy. Token types are immutable, so the type hierarchies are cached by the wallet.
+func (w *Wallet) GetTokenWithTypeContext(ctx context.Context, tokenID sdktypes.TokenID) (*TokenContext, error) {
+	unitType, err := w.pdr.ExtractUnitType(tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("extracting unit type: %w", err)
+	}
+	switch unitType {
+	case tokens.FungibleTokenUnitType:
+		token, err := w.GetFungibleToken(ctx, tokenID)
+		if err != nil {
+			return nil, err
+		}
+		typez, err := w.getTypeHierarchy(ctx, token.TypeID, true)
+		if err != nil {
+			return nil, err
+		}
+		return &TokenContext{
+			Token:          token,
+			Fungible:       true,
+			OwnerPredicate: DescribePredicate(token.OwnerPredicate),
+			Types:          typez,
+		}, nil
+	case tokens.NonFungibleTokenUnitType:
+		token, err := w.GetNonFungibleToken(ctx, tokenID)
+		if err != nil {
+			return nil, err
+		}
+		typez, err := w.getTypeHierarchy(ctx, token.TypeID, false)
+		if err != nil {
+			return nil, err
+		}
+		return &TokenContext{
+			Token:               token,
+			OwnerPredicate:      DescribePredicate(token.OwnerPredicate),
+			DataUpdatePredicate: DescribePredicate(token.DataUpdatePredicate),
+			Types:               typez,
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid token ID: unit type %d", unitType)
+	}
+}
+
+// getTypeHierarchy returns the descriptions of the type hierarchy of the given type, the hierarchy
+// is fetched only once per type.
+func (w *Wallet) getTypeHierarchy(ctx context.Context, typeID sdktypes.TokenTypeID, fungible bool) ([]*TokenTypeDescription, error) {
+	w.typeMu.Lock()
+	typez, cached := w.typeCache[string(typeID)]
+	w.typeMu.Unlock()
+	if cached {
+		return typez, nil
+	}
+
+	if fungible {
+		hierarchy, err := w.tokensClient.GetFungibleTokenTypeHierarchy(ctx, typeID)
+		if err != nil {
+			return nil, fmt.Errorf("fetching fungible token type hierarchy: %w", err)
+		}
+		for _, tt := range hierarchy {
+			typez = append(typez, describeFungibleTokenType(tt))
+		}
+	} else {
+		hierarchy, err := w.tokensClient.GetNonFungibleTokenTypeHierarchy(ctx, typeID)
+		if err != nil {
+			return nil, fmt.Errorf("fetching non-fungible token type hierarchy: %w", err)
+		}
+		for _, tt := range hierarchy {
+			typez = append(typez, describeNonFungibleTokenType(tt))
+		}
+	}
+	if len(typez) == 0 {
+		return nil, fmt.Errorf("%w: %s", errTokenTypeNotFound, typeID)
+	}
+
+	w.typeMu.Lock()
+	defer w.typeMu.Unlock()
+	if w.typeCache == nil {
+		w.typeCache = make(map[string][]*TokenTypeDescription)
+	}
+	w.typeCache[string(typeID)] = typez
+	return typez, nil
+}
+
+// GetTokenTypeIcon fetches the fungible or non-fungible token type with the given ID and returns
+// the icon embedded in the type, nil is returned if the type has no icon.
+func (w *Wallet) GetTokenTypeIcon(ctx context.Context, typeID sdktypes.TokenTypeID) (*tokens.Icon, error) {
+	unitType, err := w.pdr.ExtractUnitType(typeID)
+	if err != nil {
+		return nil, fmt.Errorf("extracting unit type: %w", err)
+	}
+	var icon *tokens.Icon
+	switch unitType {
+	case tokens.FungibleTokenTypeUnitType:
+		tt, err := w.GetFungibleTokenType(ctx, typeID)
+		if err != nil {
+			return nil, fmt.Errorf("fetching fungible token type: %w", err)
+		}
+		if tt == nil {
+			return nil, fmt.Errorf("%w: %s", errTokenTypeNotFound, typeID)
+		}
+		icon = tt.Icon
+	case tokens.NonFungibleTokenTypeUnitType:
+		tt, err := w.GetNonFungibleTokenType(ctx, typeID)
+		if err != nil {
+			return nil, fmt.Errorf("fetching non-fungible token type: %w", err)
+		}
+		if tt == nil {
+			return nil, fmt.Errorf("%w: %s", errTokenTypeNotFound, typeID)
+		}
+		icon = tt.Icon
+	default:
+		return nil, fmt.Errorf("invalid token type ID: unit type %d", unitType)
+	}
+	if icon == nil || (icon.Type == "" && len(icon.Data) == 0) {
+		return nil, nil
+	}
+	return icon, nil
+}
+
+// ttlCache is a concurrency safe cache whose entries expire after the TTL. The methods of a nil
+// cache are no-ops, so that caching can be optional.
+type ttlCache[V any] struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[string]ttlCacheEntry[V]
+}
+
+type ttlCacheEntry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+func newTTLCache[V any](ttl time.Duration) *ttlCache[V] {
+	return &ttlCache[V]{ttl: ttl, now: time.Now, entries: make(map[string]ttlCacheEntry[V])}
+}
+
+func (c *ttlCache[V]) get(key string) (v V, ok bool) {
+	if c == nil {
+		return v, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return v, false
+	}
+	if !c.now().Before(e.expires) {
+		delete(c.entries, key)
+		return v, false
+	}
+	return e.value, true
+}
+
+func (c *ttlCache[V]) set(key string, v V) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = ttlCacheEntry[V]{value: v, expires: c.now().Add(c.ttl)}
+}
@@ -0,0 +1,348 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alphabill-org/alphabill-go-base/predicates/templates"
+	tokenid "github.com/alphabill-org/alphabill-go-base/testutils/tokens"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
+	"github.com/stretchr/testify/require"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+)
+
+func TestFindUnresolvedTypeTokens(t *testing.T) {
+	validFtTypeID := tokenid.NewFungibleTokenTypeID(t)
+	missingFtTypeID := tokenid.NewFungibleTokenTypeID(t)
+	validNftTypeID := tokenid.NewNonFungibleTokenTypeID(t)
+	brokenNftTypeID := tokenid.NewNonFungibleTokenTypeID(t)
+
+	ftHierarchyCalls := 0
+	rpcClient := &mockTokensPartitionClient{
+		getFungibleTokenTypeHierarchy: func(ctx context.Context, id sdktypes.TokenTypeID) ([]*sdktypes.FungibleTokenType, error) {
+			ftHierarchyCalls++
+			if id.Eq(validFtTypeID) {
+				return []*sdktypes.FungibleTokenType{{ID: validFtTypeID, Symbol: "AB", DecimalPlaces: 2}}, nil
+			}
+			return nil, nil
+		},
+		getNonFungibleTokenTypeHierarchy: func(ctx context.Context, id sdktypes.TokenTypeID) ([]*sdktypes.NonFungibleTokenType, error) {
+			if id.Eq(validNftTypeID) {
+				return []*sdktypes.NonFungibleTokenType{{ID: validNftTypeID, Symbol: "NFT"}}, nil
+			}
+			return nil, errors.New("state error")
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+
+	fts := []*sdktypes.FungibleToken{
+		{ID: tokenid.NewFungibleTokenID(t), TypeID: validFtTypeID, Symbol: "AB", DecimalPlaces: 2},
+		{ID: tokenid.NewFungibleTokenID(t), TypeID: validFtTypeID, Symbol: "AB", DecimalPlaces: 2},
+		{ID: tokenid.NewFungibleTokenID(t), TypeID: validFtTypeID, Symbol: "XX", DecimalPlaces: 2},
+		{ID: tokenid.NewFungibleTokenID(t), TypeID: missingFtTypeID, Symbol: "AB", DecimalPlaces: 2},
+	}
+	nfts := []*sdktypes.NonFungibleToken{
+		{ID: tokenid.NewNonFungibleTokenID(t), TypeID: validNftTypeID, Symbol: "NFT"},
+		{ID: tokenid.NewNonFungibleTokenID(t), TypeID: brokenNftTypeID, Symbol: "NFT"},
+	}
+
+	res, err := tw.FindUnresolvedTypeTokens(context.Background(), fts, nfts)
+	require.NoError(t, err)
+	require.Len(t, res, 3)
+	require.EqualValues(t, fts[2].ID, res[0].ID)
+	require.ErrorContains(t, res[0].Reason, `symbol mismatch: token has "XX", type has "AB"`)
+	require.EqualValues(t, fts[3].ID, res[1].ID)
+	require.EqualValues(t, missingFtTypeID, res[1].TypeID)
+	require.ErrorIs(t, res[1].Reason, errTokenTypeNotFound)
+	require.EqualValues(t, nfts[1].ID, res[2].ID)
+	require.ErrorContains(t, res[2].Reason, "state error")
+
+	// each type is fetched only once
+	require.Equal(t, 2, ftHierarchyCalls)
+
+	// no tokens, nothing to report
+	res, err = tw.FindUnresolvedTypeTokens(context.Background(), nil, nil)
+	require.NoError(t, err)
+	require.Empty(t, res)
+}
+
+func TestDescribeTokenType(t *testing.T) {
+	ftTypeID := tokenid.NewFungibleTokenTypeID(t)
+	parentTypeID := tokenid.NewFungibleTokenTypeID(t)
+	nftTypeID := tokenid.NewNonFungibleTokenTypeID(t)
+	missingTypeID := tokenid.NewNonFungibleTokenTypeID(t)
+	ownerPredicate := templates.NewP2pkh256BytesFromKeyHash([]byte{1, 2, 3})
+	pdr := tokenid.PDR()
+
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getFungibleTokenTypeHierarchy: func(ctx context.Context, id sdktypes.TokenTypeID) ([]*sdktypes.FungibleTokenType, error) {
+			return []*sdktypes.FungibleTokenType{{
+				ID:                       ftTypeID,
+				ParentTypeID:             parentTypeID,
+				Symbol:                   "AB",
+				Name:                     "Alphabill",
+				DecimalPlaces:            8,
+				SubTypeCreationPredicate: sdktypes.Predicate(templates.AlwaysFalseBytes()),
+				TokenMintingPredicate:    sdktypes.Predicate(ownerPredicate),
+				TokenTypeOwnerPredicate:  sdktypes.Predicate(templates.AlwaysTrueBytes()),
+			}}, nil
+		},
+		getNonFungibleTokenTypeHierarchy: func(ctx context.Context, id sdktypes.TokenTypeID) ([]*sdktypes.NonFungibleTokenType, error) {
+			if !id.Eq(nftTypeID) {
+				return nil, nil
+			}
+			return []*sdktypes.NonFungibleTokenType{{
+				ID:                       nftTypeID,
+				Symbol:                   "NFT",
+				SubTypeCreationPredicate: sdktypes.Predicate(templates.AlwaysTrueBytes()),
+				TokenMintingPredicate:    sdktypes.Predicate(templates.AlwaysTrueBytes()),
+				TokenTypeOwnerPredicate:  sdktypes.Predicate(templates.AlwaysTrueBytes()),
+				DataUpdatePredicate:      sdktypes.Predicate(templates.AlwaysFalseBytes()),
+			}}, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+
+	desc, err := tw.DescribeTokenType(context.Background(), ftTypeID)
+	require.NoError(t, err)
+	require.Equal(t, &TokenTypeDescription{
+		ID:                       ftTypeID,
+		ParentTypeID:             parentTypeID,
+		Fungible:                 true,
+		Symbol:                   "AB",
+		Name:                     "Alphabill",
+		DecimalPlaces:            8,
+		SubTypeCreationPredicate: "always false",
+		TokenMintingPredicate:    "p2pkh(0x010203)",
+		TokenTypeOwnerPredicate:  "always true",
+	}, desc)
+
+	desc, err = tw.DescribeTokenType(context.Background(), nftTypeID)
+	require.NoError(t, err)
+	require.False(t, desc.Fungible)
+	require.Equal(t, "NFT", desc.Symbol)
+	require.Empty(t, desc.ParentTypeID)
+	require.Equal(t, "always false", desc.DataUpdatePredicate)
+
+	_, err = tw.DescribeTokenType(context.Background(), missingTypeID)
+	require.ErrorIs(t, err, errTokenTypeNotFound)
+
+	_, err = tw.DescribeTokenType(context.Background(), tokenid.NewFungibleTokenID(t))
+	require.ErrorContains(t, err, "invalid token type ID")
+}
+
+func TestGetTokenWithTypeContext(t *testing.T) {
+	rootTypeID := tokenid.NewFungibleTokenTypeID(t)
+	ftTypeID := tokenid.NewFungibleTokenTypeID(t)
+	nftTypeID := tokenid.NewNonFungibleTokenTypeID(t)
+	ftID := tokenid.NewFungibleTokenID(t)
+	nftID := tokenid.NewNonFungibleTokenID(t)
+	ownerPredicate := templates.NewP2pkh256BytesFromKeyHash([]byte{1, 2, 3})
+	pdr := tokenid.PDR()
+
+	ftHierarchyCalls := 0
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getFungibleToken: func(ctx context.Context, id sdktypes.TokenID) (*sdktypes.FungibleToken, error) {
+			if !id.Eq(ftID) {
+				return nil, nil
+			}
+			return &sdktypes.FungibleToken{ID: ftID, TypeID: ftTypeID, Symbol: "AB", Amount: 5, OwnerPredicate: ownerPredicate}, nil
+		},
+		getNonFungibleToken: func(ctx context.Context, id sdktypes.TokenID) (*sdktypes.NonFungibleToken, error) {
+			return &sdktypes.NonFungibleToken{ID: nftID, TypeID: nftTypeID, OwnerPredicate: sdktypes.Predicate(templates.AlwaysTrueBytes()), DataUpdatePredicate: sdktypes.Predicate(templates.AlwaysFalseBytes())}, nil
+		},
+		getFungibleTokenTypeHierarchy: func(ctx context.Context, id sdktypes.TokenTypeID) ([]*sdktypes.FungibleTokenType, error) {
+			ftHierarchyCalls++
+			return []*sdktypes.FungibleTokenType{
+				{ID: ftTypeID, ParentTypeID: rootTypeID, Symbol: "AB", TokenMintingPredicate: sdktypes.Predicate(ownerPredicate), SubTypeCreationPredicate: sdktypes.Predicate(templates.AlwaysTrueBytes()), TokenTypeOwnerPredicate: sdktypes.Predicate(templates.AlwaysTrueBytes())},
+				{ID: rootTypeID, Symbol: "ROOT", TokenMintingPredicate: sdktypes.Predicate(templates.AlwaysFalseBytes()), SubTypeCreationPredicate: sdktypes.Predicate(templates.AlwaysTrueBytes()), TokenTypeOwnerPredicate: sdktypes.Predicate(templates.AlwaysFalseBytes())},
+			}, nil
+		},
+		getNonFungibleTokenTypeHierarchy: func(ctx context.Context, id sdktypes.TokenTypeID) ([]*sdktypes.NonFungibleTokenType, error) {
+			return []*sdktypes.NonFungibleTokenType{{ID: nftTypeID, Symbol: "NFT", DataUpdatePredicate: sdktypes.Predicate(templates.AlwaysTrueBytes())}}, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+
+	tc, err := tw.GetTokenWithTypeContext(context.Background(), ftID)
+	require.NoError(t, err)
+	require.True(t, tc.Fungible)
+	require.EqualValues(t, ftID, tc.Token.GetID())
+	require.Equal(t, "p2pkh(0x010203)", tc.OwnerPredicate)
+	require.Empty(t, tc.DataUpdatePredicate)
+	require.Len(t, tc.Types, 2)
+	require.EqualValues(t, ftTypeID, tc.Types[0].ID)
+	require.Equal(t, "p2pkh(0x010203)", tc.Types[0].TokenMintingPredicate)
+	require.EqualValues(t, rootTypeID, tc.Types[1].ID)
+	require.Equal(t, "always false", tc.Types[1].TokenTypeOwnerPredicate)
+
+	// type hierarchy is cached
+	_, err = tw.GetTokenWithTypeContext(context.Background(), ftID)
+	require.NoError(t, err)
+	require.Equal(t, 1, ftHierarchyCalls)
+
+	tc, err = tw.GetTokenWithTypeContext(context.Background(), nftID)
+	require.NoError(t, err)
+	require.False(t, tc.Fungible)
+	require.Equal(t, "always true", tc.OwnerPredicate)
+	require.Equal(t, "always false", tc.DataUpdatePredicate)
+	require.Len(t, tc.Types, 1)
+	require.Equal(t, "always true", tc.Types[0].DataUpdatePredicate)
+
+	_, err = tw.GetTokenWithTypeContext(context.Background(), tokenid.NewFungibleTokenID(t))
+	require.ErrorContains(t, err, "token not found")
+
+	_, err = tw.GetTokenWithTypeContext(context.Background(), ftTypeID)
+	require.ErrorContains(t, err, "invalid token ID")
+}
+
+func TestGetTokenTypeIcon(t *testing.T) {
+	ftTypeID := tokenid.NewFungibleTokenTypeID(t)
+	nftTypeID := tokenid.NewNonFungibleTokenTypeID(t)
+	missingTypeID := tokenid.NewNonFungibleTokenTypeID(t)
+	icon := &tokens.Icon{Type: "image/png", Data: []byte{1, 2, 3}}
+	pdr := tokenid.PDR()
+
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getFungibleTokenTypeHierarchy: func(ctx context.Context, id sdktypes.TokenTypeID) ([]*sdktypes.FungibleTokenType, error) {
+			return []*sdktypes.FungibleTokenType{{ID: ftTypeID, Symbol: "AB", Icon: icon}}, nil
+		},
+		getNonFungibleTokenTypeHierarchy: func(ctx context.Context, id sdktypes.TokenTypeID) ([]*sdktypes.NonFungibleTokenType, error) {
+			if !id.Eq(nftTypeID) {
+				return nil, nil
+			}
+			return []*sdktypes.NonFungibleTokenType{{ID: nftTypeID, Symbol: "NFT", Icon: &tokens.Icon{}}}, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+
+	res, err := tw.GetTokenTypeIcon(context.Background(), ftTypeID)
+	require.NoError(t, err)
+	require.Equal(t, icon, res)
+
+	// empty icon is reported as no icon
+	res, err = tw.GetTokenTypeIcon(context.Background(), nftTypeID)
+	require.NoError(t, err)
+	require.Nil(t, res)
+
+	_, err = tw.GetTokenTypeIcon(context.Background(), missingTypeID)
+	require.ErrorIs(t, err, errTokenTypeNotFound)
+
+	_, err = tw.GetTokenTypeIcon(context.Background(), tokenid.NewFungibleTokenID(t))
+	require.ErrorContains(t, err, "invalid token type ID")
+}
+
+func TestWithTypeCache(t *testing.T) {
+	ftTypeID := tokenid.NewFungibleTokenTypeID(t)
+	nftTypeID := tokenid.NewNonFungibleTokenTypeID(t)
+	ftCalls, nftCalls := 0, 0
+	rpcClient := &mockTokensPartitionClient{
+		getFungibleTokenTypeHierarchy: func(ctx context.Context, id sdktypes.TokenTypeID) ([]*sdktypes.FungibleTokenType, error) {
+			ftCalls++
+			if id.Eq(ftTypeID) {
+				return []*sdktypes.FungibleTokenType{{ID: ftTypeID, Symbol: "FT"}}, nil
+			}
+			return nil, nil
+		},
+		getNonFungibleTokenTypeHierarchy: func(ctx context.Context, id sdktypes.TokenTypeID) ([]*sdktypes.NonFungibleTokenType, error) {
+			nftCalls++
+			return []*sdktypes.NonFungibleTokenType{{ID: nftTypeID, Symbol: "NFT"}}, nil
+		},
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		ftCalls = 0
+		tw := initTestWallet(t, rpcClient)
+		for range 2 {
+			tt, err := tw.GetFungibleTokenType(context.Background(), ftTypeID)
+			require.NoError(t, err)
+			require.Equal(t, "FT", tt.Symbol)
+		}
+		require.Equal(t, 2, ftCalls)
+	})
+
+	t.Run("second lookup is served from the cache", func(t *testing.T) {
+		ftCalls, nftCalls = 0, 0
+		tw := initTestWallet(t, rpcClient)
+		WithTypeCache(time.Minute)(tw)
+		now := time.Now()
+		tw.ftTypeCache.now = func() time.Time { return now }
+
+		for range 2 {
+			tt, err := tw.GetFungibleTokenType(context.Background(), ftTypeID)
+			require.NoError(t, err)
+			require.Equal(t, "FT", tt.Symbol)
+			nft, err := tw.GetNonFungibleTokenType(context.Background(), nftTypeID)
+			require.NoError(t, err)
+			require.Equal(t, "NFT", nft.Symbol)
+		}
+		require.Equal(t, 1, ftCalls)
+		require.Equal(t, 1, nftCalls)
+
+		// types that are not found are not cached
+		missingTypeID := tokenid.NewFungibleTokenTypeID(t)
+		for range 2 {
+			tt, err := tw.GetFungibleTokenType(context.Background(), missingTypeID)
+			require.NoError(t, err)
+			require.Nil(t, tt)
+		}
+		require.Equal(t, 3, ftCalls)
+
+		// expired entry is fetched again
+		now = now.Add(time.Minute)
+		_, err := tw.GetFungibleTokenType(context.Background(), ftTypeID)
+		require.NoError(t, err)
+		require.Equal(t, 4, ftCalls)
+	})
+}
+
+func TestWithRoundNumberCache(t *testing.T) {
+	calls := 0
+	round := uint64(10)
+	rpcClient := &mockTokensPartitionClient{
+		getRoundInfo: func(ctx context.Context) (*sdktypes.RoundInfo, error) {
+			calls++
+			return &sdktypes.RoundInfo{RoundNumber: round}, nil
+		},
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		calls = 0
+		tw := initTestWallet(t, rpcClient)
+		for range 2 {
+			roundNumber, err := tw.GetRoundNumber(context.Background())
+			require.NoError(t, err)
+			require.EqualValues(t, 10, roundNumber)
+		}
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("calls within TTL make one RPC", func(t *testing.T) {
+		calls = 0
+		tw := initTestWallet(t, rpcClient)
+		WithRoundNumberCache(time.Second)(tw)
+		now := time.Now()
+		tw.roundCache.now = func() time.Time { return now }
+
+		for range 2 {
+			roundNumber, err := tw.GetRoundNumber(context.Background())
+			require.NoError(t, err)
+			require.EqualValues(t, 10, roundNumber)
+		}
+		require.Equal(t, 1, calls)
+
+		// expired round number is fetched again
+		round = 11
+		now = now.Add(time.Second)
+		roundNumber, err := tw.GetRoundNumber(context.Background())
+		require.NoError(t, err)
+		require.EqualValues(t, 11, roundNumber)
+		require.Equal(t, 2, calls)
+	})
+}
@@ -10,6 +10,7 @@ import (
 
 	abcrypto "github.com/alphabill-org/alphabill-go-base/crypto"
 	"github.com/alphabill-org/alphabill-go-base/predicates/templates"
+	"github.com/alphabill-org/alphabill-go-base/predicates/wasm"
 	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
 	"github.com/alphabill-org/alphabill-go-base/types"
 
@@ -267,3 +268,93 @@ func (p *PredicateInput) Proof(sigBytes []byte) ([]byte, error) {
 	}
 	return p.Argument, nil
 }
+
+// Ownership is the kind of the owner predicate of a token, it tells what is needed to
+// satisfy the predicate.
+type Ownership int
+
+const (
+	// OwnershipOther is a template predicate that can't be satisfied by the wallet, ie "always false".
+	OwnershipOther Ownership = iota
+	// OwnershipP2PKH is satisfied by the signature of the account key.
+	OwnershipP2PKH
+	// OwnershipAlwaysTrue is satisfied without any owner proof.
+	OwnershipAlwaysTrue
+	// OwnershipCustom is a non-template predicate, the owner proof must be supplied by the caller.
+	OwnershipCustom
+)
+
+func (o Ownership) String() string {
+	switch o {
+	case OwnershipP2PKH:
+		return "p2pkh"
+	case OwnershipAlwaysTrue:
+		return "always true"
+	case OwnershipCustom:
+		return "custom"
+	default:
+		return "other"
+	}
+}
+
+// OwnershipKind returns the kind of the owner predicate of the token so that the caller can
+// decide up front whether a custom owner proof has to be provided to spend the token.
+func OwnershipKind(token Token) (Ownership, error) {
+	kind, err := predicateKind(token.GetOwnerPredicate())
+	if err != nil {
+		return OwnershipOther, fmt.Errorf("decoding owner predicate of token '%s': %w", token.GetID(), err)
+	}
+	return kind, nil
+}
+
+// predicateKind returns the kind of the CBOR encoded predicate.
+func predicateKind(predicate []byte) (Ownership, error) {
+	p, err := extractPredicate(predicate)
+	if err != nil {
+		return OwnershipOther, err
+	}
+	if p.Tag != templates.TemplateStartByte {
+		return OwnershipCustom, nil
+	}
+	if len(p.Code) == 1 {
+		switch p.Code[0] {
+		case templates.P2pkh256ID:
+			return OwnershipP2PKH, nil
+		case templates.AlwaysTrueID:
+			return OwnershipAlwaysTrue, nil
+		}
+	}
+	return OwnershipOther, nil
+}
+
+// DescribePredicate returns human-readable description of the CBOR encoded predicate,
+// ie "always true" or "p2pkh(0x<public key hash>)".
+func DescribePredicate(predicate []byte) string {
+	if len(predicate) == 0 {
+		return predicateEmpty
+	}
+	p, err := extractPredicate(predicate)
+	if err != nil {
+		return fmt.Sprintf("invalid predicate 0x%X", predicate)
+	}
+	switch p.Tag {
+	case templates.TemplateStartByte:
+		if len(p.Code) != 1 {
+			return fmt.Sprintf("unknown template 0x%X", p.Code)
+		}
+		switch p.Code[0] {
+		case templates.AlwaysTrueID:
+			return "always true"
+		case templates.AlwaysFalseID:
+			return "always false"
+		case templates.P2pkh256ID:
+			return fmt.Sprintf("p2pkh(0x%X)", p.Params)
+		default:
+			return fmt.Sprintf("unknown template 0x%X", p.Code)
+		}
+	case wasm.PredicateEngineID:
+		return fmt.Sprintf("wasm predicate (code %d bytes, parameter %d bytes)", len(p.Code), len(p.Params))
+	default:
+		return fmt.Sprintf("engine %d predicate (code %d bytes, parameter %d bytes)", p.Tag, len(p.Code), len(p.Params))
+	}
+}
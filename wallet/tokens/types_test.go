@@ -8,10 +8,13 @@ import (
 	"syscall"
 	"testing"
 
+	"github.com/alphabill-org/alphabill-go-base/predicates"
 	"github.com/alphabill-org/alphabill-go-base/predicates/templates"
+	"github.com/alphabill-org/alphabill-go-base/predicates/wasm"
 	"github.com/alphabill-org/alphabill-go-base/types"
 	"github.com/stretchr/testify/require"
 
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
 	"github.com/alphabill-org/alphabill-wallet/wallet/account"
 )
 
@@ -289,6 +292,46 @@ func TestDecodeHexOrEmpty(t *testing.T) {
 	}
 }
 
+func TestDescribePredicate(t *testing.T) {
+	wasmPredicate, err := types.Cbor.Marshal(predicates.Predicate{Tag: wasm.PredicateEngineID, Code: []byte{1, 2, 3}, Params: []byte{4}})
+	require.NoError(t, err)
+	unknownEngine, err := types.Cbor.Marshal(predicates.Predicate{Tag: 5, Code: []byte{1}})
+	require.NoError(t, err)
+	unknownTemplate, err := types.Cbor.Marshal(predicates.Predicate{Tag: templates.TemplateStartByte, Code: []byte{9}})
+	require.NoError(t, err)
+
+	require.Equal(t, "empty", DescribePredicate(nil))
+	require.Equal(t, "always true", DescribePredicate(templates.AlwaysTrueBytes()))
+	require.Equal(t, "always false", DescribePredicate(templates.AlwaysFalseBytes()))
+	require.Equal(t, "p2pkh(0x0102)", DescribePredicate(templates.NewP2pkh256BytesFromKeyHash([]byte{1, 2})))
+	require.Equal(t, "wasm predicate (code 3 bytes, parameter 1 bytes)", DescribePredicate(wasmPredicate))
+	require.Equal(t, "engine 5 predicate (code 1 bytes, parameter 0 bytes)", DescribePredicate(unknownEngine))
+	require.Equal(t, "unknown template 0x09", DescribePredicate(unknownTemplate))
+	require.Equal(t, "invalid predicate 0x0102", DescribePredicate([]byte{1, 2}))
+}
+
+func TestOwnershipKind(t *testing.T) {
+	wasmPredicate, err := types.Cbor.Marshal(predicates.Predicate{Tag: wasm.PredicateEngineID, Code: []byte{1, 2, 3}})
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		predicate []byte
+		kind      Ownership
+	}{
+		{predicate: templates.NewP2pkh256BytesFromKeyHash([]byte{1, 2}), kind: OwnershipP2PKH},
+		{predicate: templates.AlwaysTrueBytes(), kind: OwnershipAlwaysTrue},
+		{predicate: templates.AlwaysFalseBytes(), kind: OwnershipOther},
+		{predicate: wasmPredicate, kind: OwnershipCustom},
+	} {
+		kind, err := OwnershipKind(&sdktypes.NonFungibleToken{OwnerPredicate: tc.predicate})
+		require.NoError(t, err)
+		require.Equal(t, tc.kind, kind, DescribePredicate(tc.predicate))
+	}
+
+	_, err = OwnershipKind(&sdktypes.FungibleToken{OwnerPredicate: []byte{1, 2}})
+	require.ErrorContains(t, err, "decoding owner predicate of token")
+}
+
 type accountManagerMock struct {
 	keyHash       []byte
 	recordedIndex uint64
@@ -0,0 +1,68 @@
+package tokens
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TokenEvent is emitted by WatchIncomingTokens when a new token appears in the account.
+type TokenEvent struct {
+	// Token is either *sdktypes.FungibleToken or *sdktypes.NonFungibleToken
+	Token Token
+}
+
+// WatchIncomingTokens polls the tokens of the account with the given interval and emits an event
+// for each token that was not owned by the account on the previous poll. The tokens owned by the
+// account when the watch starts are not reported. Polling errors are logged and the watch goes
+// on, the returned channel is closed when the context is cancelled.
+func (w *Wallet) WatchIncomingTokens(ctx context.Context, accountNumber uint64, interval time.Duration) (<-chan TokenEvent, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid poll interval %s, must be positive", interval)
+	}
+	tokenz, err := w.listTokens(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]struct{}, len(tokenz))
+	for _, t := range tokenz {
+		seen[string(t.GetID())] = struct{}{}
+	}
+
+	events := make(chan TokenEvent)
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			tokenz, err := w.listTokens(ctx, accountNumber)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				w.log.WarnContext(ctx, fmt.Sprintf("polling tokens of account #%d: %v", accountNumber, err))
+				continue
+			}
+			current := make(map[string]struct{}, len(tokenz))
+			for _, t := range tokenz {
+				id := string(t.GetID())
+				current[id] = struct{}{}
+				if _, ok := seen[id]; ok {
+					continue
+				}
+				select {
+				case events <- TokenEvent{Token: t}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			seen = current
+		}
+	}()
+	return events, nil
+}
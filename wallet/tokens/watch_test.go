@@ -0,0 +1,81 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	tokenid "github.com/alphabill-org/alphabill-go-base/testutils/tokens"
+	"github.com/stretchr/testify/require"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+)
+
+func TestWatchIncomingTokens(t *testing.T) {
+	typeID := tokenid.NewFungibleTokenTypeID(t)
+	oldFT := newFungibleToken(t, tokenid.NewFungibleTokenID(t), typeID, "AB", 10, 0)
+	newFT := newFungibleToken(t, tokenid.NewFungibleTokenID(t), typeID, "AB", 5, 0)
+	newNFT := newNonFungibleToken(t, "ABNFT", nil, 0, 0)
+
+	var mu sync.Mutex
+	fts := []*sdktypes.FungibleToken{oldFT}
+	var nfts []*sdktypes.NonFungibleToken
+	var pollErr error
+	rpcClient := &mockTokensPartitionClient{
+		getFungibleTokens: func(ctx context.Context, ownerID []byte) ([]*sdktypes.FungibleToken, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return fts, pollErr
+		},
+		getNonFungibleTokens: func(ctx context.Context, ownerID []byte) ([]*sdktypes.NonFungibleToken, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return nfts, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+
+	_, err := tw.WatchIncomingTokens(context.Background(), 1, 0)
+	require.EqualError(t, err, "invalid poll interval 0s, must be positive")
+
+	_, err = tw.WatchIncomingTokens(context.Background(), 0, time.Millisecond)
+	require.ErrorContains(t, err, "invalid account number: 0")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := tw.WatchIncomingTokens(ctx, 1, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	// failed poll doesn't stop the watch
+	mu.Lock()
+	pollErr = errors.New("connection refused")
+	mu.Unlock()
+	time.Sleep(30 * time.Millisecond)
+
+	mu.Lock()
+	pollErr = nil
+	fts = []*sdktypes.FungibleToken{oldFT, newFT}
+	nfts = []*sdktypes.NonFungibleToken{newNFT}
+	mu.Unlock()
+
+	var received []Token
+	for len(received) < 2 {
+		select {
+		case ev := <-events:
+			received = append(received, ev.Token)
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for incoming tokens")
+		}
+	}
+	require.Equal(t, []Token{newFT, newNFT}, received)
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		require.False(t, ok, "expected channel to be closed")
+	case <-time.After(time.Second):
+		t.Fatal("channel was not closed on context cancellation")
+	}
+}
@@ -5,13 +5,20 @@ import (
 	"crypto"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"net"
+	"net/http"
+	"syscall"
 	"time"
 
 	"github.com/alphabill-org/alphabill-go-base/types"
 	"github.com/alphabill-org/alphabill-go-base/types/hex"
+	"github.com/ethereum/go-ethereum/rpc"
 
 	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"github.com/alphabill-org/alphabill-wallet/util"
+	"github.com/alphabill-org/alphabill-wallet/wallet"
 )
 
 type (
@@ -24,12 +31,41 @@ type (
 
 	TxSubmissionBatch struct {
 		submissions     []*TxSubmission
-		maxTimeout      uint64
 		partitionClient sdktypes.PartitionClient
 		log             *slog.Logger
+		// number of times sending a transaction is retried on a retryable error
+		maxRetries   int
+		retryBackoff time.Duration
 	}
+
+	BatchOption func(*TxSubmissionBatch)
+)
+
+const (
+	defaultRetryBackoff = 500 * time.Millisecond
+	// interval of polling the proofs of the submitted transactions
+	confirmationPollInterval = 500 * time.Millisecond
 )
 
+// WithMaxRetries makes the batch retry sending a transaction up to n times when the node
+// returns a retryable error (network error, HTTP 5xx), see IsRetryable. By default
+// sending is not retried.
+func WithMaxRetries(n int) BatchOption {
+	return func(b *TxSubmissionBatch) {
+		b.maxRetries = n
+	}
+}
+
+// WithRetryBackoff sets the delay before the first retry, the delay is doubled on every
+// following retry. By default 500ms is used.
+func WithRetryBackoff(d time.Duration) BatchOption {
+	return func(b *TxSubmissionBatch) {
+		if d > 0 {
+			b.retryBackoff = d
+		}
+	}
+}
+
 func New(tx *types.TransactionOrder) (*TxSubmission, error) {
 	txHash, err := tx.Hash(crypto.SHA256)
 	if err != nil {
@@ -42,31 +78,46 @@ func New(tx *types.TransactionOrder) (*TxSubmission, error) {
 	}, nil
 }
 
-func (s *TxSubmission) ToBatch(partitionClient sdktypes.PartitionClient, log *slog.Logger) *TxSubmissionBatch {
-	return &TxSubmissionBatch{
-		partitionClient: partitionClient,
-		submissions:     []*TxSubmission{s},
-		maxTimeout:      s.Transaction.Timeout(),
-		log:             log,
-	}
+func (s *TxSubmission) ToBatch(partitionClient sdktypes.PartitionClient, log *slog.Logger, opts ...BatchOption) *TxSubmissionBatch {
+	b := NewBatch(partitionClient, log, opts...)
+	b.Add(s)
+	return b
+}
+
+// TxID returns the transaction hash in the canonical display format.
+func (s *TxSubmission) TxID() string {
+	return util.FormatTxID(s.TxHash)
 }
 
 func (s *TxSubmission) Confirmed() bool {
 	return s.Proof != nil
 }
 
-func NewBatch(partitionClient sdktypes.PartitionClient, log *slog.Logger) *TxSubmissionBatch {
-	return &TxSubmissionBatch{
+// Success returns true when the transaction is confirmed and executed successfully, ie a
+// confirmed transaction may still have failed (and the fee was charged).
+func (s *TxSubmission) Success() bool {
+	return s.Confirmed() && s.Proof.TxRecord.TxStatus() == types.TxStatusSuccessful
+}
+
+// logAttrs returns the attributes identifying the transaction in structured log records.
+func (s *TxSubmission) logAttrs() []any {
+	return []any{slog.String("txHash", s.TxID()), slog.String("unitID", s.UnitID.String())}
+}
+
+func NewBatch(partitionClient sdktypes.PartitionClient, log *slog.Logger, opts ...BatchOption) *TxSubmissionBatch {
+	b := &TxSubmissionBatch{
 		partitionClient: partitionClient,
 		log:             log,
+		retryBackoff:    defaultRetryBackoff,
+	}
+	for _, opt := range opts {
+		opt(b)
 	}
+	return b
 }
 
 func (t *TxSubmissionBatch) Add(sub *TxSubmission) {
 	t.submissions = append(t.submissions, sub)
-	if sub.Transaction.Timeout() > t.maxTimeout {
-		t.maxTimeout = sub.Transaction.Timeout()
-	}
 }
 
 func (t *TxSubmissionBatch) Submissions() []*TxSubmission {
@@ -78,8 +129,7 @@ func (t *TxSubmissionBatch) SendTx(ctx context.Context, confirmTx bool) error {
 		return errors.New("no transactions to send")
 	}
 	for _, txSubmission := range t.submissions {
-		_, err := t.partitionClient.SendTransaction(ctx, txSubmission.Transaction)
-		if err != nil {
+		if err := t.sendTransaction(ctx, txSubmission); err != nil {
 			return err
 		}
 	}
@@ -89,70 +139,103 @@ func (t *TxSubmissionBatch) SendTx(ctx context.Context, confirmTx bool) error {
 	return nil
 }
 
+// sendTransaction sends the transaction of the submission, retrying with exponential backoff
+// on retryable errors when the batch has been configured to retry.
+func (t *TxSubmissionBatch) sendTransaction(ctx context.Context, sub *TxSubmission) error {
+	backoff := t.retryBackoff
+	for attempt := 0; ; attempt++ {
+		_, err := t.partitionClient.SendTransaction(ctx, sub.Transaction)
+		if err == nil {
+			t.log.DebugContext(ctx, "Tx submitted", append(sub.logAttrs(), slog.Uint64("timeout", sub.Transaction.Timeout()))...)
+			return nil
+		}
+		if attempt >= t.maxRetries || !IsRetryable(err) {
+			t.log.InfoContext(ctx, "Sending tx failed", append(sub.logAttrs(), slog.Any("error", err))...)
+			return err
+		}
+		t.log.WarnContext(ctx, "Sending tx failed, retrying", append(sub.logAttrs(), slog.Duration("backoff", backoff), slog.Any("error", err))...)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("sending transaction interrupted: %w", errors.Join(ctx.Err(), err))
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// IsRetryable returns true if the error of sending a transaction is transient, ie a network
+// error or an HTTP 5xx (or 429) response, so that sending the transaction again may succeed.
+// Errors returned by the node for the transaction, ie validation errors, are not retryable.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return false
+	}
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= http.StatusInternalServerError || httpErr.StatusCode == http.StatusTooManyRequests
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// confirmUnitsTx waits for the proofs of the submitted transactions, the transactions were all
+// sent before, so the proofs are polled one transaction at a time.
 func (t *TxSubmissionBatch) confirmUnitsTx(ctx context.Context) error {
-	t.log.InfoContext(ctx, "Confirming submitted transactions")
+	t.log.InfoContext(ctx, "Confirming submitted transactions", slog.Int("count", len(t.submissions)))
 
-	for {
-		if err := ctx.Err(); err != nil {
-			return fmt.Errorf("confirming transactions interrupted: %w", err)
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("confirming transactions interrupted: %w", err)
+	}
+	unconfirmed := false
+	failed := false
+	for _, sub := range t.submissions {
+		if sub.Confirmed() {
+			continue
 		}
-
-		roundInfo, err := t.partitionClient.GetRoundInfo(ctx)
+		proof, err := wallet.WaitForConfirmation(ctx, t.partitionClient, sub.Transaction, confirmationPollInterval)
 		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return fmt.Errorf("confirming transactions interrupted: %w", ctxErr)
+			}
 			return err
 		}
-		unconfirmed := false
-		failed := false
-		for _, sub := range t.submissions {
-			if sub.Confirmed() {
-				continue
-			}
-			if roundInfo.RoundNumber <= sub.Transaction.Timeout() {
-				proof, err := t.partitionClient.GetTransactionProof(ctx, sub.TxHash)
-				if err != nil {
-					return err
-				}
-				if proof != nil {
-					sub.Proof = proof
-
-					var status types.TxStatus
-					if proof.TxRecord != nil && proof.TxRecord.ServerMetadata != nil {
-						status = proof.TxRecord.ServerMetadata.SuccessIndicator
-					}
-					switch status {
-					case types.TxStatusSuccessful:
-						t.log.DebugContext(ctx, fmt.Sprintf("Tx confirmed: hash=%X, unitID=%s", sub.TxHash, sub.UnitID))
-					case types.TxErrOutOfGas:
-						t.log.InfoContext(ctx, fmt.Sprintf("Tx failed: out of gas: hash=%X, unitID=%s", sub.TxHash, sub.UnitID))
-						failed = true
-					case types.TxStatusFailed:
-						t.log.InfoContext(ctx, fmt.Sprintf("Tx failed: hash=%X, unitID=%s", sub.TxHash, sub.UnitID))
-						failed = true
-					}
-				}
-			}
-
-			unconfirmed = unconfirmed || !sub.Confirmed()
+		if proof == nil {
+			t.log.InfoContext(ctx, "Tx not confirmed", sub.logAttrs()...)
+			unconfirmed = true
+			continue
 		}
-		if unconfirmed {
-			// If this was the last attempt to get proofs, log the ones that timed out.
-			if roundInfo.RoundNumber > t.maxTimeout {
-				t.log.InfoContext(ctx, fmt.Sprintf("Tx confirmation timeout is reached: round=%d", roundInfo.RoundNumber))
-
-				for _, sub := range t.submissions {
-					if !sub.Confirmed() {
-						t.log.InfoContext(ctx, fmt.Sprintf("Tx not confirmed: hash=%X, unitID=%s", sub.TxHash, sub.UnitID))
-					}
-				}
-				return errors.New("confirmation timeout")
-			}
+		sub.Proof = proof
 
-			time.Sleep(500 * time.Millisecond)
-		} else if failed {
-			return errors.New("transaction(s) failed")
-		} else {
-			t.log.InfoContext(ctx, "All transactions confirmed")
-			return nil
+		var status types.TxStatus
+		if proof.TxRecord != nil && proof.TxRecord.ServerMetadata != nil {
+			status = proof.TxRecord.ServerMetadata.SuccessIndicator
+		}
+		switch status {
+		case types.TxStatusSuccessful:
+			t.log.DebugContext(ctx, "Tx confirmed", append(sub.logAttrs(), slog.Uint64("fee", proof.ActualFee()))...)
+		case types.TxErrOutOfGas:
+			t.log.InfoContext(ctx, "Tx failed: out of gas", append(sub.logAttrs(), slog.Uint64("fee", proof.ActualFee()))...)
+			failed = true
+		case types.TxStatusFailed:
+			t.log.InfoContext(ctx, "Tx failed", append(sub.logAttrs(), slog.Uint64("fee", proof.ActualFee()))...)
+			failed = true
 		}
 	}
+	if unconfirmed {
+		t.log.InfoContext(ctx, "Tx confirmation timeout is reached")
+		return errors.New("confirmation timeout")
+	}
+	if failed {
+		return errors.New("transaction(s) failed")
+	}
+	t.log.InfoContext(ctx, "All transactions confirmed")
+	return nil
 }
@@ -1,11 +1,24 @@
 package txsubmitter
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/stretchr/testify/require"
 
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
 	"github.com/alphabill-org/alphabill-wallet/internal/testutils/logger"
 )
 
@@ -25,3 +38,111 @@ func TestConfirmUnitsTx_contextError(t *testing.T) {
 	err := batch.confirmUnitsTx(ctx)
 	require.ErrorContains(t, err, "confirming transactions interrupted")
 }
+
+type sendTxClient struct {
+	sdktypes.PartitionClient
+	sendTransaction func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error)
+}
+
+func (c *sendTxClient) SendTransaction(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+	return c.sendTransaction(ctx, tx)
+}
+
+func TestSendTx_retry(t *testing.T) {
+	newSub := func(t *testing.T) *TxSubmission {
+		sub, err := New(&types.TransactionOrder{Version: 1, Payload: types.Payload{UnitID: []byte{1}, ClientMetadata: &types.ClientMetadata{Timeout: 10}}})
+		require.NoError(t, err)
+		return sub
+	}
+	retryableErr := rpc.HTTPError{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable"}
+
+	t.Run("retryable error is retried", func(t *testing.T) {
+		calls := 0
+		client := &sendTxClient{sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+			calls++
+			if calls < 3 {
+				return nil, retryableErr
+			}
+			return nil, nil
+		}}
+		batch := newSub(t).ToBatch(client, logger.New(t), WithMaxRetries(3), WithRetryBackoff(time.Millisecond))
+		require.NoError(t, batch.SendTx(context.Background(), false))
+		require.Equal(t, 3, calls)
+	})
+
+	t.Run("retries are exhausted", func(t *testing.T) {
+		calls := 0
+		client := &sendTxClient{sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+			calls++
+			return nil, retryableErr
+		}}
+		batch := newSub(t).ToBatch(client, logger.New(t), WithMaxRetries(2), WithRetryBackoff(time.Millisecond))
+		require.ErrorAs(t, batch.SendTx(context.Background(), false), &rpc.HTTPError{})
+		require.Equal(t, 3, calls)
+	})
+
+	t.Run("not retried by default", func(t *testing.T) {
+		calls := 0
+		client := &sendTxClient{sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+			calls++
+			return nil, retryableErr
+		}}
+		batch := newSub(t).ToBatch(client, logger.New(t))
+		require.ErrorAs(t, batch.SendTx(context.Background(), false), &rpc.HTTPError{})
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("validation error fails fast", func(t *testing.T) {
+		calls := 0
+		validationErr := errors.New("invalid transaction")
+		client := &sendTxClient{sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+			calls++
+			return nil, validationErr
+		}}
+		batch := newSub(t).ToBatch(client, logger.New(t), WithMaxRetries(3), WithRetryBackoff(time.Millisecond))
+		require.ErrorIs(t, batch.SendTx(context.Background(), false), validationErr)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("context is canceled while waiting", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		client := &sendTxClient{sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+			cancel()
+			return nil, retryableErr
+		}}
+		batch := newSub(t).ToBatch(client, logger.New(t), WithMaxRetries(3), WithRetryBackoff(time.Hour))
+		err := batch.SendTx(ctx, false)
+		require.ErrorIs(t, err, context.Canceled)
+		require.ErrorContains(t, err, "sending transaction interrupted")
+	})
+}
+
+func TestIsRetryable(t *testing.T) {
+	require.True(t, IsRetryable(rpc.HTTPError{StatusCode: http.StatusBadGateway}))
+	require.True(t, IsRetryable(rpc.HTTPError{StatusCode: http.StatusTooManyRequests}))
+	require.False(t, IsRetryable(rpc.HTTPError{StatusCode: http.StatusBadRequest}))
+	require.True(t, IsRetryable(fmt.Errorf("sending: %w", syscall.ECONNREFUSED)))
+	require.True(t, IsRetryable(io.ErrUnexpectedEOF))
+	require.True(t, IsRetryable(&net.OpError{Op: "dial", Err: errors.New("timeout")}))
+	require.False(t, IsRetryable(context.Canceled))
+	require.False(t, IsRetryable(errors.New("invalid transaction")))
+	require.False(t, IsRetryable(nil))
+}
+
+func TestSendTx_structuredLog(t *testing.T) {
+	sub, err := New(&types.TransactionOrder{Version: 1, Payload: types.Payload{UnitID: []byte{1}, ClientMetadata: &types.ClientMetadata{Timeout: 10}}})
+	require.NoError(t, err)
+	buf := &bytes.Buffer{}
+	log := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := &sendTxClient{sendTransaction: func(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+		return nil, nil
+	}}
+	require.NoError(t, sub.ToBatch(client, log).SendTx(context.Background(), false))
+
+	var rec map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &rec))
+	require.Equal(t, "Tx submitted", rec["msg"])
+	require.Equal(t, sub.TxID(), rec["txHash"])
+	require.Equal(t, sub.UnitID.String(), rec["unitID"])
+	require.EqualValues(t, 10, rec["timeout"])
+}
@@ -1,5 +1,10 @@
 package wallet
 
+import (
+	"github.com/alphabill-org/alphabill-go-base/predicates/templates"
+	"github.com/alphabill-org/alphabill-go-base/types"
+)
+
 const (
 	LockReasonAddFees = 1 + iota
 	LockReasonReclaimFees
@@ -26,3 +31,13 @@ func (r LockReason) String() string {
 	}
 	return "locked"
 }
+
+// NewP2PKHStateLock returns state lock whose execution and rollback predicates are both the P2PKH
+// predicate of the public key hash, ie the locked transaction can be executed or discarded by the
+// owner of the key.
+func NewP2PKHStateLock(pubKeyHash []byte) *types.StateLock {
+	return &types.StateLock{
+		ExecutionPredicate: templates.NewP2pkh256BytesFromKeyHash(pubKeyHash),
+		RollbackPredicate:  templates.NewP2pkh256BytesFromKeyHash(pubKeyHash),
+	}
+}